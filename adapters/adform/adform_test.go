@@ -11,7 +11,7 @@ import (
 	"time"
 
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters/adapterstest"
-	"github.com/PubMatic-OpenWrap/prebid-server/cache/dummycache"
+	"github.com/PubMatic-OpenWrap/prebid-server/cache/datacache"
 	"github.com/PubMatic-OpenWrap/prebid-server/pbs"
 	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
 
@@ -191,7 +191,7 @@ func preparePrebidRequest(serverUrl string, t *testing.T) *pbs.PBSRequest {
 	pbsCookie.SetCookieOnResponse(fakeWriter, false, "", &config.HostCookie{Domain: ""}, time.Minute)
 	prebidHttpRequest.Header.Add("Cookie", fakeWriter.Header().Get("Set-Cookie"))
 
-	cacheClient, _ := dummycache.New()
+	cacheClient := datacache.NewDummy()
 	r, err := pbs.ParsePBSRequest(prebidHttpRequest, &config.AuctionTimeouts{
 		Default: 2000,
 		Max:     2000,
@@ -1,14 +1,15 @@
 package adapters
 
 import (
+	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"strings"
 
 	"github.com/PubMatic-OpenWrap/openrtb"
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
 	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	"github.com/PubMatic-OpenWrap/prebid-server/static"
 	"github.com/golang/glog"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -133,17 +134,23 @@ type BidderInfos map[string]BidderInfo
 // The map it returns will have a key for every element of the bidders array.
 // If a {bidder}.yaml file does not exist for some bidder, it will panic.
 func ParseBidderInfos(cfg map[string]config.Adapter, infoDir string, bidders []openrtb_ext.BidderName) BidderInfos {
+	return ParseBidderInfosFS(cfg, static.Dir(infoDir), bidders)
+}
+
+// ParseBidderInfosFS works like ParseBidderInfos, but reads the {bidder}.yaml files from the root of infos.
+// Use static.Embedded(static.BidderInfoDir) for the files which were compiled into the binary.
+func ParseBidderInfosFS(cfg map[string]config.Adapter, infos static.FS, bidders []openrtb_ext.BidderName) BidderInfos {
 	bidderInfos := make(map[string]BidderInfo, len(bidders))
 	for _, bidderName := range bidders {
 		bidderString := string(bidderName)
-		fileData, err := ioutil.ReadFile(infoDir + "/" + bidderString + ".yaml")
+		fileData, err := infos.ReadFile(bidderString + ".yaml")
 		if err != nil {
-			glog.Fatalf("error reading from file %s: %v", infoDir+"/"+bidderString+".yaml", err)
+			glog.Fatalf("error reading from file %v/%s.yaml: %v", infos, bidderString, err)
 		}
 
 		var parsedInfo BidderInfo
 		if err := yaml.Unmarshal(fileData, &parsedInfo); err != nil {
-			glog.Fatalf("error parsing yaml in file %s: %v", infoDir+"/"+bidderString+".yaml", err)
+			glog.Fatalf("error parsing yaml in file %v/%s.yaml: %v", infos, bidderString, err)
 		}
 
 		if isEnabledBidder(cfg, bidderString) {
@@ -161,6 +168,12 @@ func (infos BidderInfos) IsActive(bidder openrtb_ext.BidderName) bool {
 	return infos[string(bidder)].Status == StatusActive
 }
 
+// SupportsOpenRTB26 returns true if the bidder reads the OpenRTB 2.6 fields of its requests.
+func (infos BidderInfos) SupportsOpenRTB26(bidder openrtb_ext.BidderName) bool {
+	openRTB := infos[string(bidder)].OpenRTB
+	return openRTB != nil && openRTB.Version == "2.6"
+}
+
 func (infos BidderInfos) HasAppSupport(bidder openrtb_ext.BidderName) bool {
 	return infos[string(bidder)].Capabilities.App != nil
 }
@@ -196,9 +209,17 @@ type BidderInfo struct {
 	Status       BidderStatus      `yaml:"status" json:"status"`
 	Maintainer   *MaintainerInfo   `yaml:"maintainer" json:"maintainer"`
 	Capabilities *CapabilitiesInfo `yaml:"capabilities" json:"capabilities"`
+	OpenRTB      *OpenRTBInfo      `yaml:"openrtb" json:"openrtb,omitempty"`
 	AliasOf      string            `json:"aliasOf,omitempty"`
 }
 
+// OpenRTBInfo describes the OpenRTB requests which a Bidder's endpoint reads.
+type OpenRTBInfo struct {
+	// Version is "2.6" if the endpoint reads the OpenRTB 2.6 fields. Otherwise they're sent in
+	// their OpenRTB 2.5 ext locations.
+	Version string `yaml:"version" json:"version"`
+}
+
 type MaintainerInfo struct {
 	Email string `yaml:"email" json:"email"`
 }
@@ -250,4 +271,8 @@ func parseBidderInfo(info BidderInfo) parsedBidderInfo {
 
 type ExtraRequestInfo struct {
 	PbsEntryPoint pbsmetrics.RequestType
+
+	// StoredBidResponses holds the canned server responses for the imps which use imp.ext.prebid.storedbidresponse,
+	// by imp ID. The exchange passes them to MakeBids instead of calling the server, so Bidders can ignore them.
+	StoredBidResponses map[string]json.RawMessage
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/errortypes"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/static"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -155,3 +156,12 @@ func TestParsing(t *testing.T) {
 	assert.Equal(t, false, infos.SupportsWebMediaType(mockBidderName, openrtb_ext.BidTypeAudio))
 	assert.Equal(t, true, infos.SupportsWebMediaType(mockBidderName, openrtb_ext.BidTypeNative))
 }
+
+func TestParsingEmbedded(t *testing.T) {
+	cfg := blankAdapterConfig(openrtb_ext.BidderAppnexus)
+	embedded := adapters.ParseBidderInfosFS(cfg, static.Embedded(static.BidderInfoDir), openrtb_ext.BidderList())
+	onDisk := adapters.ParseBidderInfos(cfg, "../static/bidder-info", openrtb_ext.BidderList())
+
+	assert.Equal(t, onDisk, embedded)
+	assert.Equal(t, true, embedded.IsActive(openrtb_ext.BidderAppnexus))
+}
@@ -10,7 +10,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/PubMatic-OpenWrap/prebid-server/cache/dummycache"
+	"github.com/PubMatic-OpenWrap/prebid-server/cache/datacache"
 	"github.com/PubMatic-OpenWrap/prebid-server/pbs"
 	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
 
@@ -231,7 +231,7 @@ func TestLifestreetBasicResponse(t *testing.T) {
 	pc.SetCookieOnResponse(fakewriter, false, "", &config.HostCookie{Domain: ""}, 90*24*time.Hour)
 	req.Header.Add("Cookie", fakewriter.Header().Get("Set-Cookie"))
 
-	cacheClient, _ := dummycache.New()
+	cacheClient := datacache.NewDummy()
 	hcc := config.HostCookie{}
 	pbReq, err := pbs.ParsePBSRequest(req, &config.AuctionTimeouts{
 		Default: 2000,
@@ -6,6 +6,7 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/macros"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/gpp"
 	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
 )
 
@@ -47,6 +48,8 @@ func (s *Syncer) GetUsersyncInfo(privacyPolicies privacy.Policies) (*usersync.Us
 		GDPR:        privacyPolicies.GDPR.Signal,
 		GDPRConsent: privacyPolicies.GDPR.Consent,
 		USPrivacy:   privacyPolicies.CCPA.Value,
+		GPP:         privacyPolicies.GPP.Value,
+		GPPSID:      gpp.JoinSectionIDs(privacyPolicies.GPP.SectionIDs),
 	})
 	if err != nil {
 		return nil, err
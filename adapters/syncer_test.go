@@ -7,6 +7,7 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy"
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy/ccpa"
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy/gdpr"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/gpp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,9 +20,13 @@ func TestGetUsersyncInfo(t *testing.T) {
 		CCPA: ccpa.Policy{
 			Value: "C",
 		},
+		GPP: gpp.Policy{
+			Value:      "D",
+			SectionIDs: []gpp.SectionID{2, 6},
+		},
 	}
 
-	syncURL := "{{.GDPR}}{{.GDPRConsent}}{{.USPrivacy}}"
+	syncURL := "{{.GDPR}}{{.GDPRConsent}}{{.USPrivacy}}{{.GPP}}{{.GPPSID}}"
 	syncURLTemplate := template.Must(
 		template.New("sync-template").Parse(syncURL),
 	)
@@ -32,5 +37,5 @@ func TestGetUsersyncInfo(t *testing.T) {
 	syncInfo, err := syncer.GetUsersyncInfo(privacyPolicies)
 
 	assert.NoError(t, err)
-	assert.Equal(t, "ABC", syncInfo.URL)
+	assert.Equal(t, "ABCD2,6", syncInfo.URL)
 }
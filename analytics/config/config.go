@@ -1,18 +1,27 @@
 package config
 
 import (
+	"github.com/PubMatic-OpenWrap/openrtb"
 	"github.com/PubMatic-OpenWrap/prebid-server/analytics"
 	"github.com/PubMatic-OpenWrap/prebid-server/analytics/filesystem"
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/activity"
 	"github.com/golang/glog"
 )
 
 //Modules that need to be logged to need to be initialized here
 func NewPBSAnalytics(analytics *config.Analytics) analytics.PBSAnalyticsModule {
+	return NewPBSAnalyticsWithActivities(analytics, nil)
+}
+
+// NewPBSAnalyticsWithActivities returns the analytics modules, which only log the requests whose reportAnalytics
+// activity the controls allow. The file logger is the "file" analytics component. Nil controls allow everything.
+func NewPBSAnalyticsWithActivities(analytics *config.Analytics, activities *config.ActivityControls) analytics.PBSAnalyticsModule {
 	modules := make(enabledAnalytics, 0)
 	if len(analytics.File.Filename) > 0 {
 		if mod, err := filesystem.NewFileLogger(analytics.File.Filename); err == nil {
-			modules = append(modules, mod)
+			modules = append(modules, withActivities("file", mod, activities))
 		} else {
 			glog.Fatalf("Could not initialize FileLogger for file %v :%v", analytics.File.Filename, err)
 		}
@@ -47,8 +56,90 @@ func (ea enabledAnalytics) LogSetUIDObject(so *analytics.SetUIDObject) {
 	}
 }
 
+func (ea enabledAnalytics) LogOptOutObject(oo *analytics.OptOutObject) {
+	for _, module := range ea {
+		module.LogOptOutObject(oo)
+	}
+}
+
 func (ea enabledAnalytics) LogAmpObject(ao *analytics.AmpObject) {
 	for _, module := range ea {
 		module.LogAmpObject(ao)
 	}
 }
+
+// Flush flushes every module which buffers its logs.
+func (ea enabledAnalytics) Flush() {
+	for _, module := range ea {
+		if flusher, ok := module.(analytics.Flusher); ok {
+			flusher.Flush()
+		}
+	}
+}
+
+// withActivities wraps a module so that it only logs what the reportAnalytics activity controls allow.
+func withActivities(name string, module analytics.PBSAnalyticsModule, activities *config.ActivityControls) analytics.PBSAnalyticsModule {
+	if activities == nil {
+		return module
+	}
+	return &activityModule{
+		module:     module,
+		component:  activity.Component{Type: activity.ComponentAnalytics, Name: name},
+		activities: activities,
+	}
+}
+
+// activityModule checks the reportAnalytics activity before it passes an object to the module. The objects which
+// carry an OpenRTB request use that request's account and signals, and the others only match the host's rules
+// which don't need any signal.
+type activityModule struct {
+	module     analytics.PBSAnalyticsModule
+	component  activity.Component
+	activities *config.ActivityControls
+}
+
+func (m *activityModule) allow(req *openrtb.BidRequest) bool {
+	return activity.NewControls(m.activities, openrtb_ext.AccountID(req)).Allow(activity.ReportAnalytics, m.component, activity.ReadSignals(req))
+}
+
+func (m *activityModule) LogAuctionObject(ao *analytics.AuctionObject) {
+	if m.allow(ao.Request) {
+		m.module.LogAuctionObject(ao)
+	}
+}
+
+func (m *activityModule) LogVideoObject(vo *analytics.VideoObject) {
+	if m.allow(vo.Request) {
+		m.module.LogVideoObject(vo)
+	}
+}
+
+func (m *activityModule) LogCookieSyncObject(cso *analytics.CookieSyncObject) {
+	if m.allow(nil) {
+		m.module.LogCookieSyncObject(cso)
+	}
+}
+
+func (m *activityModule) LogSetUIDObject(so *analytics.SetUIDObject) {
+	if m.allow(nil) {
+		m.module.LogSetUIDObject(so)
+	}
+}
+
+func (m *activityModule) LogOptOutObject(oo *analytics.OptOutObject) {
+	if m.allow(nil) {
+		m.module.LogOptOutObject(oo)
+	}
+}
+
+func (m *activityModule) LogAmpObject(ao *analytics.AmpObject) {
+	if m.allow(ao.Request) {
+		m.module.LogAmpObject(ao)
+	}
+}
+
+func (m *activityModule) Flush() {
+	if flusher, ok := m.module.(analytics.Flusher); ok {
+		flusher.Flush()
+	}
+}
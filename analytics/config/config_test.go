@@ -50,6 +50,11 @@ func TestSampleModule(t *testing.T) {
 	if count != 5 {
 		t.Errorf("PBSAnalyticsModule failed at LogVideoObject")
 	}
+
+	am.LogOptOutObject(&analytics.OptOutObject{})
+	if count != 6 {
+		t.Errorf("PBSAnalyticsModule failed at LogOptOutObject")
+	}
 }
 
 type sampleModule struct {
@@ -66,6 +71,26 @@ func (m *sampleModule) LogSetUIDObject(so *analytics.SetUIDObject) { *m.count++
 
 func (m *sampleModule) LogAmpObject(ao *analytics.AmpObject) { *m.count++ }
 
+func (m *sampleModule) LogOptOutObject(oo *analytics.OptOutObject) { *m.count++ }
+
+type flushingModule struct {
+	sampleModule
+	flushed bool
+}
+
+func (m *flushingModule) Flush() { m.flushed = true }
+
+func TestFlush(t *testing.T) {
+	var count int
+	flushing := &flushingModule{sampleModule: sampleModule{&count}}
+	modules := enabledAnalytics{&sampleModule{&count}, flushing}
+
+	modules.Flush()
+	if !flushing.flushed {
+		t.Errorf("PBSAnalyticsModule failed to flush the modules which buffer their logs")
+	}
+}
+
 func initAnalytics(count *int) analytics.PBSAnalyticsModule {
 	modules := make(enabledAnalytics, 0)
 	modules = append(modules, &sampleModule{count})
@@ -89,3 +114,50 @@ func TestNewPBSAnalytics(t *testing.T) {
 		t.Fatalf("Failed to initialize analytics module")
 	}
 }
+
+func TestActivityControls(t *testing.T) {
+	deny := false
+	activities := &config.ActivityControls{
+		Accounts: map[string]config.Activities{
+			"1001": {ReportAnalytics: config.Activity{Rules: []config.ActivityRule{
+				{Allow: false, Condition: config.ActivityCondition{ComponentName: []string{"sample"}, Geo: []string{"USA.CA"}}},
+			}}},
+			"1002": {ReportAnalytics: config.Activity{Default: &deny}},
+		},
+	}
+
+	var count int
+	module := withActivities("sample", &sampleModule{&count}, activities)
+
+	module.LogAuctionObject(&analytics.AuctionObject{Request: &openrtb.BidRequest{
+		Site:   &openrtb.Site{Publisher: &openrtb.Publisher{ID: "1001"}},
+		Device: &openrtb.Device{Geo: &openrtb.Geo{Country: "USA", Region: "CA"}},
+	}})
+	if count != 0 {
+		t.Errorf("The account's rule should deny the auction object")
+	}
+
+	module.LogAmpObject(&analytics.AmpObject{Request: &openrtb.BidRequest{
+		Site:   &openrtb.Site{Publisher: &openrtb.Publisher{ID: "1001"}},
+		Device: &openrtb.Device{Geo: &openrtb.Geo{Country: "USA", Region: "NY"}},
+	}})
+	if count != 1 {
+		t.Errorf("The account's rule shouldn't match the AMP object")
+	}
+
+	module.LogVideoObject(&analytics.VideoObject{Request: &openrtb.BidRequest{
+		App: &openrtb.App{Publisher: &openrtb.Publisher{ID: "1002"}},
+	}})
+	if count != 1 {
+		t.Errorf("The account's default should deny the video object")
+	}
+
+	module.LogSetUIDObject(&analytics.SetUIDObject{})
+	if count != 2 {
+		t.Errorf("The host allows the objects without a request")
+	}
+
+	if _, ok := withActivities("sample", &sampleModule{&count}, nil).(*sampleModule); !ok {
+		t.Errorf("Nil activity controls shouldn't wrap the module")
+	}
+}
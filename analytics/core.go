@@ -11,7 +11,7 @@ import (
 
 	New modules can use the /analytics/endpoint_data_objects, extract the
 	information required and are responsible for handling all their logging activities inside LogAuctionObject, LogAmpObject
-	LogCookieSyncObject and LogSetUIDObject method implementations.
+	LogCookieSyncObject, LogSetUIDObject and LogOptOutObject method implementations.
 */
 
 type PBSAnalyticsModule interface {
@@ -20,6 +20,13 @@ type PBSAnalyticsModule interface {
 	LogCookieSyncObject(*CookieSyncObject)
 	LogSetUIDObject(*SetUIDObject)
 	LogAmpObject(*AmpObject)
+	LogOptOutObject(*OptOutObject)
+}
+
+// Flusher may be implemented by the analytics modules which buffer their logs. Flush gets called when
+// Prebid Server shuts down, and should only return once everything logged so far has been written.
+type Flusher interface {
+	Flush()
 }
 
 //Loggable object of a transaction at /openrtb2/auction endpoint
@@ -65,3 +72,13 @@ type CookieSyncObject struct {
 	Errors       []error
 	BidderStatus []*usersync.CookieSyncBidders
 }
+
+//Loggable object of a transaction at /optout
+type OptOutObject struct {
+	Status   int
+	Errors   []error
+	Verifier string
+	// OptOut is the user's preference after the request. Changed is false if the request was rejected, or didn't change it.
+	OptOut  bool
+	Changed bool
+}
@@ -17,6 +17,7 @@ const (
 	VIDEO       RequestType = "/openrtb2/video"
 	SETUID      RequestType = "/set_uid"
 	AMP         RequestType = "/openrtb2/amp"
+	OPTOUT      RequestType = "/optout"
 )
 
 //Module that can perform transactional logging
@@ -72,6 +73,20 @@ func (f *FileLogger) LogAmpObject(ao *analytics.AmpObject) {
 	f.Logger.Flush()
 }
 
+//Logs OptOutObject to file
+func (f *FileLogger) LogOptOutObject(oo *analytics.OptOutObject) {
+	//Code to parse the object and log in a way required
+	var b bytes.Buffer
+	b.WriteString(jsonifyOptOutObject(oo))
+	f.Logger.Debug(b.String())
+	f.Logger.Flush()
+}
+
+//Flushes the logs which are still buffered
+func (f *FileLogger) Flush() {
+	f.Logger.Flush()
+}
+
 //Method to initialize the analytic module
 func NewFileLogger(filename string) (analytics.PBSAnalyticsModule, error) {
 	options := glog.LogOptions{
@@ -176,3 +191,20 @@ func jsonifyAmpObject(ao *analytics.AmpObject) string {
 		return fmt.Sprintf("Transactional Logs Error: Amp object badly formed %v", err)
 	}
 }
+
+func jsonifyOptOutObject(oo *analytics.OptOutObject) string {
+	type alias analytics.OptOutObject
+	b, err := json.Marshal(&struct {
+		Type RequestType `json:"type"`
+		*alias
+	}{
+		Type:  OPTOUT,
+		alias: (*alias)(oo),
+	})
+
+	if err == nil {
+		return string(b)
+	} else {
+		return fmt.Sprintf("Transactional Logs Error: Opt out object badly formed %v", err)
+	}
+}
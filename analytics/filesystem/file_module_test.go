@@ -77,6 +77,7 @@ func TestFileLogger_LogObjects(t *testing.T) {
 		fl.LogAmpObject(&analytics.AmpObject{})
 		fl.LogSetUIDObject(&analytics.SetUIDObject{})
 		fl.LogCookieSyncObject(&analytics.CookieSyncObject{})
+		fl.LogOptOutObject(&analytics.OptOutObject{})
 	} else {
 		t.Fatalf("Couldn't initialize file logger: %v", err)
 	}
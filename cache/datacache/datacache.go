@@ -0,0 +1,220 @@
+package datacache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/cache"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/memory_store"
+	"github.com/golang/glog"
+	"gopkg.in/yaml.v2"
+)
+
+// fetchTimeout bounds each lookup, like the queries of the old Postgres cache.
+const fetchTimeout = 50 * time.Millisecond
+
+// LegacyPostgresQuery reads the accounts_account and s2sconfig_config tables of the old Postgres data cache.
+// It's a stored_requests.postgres.fetcher.query, so those tables can be read with the Stored Request backends
+// until the data has been moved.
+const LegacyPostgresQuery = "SELECT uuid::text, json_build_object('id', uuid::text, 'price_granularity', price_granularity)::text, 'request' AS type " +
+	"FROM accounts_account WHERE uuid::text IN %REQUEST_ID_LIST% " +
+	"UNION ALL " +
+	"SELECT uuid::text, config, 'imp' AS type FROM s2sconfig_config WHERE uuid::text IN %IMP_ID_LIST%"
+
+// Cache is the cache.Cache of the legacy /auction endpoint. It reads the accounts and the ad unit configs through
+// a stored_requests.Fetcher, so that they share the backends, caches and invalidation events of Stored Requests.
+// An account is kept as a Stored Request with the account's JSON, and an ad unit config as a Stored Imp.
+type Cache struct {
+	fetcher  stored_requests.Fetcher
+	store    stored_requests.Store
+	accounts *accountService
+	config   *configService
+}
+
+// New returns a Cache which reads from the fetcher. It can't be written to.
+func New(fetcher stored_requests.Fetcher) *Cache {
+	return newCache(fetcher, nil, false)
+}
+
+// NewDummy returns a Cache which accepts every account, and keeps its ad unit configs in memory.
+// Like the old dummycache, it ignores the IDs of the configs: Config().Get returns the last config set.
+func NewDummy() *Cache {
+	store := memory_store.NewStore()
+	c := newCache(store, store, true)
+	c.config.ignoreIDs = true
+	return c
+}
+
+// dummyConfigID is the ID which NewDummy keeps its only ad unit config under.
+const dummyConfigID = "dummy"
+
+// NewFromYAML loads the file of the old filecache into memory. The file lists the accounts and ad unit configs:
+//
+//	accounts:
+//	  - account1
+//	configs:
+//	  - id: config1
+//	    config: '[{"bidder":"appnexus","params":{"placementId":1}}]'
+func NewFromYAML(filename string) (*Cache, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var file yamlFile
+	if err = yaml.Unmarshal(b, &file); err != nil {
+		return nil, err
+	}
+
+	store := memory_store.NewStore()
+	if err = file.saveTo(context.Background(), store); err != nil {
+		return nil, err
+	}
+	glog.Infof("Loaded %d accounts and %d configs", len(file.Accounts), len(file.Configs))
+
+	return newCache(store, nil, false), nil
+}
+
+type yamlFile struct {
+	Configs []struct {
+		ID     string `yaml:"id"`
+		Config string `yaml:"config"`
+	} `yaml:"configs"`
+	Accounts []string `yaml:"accounts"`
+}
+
+// saveTo saves the accounts as Stored Requests, and the ad unit configs as Stored Imps.
+func (file *yamlFile) saveTo(ctx context.Context, store stored_requests.Store) error {
+	for _, account := range file.Accounts {
+		data, err := json.Marshal(cache.Account{ID: account})
+		if err != nil {
+			return err
+		}
+		if _, err := store.Save(ctx, stored_requests.RequestDataType, account, data); err != nil {
+			return fmt.Errorf("Failed to save account %s: %v", account, err)
+		}
+	}
+	for _, config := range file.Configs {
+		if _, err := store.Save(ctx, stored_requests.ImpDataType, config.ID, json.RawMessage(config.Config)); err != nil {
+			return fmt.Errorf("Failed to save config %s: %v", config.ID, err)
+		}
+	}
+	return nil
+}
+
+func newCache(fetcher stored_requests.Fetcher, store stored_requests.Store, acceptAllAccounts bool) *Cache {
+	c := &Cache{
+		fetcher: fetcher,
+		store:   store,
+	}
+	c.accounts = &accountService{c, acceptAllAccounts}
+	c.config = &configService{cache: c}
+	return c
+}
+
+// Close does nothing. The backends are closed with the Stored Requests.
+func (c *Cache) Close() error {
+	return nil
+}
+
+func (c *Cache) Accounts() cache.AccountsService {
+	return c.accounts
+}
+
+func (c *Cache) Config() cache.ConfigService {
+	return c.config
+}
+
+func (c *Cache) save(dataType stored_requests.DataType, id string, data json.RawMessage) error {
+	if c.store == nil {
+		return fmt.Errorf("Not supported")
+	}
+	_, err := c.store.Save(context.Background(), dataType, id, data)
+	return err
+}
+
+type accountService struct {
+	cache *Cache
+	// acceptAllAccounts makes Get return every account which isn't stored as if it were.
+	acceptAllAccounts bool
+}
+
+// Get returns the account, or an error if it doesn't exist.
+func (s *accountService) Get(id string) (*cache.Account, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	data, _, errs := s.cache.fetcher.FetchRequests(ctx, []string{id}, nil)
+
+	raw, ok := data[id]
+	if !ok {
+		if s.acceptAllAccounts {
+			return &cache.Account{ID: id}, nil
+		}
+		return nil, notFound(id, stored_requests.RequestDataType, errs)
+	}
+
+	var account cache.Account
+	if err := json.Unmarshal(raw, &account); err != nil {
+		return nil, fmt.Errorf("Account %s is malformed: %v", id, err)
+	}
+	if account.ID == "" {
+		account.ID = id
+	}
+	return &account, nil
+}
+
+// Set saves the account, if the Cache has a store.
+func (s *accountService) Set(account *cache.Account) error {
+	data, err := json.Marshal(account)
+	if err != nil {
+		return err
+	}
+	return s.cache.save(stored_requests.RequestDataType, account.ID, data)
+}
+
+type configService struct {
+	cache *Cache
+	// ignoreIDs keeps every config under dummyConfigID.
+	ignoreIDs bool
+}
+
+// Get returns the ad unit config, or an error if it doesn't exist.
+func (s *configService) Get(id string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	storedID := s.storedID(id)
+	_, data, errs := s.cache.fetcher.FetchRequests(ctx, nil, []string{storedID})
+
+	if raw, ok := data[storedID]; ok {
+		return string(raw), nil
+	}
+	if storedID != id {
+		// The fetcher's errors would name the dummy ID.
+		errs = nil
+	}
+	return "", notFound(id, stored_requests.ImpDataType, errs)
+}
+
+// Set saves the ad unit config, if the Cache has a store.
+func (s *configService) Set(id, value string) error {
+	return s.cache.save(stored_requests.ImpDataType, s.storedID(id), json.RawMessage(value))
+}
+
+func (s *configService) storedID(id string) string {
+	if s.ignoreIDs {
+		return dummyConfigID
+	}
+	return id
+}
+
+// notFound returns the fetcher's error, or a NotFoundError if it didn't give one.
+func notFound(id string, dataType stored_requests.DataType, errs []error) error {
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return stored_requests.NotFoundError{ID: id, DataType: string(dataType)}
+}
@@ -0,0 +1,136 @@
+package datacache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/cache"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/memory_store"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/caches/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	store := memory_store.NewStore()
+	store.Save(context.Background(), stored_requests.RequestDataType, "account1", json.RawMessage(`{"price_granularity":"high"}`))
+	store.Save(context.Background(), stored_requests.ImpDataType, "config1", json.RawMessage(`[{"bidder":"appnexus"}]`))
+	c := New(store)
+
+	account, err := c.Accounts().Get("account1")
+	assert.NoError(t, err)
+	assert.Equal(t, &cache.Account{ID: "account1", PriceGranularity: "high"}, account)
+
+	_, err = c.Accounts().Get("unknown")
+	assert.EqualError(t, err, `Stored Request with ID="unknown" not found.`)
+
+	adUnitConfig, err := c.Config().Get("config1")
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"bidder":"appnexus"}]`, adUnitConfig)
+
+	_, err = c.Config().Get("unknown")
+	assert.EqualError(t, err, `Stored Imp with ID="unknown" not found.`)
+
+	assert.EqualError(t, c.Config().Set("config2", "[]"), "Not supported", "A Cache over a Fetcher can't be written to")
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	store := memory_store.NewStore()
+	store.Save(context.Background(), stored_requests.ImpDataType, "config1", json.RawMessage(`"old"`))
+	memoryCache := memory.NewCache(&config.InMemoryCache{Type: "unbounded"})
+	c := New(stored_requests.WithCache(store, memoryCache, &metricsConf.DummyMetricsEngine{}))
+
+	adUnitConfig, _ := c.Config().Get("config1")
+	assert.Equal(t, `"old"`, adUnitConfig)
+
+	store.Save(context.Background(), stored_requests.ImpDataType, "config1", json.RawMessage(`"new"`))
+	adUnitConfig, _ = c.Config().Get("config1")
+	assert.Equal(t, `"old"`, adUnitConfig, "The config should come from the cache")
+
+	memoryCache.Invalidate(context.Background(), nil, []string{"config1"})
+	adUnitConfig, _ = c.Config().Get("config1")
+	assert.Equal(t, `"new"`, adUnitConfig, "An invalidation event should reach the configs")
+}
+
+func TestDummy(t *testing.T) {
+	c := NewDummy()
+
+	account, err := c.Accounts().Get("account1")
+	assert.NoError(t, err)
+	assert.Equal(t, "account1", account.ID, "The dummy cache should accept every account")
+
+	_, err = c.Config().Get("config")
+	assert.Equal(t, stored_requests.NotFoundError{ID: "config", DataType: "Imp"}, err)
+
+	assert.NoError(t, c.Config().Set("config", "abc123"))
+	adUnitConfig, err := c.Config().Get("config")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", adUnitConfig)
+	adUnitConfig, err = c.Config().Get("other")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", adUnitConfig, "The dummy cache should ignore the IDs of the configs")
+
+	assert.NoError(t, c.Accounts().Set(&cache.Account{ID: "account2", PriceGranularity: "low"}))
+	account, _ = c.Accounts().Get("account2")
+	assert.Equal(t, "low", account.PriceGranularity)
+}
+
+func TestNewFromYAML(t *testing.T) {
+	file, err := ioutil.TempFile("", "filecache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`
+accounts:
+  - account1
+  - account2
+configs:
+  - id: one
+    config: config1
+  - id: two
+    config: config2
+`)
+	file.Close()
+
+	c, err := NewFromYAML(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account, err := c.Accounts().Get("account2")
+	assert.NoError(t, err)
+	assert.Equal(t, "account2", account.ID)
+	_, err = c.Accounts().Get("account3")
+	assert.Error(t, err)
+
+	adUnitConfig, err := c.Config().Get("two")
+	assert.NoError(t, err)
+	assert.Equal(t, "config2", adUnitConfig)
+	_, err = c.Config().Get("three")
+	assert.Error(t, err)
+
+	_, err = NewFromYAML(file.Name() + "-missing")
+	assert.Error(t, err)
+}
+
+func TestSaveYAMLFileErrors(t *testing.T) {
+	file := &yamlFile{Accounts: []string{"account1"}}
+	err := file.saveTo(context.Background(), failingStore{})
+	assert.EqualError(t, err, "Failed to save account account1: the store is down")
+}
+
+// failingStore is a stored_requests.Store which can't save anything.
+type failingStore struct {
+	stored_requests.Store
+}
+
+func (failingStore) Save(ctx context.Context, dataType stored_requests.DataType, id string, data json.RawMessage) (stored_requests.Version, error) {
+	return stored_requests.Version{}, errors.New("the store is down")
+}
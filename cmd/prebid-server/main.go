@@ -0,0 +1,28 @@
+// Command prebid-server runs Prebid Server as its own server.
+package main
+
+import (
+	"flag"
+
+	prebidServer "github.com/PubMatic-OpenWrap/prebid-server"
+
+	"github.com/golang/glog"
+)
+
+// Rev holds binary revision string
+// Set manually at build time using:
+//
+//	go build -ldflags "-X main.Rev=`git rev-parse --short HEAD`" ./cmd/prebid-server
+//
+// See issue #559
+var Rev string
+
+func main() {
+	configFile := flag.String("config", "pbs.yaml", "The config file to load. The defaults and the PBS_ environment variables apply if it doesn't exist.")
+	flag.Parse() // required for glog flags and testing package flags
+
+	prebidServer.Rev = Rev
+	if err := prebidServer.ServeStandalone(*configFile); err != nil {
+		glog.Fatalf("prebid-server failed: %v", err)
+	}
+}
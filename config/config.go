@@ -2,6 +2,7 @@ package config
 
 import (
 	"bytes"
+	"crypto/tls"
 	"fmt"
 	"net/url"
 	"reflect"
@@ -33,23 +34,36 @@ type Configuration struct {
 	ExtCacheURL     ExternalCache      `mapstructure:"external_cache"`
 	RecaptchaSecret string             `mapstructure:"recaptcha_secret"`
 	HostCookie      HostCookie         `mapstructure:"host_cookie"`
+	SetUID          SetUID             `mapstructure:"setuid"`
+	SyncPage        SyncPage           `mapstructure:"sync_page"`
+	OptOut          OptOut             `mapstructure:"optout"`
 	Metrics         Metrics            `mapstructure:"metrics"`
 	DataCache       DataCache          `mapstructure:"datacache"`
 	StoredRequests  StoredRequests     `mapstructure:"stored_requests"`
 	CategoryMapping StoredRequestsSlim `mapstructure:"category_mapping"`
 	// Note that StoredVideo refers to stored video requests, and has nothing to do with caching video creatives.
 	StoredVideo StoredRequestsSlim `mapstructure:"stored_video_req"`
+	// StoredRequestsAdmin configures the API which manages the data of the three endpoints above.
+	StoredRequestsAdmin StoredRequestsAdmin `mapstructure:"stored_requests_admin"`
 
 	// Adapters should have a key for every openrtb_ext.BidderName, converted to lower-case.
 	// Se also: https://github.com/spf13/viper/issues/371#issuecomment-335388559
-	Adapters             map[string]Adapter `mapstructure:"adapters"`
-	MaxRequestSize       int64              `mapstructure:"max_request_size"`
-	Analytics            Analytics          `mapstructure:"analytics"`
-	AMPTimeoutAdjustment int64              `mapstructure:"amp_timeout_adjustment_ms"`
-	GDPR                 GDPR               `mapstructure:"gdpr"`
-	CCPA                 CCPA               `mapstructure:"ccpa"`
-	CurrencyConverter    CurrencyConverter  `mapstructure:"currency_converter"`
-	DefReqConfig         DefReqConfig       `mapstructure:"default_request"`
+	Adapters map[string]Adapter `mapstructure:"adapters"`
+	// BidderAliases are the aliases which every request can use without defining them in request.ext.prebid.aliases.
+	BidderAliases        map[string]BidderAlias `mapstructure:"bidder_aliases"`
+	MaxRequestSize       int64                  `mapstructure:"max_request_size"`
+	Analytics            Analytics              `mapstructure:"analytics"`
+	AMPTimeoutAdjustment int64                  `mapstructure:"amp_timeout_adjustment_ms"`
+	GDPR                 GDPR                   `mapstructure:"gdpr"`
+	CCPA                 CCPA                   `mapstructure:"ccpa"`
+	CurrencyConverter    CurrencyConverter      `mapstructure:"currency_converter"`
+	DefReqConfig         DefReqConfig           `mapstructure:"default_request"`
+	BidBlocking          BidBlocking            `mapstructure:"bid_blocking"`
+	CreativeValidation   CreativeValidation     `mapstructure:"creative_validation"`
+	ActivityControls     ActivityControls       `mapstructure:"activity_controls"`
+	GeoLocation          GeoLocation            `mapstructure:"geolocation"`
+	StaticAssets         StaticAssets           `mapstructure:"static_assets"`
+	ConfigReload         ConfigReload           `mapstructure:"config_reload"`
 
 	VideoStoredRequestRequired bool `mapstructure:"video_stored_request_required"`
 
@@ -95,13 +109,24 @@ func (cfg *Configuration) validate() configErrors {
 	var errs configErrors
 	errs = cfg.AuctionTimeouts.validate(errs)
 	errs = cfg.StoredRequests.validate(errs)
+	errs = cfg.StoredRequestsAdmin.validate(errs)
 	errs = cfg.Metrics.validate(errs)
 	if cfg.MaxRequestSize < 0 {
 		errs = append(errs, fmt.Errorf("cfg.max_request_size must be >= 0. Got %d", cfg.MaxRequestSize))
 	}
 	errs = cfg.GDPR.validate(errs)
 	errs = cfg.CurrencyConverter.validate(errs)
+	errs = cfg.BidBlocking.validate(errs)
+	errs = cfg.CreativeValidation.validate(errs)
+	errs = cfg.ActivityControls.validate(errs)
+	errs = cfg.GeoLocation.validate(errs)
+	errs = cfg.ConfigReload.validate(errs)
+	errs = cfg.SetUID.validate(errs)
+	errs = cfg.SyncPage.validate(errs)
+	errs = cfg.OptOut.validate(errs)
+	errs = cfg.HostCookie.Security.validate(errs)
 	errs = validateAdapters(cfg.Adapters, errs)
+	errs = validateBidderAliases(cfg.BidderAliases, errs)
 	return errs
 }
 
@@ -140,6 +165,10 @@ type GDPR struct {
 	Timeouts                GDPRTimeouts `mapstructure:"timeouts_ms"`
 	NonStandardPublishers   []string     `mapstructure:"non_standard_publishers,flow"`
 	NonStandardPublisherMap map[string]int
+	// EEACountries are the ISO-3166-1 alpha-3 codes of the countries where GDPR applies. A request which doesn't
+	// say whether GDPR applies is decided by the country of its device.geo, if known.
+	EEACountries    []string `mapstructure:"eea_countries,flow"`
+	EEACountriesMap map[string]struct{}
 }
 
 func (cfg *GDPR) validate(errs configErrors) configErrors {
@@ -166,6 +195,251 @@ type CCPA struct {
 	Enforce bool `mapstructure:"enforce"`
 }
 
+// BidBlocking configures the exchange to enforce the bcat, badv and battr block lists on the bids returned by the bidders.
+type BidBlocking struct {
+	// Enforce removes any bid whose adomain, cat or attr is blocked by the request or by the account.
+	Enforce bool `mapstructure:"enforce"`
+	// RequireAdomain removes any bid which doesn't declare an adomain. It only applies if Enforce is true.
+	RequireAdomain bool `mapstructure:"require_adomain"`
+	// Accounts holds the block lists which apply to every request of an account, on top of the request's own lists.
+	// The map is keyed by account ID.
+	Accounts map[string]BlockLists `mapstructure:"accounts"`
+}
+
+// BlockLists mirror the OpenRTB bcat, badv and battr fields.
+type BlockLists struct {
+	BCat  []string `mapstructure:"bcat,flow"`
+	BAdv  []string `mapstructure:"badv,flow"`
+	BAttr []int    `mapstructure:"battr,flow"`
+}
+
+func (cfg *BidBlocking) validate(errs configErrors) configErrors {
+	for account, lists := range cfg.Accounts {
+		for _, attr := range lists.BAttr {
+			if attr < 1 || attr > 17 {
+				errs = append(errs, fmt.Errorf("bid_blocking.accounts.%s.battr must only contain values in the range [1, 17]. Got %d", account, attr))
+			}
+		}
+	}
+	return errs
+}
+
+// ValidationMode sets what the exchange does with a bid which fails a creative validation check.
+type ValidationMode string
+
+const (
+	// ValidationSkip doesn't run the check at all. An empty mode behaves the same way.
+	ValidationSkip ValidationMode = "skip"
+	// ValidationWarn runs the check and counts the failures in the metrics, but keeps the bid.
+	ValidationWarn ValidationMode = "warn"
+	// ValidationEnforce runs the check and removes the bids which fail it.
+	ValidationEnforce ValidationMode = "enforce"
+)
+
+// CreativeValidation configures the checks which the exchange runs on the creatives returned by the bidders.
+type CreativeValidation struct {
+	CreativeValidationModes `mapstructure:",squash"`
+	// Accounts overrides the host modes for the requests of an account. The map is keyed by account ID,
+	// and any mode which an account leaves empty falls back to the host's.
+	Accounts map[string]CreativeValidationModes `mapstructure:"accounts"`
+}
+
+// CreativeValidationModes holds the mode of each creative validation check.
+type CreativeValidationModes struct {
+	// SecureMarkup checks that the adm doesn't load any http:// resource if the imp requires secure creatives.
+	SecureMarkup ValidationMode `mapstructure:"secure_markup"`
+	// BannerSize checks that the w and h of a banner bid match one of the sizes requested by the imp.
+	BannerSize ValidationMode `mapstructure:"banner_size"`
+	// VastXML checks that the adm of a video bid is well-formed XML.
+	VastXML ValidationMode `mapstructure:"vast_xml"`
+}
+
+// ForAccount returns the modes which apply to the requests of the given account.
+func (cfg *CreativeValidation) ForAccount(account string) CreativeValidationModes {
+	modes := cfg.CreativeValidationModes
+	if accountModes, ok := cfg.Accounts[account]; ok {
+		if accountModes.SecureMarkup != "" {
+			modes.SecureMarkup = accountModes.SecureMarkup
+		}
+		if accountModes.BannerSize != "" {
+			modes.BannerSize = accountModes.BannerSize
+		}
+		if accountModes.VastXML != "" {
+			modes.VastXML = accountModes.VastXML
+		}
+	}
+	return modes
+}
+
+func (cfg *CreativeValidation) validate(errs configErrors) configErrors {
+	errs = cfg.CreativeValidationModes.validate("creative_validation", errs)
+	for account, modes := range cfg.Accounts {
+		errs = modes.validate("creative_validation.accounts."+account, errs)
+	}
+	return errs
+}
+
+func (modes *CreativeValidationModes) validate(prefix string, errs configErrors) configErrors {
+	errs = validateMode(prefix+".secure_markup", modes.SecureMarkup, errs)
+	errs = validateMode(prefix+".banner_size", modes.BannerSize, errs)
+	errs = validateMode(prefix+".vast_xml", modes.VastXML, errs)
+	return errs
+}
+
+// validateMode accepts an empty mode, which behaves like ValidationSkip.
+func validateMode(field string, mode ValidationMode, errs configErrors) configErrors {
+	switch mode {
+	case "", ValidationSkip, ValidationWarn, ValidationEnforce:
+		return errs
+	}
+	return append(errs, fmt.Errorf("%s must be one of \"skip\", \"warn\" or \"enforce\". Got \"%s\"", field, mode))
+}
+
+// GeoLocation configures the lookup of the device's location from its IP address. The exchange fills
+// device.geo with the location when the request doesn't have a country.
+type GeoLocation struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Type is the kind of database. Only "maxmind" is supported, which reads a MaxMind DB file such as GeoIP2 City.
+	Type     string `mapstructure:"type"`
+	Database string `mapstructure:"database"`
+}
+
+func (cfg *GeoLocation) validate(errs configErrors) configErrors {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Type != "maxmind" {
+		errs = append(errs, fmt.Errorf("geolocation.type must be \"maxmind\". Got \"%s\"", cfg.Type))
+	}
+	if cfg.Database == "" {
+		errs = append(errs, fmt.Errorf("geolocation.database is required when geolocation.enabled is true"))
+	}
+	return errs
+}
+
+// ActivityControls configures which components may do each privacy sensitive activity, such as syncing a user
+// or receiving their first party data. Activities without any rules are allowed.
+type ActivityControls struct {
+	Activities `mapstructure:",squash"`
+	// Accounts overrides the host's controls for the requests of an account. The map is keyed by account ID,
+	// and any activity which an account leaves without rules or a default falls back to the host's.
+	Accounts map[string]Activities `mapstructure:"accounts"`
+}
+
+// Activities holds the controls of each activity.
+type Activities struct {
+	// SyncUser decides which bidders may sync the user in /cookie_sync, /sync and /setuid.
+	SyncUser Activity `mapstructure:"sync_user"`
+	// TransmitUFPD decides which bidders get the user IDs, demographics, data segments and eids of a request.
+	TransmitUFPD Activity `mapstructure:"transmit_ufpd"`
+	// TransmitPreciseGeo decides which bidders get the exact lat/lon and IP address of a request.
+	TransmitPreciseGeo Activity `mapstructure:"transmit_precise_geo"`
+	// FetchBids decides which bidders are called in an auction.
+	FetchBids Activity `mapstructure:"fetch_bids"`
+	// ReportAnalytics decides which analytics modules are sent the objects of each endpoint.
+	ReportAnalytics Activity `mapstructure:"report_analytics"`
+}
+
+// Activity is a list of rules. The first rule whose condition matches decides whether the activity is allowed,
+// and Default decides if none match. An activity with no Default is allowed if no rule matches.
+type Activity struct {
+	Default *bool          `mapstructure:"default"`
+	Rules   []ActivityRule `mapstructure:"rules"`
+}
+
+// IsSet returns true if the activity has any rules or a default.
+func (a Activity) IsSet() bool {
+	return a.Default != nil || len(a.Rules) > 0
+}
+
+// ActivityRule allows or denies an activity for the requests which match its condition.
+type ActivityRule struct {
+	Allow     bool              `mapstructure:"allow"`
+	Condition ActivityCondition `mapstructure:"condition"`
+}
+
+// ActivityCondition matches a component and the privacy signals of a request. Every field which is set must match,
+// and a list matches if any of its values do. An empty condition matches everything.
+type ActivityCondition struct {
+	// ComponentType holds "bidder" or "analytics".
+	ComponentType []string `mapstructure:"component_type,flow"`
+	// ComponentName holds bidder codes or analytics module names.
+	ComponentName []string `mapstructure:"component_name,flow"`
+	// GPPSID matches the requests whose regs.ext.gpp_sid has any of the section IDs.
+	GPPSID []int8 `mapstructure:"gpp_sid,flow"`
+	// Geo holds "{country}" or "{country}.{region}" values, using the ISO-3166-1-alpha-3 country codes and the
+	// ISO-3166-2 region codes of device.geo.
+	Geo []string `mapstructure:"geo,flow"`
+	// GDPR matches the requests whose regs.ext.gdpr is 1 if true, and the other requests if false.
+	GDPR *bool `mapstructure:"gdpr"`
+}
+
+// ForAccount returns the activities which apply to the requests of the given account.
+func (cfg *ActivityControls) ForAccount(account string) Activities {
+	activities := cfg.Activities
+	if accountActivities, ok := cfg.Accounts[account]; ok {
+		if accountActivities.SyncUser.IsSet() {
+			activities.SyncUser = accountActivities.SyncUser
+		}
+		if accountActivities.TransmitUFPD.IsSet() {
+			activities.TransmitUFPD = accountActivities.TransmitUFPD
+		}
+		if accountActivities.TransmitPreciseGeo.IsSet() {
+			activities.TransmitPreciseGeo = accountActivities.TransmitPreciseGeo
+		}
+		if accountActivities.FetchBids.IsSet() {
+			activities.FetchBids = accountActivities.FetchBids
+		}
+		if accountActivities.ReportAnalytics.IsSet() {
+			activities.ReportAnalytics = accountActivities.ReportAnalytics
+		}
+	}
+	return activities
+}
+
+func (cfg *ActivityControls) validate(errs configErrors) configErrors {
+	errs = cfg.Activities.validate("activity_controls", errs)
+	for account, activities := range cfg.Accounts {
+		errs = activities.validate("activity_controls.accounts."+account, errs)
+	}
+	return errs
+}
+
+func (activities *Activities) validate(prefix string, errs configErrors) configErrors {
+	errs = activities.SyncUser.validate(prefix+".sync_user", errs)
+	errs = activities.TransmitUFPD.validate(prefix+".transmit_ufpd", errs)
+	errs = activities.TransmitPreciseGeo.validate(prefix+".transmit_precise_geo", errs)
+	errs = activities.FetchBids.validate(prefix+".fetch_bids", errs)
+	errs = activities.ReportAnalytics.validate(prefix+".report_analytics", errs)
+	return errs
+}
+
+func (activity *Activity) validate(prefix string, errs configErrors) configErrors {
+	for i, rule := range activity.Rules {
+		for _, componentType := range rule.Condition.ComponentType {
+			if componentType != "bidder" && componentType != "analytics" {
+				errs = append(errs, fmt.Errorf("%s.rules[%d].condition.component_type must only contain \"bidder\" or \"analytics\". Got \"%s\"", prefix, i, componentType))
+			}
+		}
+		for _, geo := range rule.Condition.Geo {
+			if parts := strings.Split(geo, "."); len(parts) > 2 || parts[0] == "" {
+				errs = append(errs, fmt.Errorf("%s.rules[%d].condition.geo must only contain \"{country}\" or \"{country}.{region}\" values. Got \"%s\"", prefix, i, geo))
+			}
+		}
+	}
+	return errs
+}
+
+// StaticAssets configures where the bidder params JSON schemas and the bidder info files are read from.
+// An empty directory means that the copy embedded into the binary is used.
+// The category mapping files are configured by category_mapping.filesystem.directorypath, which works the same way.
+type StaticAssets struct {
+	// BidderParamsDirectory holds a {bidder}.json schema for every bidder.
+	BidderParamsDirectory string `mapstructure:"bidder_params_directory"`
+	// BidderInfoDirectory holds a {bidder}.yaml file for every bidder.
+	BidderInfoDirectory string `mapstructure:"bidder_info_directory"`
+}
+
 type Analytics struct {
 	File FileLogs `mapstructure:"file"`
 }
@@ -197,18 +471,157 @@ type HostCookie struct {
 	OptOutCookie       Cookie `mapstructure:"optout_cookie"`
 	// Cookie timeout in days
 	TTL int64 `mapstructure:"ttl_days"`
+	// Security signs, and optionally encrypts, the uids cookie.
+	Security CookieSecurity `mapstructure:"security"`
+}
+
+// CookieSecurity protects the uids cookie, so that nobody but the host can forge or change the IDs and the opt-out in it.
+// The cookie is left unsigned if there are no Keys.
+type CookieSecurity struct {
+	// Keys sign the cookie. The first one signs the cookies which Prebid Server writes, and all of them are used to
+	// check the cookies which it reads. A key can be rotated by adding the new one at the front, and removing the old
+	// one once the cookies it signed have expired.
+	Keys []CookieKey `mapstructure:"keys"`
+	// Encrypt hides the cookie's content with AES-GCM, on top of signing it.
+	Encrypt bool `mapstructure:"encrypt"`
+	// AcceptUnsigned keeps reading the unsigned cookies which users got before the Keys were added.
+	// Turn it off once they've expired, or anyone can still forge an unsigned cookie.
+	AcceptUnsigned bool `mapstructure:"accept_unsigned"`
+}
+
+// CookieKey is a secret which signs the uids cookie. The ID is written into the cookie, so it mustn't be secret.
+type CookieKey struct {
+	ID     string `mapstructure:"id"`
+	Secret string `mapstructure:"secret"`
+}
+
+func (cfg *CookieSecurity) validate(errs configErrors) configErrors {
+	ids := make(map[string]struct{}, len(cfg.Keys))
+	for i, key := range cfg.Keys {
+		if key.ID == "" || strings.Contains(key.ID, ".") {
+			errs = append(errs, fmt.Errorf("host_cookie.security.keys[%d].id must be non-empty and can't contain a \".\". Got %q", i, key.ID))
+		} else if _, ok := ids[key.ID]; ok {
+			errs = append(errs, fmt.Errorf("host_cookie.security.keys[%d].id %q is used by more than one key", i, key.ID))
+		}
+		ids[key.ID] = struct{}{}
+		if len(key.Secret) < 16 {
+			errs = append(errs, fmt.Errorf("host_cookie.security.keys[%d].secret must have at least 16 characters", i))
+		}
+	}
+	if cfg.Encrypt && len(cfg.Keys) == 0 {
+		errs = append(errs, fmt.Errorf("host_cookie.security.keys must be set if host_cookie.security.encrypt=true"))
+	}
+	return errs
 }
 
 func (cfg *HostCookie) TTLDuration() time.Duration {
 	return time.Duration(cfg.TTL) * time.Hour * 24
 }
 
+// SetUID configures the /setuid endpoint.
+type SetUID struct {
+	// RedirectSecret is the HMAC key which signs the redirect query param, so that /setuid can pass the user on to
+	// the next sync URL of a chain. If it's empty, requests with a redirect are rejected.
+	RedirectSecret string `mapstructure:"redirect_secret"`
+	// RedirectMaxAgeSeconds is how long a redirect signature is accepted for, after the host made it.
+	RedirectMaxAgeSeconds int `mapstructure:"redirect_max_age_seconds"`
+	// OverwriteIntervalSeconds is the least time between two changes to a bidder's uid in the same cookie.
+	// The changes which come sooner are rejected. Use 0 for no limit.
+	OverwriteIntervalSeconds int `mapstructure:"overwrite_interval_seconds"`
+	// BidderOverwriteIntervalSeconds overrides OverwriteIntervalSeconds for some cookie families.
+	BidderOverwriteIntervalSeconds map[string]int `mapstructure:"bidder_overwrite_interval_seconds"`
+}
+
+func (cfg *SetUID) validate(errs configErrors) configErrors {
+	if cfg.RedirectSecret != "" && cfg.RedirectMaxAgeSeconds <= 0 {
+		errs = append(errs, fmt.Errorf("setuid.redirect_max_age_seconds must be > 0 when setuid.redirect_secret is set. Got %d", cfg.RedirectMaxAgeSeconds))
+	}
+	if cfg.OverwriteIntervalSeconds < 0 {
+		errs = append(errs, fmt.Errorf("setuid.overwrite_interval_seconds must be >= 0. Got %d", cfg.OverwriteIntervalSeconds))
+	}
+	for family, interval := range cfg.BidderOverwriteIntervalSeconds {
+		if interval < 0 {
+			errs = append(errs, fmt.Errorf("setuid.bidder_overwrite_interval_seconds.%s must be >= 0. Got %d", family, interval))
+		}
+	}
+	return errs
+}
+
+// RedirectMaxAge returns how long a redirect signature is accepted for.
+func (cfg *SetUID) RedirectMaxAge() time.Duration {
+	return time.Duration(cfg.RedirectMaxAgeSeconds) * time.Second
+}
+
+// OverwriteInterval returns the least time between two changes to the uid of the cookie family.
+func (cfg *SetUID) OverwriteInterval(family string) time.Duration {
+	if interval, ok := cfg.BidderOverwriteIntervalSeconds[family]; ok {
+		return time.Duration(interval) * time.Second
+	}
+	return time.Duration(cfg.OverwriteIntervalSeconds) * time.Second
+}
+
+// SyncPage configures the /sync page, which runs the user syncs of many bidders from a single iframe.
+type SyncPage struct {
+	// MaxConcurrentSyncs is the number of syncs which the page runs at once. Use 0 to run them all at once.
+	MaxConcurrentSyncs int `mapstructure:"max_concurrent_syncs"`
+	// TimeoutMillis is how long the page waits for a sync before it gives up on it and starts the next one.
+	// Use 0 for no timeout.
+	TimeoutMillis int `mapstructure:"timeout_ms"`
+}
+
+func (cfg *SyncPage) validate(errs configErrors) configErrors {
+	if cfg.MaxConcurrentSyncs < 0 {
+		errs = append(errs, fmt.Errorf("sync_page.max_concurrent_syncs must be >= 0. Got %d", cfg.MaxConcurrentSyncs))
+	}
+	if cfg.TimeoutMillis < 0 {
+		errs = append(errs, fmt.Errorf("sync_page.timeout_ms must be >= 0. Got %d", cfg.TimeoutMillis))
+	}
+	return errs
+}
+
+// The values of optout.verifier.
+const (
+	OptOutVerifierRecaptcha = "recaptcha"
+	OptOutVerifierToken     = "token"
+	OptOutVerifierNone      = "none"
+)
+
+// OptOut configures the /optout endpoint.
+type OptOut struct {
+	// Verifier picks how /optout checks that the user, and not a script or another site, changed their preference.
+	// "recaptcha" verifies a Google reCAPTCHA response with recaptcha_secret, "token" requires a signed token which
+	// /optout hands out to the user's browser, and "none" doesn't check anything. It defaults to "recaptcha".
+	Verifier string `mapstructure:"verifier"`
+	// TokenSecret is the HMAC key which signs the tokens of the "token" verifier.
+	TokenSecret string `mapstructure:"token_secret"`
+	// TokenTTLSeconds is how long a token of the "token" verifier can be used.
+	TokenTTLSeconds int `mapstructure:"token_ttl_seconds"`
+}
+
+func (cfg *OptOut) validate(errs configErrors) configErrors {
+	switch cfg.Verifier {
+	case "", OptOutVerifierRecaptcha, OptOutVerifierNone:
+	case OptOutVerifierToken:
+		if len(cfg.TokenSecret) < 16 {
+			errs = append(errs, fmt.Errorf("optout.token_secret must have at least 16 characters if optout.verifier=token"))
+		}
+		if cfg.TokenTTLSeconds <= 0 {
+			errs = append(errs, fmt.Errorf("optout.token_ttl_seconds must be positive if optout.verifier=token. Got %d", cfg.TokenTTLSeconds))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("optout.verifier must be one of recaptcha, token or none. Got %s", cfg.Verifier))
+	}
+	return errs
+}
+
 const (
 	dummyHost        string = "dummyhost.com"
 	dummyPublisherID string = "12"
 	dummyGDPR        string = "0"
 	dummyGDPRConsent string = "someGDPRConsentString"
 	dummyCCPA        string = "1NYN"
+	dummyGPP         string = "DBABMA~someGPPString"
+	dummyGPPSID      string = "2"
 )
 
 type Adapter struct {
@@ -240,6 +653,87 @@ type Adapter struct {
 	// needed for Facebook
 	PlatformID string `mapstructure:"platform_id"`
 	AppSecret  string `mapstructure:"app_secret"`
+
+	// MaxImps limits the number of imps sent to this bidder in a single request. Requests with more imps
+	// are split into several smaller ones. 0 means no limit.
+	MaxImps int `mapstructure:"max_imps"`
+	// MaxHTTPCalls limits the number of HTTP calls to this bidder which a single auction has in flight at once.
+	// The other calls wait for one of them to finish. 0 means no limit.
+	MaxHTTPCalls int `mapstructure:"max_http_calls"`
+
+	// HTTPClient overrides the host's http_client settings for this bidder. If it's left empty, the bidder
+	// shares the host's client and connection pool.
+	HTTPClient AdapterHTTPClient `mapstructure:"http_client"`
+}
+
+// BidderAlias lets a core bidder's adapter bid under another name.
+type BidderAlias struct {
+	// Bidder is the core bidder whose adapter the alias uses.
+	Bidder string `mapstructure:"bidder"`
+	// GVLVendorID is the alias's ID in the IAB Global Vendor List. If it's 0, the alias is treated
+	// like its core bidder for GDPR.
+	GVLVendorID uint16 `mapstructure:"gvl_vendor_id"`
+	// UserSyncURL gives the alias a cookie family of its own, which is synced with this URL. It takes the
+	// same Template variables as adapters.{bidder}.usersync_url. If it's empty, the alias uses the
+	// core bidder's IDs and has no syncer.
+	UserSyncURL string `mapstructure:"usersync_url"`
+	// UserSyncType is "redirect" or "iframe".
+	UserSyncType string `mapstructure:"usersync_type"`
+}
+
+func validateBidderAliases(aliases map[string]BidderAlias, errs configErrors) configErrors {
+	for name, alias := range aliases {
+		if _, isBidder := openrtb_ext.BidderMap[name]; isBidder {
+			errs = append(errs, fmt.Errorf("bidder_aliases.%s has the name of a core bidder", name))
+		}
+		if _, isBidder := openrtb_ext.BidderMap[alias.Bidder]; !isBidder {
+			errs = append(errs, fmt.Errorf("bidder_aliases.%s.bidder must be a core bidder. Got %q", name, alias.Bidder))
+		}
+		if alias.UserSyncType != "" && alias.UserSyncType != "redirect" && alias.UserSyncType != "iframe" {
+			errs = append(errs, fmt.Errorf("bidder_aliases.%s.usersync_type must be \"redirect\" or \"iframe\". Got %q", name, alias.UserSyncType))
+		}
+		errs = validateAdapterUserSyncURL(alias.UserSyncURL, name, errs)
+	}
+	return errs
+}
+
+// AdapterHTTPClient configures the HTTP client of a single bidder. The connection pool settings which are
+// left at 0 inherit the host's http_client values.
+type AdapterHTTPClient struct {
+	MaxIdleConns        int `mapstructure:"max_idle_connections"`
+	MaxIdleConnsPerHost int `mapstructure:"max_idle_connections_per_host"`
+	IdleConnTimeout     int `mapstructure:"idle_connection_timeout_seconds"`
+	// DisableKeepAlives opens a new connection for every call to the bidder.
+	DisableKeepAlives bool `mapstructure:"disable_keep_alives"`
+	// EnableHTTP2 lets the client negotiate HTTP/2 with the bidder's servers over TLS.
+	EnableHTTP2 bool `mapstructure:"enable_http2"`
+	// TLSMinVersion is the lowest TLS version the client accepts: "1.0", "1.1", "1.2" or "1.3".
+	// If empty, Go's default applies.
+	TLSMinVersion string `mapstructure:"tls_min_version"`
+	// GzipRequests compresses the body of the requests sent to the bidder, and sets "Content-Encoding: gzip".
+	GzipRequests bool `mapstructure:"gzip_requests"`
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSVersion returns the crypto/tls constant for TLSMinVersion, or 0 if it isn't set.
+func (cfg *AdapterHTTPClient) TLSVersion() uint16 {
+	return tlsVersions[cfg.TLSMinVersion]
+}
+
+func (cfg *AdapterHTTPClient) validate(adapterName string, errs configErrors) configErrors {
+	if cfg.MaxIdleConns < 0 || cfg.MaxIdleConnsPerHost < 0 || cfg.IdleConnTimeout < 0 {
+		errs = append(errs, fmt.Errorf("adapters.%s.http_client connection pool settings must be >= 0", adapterName))
+	}
+	if _, ok := tlsVersions[cfg.TLSMinVersion]; cfg.TLSMinVersion != "" && !ok {
+		errs = append(errs, fmt.Errorf("adapters.%s.http_client.tls_min_version must be one of \"1.0\", \"1.1\", \"1.2\" or \"1.3\". Got \"%s\"", adapterName, cfg.TLSMinVersion))
+	}
+	return errs
 }
 
 // validateAdapterEndpoint makes sure that an adapter has a valid endpoint
@@ -287,6 +781,8 @@ func validateAdapterUserSyncURL(userSyncURL string, adapterName string, errs con
 			GDPR:        dummyGDPR,
 			GDPRConsent: dummyGDPRConsent,
 			USPrivacy:   dummyCCPA,
+			GPP:         dummyGPP,
+			GPPSID:      dummyGPPSID,
 		}
 		resolvedUserSyncURL, err := macros.ResolveMacros(*userSyncTemplate, dummyMacroValues)
 		if err != nil {
@@ -316,6 +812,14 @@ func validateAdapters(adapterMap map[string]Adapter, errs configErrors) configEr
 
 			// Verify that valid user_sync URLs are specified in the config
 			errs = validateAdapterUserSyncURL(adapter.UserSyncURL, adapterName, errs)
+
+			if adapter.MaxImps < 0 {
+				errs = append(errs, fmt.Errorf("adapters.%s.max_imps must be >= 0. Got %d", adapterName, adapter.MaxImps))
+			}
+			if adapter.MaxHTTPCalls < 0 {
+				errs = append(errs, fmt.Errorf("adapters.%s.max_http_calls must be >= 0. Got %d", adapterName, adapter.MaxHTTPCalls))
+			}
+			errs = adapter.HTTPClient.validate(adapterName, errs)
 		}
 	}
 	return errs
@@ -330,6 +834,10 @@ type Metrics struct {
 type DisabledMetrics struct {
 	// True if we want to stop collecting account-to-adapter metrics
 	AccountAdapterDetails bool `mapstructure:"account_adapter_details"`
+
+	// True if we don't want to trace the connections made to the adapters' servers. Tracing costs a little
+	// on every HTTP call, so it's disabled by default.
+	AdapterConnectionMetrics bool `mapstructure:"adapter_connections_metrics"`
 }
 
 func (cfg *Metrics) validate(errs configErrors) configErrors {
@@ -362,11 +870,19 @@ func (m *PrometheusMetrics) Timeout() time.Duration {
 	return time.Duration(m.TimeoutMillisRaw) * time.Millisecond
 }
 
+// DataCache configures the accounts and ad unit configs of the legacy /auction endpoint.
 type DataCache struct {
-	Type       string `mapstructure:"type"`
-	Filename   string `mapstructure:"filename"`
-	CacheSize  int    `mapstructure:"cache_size"`
-	TTLSeconds int    `mapstructure:"ttl_seconds"`
+	// Type is "stored" to read them with the Stored Request backends in Stored, or "dummy" to accept every account.
+	// The deprecated "filecache" reads them from the YAML file in Filename, and the deprecated "postgres" reads the
+	// old tables through the stored_requests.postgres connection.
+	Type     string `mapstructure:"type"`
+	Filename string `mapstructure:"filename"`
+	// CacheSize and TTLSeconds configure the in-memory cache in front of the "postgres" type.
+	CacheSize  int `mapstructure:"cache_size"`
+	TTLSeconds int `mapstructure:"ttl_seconds"`
+	// Stored configures the backends, cache and events of the "stored" type. Accounts are read as Stored Requests,
+	// which hold the account's JSON, and ad unit configs are read as Stored Imps.
+	Stored StoredRequestsSlim `mapstructure:"stored"`
 }
 
 // Data type where we store the external cache URL elements. This is completely unrelated to type Cache struct defined afterwards, because
@@ -435,6 +951,11 @@ func New(v *viper.Viper) (*Configuration, error) {
 		c.GDPR.NonStandardPublisherMap[c.GDPR.NonStandardPublishers[i]] = 1
 	}
 
+	c.GDPR.EEACountriesMap = make(map[string]struct{}, len(c.GDPR.EEACountries))
+	for _, country := range c.GDPR.EEACountries {
+		c.GDPR.EEACountriesMap[strings.ToUpper(country)] = struct{}{}
+	}
+
 	// To look for a request's app_id in O(1) time, we fill this hash table located in the
 	// the BlacklistedApps field of the Configuration struct defined in this file
 	c.BlacklistedAppMap = make(map[string]bool)
@@ -580,11 +1101,22 @@ func SetupViper(v *viper.Viper, filename string) {
 	v.SetDefault("host_cookie.value", "")
 	v.SetDefault("host_cookie.ttl_days", 90)
 	v.SetDefault("host_cookie.max_cookie_size_bytes", 0)
+	v.SetDefault("host_cookie.security.encrypt", false)
+	v.SetDefault("host_cookie.security.accept_unsigned", true)
+	v.SetDefault("setuid.redirect_secret", "")
+	v.SetDefault("setuid.redirect_max_age_seconds", 300)
+	v.SetDefault("setuid.overwrite_interval_seconds", 0)
+	v.SetDefault("sync_page.max_concurrent_syncs", 4)
+	v.SetDefault("sync_page.timeout_ms", 5000)
+	v.SetDefault("optout.verifier", OptOutVerifierRecaptcha)
+	v.SetDefault("optout.token_secret", "")
+	v.SetDefault("optout.token_ttl_seconds", 3600)
 	v.SetDefault("http_client.max_idle_connections", 400)
 	v.SetDefault("http_client.max_idle_connections_per_host", 10)
 	v.SetDefault("http_client.idle_connection_timeout_seconds", 60)
 	// no metrics configured by default (metrics{host|database|username|password})
 	v.SetDefault("metrics.disabled_metrics.account_adapter_details", false)
+	v.SetDefault("metrics.disabled_metrics.adapter_connections_metrics", true)
 	v.SetDefault("metrics.influxdb.host", "")
 	v.SetDefault("metrics.influxdb.database", "")
 	v.SetDefault("metrics.influxdb.username", "")
@@ -598,8 +1130,36 @@ func SetupViper(v *viper.Viper, filename string) {
 	v.SetDefault("datacache.filename", "")
 	v.SetDefault("datacache.cache_size", 0)
 	v.SetDefault("datacache.ttl_seconds", 0)
+	v.SetDefault("datacache.stored.filesystem.enabled", false)
+	v.SetDefault("datacache.stored.filesystem.directorypath", "")
+	v.SetDefault("datacache.stored.postgres.connection.dbname", "")
+	v.SetDefault("datacache.stored.postgres.connection.host", "")
+	v.SetDefault("datacache.stored.postgres.connection.port", 0)
+	v.SetDefault("datacache.stored.postgres.connection.user", "")
+	v.SetDefault("datacache.stored.postgres.connection.password", "")
+	v.SetDefault("datacache.stored.postgres.fetcher.query", "")
+	v.SetDefault("datacache.stored.postgres.fetcher.responses_query", "")
+	v.SetDefault("datacache.stored.postgres.initialize_caches.timeout_ms", 0)
+	v.SetDefault("datacache.stored.postgres.initialize_caches.query", "")
+	v.SetDefault("datacache.stored.postgres.poll_for_updates.refresh_rate_seconds", 0)
+	v.SetDefault("datacache.stored.postgres.poll_for_updates.timeout_ms", 0)
+	v.SetDefault("datacache.stored.postgres.poll_for_updates.query", "")
+	v.SetDefault("datacache.stored.http.endpoint", "")
+	v.SetDefault("datacache.stored.in_memory_cache.type", "none")
+	v.SetDefault("datacache.stored.in_memory_cache.ttl_seconds", 0)
+	v.SetDefault("datacache.stored.in_memory_cache.request_cache_size_bytes", 0)
+	v.SetDefault("datacache.stored.in_memory_cache.imp_cache_size_bytes", 0)
+	v.SetDefault("datacache.stored.cache_events.enabled", false)
+	v.SetDefault("datacache.stored.cache_events.endpoint", "/storedrequests/datacache")
+	v.SetDefault("datacache.stored.http_events.endpoint", "")
+	v.SetDefault("datacache.stored.http_events.refresh_rate_seconds", 0)
+	v.SetDefault("datacache.stored.http_events.timeout_ms", 0)
 	v.SetDefault("category_mapping.filesystem.enabled", true)
-	v.SetDefault("category_mapping.filesystem.directorypath", "/home/http/GO_SERVER/dmhbserver/static/category-mapping")
+	v.SetDefault("category_mapping.filesystem.directorypath", "")
+	v.SetDefault("static_assets.bidder_params_directory", "")
+	v.SetDefault("static_assets.bidder_info_directory", "")
+	v.SetDefault("config_reload.enabled", false)
+	v.SetDefault("config_reload.poll_interval_seconds", 10)
 	v.SetDefault("category_mapping.http.endpoint", "")
 	v.SetDefault("stored_requests.filesystem", false)
 	v.SetDefault("stored_requests.directorypath", "./stored_requests/data/by_id")
@@ -610,6 +1170,7 @@ func SetupViper(v *viper.Viper, filename string) {
 	v.SetDefault("stored_requests.postgres.connection.password", "")
 	v.SetDefault("stored_requests.postgres.fetcher.query", "")
 	v.SetDefault("stored_requests.postgres.fetcher.amp_query", "")
+	v.SetDefault("stored_requests.postgres.fetcher.responses_query", "")
 	v.SetDefault("stored_requests.postgres.initialize_caches.timeout_ms", 0)
 	v.SetDefault("stored_requests.postgres.initialize_caches.query", "")
 	v.SetDefault("stored_requests.postgres.initialize_caches.amp_query", "")
@@ -638,6 +1199,7 @@ func SetupViper(v *viper.Viper, filename string) {
 	v.SetDefault("stored_video_req.postgres.connection.user", "")
 	v.SetDefault("stored_video_req.postgres.connection.password", "")
 	v.SetDefault("stored_video_req.postgres.fetcher.query", "")
+	v.SetDefault("stored_video_req.postgres.fetcher.responses_query", "")
 	v.SetDefault("stored_video_req.postgres.initialize_caches.timeout_ms", 0)
 	v.SetDefault("stored_video_req.postgres.initialize_caches.query", "")
 	v.SetDefault("stored_video_req.postgres.poll_for_updates.refresh_rate_seconds", 0)
@@ -653,6 +1215,9 @@ func SetupViper(v *viper.Viper, filename string) {
 	v.SetDefault("stored_video_req.http_events.endpoint", "")
 	v.SetDefault("stored_video_req.http_events.refresh_rate_seconds", 0)
 	v.SetDefault("stored_video_req.http_events.timeout_ms", 0)
+	v.SetDefault("stored_requests_admin.enabled", false)
+	v.SetDefault("stored_requests_admin.endpoint", "/admin/storedrequests")
+	v.SetDefault("stored_requests_admin.token", "")
 
 	for _, bidder := range openrtb_ext.BidderMap {
 		setBidderDefaults(v, strings.ToLower(string(bidder)))
@@ -725,12 +1290,21 @@ func SetupViper(v *viper.Viper, filename string) {
 	v.SetDefault("gdpr.timeouts_ms.init_vendorlist_fetches", 0)
 	v.SetDefault("gdpr.timeouts_ms.active_vendorlist_fetch", 0)
 	v.SetDefault("gdpr.non_standard_publishers", []string{""})
+	v.SetDefault("gdpr.eea_countries", []string{"ALA", "AUT", "BEL", "BGR", "HRV", "CYP", "CZE", "DNK", "EST", "FIN", "FRA", "GUF", "DEU", "GIB", "GRC", "GLP", "GGY", "HUN", "ISL", "IRL", "IMN", "ITA", "JEY", "LVA", "LIE", "LTU", "LUX", "MLT", "MTQ", "MYT", "NLD", "NOR", "POL", "PRT", "REU", "ROU", "BLM", "MAF", "SPM", "SVK", "SVN", "ESP", "SWE", "GBR"})
 	v.SetDefault("ccpa.enforce", false)
 	v.SetDefault("currency_converter.fetch_url", "https://cdn.jsdelivr.net/gh/prebid/currency-file@1/latest.json")
 	v.SetDefault("currency_converter.fetch_interval_seconds", 1800) // fetch currency rates every 30 minutes
 	v.SetDefault("default_request.type", "")
 	v.SetDefault("default_request.file.name", "")
 	v.SetDefault("default_request.alias_info", false)
+	v.SetDefault("bid_blocking.enforce", false)
+	v.SetDefault("bid_blocking.require_adomain", false)
+	v.SetDefault("creative_validation.secure_markup", ValidationSkip)
+	v.SetDefault("creative_validation.banner_size", ValidationSkip)
+	v.SetDefault("creative_validation.vast_xml", ValidationSkip)
+	v.SetDefault("geolocation.enabled", false)
+	v.SetDefault("geolocation.type", "maxmind")
+	v.SetDefault("geolocation.database", "")
 	v.SetDefault("blacklisted_apps", []string{""})
 	v.SetDefault("blacklisted_accts", []string{""})
 	v.SetDefault("account_required", false)
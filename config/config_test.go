@@ -26,6 +26,7 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "max_request_size", int(cfg.MaxRequestSize), 1024*256)
 	cmpInts(t, "host_cookie.ttl_days", int(cfg.HostCookie.TTL), 90)
 	cmpInts(t, "host_cookie.max_cookie_size_bytes", cfg.HostCookie.MaxCookieSizeBytes, 0)
+	cmpInts(t, "setuid.redirect_max_age_seconds", cfg.SetUID.RedirectMaxAgeSeconds, 300)
 	cmpStrings(t, "datacache.type", cfg.DataCache.Type, "dummy")
 	cmpStrings(t, "adapters.pubmatic.endpoint", cfg.Adapters[string(openrtb_ext.BidderPubmatic)].Endpoint, "https://hbopenbid.pubmatic.com/translator?source=prebid-server")
 	cmpInts(t, "currency_converter.fetch_interval_seconds", cfg.CurrencyConverter.FetchIntervalSeconds, 1800)
@@ -34,6 +35,22 @@ func TestDefaults(t *testing.T) {
 	cmpInts(t, "metrics.influxdb.collection_rate_seconds", cfg.Metrics.Influxdb.MetricSendInterval, 20)
 	cmpBools(t, "account_adapter_details", cfg.Metrics.Disabled.AccountAdapterDetails, false)
 	cmpStrings(t, "certificates_file", cfg.PemCertsFile, "")
+	cmpBools(t, "bid_blocking.enforce", cfg.BidBlocking.Enforce, false)
+	cmpBools(t, "stored_requests_admin.enabled", cfg.StoredRequestsAdmin.Enabled, false)
+	cmpStrings(t, "stored_requests_admin.endpoint", cfg.StoredRequestsAdmin.Endpoint, "/admin/storedrequests")
+	cmpBools(t, "adapter_connections_metrics", cfg.Metrics.Disabled.AdapterConnectionMetrics, true)
+	cmpStrings(t, "creative_validation.secure_markup", string(cfg.CreativeValidation.SecureMarkup), "skip")
+	cmpStrings(t, "creative_validation.banner_size", string(cfg.CreativeValidation.BannerSize), "skip")
+	cmpStrings(t, "creative_validation.vast_xml", string(cfg.CreativeValidation.VastXML), "skip")
+	cmpStrings(t, "static_assets.bidder_params_directory", cfg.StaticAssets.BidderParamsDirectory, "")
+	cmpStrings(t, "static_assets.bidder_info_directory", cfg.StaticAssets.BidderInfoDirectory, "")
+	cmpStrings(t, "category_mapping.filesystem.directorypath", cfg.CategoryMapping.Files.Path, "")
+	cmpBools(t, "geolocation.enabled", cfg.GeoLocation.Enabled, false)
+	cmpStrings(t, "geolocation.type", cfg.GeoLocation.Type, "maxmind")
+	_, found := cfg.GDPR.EEACountriesMap["DEU"]
+	cmpBools(t, "gdpr.eea_countries", found, true)
+	_, found = cfg.GDPR.EEACountriesMap["USA"]
+	cmpBools(t, "gdpr.eea_countries", found, false)
 }
 
 var fullConfig = []byte(`
@@ -41,6 +58,7 @@ gdpr:
   host_vendor_id: 15
   usersync_if_ambiguous: true
   non_standard_publishers: ["siteID","fake-site-id","appID","agltb3B1Yi1pbmNyDAsSA0FwcBiJkfIUDA"]
+  eea_countries: ["fra","DEU"]
 ccpa:
   enforce: true
 host_cookie:
@@ -90,6 +108,13 @@ adapters:
   appnexus:
     endpoint: http://ib.adnxs.com/some/endpoint
     extra_info: "{\"native\":\"http://www.native.org/endpoint\",\"video\":\"http://www.video.org/endpoint\"}"
+    max_imps: 10
+    max_http_calls: 4
+    http_client:
+      max_idle_connections: 50
+      enable_http2: true
+      tls_min_version: "1.2"
+      gzip_requests: true
   audienceNetwork:
     endpoint: http://facebook.com/pbs
     usersync_url: http://facebook.com/ortb/prebid-s2s
@@ -111,6 +136,44 @@ adapters:
 blacklisted_apps: ["spamAppID","sketchy-app-id"]
 account_required: true
 certificates_file: /etc/ssl/cert.pem
+geolocation:
+  enabled: true
+  type: maxmind
+  database: /var/lib/geoip/GeoIP2-City.mmdb
+bid_blocking:
+  enforce: true
+  require_adomain: true
+  accounts:
+    "1001":
+      bcat: ["IAB25","IAB26"]
+      badv: ["badadvertiser.com"]
+      battr: [1,2]
+creative_validation:
+  secure_markup: enforce
+  banner_size: warn
+  accounts:
+    "1001":
+      banner_size: enforce
+activity_controls:
+  sync_user:
+    default: false
+    rules:
+      - allow: true
+        condition:
+          component_name: ["appnexus"]
+          geo: ["USA.CA"]
+  accounts:
+    "1001":
+      fetch_bids:
+        rules:
+          - allow: false
+            condition:
+              component_type: ["bidder"]
+              gpp_sid: [7, 8]
+              gdpr: true
+static_assets:
+  bidder_params_directory: /etc/prebid/bidder-params
+  bidder_info_directory: /etc/prebid/bidder-info
 `)
 
 var adapterExtraInfoConfig = []byte(`
@@ -232,6 +295,11 @@ func TestFullConfig(t *testing.T) {
 	_, found = cfg.GDPR.NonStandardPublisherMap["appnexus"]
 	cmpBools(t, "cfg.GDPR.NonStandardPublisherMap", found, false)
 
+	assert.Equal(t, map[string]struct{}{"FRA": {}, "DEU": {}}, cfg.GDPR.EEACountriesMap, "gdpr.eea_countries")
+	cmpBools(t, "geolocation.enabled", cfg.GeoLocation.Enabled, true)
+	cmpStrings(t, "geolocation.type", cfg.GeoLocation.Type, "maxmind")
+	cmpStrings(t, "geolocation.database", cfg.GeoLocation.Database, "/var/lib/geoip/GeoIP2-City.mmdb")
+
 	cmpBools(t, "ccpa.enforce", cfg.CCPA.Enforce, true)
 
 	//Assert the NonStandardPublishers was correctly unmarshalled
@@ -259,6 +327,13 @@ func TestFullConfig(t *testing.T) {
 	cmpStrings(t, "", cfg.GetCachedAssetURL("a0eebc99-9c0b-4ef8-bb00-6bb9bd380a11"), "http://prebidcache.net/cache?uuid=a0eebc99-9c0b-4ef8-bb00-6bb9bd380a11")
 	cmpStrings(t, "adapters.appnexus.endpoint", cfg.Adapters[string(openrtb_ext.BidderAppnexus)].Endpoint, "http://ib.adnxs.com/some/endpoint")
 	cmpStrings(t, "adapters.appnexus.extra_info", cfg.Adapters[string(openrtb_ext.BidderAppnexus)].ExtraAdapterInfo, "{\"native\":\"http://www.native.org/endpoint\",\"video\":\"http://www.video.org/endpoint\"}")
+	cmpInts(t, "adapters.appnexus.max_imps", cfg.Adapters[string(openrtb_ext.BidderAppnexus)].MaxImps, 10)
+	cmpInts(t, "adapters.appnexus.max_http_calls", cfg.Adapters[string(openrtb_ext.BidderAppnexus)].MaxHTTPCalls, 4)
+	cmpInts(t, "adapters.appnexus.http_client.max_idle_connections", cfg.Adapters[string(openrtb_ext.BidderAppnexus)].HTTPClient.MaxIdleConns, 50)
+	cmpBools(t, "adapters.appnexus.http_client.enable_http2", cfg.Adapters[string(openrtb_ext.BidderAppnexus)].HTTPClient.EnableHTTP2, true)
+	cmpStrings(t, "adapters.appnexus.http_client.tls_min_version", cfg.Adapters[string(openrtb_ext.BidderAppnexus)].HTTPClient.TLSMinVersion, "1.2")
+	cmpBools(t, "adapters.appnexus.http_client.gzip_requests", cfg.Adapters[string(openrtb_ext.BidderAppnexus)].HTTPClient.GzipRequests, true)
+	cmpBools(t, "adapters.appnexus.http_client.disable_keep_alives", cfg.Adapters[string(openrtb_ext.BidderAppnexus)].HTTPClient.DisableKeepAlives, false)
 	cmpStrings(t, "adapters.audiencenetwork.endpoint", cfg.Adapters[strings.ToLower(string(openrtb_ext.BidderFacebook))].Endpoint, "http://facebook.com/pbs")
 	cmpStrings(t, "adapters.audiencenetwork.usersync_url", cfg.Adapters[strings.ToLower(string(openrtb_ext.BidderFacebook))].UserSyncURL, "http://facebook.com/ortb/prebid-s2s")
 	cmpStrings(t, "adapters.audiencenetwork.platform_id", cfg.Adapters[strings.ToLower(string(openrtb_ext.BidderFacebook))].PlatformID, "abcdefgh1234")
@@ -276,6 +351,25 @@ func TestFullConfig(t *testing.T) {
 	cmpBools(t, "account_required", cfg.AccountRequired, true)
 	cmpBools(t, "account_adapter_details", cfg.Metrics.Disabled.AccountAdapterDetails, true)
 	cmpStrings(t, "certificates_file", cfg.PemCertsFile, "/etc/ssl/cert.pem")
+	cmpBools(t, "bid_blocking.enforce", cfg.BidBlocking.Enforce, true)
+	cmpBools(t, "bid_blocking.require_adomain", cfg.BidBlocking.RequireAdomain, true)
+	assert.Equal(t, []string{"IAB25", "IAB26"}, cfg.BidBlocking.Accounts["1001"].BCat, "bid_blocking.accounts.1001.bcat")
+	assert.Equal(t, []string{"badadvertiser.com"}, cfg.BidBlocking.Accounts["1001"].BAdv, "bid_blocking.accounts.1001.badv")
+	assert.Equal(t, []int{1, 2}, cfg.BidBlocking.Accounts["1001"].BAttr, "bid_blocking.accounts.1001.battr")
+	cmpStrings(t, "creative_validation.secure_markup", string(cfg.CreativeValidation.SecureMarkup), "enforce")
+	cmpStrings(t, "creative_validation.banner_size", string(cfg.CreativeValidation.BannerSize), "warn")
+	cmpStrings(t, "creative_validation.vast_xml", string(cfg.CreativeValidation.VastXML), "skip")
+	cmpStrings(t, "creative_validation.accounts.1001.banner_size", string(cfg.CreativeValidation.Accounts["1001"].BannerSize), "enforce")
+	cmpBools(t, "activity_controls.sync_user.default", *cfg.ActivityControls.SyncUser.Default, false)
+	assert.Equal(t, []ActivityRule{{Allow: true, Condition: ActivityCondition{ComponentName: []string{"appnexus"}, Geo: []string{"USA.CA"}}}}, cfg.ActivityControls.SyncUser.Rules)
+	accountRule := cfg.ActivityControls.Accounts["1001"].FetchBids.Rules[0]
+	cmpBools(t, "activity_controls.accounts.1001.fetch_bids.rules[0].allow", accountRule.Allow, false)
+	assert.Equal(t, []int8{7, 8}, accountRule.Condition.GPPSID)
+	cmpBools(t, "activity_controls.accounts.1001.fetch_bids.rules[0].condition.gdpr", *accountRule.Condition.GDPR, true)
+	assert.False(t, cfg.ActivityControls.ForAccount("1001").FetchBids.Rules[0].Allow)
+	assert.Equal(t, cfg.ActivityControls.SyncUser, cfg.ActivityControls.ForAccount("1001").SyncUser, "An account should fall back to the host's activities")
+	cmpStrings(t, "static_assets.bidder_params_directory", cfg.StaticAssets.BidderParamsDirectory, "/etc/prebid/bidder-params")
+	cmpStrings(t, "static_assets.bidder_info_directory", cfg.StaticAssets.BidderInfoDirectory, "/etc/prebid/bidder-info")
 }
 
 func TestUnmarshalAdapterExtraInfo(t *testing.T) {
@@ -367,6 +461,166 @@ func TestOverflowedVendorID(t *testing.T) {
 	assertOneError(t, cfg.validate(), "gdpr.host_vendor_id must be in the range [0, 65535]. Got 65536")
 }
 
+func TestInvalidBlockedAttribute(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.BidBlocking.Accounts = map[string]BlockLists{
+		"1001": {BAttr: []int{18}},
+	}
+	assertOneError(t, cfg.validate(), "bid_blocking.accounts.1001.battr must only contain values in the range [1, 17]. Got 18")
+}
+
+func TestInvalidCreativeValidationMode(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.CreativeValidation.VastXML = "reject"
+	assertOneError(t, cfg.validate(), "creative_validation.vast_xml must be one of \"skip\", \"warn\" or \"enforce\". Got \"reject\"")
+
+	cfg = newDefaultConfig(t)
+	cfg.CreativeValidation.Accounts = map[string]CreativeValidationModes{
+		"1001": {SecureMarkup: "on"},
+	}
+	assertOneError(t, cfg.validate(), "creative_validation.accounts.1001.secure_markup must be one of \"skip\", \"warn\" or \"enforce\". Got \"on\"")
+}
+
+func TestInvalidActivityControls(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.ActivityControls.TransmitUFPD.Rules = []ActivityRule{{Condition: ActivityCondition{ComponentType: []string{"module"}}}}
+	assertOneError(t, cfg.validate(), "activity_controls.transmit_ufpd.rules[0].condition.component_type must only contain \"bidder\" or \"analytics\". Got \"module\"")
+
+	cfg = newDefaultConfig(t)
+	cfg.ActivityControls.Accounts = map[string]Activities{
+		"1001": {SyncUser: Activity{Rules: []ActivityRule{{Condition: ActivityCondition{Geo: []string{"USA.CA.LA"}}}}}},
+	}
+	assertOneError(t, cfg.validate(), "activity_controls.accounts.1001.sync_user.rules[0].condition.geo must only contain \"{country}\" or \"{country}.{region}\" values. Got \"USA.CA.LA\"")
+}
+
+func TestInvalidGeoLocation(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.GeoLocation = GeoLocation{Enabled: true, Type: "ip2location", Database: "/var/lib/geoip/db.bin"}
+	assertOneError(t, cfg.validate(), "geolocation.type must be \"maxmind\". Got \"ip2location\"")
+
+	cfg = newDefaultConfig(t)
+	cfg.GeoLocation = GeoLocation{Enabled: true, Type: "maxmind"}
+	assertOneError(t, cfg.validate(), "geolocation.database is required when geolocation.enabled is true")
+}
+
+func TestInvalidBidderAliases(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.BidderAliases = map[string]BidderAlias{
+		"appnexus": {Bidder: "rubicon"},
+	}
+	assertOneError(t, cfg.validate(), "bidder_aliases.appnexus has the name of a core bidder")
+
+	cfg = newDefaultConfig(t)
+	cfg.BidderAliases = map[string]BidderAlias{
+		"myalias": {Bidder: "unknown"},
+	}
+	assertOneError(t, cfg.validate(), "bidder_aliases.myalias.bidder must be a core bidder. Got \"unknown\"")
+
+	cfg = newDefaultConfig(t)
+	cfg.BidderAliases = map[string]BidderAlias{
+		"myalias": {Bidder: "appnexus", UserSyncURL: "http://sync.example.com", UserSyncType: "image"},
+	}
+	assertOneError(t, cfg.validate(), "bidder_aliases.myalias.usersync_type must be \"redirect\" or \"iframe\". Got \"image\"")
+}
+
+func TestNegativeSetUIDOverwriteInterval(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.SetUID.BidderOverwriteIntervalSeconds = map[string]int{"adnxs": -1}
+	assertOneError(t, cfg.validate(), "setuid.bidder_overwrite_interval_seconds.adnxs must be >= 0. Got -1")
+}
+
+func TestInvalidSetUIDRedirectMaxAge(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.SetUID.RedirectSecret = "secret"
+	cfg.SetUID.RedirectMaxAgeSeconds = 0
+	assertOneError(t, cfg.validate(), "setuid.redirect_max_age_seconds must be > 0 when setuid.redirect_secret is set. Got 0")
+}
+
+func TestInvalidCookieSecurity(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.HostCookie.Security.Keys = []CookieKey{{ID: "a.b", Secret: "0123456789abcdef"}}
+	assertOneError(t, cfg.validate(), "host_cookie.security.keys[0].id must be non-empty and can't contain a \".\". Got \"a.b\"")
+
+	cfg = newDefaultConfig(t)
+	cfg.HostCookie.Security.Keys = []CookieKey{{ID: "key", Secret: "short"}}
+	assertOneError(t, cfg.validate(), "host_cookie.security.keys[0].secret must have at least 16 characters")
+
+	cfg = newDefaultConfig(t)
+	cfg.HostCookie.Security.Encrypt = true
+	assertOneError(t, cfg.validate(), "host_cookie.security.keys must be set if host_cookie.security.encrypt=true")
+}
+
+func TestInvalidSyncPage(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.SyncPage.MaxConcurrentSyncs = -1
+	assertOneError(t, cfg.validate(), "sync_page.max_concurrent_syncs must be >= 0. Got -1")
+
+	cfg = newDefaultConfig(t)
+	cfg.SyncPage.TimeoutMillis = -1
+	assertOneError(t, cfg.validate(), "sync_page.timeout_ms must be >= 0. Got -1")
+}
+
+func TestInvalidOptOut(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.OptOut.Verifier = "captcha"
+	assertOneError(t, cfg.validate(), "optout.verifier must be one of recaptcha, token or none. Got captcha")
+
+	cfg = newDefaultConfig(t)
+	cfg.OptOut.Verifier = OptOutVerifierToken
+	assertOneError(t, cfg.validate(), "optout.token_secret must have at least 16 characters if optout.verifier=token")
+
+	cfg.OptOut.TokenSecret = "0123456789abcdef"
+	assert.Empty(t, cfg.validate())
+}
+
+func TestInvalidStoredRequestsAdmin(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.StoredRequestsAdmin.Enabled = true
+	assertOneError(t, cfg.validate(), "stored_requests_admin.token must be set if stored_requests_admin.enabled=true")
+
+	cfg.StoredRequestsAdmin.Token = "secret"
+	assert.Empty(t, cfg.validate())
+
+	cfg.StoredRequestsAdmin.Endpoint = "/admin/"
+	assertOneError(t, cfg.validate(), "stored_requests_admin.endpoint must start with a / and not end with one. Got /admin/")
+}
+
+func TestCreativeValidationForAccount(t *testing.T) {
+	cfg := CreativeValidation{
+		CreativeValidationModes: CreativeValidationModes{
+			SecureMarkup: ValidationEnforce,
+			BannerSize:   ValidationWarn,
+			VastXML:      ValidationSkip,
+		},
+		Accounts: map[string]CreativeValidationModes{
+			"1001": {BannerSize: ValidationEnforce},
+		},
+	}
+	assert.Equal(t, CreativeValidationModes{SecureMarkup: ValidationEnforce, BannerSize: ValidationEnforce, VastXML: ValidationSkip}, cfg.ForAccount("1001"))
+	assert.Equal(t, cfg.CreativeValidationModes, cfg.ForAccount("1002"))
+}
+
+func TestNegativeAdapterLimits(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.Adapters["appnexus"] = Adapter{
+		Endpoint:     "http://ib.adnxs.com/openrtb2",
+		MaxImps:      -1,
+		MaxHTTPCalls: 3,
+	}
+	assertOneError(t, cfg.validate(), "adapters.appnexus.max_imps must be >= 0. Got -1")
+}
+
+func TestInvalidAdapterTLSMinVersion(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.Adapters["appnexus"] = Adapter{
+		Endpoint: "http://ib.adnxs.com/openrtb2",
+		HTTPClient: AdapterHTTPClient{
+			TLSMinVersion: "1.4",
+		},
+	}
+	assertOneError(t, cfg.validate(), "adapters.appnexus.http_client.tls_min_version must be one of \"1.0\", \"1.1\", \"1.2\" or \"1.3\". Got \"1.4\"")
+}
+
 func TestNegativeCurrencyConverterFetchInterval(t *testing.T) {
 	cfg := Configuration{
 		CurrencyConverter: CurrencyConverter{
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/golang/glog"
+)
+
+// ConfigReload configures the hot reload of the config file.
+type ConfigReload struct {
+	// Enabled reloads the config file when it changes, or when the process gets SIGHUP.
+	Enabled bool `mapstructure:"enabled"`
+	// PollIntervalSeconds is how often the config file is checked for changes. Use 0 to only reload on SIGHUP.
+	PollIntervalSeconds int `mapstructure:"poll_interval_seconds"`
+}
+
+func (cfg *ConfigReload) validate(errs configErrors) configErrors {
+	if cfg.PollIntervalSeconds < 0 {
+		errs = append(errs, fmt.Errorf("config_reload.poll_interval_seconds must be >= 0. Got %d", cfg.PollIntervalSeconds))
+	}
+	return errs
+}
+
+// Validate runs the same checks as New, for configs which were built or changed some other way.
+func (cfg *Configuration) Validate() error {
+	if errs := cfg.validate(); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Diff lists the settings which differ between two configs as "key: old -> new", sorted by key.
+// The settings are named and redacted the same way as in the config which gets logged at startup.
+func Diff(old *Configuration, new *Configuration) []string {
+	before := flattenConfig(old)
+	after := flattenConfig(new)
+
+	var changes []string
+	for key, oldValue := range before {
+		if newValue, ok := after[key]; !ok {
+			changes = append(changes, fmt.Sprintf("%s: %s -> <unset>", key, oldValue))
+		} else if newValue != oldValue {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", key, oldValue, newValue))
+		}
+	}
+	for key, newValue := range after {
+		if _, ok := before[key]; !ok {
+			changes = append(changes, fmt.Sprintf("%s: <unset> -> %s", key, newValue))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// WithSectionsOf returns a copy of cfg whose top level sections, named by their config keys, hold the
+// values from other instead.
+func (cfg *Configuration) WithSectionsOf(other *Configuration, sections []string) *Configuration {
+	merged := *cfg
+	target := reflect.ValueOf(&merged).Elem()
+	source := reflect.ValueOf(other).Elem()
+	for i := 0; i < target.NumField(); i++ {
+		key := strings.Split(target.Type().Field(i).Tag.Get("mapstructure"), ",")[0]
+		for _, section := range sections {
+			if key == section {
+				target.Field(i).Set(source.Field(i))
+			}
+		}
+	}
+	return &merged
+}
+
+func flattenConfig(cfg *Configuration) map[string]string {
+	settings := make(map[string]string)
+	logGeneralWithLogger(reflect.ValueOf(*cfg), "", func(msg string, args ...interface{}) {
+		line := fmt.Sprintf(msg, args...)
+		if i := strings.Index(line, ": "); i >= 0 {
+			settings[line[:i]] = line[i+2:]
+		}
+	})
+	return settings
+}
+
+// WatchFile calls reload whenever the modification time of the file changes, or the process gets SIGHUP.
+// The file is checked every interval. An interval of 0 only reacts to SIGHUP.
+// WatchFile blocks until stop gets closed.
+func WatchFile(filename string, interval time.Duration, reload func(), stop <-chan struct{}) {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+
+	var ticks <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	lastModified := modTime(filename)
+	for {
+		select {
+		case <-hangups:
+			glog.Infof("Reloading %s because of SIGHUP", filename)
+			lastModified = modTime(filename)
+			reload()
+		case <-ticks:
+			if modified := modTime(filename); !modified.Equal(lastModified) {
+				glog.Infof("Reloading %s because it changed", filename)
+				lastModified = modified
+				reload()
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+func modTime(filename string) time.Time {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
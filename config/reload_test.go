@@ -0,0 +1,93 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigReloadDefaults(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cmpBools(t, "config_reload.enabled", cfg.ConfigReload.Enabled, false)
+	cmpInts(t, "config_reload.poll_interval_seconds", cfg.ConfigReload.PollIntervalSeconds, 10)
+}
+
+func TestNegativeConfigReloadInterval(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	cfg.ConfigReload.PollIntervalSeconds = -1
+	assertOneError(t, cfg.validate(), "config_reload.poll_interval_seconds must be >= 0. Got -1")
+}
+
+func TestValidate(t *testing.T) {
+	cfg := newDefaultConfig(t)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.MaxRequestSize = -1
+	assert.EqualError(t, cfg.Validate(), configErrors{cfg.validate()[0]}.Error())
+}
+
+func TestDiff(t *testing.T) {
+	old := newDefaultConfig(t)
+	new := newDefaultConfig(t)
+	assert.Empty(t, Diff(old, new))
+
+	new.AuctionTimeouts.Max = 5000
+	new.BlacklistedApps = []string{"spam-app"}
+	new.StoredRequests.Postgres.ConnectionInfo.Password = "secret"
+	new.StoredRequestsAdmin.Token = "secret"
+	new.Adapters["appnexus"] = Adapter{Endpoint: "http://appnexus.test/bid"}
+
+	changes := Diff(old, new)
+	assert.Contains(t, changes, "auction_timeouts_ms.max: 0 -> 5000")
+	assert.Contains(t, changes, "blacklisted_apps: [] -> [spam-app]")
+	assert.Contains(t, changes, "adapters[appnexus].endpoint: http://ib.adnxs.com/openrtb2 -> http://appnexus.test/bid")
+	for _, change := range changes {
+		assert.NotContains(t, change, "secret", "Diff should redact the passwords and tokens")
+	}
+}
+
+func TestWithSectionsOf(t *testing.T) {
+	current := newDefaultConfig(t)
+	next := newDefaultConfig(t)
+	next.Client.MaxIdleConns = 1
+	next.Port = 9000
+	next.StatusResponse = "ready"
+
+	merged := next.WithSectionsOf(current, []string{"http_client", "port"})
+
+	assert.Equal(t, current.Client, merged.Client)
+	assert.Equal(t, current.Port, merged.Port)
+	assert.Equal(t, "ready", merged.StatusResponse, "The other sections should come from the config itself")
+	assert.Equal(t, 1, next.Client.MaxIdleConns, "The config itself shouldn't change")
+}
+
+func TestWatchFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "pbs-config")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.Remove(file.Name())
+	file.Close()
+
+	reloads := make(chan struct{}, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+	go WatchFile(file.Name(), 10*time.Millisecond, func() { reloads <- struct{}{} }, stop)
+
+	select {
+	case <-reloads:
+		t.Fatal("WatchFile shouldn't reload a file which didn't change")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	later := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(file.Name(), later, later))
+	select {
+	case <-reloads:
+	case <-time.After(time.Second):
+		t.Error("WatchFile should reload the file once it changes")
+	}
+}
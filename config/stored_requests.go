@@ -140,6 +140,34 @@ func (cfg *StoredRequests) validate(errs configErrors) configErrors {
 	return errs
 }
 
+// StoredRequestsAdmin configures the API in stored_requests/admin, which manages the Stored Requests, Stored Imps,
+// Stored Video Requests and AMP configs kept in a writable store. The API is served on the admin port.
+//
+// Unless the host provides its own stores through router.WithStoredRequestStores, the data and its version
+// history are only kept in the memory of each instance. They're lost when the instance restarts, and a change
+// made through one instance isn't seen by the others.
+type StoredRequestsAdmin struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Endpoint is the path under which the API lives. The data used by /openrtb2/auction, /openrtb2/amp
+	// and /openrtb2/video is managed under {endpoint}/openrtb2, {endpoint}/amp and {endpoint}/video.
+	Endpoint string `mapstructure:"endpoint"`
+	// Token must be sent by every caller, in an "Authorization: Bearer {token}" header.
+	Token string `mapstructure:"token"`
+}
+
+func (cfg *StoredRequestsAdmin) validate(errs configErrors) configErrors {
+	if !cfg.Enabled {
+		return errs
+	}
+	if cfg.Token == "" {
+		errs = append(errs, errors.New("stored_requests_admin.token must be set if stored_requests_admin.enabled=true"))
+	}
+	if !strings.HasPrefix(cfg.Endpoint, "/") || strings.HasSuffix(cfg.Endpoint, "/") {
+		errs = append(errs, fmt.Errorf("stored_requests_admin.endpoint must start with a / and not end with one. Got %s", cfg.Endpoint))
+	}
+	return errs
+}
+
 // PostgresConfigSlim configures the Stored Request ecosystem to use Postgres. This must include a Fetcher,
 // and may optionally include some EventProducers to populate and refresh the caches.
 type PostgresConfigSlim struct {
@@ -240,6 +268,15 @@ type PostgresFetcherQueries struct {
 
 	// AmpQueryTemplate is the same as QueryTemplate, but used in the `/openrtb2/amp` endpoint.
 	AmpQueryTemplate string `mapstructure:"amp_query"`
+
+	// ResponsesQueryTemplate is the Postgres Query which fetches Stored Responses. It works like QueryTemplate,
+	// but has a single %ID_LIST% and must return the id and the data of each Stored Response. For example:
+	//   SELECT id, responseData
+	//     FROM stored_responses
+	//     WHERE id in %ID_LIST%
+	//
+	// If empty, Stored Responses aren't loaded from Postgres.
+	ResponsesQueryTemplate string `mapstructure:"responses_query"`
 }
 
 type PostgresFetcherQueriesSlim struct {
@@ -266,6 +303,15 @@ type PostgresFetcherQueriesSlim struct {
 	//
 	// ... where the number of "$x" args depends on how many IDs are nested within the HTTP request.
 	QueryTemplate string `mapstructure:"query"`
+
+	// ResponsesQueryTemplate is the Postgres Query which fetches Stored Responses. It works like QueryTemplate,
+	// but has a single %ID_LIST% and must return the id and the data of each Stored Response. For example:
+	//   SELECT id, responseData
+	//     FROM stored_responses
+	//     WHERE id in %ID_LIST%
+	//
+	// If empty, Stored Responses aren't loaded from Postgres.
+	ResponsesQueryTemplate string `mapstructure:"responses_query"`
 }
 
 type PostgresCacheInitializer struct {
@@ -413,6 +459,13 @@ func (cfg *PostgresFetcherQueries) MakeAmpQuery(numReqs int, numImps int) string
 	return resolve(cfg.AmpQueryTemplate, numReqs, numImps)
 }
 
+// MakeResponsesQuery builds a query which can fetch numIDs Stored Responses.
+// See the docs on ResponsesQueryTemplate for a description of how it works.
+func (cfg *PostgresFetcherQueriesSlim) MakeResponsesQuery(numIDs int) string {
+	numIDs = ensureNonNegative("Response", numIDs)
+	return strings.Replace(cfg.ResponsesQueryTemplate, "%ID_LIST%", makeIdList(0, numIDs), -1)
+}
+
 func resolve(template string, numReqs int, numImps int) (query string) {
 	numReqs = ensureNonNegative("Request", numReqs)
 	numImps = ensureNonNegative("Imp", numImps)
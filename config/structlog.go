@@ -14,6 +14,9 @@ type logMsg func(string, ...interface{})
 var mapregex = regexp.MustCompile(`mapstructure:"([^"]+)"`)
 var blacklistregexp = []*regexp.Regexp{
 	regexp.MustCompile("password"),
+	regexp.MustCompile("token"),
+	regexp.MustCompile("secret"),
+	regexp.MustCompile("key"),
 }
 
 // LogGeneral will log nearly any sort of value, but requires the name of the root object to be in the
@@ -37,6 +40,14 @@ func logGeneralWithLogger(v reflect.Value, prefix string, logger logMsg) {
 		logger("%s: %f", prefix, v.Float())
 	case reflect.Bool:
 		logger("%s: %t", prefix, v.Bool())
+	case reflect.Slice, reflect.Array:
+		logSliceWithLogger(v, prefix, logger)
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			logger("%s: <nil>", prefix)
+		} else {
+			logGeneralWithLogger(v.Elem(), prefix, logger)
+		}
 	default:
 		// logString, by using v.String(), will not fail, and indicate what additional cases we need to handle
 		logger("%s: %s", prefix, v.String())
@@ -75,12 +86,37 @@ func logMapWithLogger(v reflect.Value, prefix string, logger logMsg) {
 	}
 }
 
+// logSliceWithLogger logs a list of plain values on one line. The elements of any other list are logged one by one,
+// as <prefix>[<index>], so that the fields of each element go through the blacklist.
+func logSliceWithLogger(v reflect.Value, prefix string, logger logMsg) {
+	switch v.Type().Elem().Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Ptr, reflect.Interface:
+		if v.Len() == 0 {
+			logger("%s: []", prefix)
+		}
+		for i := 0; i < v.Len(); i++ {
+			logGeneralWithLogger(v.Index(i), fmt.Sprintf("%s[%d]", prefix, i), logger)
+		}
+	default:
+		if v.CanInterface() {
+			logger("%s: %v", prefix, v.Interface())
+		} else {
+			logger("%s: %s", prefix, v.String())
+		}
+	}
+}
+
 func fieldNameByTag(f reflect.StructField) string {
 	match := mapregex.FindStringSubmatch(string(f.Tag))
-	if len(match) == 0 || len(match[1]) == 0 {
+	if len(match) == 0 {
+		return fmt.Sprintf("((%s))", f.Name)
+	}
+	// Drop options like ",flow" and ",squash", which aren't part of the name.
+	name := strings.Split(match[1], ",")[0]
+	if len(name) == 0 {
 		return fmt.Sprintf("((%s))", f.Name)
 	}
-	return match[1]
+	return name
 }
 
 func allowedName(name string) bool {
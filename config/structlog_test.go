@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -56,3 +57,59 @@ func TestBasic(t *testing.T) {
 		t.Errorf("Did not log properly.\ndesired:%s\nfound:%s\nsource: %v", expected, result, testCfg)
 	}
 }
+
+type listStruct struct {
+	Items []innerStruct `mapstructure:"items"`
+	Names []string      `mapstructure:"names"`
+}
+
+func TestLogSlices(t *testing.T) {
+	var buf bytes.Buffer
+	mylogger := func(msg string, args ...interface{}) {
+		buf.WriteString(fmt.Sprintf(fmt.Sprintln(msg), args...))
+	}
+
+	testCfg := listStruct{
+		Items: []innerStruct{{int1: 1, password: "secret1"}, {int1: 2, password: "secret2"}},
+		Names: []string{"a", "b"},
+	}
+	logStructWithLogger(reflect.ValueOf(testCfg), "", mylogger)
+
+	expected := `items[0].int1: 1
+items[0].password: <REDACTED>
+items[1].int1: 2
+items[1].password: <REDACTED>
+names: [a b]
+`
+	if expected != buf.String() {
+		t.Errorf("Did not log properly.\ndesired:%s\nfound:%s", expected, buf.String())
+	}
+}
+
+func TestLogRedactsSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	mylogger := func(msg string, args ...interface{}) {
+		buf.WriteString(fmt.Sprintf(fmt.Sprintln(msg), args...))
+	}
+
+	cfg := Configuration{}
+	cfg.HostCookie.Security.Keys = []CookieKey{{ID: "k1", Secret: "SUPERSECRETKEY1234"}}
+	cfg.SetUID.RedirectSecret = "REDIRSECRET"
+	logStructWithLogger(reflect.ValueOf(cfg), "", mylogger)
+
+	for _, secret := range []string{"SUPERSECRETKEY1234", "REDIRSECRET"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("The log shouldn't contain %s", secret)
+		}
+	}
+
+	changed := cfg
+	changed.HostCookie.Security.Keys = nil
+	changed.SetUID.RedirectSecret = ""
+	diff := strings.Join(Diff(&cfg, &changed), "\n")
+	for _, secret := range []string{"SUPERSECRETKEY1234", "REDIRSECRET"} {
+		if strings.Contains(diff, secret) {
+			t.Errorf("The reload diff shouldn't contain %s", secret)
+		}
+	}
+}
@@ -145,7 +145,11 @@ func (rc *RateConverter) startPeriodicFetching() {
 }
 
 // StopPeriodicFetching stops the periodic fetching while keeping the latest currencies rates map
+// It does nothing if the converter was built without a fetching interval, since there is nothing to stop.
 func (rc *RateConverter) StopPeriodicFetching() {
+	if rc.fetchingInterval == time.Duration(0) {
+		return
+	}
 	rc.done <- true
 	close(rc.done)
 }
@@ -171,12 +175,17 @@ func (rc *RateConverter) Rates() Conversions {
 }
 
 // GetInfo returns setup information about the converter
+// The rates are nil until the first fetch succeeds.
 func (rc *RateConverter) GetInfo() ConverterInfo {
+	var rates *map[string]map[string]float64
+	if conversions := rc.Rates(); conversions != nil {
+		rates = conversions.GetRates()
+	}
 	return converterInfo{
 		source:           rc.syncSourceURL,
 		fetchingInterval: rc.fetchingInterval,
 		lastUpdated:      rc.LastUpdated(),
-		rates:            rc.Rates().GetRates(),
+		rates:            rates,
 	}
 }
 
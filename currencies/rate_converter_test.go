@@ -92,6 +92,7 @@ func TestFetch_Fail404(t *testing.T) {
 	assert.Equal(t, 1, len(calledURLs), "sync URL should have been called %d times but was %d", 1, len(calledURLs))
 	assert.Equal(t, currencyConverter.LastUpdated(), (time.Time{}), "LastUpdated() shouldn't return a time set")
 	assert.Nil(t, currencyConverter.Rates(), "Rates() should return nil")
+	assert.Nil(t, currencyConverter.GetInfo().Rates(), "GetInfo() shouldn't have rates before a fetch succeeds")
 }
 
 func TestFetch_FailErrorHttpClient(t *testing.T) {
@@ -363,6 +364,22 @@ func TestInitWithZeroDuration(t *testing.T) {
 	assert.True(t, ok, "Rates should be type of `currencies.ConstantRates`")
 }
 
+func TestStopWithZeroDuration(t *testing.T) {
+	currencyConverter := currencies.NewRateConverterDefault()
+
+	stopped := make(chan struct{})
+	go func() {
+		currencyConverter.StopPeriodicFetching()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(1 * time.Second):
+		t.Error("StopPeriodicFetching shouldn't block when there is no periodic fetching")
+	}
+}
+
 func TestRates(t *testing.T) {
 
 	// Setup:
@@ -11,7 +11,7 @@ import (
 	"testing"
 
 	"github.com/PubMatic-OpenWrap/openrtb"
-	"github.com/PubMatic-OpenWrap/prebid-server/cache/dummycache"
+	"github.com/PubMatic-OpenWrap/prebid-server/cache/datacache"
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/gdpr"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
@@ -69,7 +69,7 @@ func TestSortBidsAndAddKeywordsForMobile(t *testing.T) {
 	}
     `)
 	r := httptest.NewRequest("POST", "/auction", bytes.NewBuffer(body))
-	d, _ := dummycache.New()
+	d := datacache.NewDummy()
 	hcc := config.HostCookie{}
 
 	pbs_req, err := pbs.ParsePBSRequest(r, &config.AuctionTimeouts{
@@ -18,8 +18,10 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
 	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/activity"
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy/ccpa"
 	gdprPolicy "github.com/PubMatic-OpenWrap/prebid-server/privacy/gdpr"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/gpp"
 	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
 	"github.com/buger/jsonparser"
 	"github.com/golang/glog"
@@ -37,6 +39,7 @@ func NewCookieSyncEndpoint(syncers map[openrtb_ext.BidderName]usersync.Usersynce
 		metrics:         metrics,
 		pbsAnalytics:    pbsAnalytics,
 		enforceCCPA:     cfg.CCPA.Enforce,
+		activities:      &cfg.ActivityControls,
 	}
 	return deps.Endpoint
 }
@@ -49,6 +52,7 @@ type cookieSyncDeps struct {
 	metrics         pbsmetrics.MetricsEngine
 	pbsAnalytics    analytics.PBSAnalyticsModule
 	enforceCCPA     bool
+	activities      *config.ActivityControls
 }
 
 func (deps *cookieSyncDeps) Endpoint(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -63,7 +67,10 @@ func (deps *cookieSyncDeps) Endpoint(w http.ResponseWriter, r *http.Request, _ h
 	defer deps.pbsAnalytics.LogCookieSyncObject(&co)
 
 	deps.metrics.RecordCookieSync()
-	userSyncCookie := usersync.ParsePBSCookieFromRequest(r, deps.hostCookie)
+	userSyncCookie, err := usersync.ReadPBSCookieFromRequest(r, deps.hostCookie)
+	if err != nil {
+		deps.metrics.RecordUIDCookieError(usersync.CookieErrorReason(err))
+	}
 	if !userSyncCookie.AllowSyncs() {
 		http.Error(w, "User has opted out", http.StatusUnauthorized)
 		co.Status = http.StatusUnauthorized
@@ -96,11 +103,26 @@ func (deps *cookieSyncDeps) Endpoint(w http.ResponseWriter, r *http.Request, _ h
 	}
 
 	if len(biddersJSON) == 0 {
-		parsedReq.Bidders = make([]string, 0, len(deps.syncers))
-		for bidder := range deps.syncers {
-			parsedReq.Bidders = append(parsedReq.Bidders, string(bidder))
-		}
+		parsedReq.Bidders = deps.allBidders()
 	}
+	csResp := cookieSyncResponse{
+		Status:       cookieSyncStatus(userSyncCookie.LiveSyncCount()),
+		BidderStatus: deps.bidderSyncs(r, parsedReq, userSyncCookie),
+	}
+
+	if len(csResp.BidderStatus) > 0 {
+		co.BidderStatus = append(co.BidderStatus, csResp.BidderStatus...)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(csResp)
+}
+
+// bidderSyncs returns the syncs which the user still needs, out of the bidders in the request. It drops the bidders
+// which the cookie already has, or which the privacy signals block, and picks at most req.Limit of the rest.
+func (deps *cookieSyncDeps) bidderSyncs(r *http.Request, parsedReq *cookieSyncRequest, userSyncCookie *usersync.PBSCookie) []*usersync.CookieSyncBidders {
 	setSiteCookie := siteCookieCheck(r.UserAgent())
 	needSyncupForSameSite := false
 	if setSiteCookie {
@@ -119,6 +141,8 @@ func (deps *cookieSyncDeps) Endpoint(w http.ResponseWriter, r *http.Request, _ h
 			Value: parsedReq.USPrivacy,
 		},
 	}
+	// The section IDs were already checked by resolveGDPR.
+	privacyPolicy.GPP, _ = parsedReq.gppPolicy()
 
 	parsedReq.filterExistingSyncs(deps.syncers, userSyncCookie, needSyncupForSameSite)
 
@@ -128,6 +152,7 @@ func (deps *cookieSyncDeps) Endpoint(w http.ResponseWriter, r *http.Request, _ h
 		adapterSyncs[openrtb_ext.BidderName(b)] = true
 	}
 	parsedReq.filterForPrivacy(deps.syncPermissions, privacyPolicy, deps.enforceCCPA)
+	parsedReq.filterForActivities(activity.NewControls(deps.activities, parsedReq.Account), privacyPolicy.GPP)
 	// surviving bidders are not privacy blocked
 	for _, b := range parsedReq.Bidders {
 		adapterSyncs[openrtb_ext.BidderName(b)] = false
@@ -137,11 +162,6 @@ func (deps *cookieSyncDeps) Endpoint(w http.ResponseWriter, r *http.Request, _ h
 	}
 	parsedReq.filterToLimit()
 
-	csResp := cookieSyncResponse{
-		Status:       cookieSyncStatus(userSyncCookie.LiveSyncCount()),
-		BidderStatus: make([]*usersync.CookieSyncBidders, 0, len(parsedReq.Bidders)),
-	}
-
 	//For secure = true flag on cookie
 	secParam := r.URL.Query().Get("sec")
 	refererHeader := r.Header.Get("Referer")
@@ -150,6 +170,7 @@ func (deps *cookieSyncDeps) Endpoint(w http.ResponseWriter, r *http.Request, _ h
 		setSecureFlag = true
 	}
 
+	bidderStatus := make([]*usersync.CookieSyncBidders, 0, len(parsedReq.Bidders))
 	for i := 0; i < len(parsedReq.Bidders); i++ {
 		bidder := parsedReq.Bidders[i]
 
@@ -169,42 +190,77 @@ func (deps *cookieSyncDeps) Endpoint(w http.ResponseWriter, r *http.Request, _ h
 				NoCookie:     true,
 				UsersyncInfo: syncInfo,
 			}
-			csResp.BidderStatus = append(csResp.BidderStatus, newSync)
+			bidderStatus = append(bidderStatus, newSync)
 		} else {
 			glog.Errorf("Failed to get usersync info for %s: %v", newBidder, err)
 		}
 	}
+	return bidderStatus
+}
 
-	if len(csResp.BidderStatus) > 0 {
-		co.BidderStatus = append(co.BidderStatus, csResp.BidderStatus...)
+// allBidders returns every bidder which has a syncer.
+func (deps *cookieSyncDeps) allBidders() []string {
+	bidders := make([]string, 0, len(deps.syncers))
+	for bidder := range deps.syncers {
+		bidders = append(bidders, string(bidder))
 	}
-
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	enc := json.NewEncoder(w)
-	enc.SetEscapeHTML(false)
-	enc.Encode(csResp)
+	return bidders
 }
 
 func parseRequest(parsedReq *cookieSyncRequest, bodyBytes []byte, usersyncIfAmbiguous bool) error {
 	if err := json.Unmarshal(bodyBytes, parsedReq); err != nil {
 		return fmt.Errorf("JSON parsing failed: %s", err.Error())
 	}
+	return parsedReq.resolveGDPR(usersyncIfAmbiguous)
+}
 
-	if parsedReq.GDPR != nil && *parsedReq.GDPR == 1 && parsedReq.Consent == "" {
+// resolveGDPR checks the request's consent, and decides whether GDPR applies if the request didn't say.
+func (req *cookieSyncRequest) resolveGDPR(usersyncIfAmbiguous bool) error {
+	if err := req.resolveGPP(); err != nil {
+		return err
+	}
+	if req.GDPR != nil && *req.GDPR == 1 && req.Consent == "" {
 		return errors.New("gdpr_consent is required if gdpr=1")
 	}
 	// If GDPR is ambiguous, lets untangle it here.
-	if parsedReq.GDPR == nil {
+	if req.GDPR == nil {
 		var gdpr = new(int)
 		*gdpr = 1
 		if usersyncIfAmbiguous {
 			*gdpr = 0
 		}
-		parsedReq.GDPR = gdpr
+		req.GDPR = gdpr
 	}
 	return nil
 }
 
+// resolveGPP fills in the GDPR signal, consent and US Privacy string from the request's GPP string, if the request
+// doesn't have them itself. A GPP string which can't be parsed is ignored, like an invalid us_privacy.
+func (req *cookieSyncRequest) resolveGPP() error {
+	policy, err := req.gppPolicy()
+	if err != nil {
+		return err
+	}
+	signals, _ := policy.Signals()
+
+	if req.GDPR == nil && signals.GDPR != "" {
+		gdpr, _ := strconv.Atoi(signals.GDPR)
+		req.GDPR = &gdpr
+	}
+	if req.Consent == "" {
+		req.Consent = signals.TCFConsent
+	}
+	if req.USPrivacy == "" {
+		req.USPrivacy = signals.USPrivacy
+	}
+	return nil
+}
+
+func (req *cookieSyncRequest) gppPolicy() (gpp.Policy, error) {
+	sectionIDs, err := gpp.ParseSectionIDs(req.GPPSID)
+	return gpp.Policy{Value: req.GPP, SectionIDs: sectionIDs}, err
+}
+
 func gdprToString(gdpr *int) string {
 	if gdpr == nil {
 		return ""
@@ -246,7 +302,10 @@ type cookieSyncRequest struct {
 	GDPR      *int     `json:"gdpr"`
 	Consent   string   `json:"gdpr_consent"`
 	USPrivacy string   `json:"us_privacy"`
+	GPP       string   `json:"gpp"`
+	GPPSID    string   `json:"gpp_sid"`
 	Limit     int      `json:"limit"`
+	Account   string   `json:"account"`
 }
 
 func (req *cookieSyncRequest) filterExistingSyncs(valid map[openrtb_ext.BidderName]usersync.Usersyncer, cookie *usersync.PBSCookie, needSyncupForSameSite bool) {
@@ -265,7 +324,8 @@ func (req *cookieSyncRequest) filterExistingSyncs(valid map[openrtb_ext.BidderNa
 }
 
 func (req *cookieSyncRequest) filterForPrivacy(permissions gdpr.Permissions, privacyPolicies privacy.Policies, enforceCCPA bool) {
-	if enforceCCPA && privacyPolicies.CCPA.ShouldEnforce() {
+	gppSignals, _ := privacyPolicies.GPP.Signals()
+	if enforceCCPA && (privacyPolicies.CCPA.ShouldEnforce() || gppSignals.USOptOut) {
 		req.Bidders = nil
 		return
 	}
@@ -287,6 +347,22 @@ func (req *cookieSyncRequest) filterForPrivacy(permissions gdpr.Permissions, pri
 	}
 }
 
+// filterForActivities drops the bidders which the activity controls don't allow to sync the user.
+func (req *cookieSyncRequest) filterForActivities(controls *activity.Controls, gppPolicy gpp.Policy) {
+	signals := activity.Signals{GDPR: req.GDPR != nil && *req.GDPR == 1}
+	for _, id := range gppPolicy.SectionIDs {
+		signals.GPPSIDs = append(signals.GPPSIDs, int8(id))
+	}
+
+	for i := 0; i < len(req.Bidders); i++ {
+		component := activity.Component{Type: activity.ComponentBidder, Name: req.Bidders[i]}
+		if !controls.Allow(activity.SyncUser, component, signals) {
+			req.Bidders = append(req.Bidders[:i], req.Bidders[i+1:]...)
+			i--
+		}
+	}
+}
+
 // filterToLimit will enforce a max limit on cookiesyncs supplied, picking a random subset of syncs to get to the limit if over.
 func (req *cookieSyncRequest) filterToLimit() {
 	if req.Limit <= 0 {
@@ -126,6 +126,100 @@ func TestCCPA(t *testing.T) {
 	}
 }
 
+func TestGPP(t *testing.T) {
+	testCases := []struct {
+		description   string
+		requestBody   string
+		enforceCCPA   bool
+		expectedSyncs []string
+	}{
+		{
+			description:   "Feature Flag On & US National Opt-Out",
+			requestBody:   `{"bidders":["appnexus"], "gpp":"DBABLA~BAAa", "gpp_sid":"7"}`,
+			enforceCCPA:   true,
+			expectedSyncs: []string{},
+		},
+		{
+			description:   "Feature Flag Off & US National Opt-Out",
+			requestBody:   `{"bidders":["appnexus"], "gpp":"DBABLA~BAAa", "gpp_sid":"7"}`,
+			enforceCCPA:   false,
+			expectedSyncs: []string{"appnexus"},
+		},
+		{
+			description:   "Feature Flag On & US Privacy Section Opt-Out",
+			requestBody:   `{"bidders":["appnexus"], "gpp":"DBACNYA~CAAAAAAAAAAAAAAAAAAAAoAAAMAAAAAAAAAAABmA~1YYN", "gpp_sid":"6"}`,
+			enforceCCPA:   true,
+			expectedSyncs: []string{},
+		},
+		{
+			description:   "Feature Flag On & Section Doesn't Apply",
+			requestBody:   `{"bidders":["appnexus"], "gpp":"DBABLA~BAAa", "gpp_sid":"6"}`,
+			enforceCCPA:   true,
+			expectedSyncs: []string{"appnexus"},
+		},
+		{
+			description:   "TCF Section Applies Without Host Consent",
+			requestBody:   `{"bidders":["appnexus"], "gpp":"DBABMA~CAAAAAAAAAAAAAAAAAAAAoAAAMAAAAAAAAAAABmA", "gpp_sid":"2"}`,
+			expectedSyncs: []string{},
+		},
+	}
+
+	for _, test := range testCases {
+		gdpr := config.GDPR{UsersyncIfAmbiguous: true}
+		ccpa := config.CCPA{Enforce: test.enforceCCPA}
+		rr := doConfigurablePost(test.requestBody, nil, false, syncersForTest(), gdpr, ccpa, false, false, false)
+		assert.Equal(t, http.StatusOK, rr.Code, test.description+":httpResponseCode")
+		assert.ElementsMatch(t, test.expectedSyncs, parseSyncs(t, rr.Body.Bytes()), test.description+":syncs")
+	}
+}
+
+func TestGPPBadSectionIDs(t *testing.T) {
+	rr := doPost(`{"bidders":["appnexus"], "gpp":"DBABLA~BAAa", "gpp_sid":"usnat"}`, nil, false, nil, false, false, false)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Equal(t, "gpp_sid must be a comma separated list of section IDs, but it contains \"usnat\"\n", rr.Body.String())
+}
+
+func TestActivityControls(t *testing.T) {
+	deny := false
+	cfg := &config.Configuration{
+		GDPR: config.GDPR{UsersyncIfAmbiguous: true},
+		ActivityControls: config.ActivityControls{
+			Activities: config.Activities{SyncUser: config.Activity{Rules: []config.ActivityRule{
+				{Allow: false, Condition: config.ActivityCondition{ComponentName: []string{"pubmatic"}}},
+			}}},
+			Accounts: map[string]config.Activities{
+				"1001": {SyncUser: config.Activity{Default: &deny}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		description   string
+		requestBody   string
+		expectedSyncs []string
+	}{
+		{
+			description:   "Host Rule",
+			requestBody:   `{"bidders":["appnexus", "pubmatic"]}`,
+			expectedSyncs: []string{"appnexus"},
+		},
+		{
+			description:   "Account Rule",
+			requestBody:   `{"bidders":["appnexus", "pubmatic"], "account":"1001"}`,
+			expectedSyncs: []string{},
+		},
+	}
+
+	for _, test := range testCases {
+		endpoint := NewCookieSyncEndpoint(syncersForTest(), cfg, mockPermissions(true, nil), &metricsConf.DummyMetricsEngine{}, analyticsConf.NewPBSAnalytics(&config.Analytics{}))
+		req, _ := http.NewRequest("POST", "/cookie_sync", strings.NewReader(test.requestBody))
+		rr := httptest.NewRecorder()
+		endpoint(rr, req, nil)
+		assert.Equal(t, http.StatusOK, rr.Code, test.description+":httpResponseCode")
+		assert.ElementsMatch(t, test.expectedSyncs, parseSyncs(t, rr.Body.Bytes()), test.description+":syncs")
+	}
+}
+
 func TestCookieSyncHasCookies(t *testing.T) {
 	rr := doPost(`{"bidders":["appnexus", "audienceNetwork", "random"]}`, map[string]string{
 		"adnxs":           "1234",
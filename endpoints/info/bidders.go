@@ -2,17 +2,60 @@ package info
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
-	"github.com/buger/jsonparser"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy"
+	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
 	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
 )
 
-// NewBiddersEndpoint implements /info/bidders
-func NewBiddersEndpoint(aliases map[string]string) httprouter.Handle {
+// Metadata is what the info endpoints report about the bidders beyond their static/bidder-info files.
+// Every field is optional.
+type Metadata struct {
+	// Adapters is the host's adapters config, which holds the bidders' endpoints.
+	Adapters map[string]config.Adapter
+	// HostAliases hold the GVL vendor IDs of the host's bidder_aliases.
+	HostAliases map[string]config.BidderAlias
+	// Syncers hold the bidders' user sync types and GVL vendor IDs.
+	Syncers map[openrtb_ext.BidderName]usersync.Usersyncer
+	// ParamsValidator holds the JSON schemas of the bidders' params.
+	ParamsValidator openrtb_ext.BidderParamValidator
+}
+
+// bidderDetails is the response of /info/bidders/{bidderName}.
+type bidderDetails struct {
+	adapters.BidderInfo
+	// Endpoint is the host of the bidder's configured endpoint.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Aliases are the default aliases of a core bidder.
+	Aliases      []string        `json:"aliases,omitempty"`
+	GVLVendorID  uint16          `json:"gvlVendorId,omitempty"`
+	UserSync     *userSyncInfo   `json:"usersync,omitempty"`
+	ParamsSchema json.RawMessage `json:"paramsSchema,omitempty"`
+}
+
+type userSyncInfo struct {
+	Type string `json:"type"`
+}
+
+// NewBiddersEndpoint implements /info/bidders. The list can be filtered with the query params:
+//
+//	enabledonly=true  -- only the bidders which aren't disabled in the config. There is no circuit breaker,
+//	                     so a bidder which is failing at the moment is still enabled.
+//	mediatype={type}  -- only the bidders which support banner, video, audio or native
+//	platform={name}   -- only the bidders which support app or site. With mediatype, the type must be supported there.
+//	gvlid={id}        -- only the bidders with this ID in the IAB Global Vendor List
+func NewBiddersEndpoint(infos adapters.BidderInfos, aliases map[string]string, metadata Metadata) httprouter.Handle {
+	details := buildBidderDetails(infos, aliases, metadata)
 	bidderNames := make([]string, 0, len(openrtb_ext.BidderMap)+len(aliases))
 	for bidderName := range openrtb_ext.BidderMap {
 		bidderNames = append(bidderNames, bidderName)
@@ -21,58 +64,58 @@ func NewBiddersEndpoint(aliases map[string]string) httprouter.Handle {
 	for aliasName := range aliases {
 		bidderNames = append(bidderNames, aliasName)
 	}
+	sort.Strings(bidderNames)
 
 	biddersJson, err := json.Marshal(bidderNames)
 	if err != nil {
 		glog.Fatalf("error creating /info/bidders endpoint response: %v", err)
 	}
 
-	return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
+	return func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		response := biddersJson
+		if r.URL.RawQuery != "" {
+			filter, err := parseBidderFilter(r.URL.Query())
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(err.Error()))
+				return
+			}
+			filtered := make([]string, 0, len(bidderNames))
+			for _, bidderName := range bidderNames {
+				if filter.matches(details[bidderName]) {
+					filtered = append(filtered, bidderName)
+				}
+			}
+			response, _ = json.Marshal(filtered)
+		}
+
 		w.Header().Set("Content-Type", "application/json")
-		if _, err := w.Write(biddersJson); err != nil {
+		if _, err := w.Write(response); err != nil {
 			glog.Errorf("error writing response to /info/bidders: %v", err)
 		}
 	}
 }
 
 // NewBidderDetailsEndpoint implements /info/bidders/*
-func NewBidderDetailsEndpoint(infos adapters.BidderInfos, aliases map[string]string) httprouter.Handle {
+func NewBidderDetailsEndpoint(infos adapters.BidderInfos, aliases map[string]string, metadata Metadata) httprouter.Handle {
 	// Validate if there exist and alias with name "all". If it does error out because
 	// that will break the /info/bidders/all endpoint.
 	if _, ok := aliases["all"]; ok {
 		glog.Fatal("Default aliases shouldn't contain an alias with name \"all\". This will break the /info/bidders/all endpoint")
 	}
-
-	// Build a new map that's basically a copy of "infos" but will also contain
-	// alias bidder infos
-	var allBidderInfo = make(map[string]adapters.BidderInfo, len(infos)+len(aliases))
+	allBidderDetails := buildBidderDetails(infos, aliases, metadata)
 
 	// Build all the responses up front, since there are a finite number and it won't use much memory.
-	responses := make(map[string]json.RawMessage, len(infos)+len(aliases))
-	for bidderName, bidderInfo := range infos {
-		// Copy bidderInfo into "allBidderInfo" map
-		allBidderInfo[bidderName] = bidderInfo
-
-		// JSON encode bidder info and add it to the "responses" map
-		jsonData, err := json.Marshal(bidderInfo)
+	responses := make(map[string]json.RawMessage, len(allBidderDetails)+1)
+	for bidderName, details := range allBidderDetails {
+		jsonData, err := json.Marshal(details)
 		if err != nil {
 			glog.Fatalf("Failed to JSON-marshal bidder-info/%s.yaml data.", bidderName)
 		}
 		responses[bidderName] = jsonData
 	}
 
-	// Add in any default aliases
-	for aliasName, bidderName := range aliases {
-		// Add the alias bidder info into "allBidderInfo" map
-		aliasInfo := infos[bidderName]
-		aliasInfo.AliasOf = bidderName
-		allBidderInfo[aliasName] = aliasInfo
-
-		// JSON encode core bidder info for the alias and add it to the "responses" map
-		responses[aliasName] = createAliasInfo(responses, aliasName, bidderName)
-	}
-
-	allBidderResponse, err := json.Marshal(allBidderInfo)
+	allBidderResponse, err := json.Marshal(allBidderDetails)
 	if err != nil {
 		glog.Fatal("Failed to JSON-marshal all bidder info data.")
 	}
@@ -95,19 +138,126 @@ func NewBidderDetailsEndpoint(infos adapters.BidderInfos, aliases map[string]str
 	}
 }
 
-func createAliasInfo(responses map[string]json.RawMessage, alias string, core string) json.RawMessage {
-	coreJSON, ok := responses[core]
-	if !ok {
-		glog.Fatalf("Unknown core bidder %s for default alias %s", core, alias)
+// buildBidderDetails collects the details of every bidder in infos, and of every alias.
+// An alias has the bidder-info, endpoint and params of its core bidder.
+func buildBidderDetails(infos adapters.BidderInfos, aliases map[string]string, metadata Metadata) map[string]*bidderDetails {
+	details := make(map[string]*bidderDetails, len(infos)+len(aliases))
+	for bidderName, bidderInfo := range infos {
+		details[bidderName] = newBidderDetails(bidderName, bidderName, bidderInfo, metadata)
 	}
-	jsonData := make(json.RawMessage, len(coreJSON))
-	copy(jsonData, coreJSON)
 
-	jsonInfo, err := jsonparser.Set(jsonData, []byte(`"`+core+`"`), "aliasOf")
-	if err != nil {
-		glog.Fatalf("Failed to generate bidder info for %s, an alias of %s", alias, core)
+	for aliasName, bidderName := range aliases {
+		coreDetails, ok := details[bidderName]
+		if !ok {
+			glog.Fatalf("Unknown core bidder %s for default alias %s", bidderName, aliasName)
+		}
+		coreDetails.Aliases = append(coreDetails.Aliases, aliasName)
+
+		aliasInfo := infos[bidderName]
+		aliasInfo.AliasOf = bidderName
+		aliasDetails := newBidderDetails(aliasName, bidderName, aliasInfo, metadata)
+		if aliasDetails.UserSync == nil {
+			aliasDetails.UserSync = coreDetails.UserSync
+		}
+		if vendorID := metadata.HostAliases[aliasName].GVLVendorID; vendorID != 0 {
+			aliasDetails.GVLVendorID = vendorID
+		} else if aliasDetails.GVLVendorID == 0 {
+			aliasDetails.GVLVendorID = coreDetails.GVLVendorID
+		}
+		details[aliasName] = aliasDetails
+	}
+
+	for _, bidderDetails := range details {
+		sort.Strings(bidderDetails.Aliases)
+	}
+	return details
+}
+
+func newBidderDetails(bidderName string, coreBidder string, info adapters.BidderInfo, metadata Metadata) *bidderDetails {
+	details := &bidderDetails{BidderInfo: info}
+	if endpoint, err := url.Parse(metadata.Adapters[strings.ToLower(coreBidder)].Endpoint); err == nil {
+		details.Endpoint = endpoint.Host
+	}
+	if syncer, ok := metadata.Syncers[openrtb_ext.BidderName(bidderName)]; ok {
+		details.GVLVendorID = syncer.GDPRVendorID()
+		if syncInfo, err := syncer.GetUsersyncInfo(privacy.Policies{}); err == nil {
+			details.UserSync = &userSyncInfo{Type: syncInfo.Type}
+		}
+	}
+	if metadata.ParamsValidator != nil {
+		if schema := metadata.ParamsValidator.Schema(openrtb_ext.BidderName(coreBidder)); schema != "" {
+			details.ParamsSchema = json.RawMessage(schema)
+		}
+	}
+	return details
+}
+
+// bidderFilter holds the query params of /info/bidders.
+type bidderFilter struct {
+	enabledOnly bool
+	mediaType   openrtb_ext.BidType
+	platform    string
+	gvlID       uint16
+	hasGVLID    bool
+}
+
+func parseBidderFilter(query url.Values) (filter bidderFilter, err error) {
+	if value := query.Get("enabledonly"); value != "" {
+		if filter.enabledOnly, err = strconv.ParseBool(value); err != nil {
+			return filter, errors.New("the enabledonly query param must be true or false")
+		}
+	}
+	if value := query.Get("mediatype"); value != "" {
+		if filter.mediaType, err = openrtb_ext.ParseBidType(value); err != nil {
+			return filter, errors.New("the mediatype query param must be banner, video, audio or native")
+		}
+	}
+	if filter.platform = query.Get("platform"); filter.platform != "" && filter.platform != "app" && filter.platform != "site" {
+		return filter, errors.New("the platform query param must be app or site")
+	}
+	if value := query.Get("gvlid"); value != "" {
+		gvlID, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return filter, errors.New("the gvlid query param must be a Global Vendor List ID")
+		}
+		filter.gvlID, filter.hasGVLID = uint16(gvlID), true
+	}
+	return filter, nil
+}
+
+func (f bidderFilter) matches(details *bidderDetails) bool {
+	if details == nil {
+		return false
+	}
+	if f.enabledOnly && details.Status != adapters.StatusActive {
+		return false
+	}
+	if f.hasGVLID && details.GVLVendorID != f.gvlID {
+		return false
+	}
+	if f.platform == "" && f.mediaType == "" {
+		return true
+	}
+	if details.Capabilities == nil {
+		return false
+	}
+	return (f.platform != "site" && f.supports(details.Capabilities.App)) ||
+		(f.platform != "app" && f.supports(details.Capabilities.Site))
+}
+
+func (f bidderFilter) supports(platform *adapters.PlatformInfo) bool {
+	if platform == nil {
+		return false
+	}
+	if f.mediaType == "" {
+		return true
+	}
+	for _, mediaType := range platform.MediaTypes {
+		if mediaType == f.mediaType {
+			return true
+		}
 	}
-	return jsonInfo
+	return false
 }
 
 type infoFile struct {
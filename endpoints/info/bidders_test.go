@@ -10,6 +10,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"text/template"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/stretchr/testify/assert"
@@ -18,6 +19,7 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/endpoints/info"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -35,7 +37,8 @@ func TestGetBiddersWithAliases(t *testing.T) {
 }
 
 func testGetBidders(t *testing.T, aliases map[string]string) {
-	endpoint := info.NewBiddersEndpoint(aliases)
+	bidderInfos := adapters.ParseBidderInfos(blankAdapterConfig(openrtb_ext.BidderList()), "../../static/bidder-info", openrtb_ext.BidderList())
+	endpoint := info.NewBiddersEndpoint(bidderInfos, aliases, info.Metadata{})
 
 	req, err := http.NewRequest("GET", "http://prebid-server.com/info/bidders", strings.NewReader(""))
 	if err != nil {
@@ -88,7 +91,7 @@ func TestGetSpecificBidders(t *testing.T) {
 		}
 		cfg := blankAdapterConfigWithStatus(openrtb_ext.BidderList(), bidderDisabled)
 		bidderInfos := adapters.ParseBidderInfos(cfg, "../../static/bidder-info", openrtb_ext.BidderList())
-		endpoint := info.NewBidderDetailsEndpoint(bidderInfos, map[string]string{})
+		endpoint := info.NewBidderDetailsEndpoint(bidderInfos, map[string]string{}, info.Metadata{})
 
 		for bidderName := range openrtb_ext.BidderMap {
 			req, err := http.NewRequest("GET", "http://prebid-server.com/info/bidders/"+bidderName, strings.NewReader(""))
@@ -139,7 +142,7 @@ func testGetBidderAccuracy(t *testing.T, alias string) {
 		bidder = alias
 	}
 
-	endpoint := info.NewBidderDetailsEndpoint(bidderInfos, aliases)
+	endpoint := info.NewBidderDetailsEndpoint(bidderInfos, aliases, info.Metadata{})
 	req, err := http.NewRequest("GET", "http://prebid-server.com/info/bidders/"+bidder, strings.NewReader(""))
 	assert.NoError(t, err, "Failed to create a GET /info/bidders request: %v", err)
 	params := []httprouter.Param{{
@@ -172,7 +175,7 @@ func testGetBidderAccuracy(t *testing.T, alias string) {
 
 func TestGetUnknownBidder(t *testing.T) {
 	bidderInfos := adapters.BidderInfos(make(map[string]adapters.BidderInfo))
-	endpoint := info.NewBidderDetailsEndpoint(bidderInfos, map[string]string{})
+	endpoint := info.NewBidderDetailsEndpoint(bidderInfos, map[string]string{}, info.Metadata{})
 	req, err := http.NewRequest("GET", "http://prebid-server.com/info/bidders/someUnknownBidder", strings.NewReader(""))
 	if err != nil {
 		assert.FailNow(t, "Failed to create a GET /info/bidders/someUnknownBidder request: %v", err)
@@ -190,7 +193,7 @@ func TestGetUnknownBidder(t *testing.T) {
 func TestGetAllBidders(t *testing.T) {
 	cfg := blankAdapterConfig(openrtb_ext.BidderList())
 	bidderInfos := adapters.ParseBidderInfos(cfg, "../../static/bidder-info", openrtb_ext.BidderList())
-	endpoint := info.NewBidderDetailsEndpoint(bidderInfos, map[string]string{})
+	endpoint := info.NewBidderDetailsEndpoint(bidderInfos, map[string]string{}, info.Metadata{})
 	req, err := http.NewRequest("GET", "http://prebid-server.com/info/bidders/all", strings.NewReader(""))
 	if err != nil {
 		assert.FailNow(t, "Failed to create a GET /info/bidders/someUnknownBidder request: %v", err)
@@ -214,6 +217,99 @@ func TestGetAllBidders(t *testing.T) {
 	assert.Len(t, resBidderInfos, len(bidderInfos), "GET /info/bidders/all should respond with all bidders info")
 }
 
+func TestGetBiddersFilters(t *testing.T) {
+	bidderInfos := adapters.BidderInfos{
+		"appnexus": {Status: adapters.StatusActive, Capabilities: &adapters.CapabilitiesInfo{
+			App:  &adapters.PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner}},
+			Site: &adapters.PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner, openrtb_ext.BidTypeVideo}},
+		}},
+		"pubmatic": {Status: adapters.StatusDisabled, Capabilities: &adapters.CapabilitiesInfo{
+			Site: &adapters.PlatformInfo{MediaTypes: []openrtb_ext.BidType{openrtb_ext.BidTypeBanner}},
+		}},
+	}
+	metadata := info.Metadata{
+		Syncers: map[openrtb_ext.BidderName]usersync.Usersyncer{
+			"appnexus": adapters.NewSyncer("adnxs", 32, template.Must(template.New("sync").Parse("//sync")), adapters.SyncTypeRedirect),
+		},
+	}
+	endpoint := info.NewBiddersEndpoint(bidderInfos, map[string]string{"alias": "pubmatic"}, metadata)
+
+	testCases := []struct {
+		query    string
+		expected []string
+	}{
+		{query: "enabledonly=true", expected: []string{"appnexus"}},
+		{query: "enabledonly=false&platform=site", expected: []string{"alias", "appnexus", "pubmatic"}},
+		{query: "platform=app", expected: []string{"appnexus"}},
+		{query: "mediatype=video", expected: []string{"appnexus"}},
+		{query: "mediatype=video&platform=app", expected: []string{}},
+		{query: "mediatype=banner&platform=site", expected: []string{"alias", "appnexus", "pubmatic"}},
+		{query: "gvlid=32", expected: []string{"appnexus"}},
+	}
+	for _, test := range testCases {
+		r := httptest.NewRecorder()
+		endpoint(r, httptest.NewRequest("GET", "/info/bidders?"+test.query, nil), nil)
+		assert.Equal(t, http.StatusOK, r.Code, test.query)
+
+		var bidders []string
+		assert.NoError(t, json.Unmarshal(r.Body.Bytes(), &bidders), test.query)
+		assert.Equal(t, test.expected, bidders, test.query)
+	}
+}
+
+func TestGetBiddersBadFilters(t *testing.T) {
+	endpoint := info.NewBiddersEndpoint(adapters.BidderInfos{}, nil, info.Metadata{})
+	testCases := map[string]string{
+		"enabledonly=yes": "the enabledonly query param must be true or false",
+		"mediatype=image": "the mediatype query param must be banner, video, audio or native",
+		"platform=dooh":   "the platform query param must be app or site",
+		"gvlid=-1":        "the gvlid query param must be a Global Vendor List ID",
+	}
+	for query, message := range testCases {
+		r := httptest.NewRecorder()
+		endpoint(r, httptest.NewRequest("GET", "/info/bidders?"+query, nil), nil)
+		assert.Equal(t, http.StatusBadRequest, r.Code, query)
+		assert.Equal(t, message, r.Body.String(), query)
+	}
+}
+
+func TestGetBidderMetadata(t *testing.T) {
+	bidderInfos := adapters.BidderInfos{
+		"appnexus": {Status: adapters.StatusActive, Capabilities: &adapters.CapabilitiesInfo{}},
+	}
+	metadata := info.Metadata{
+		Adapters:    map[string]config.Adapter{"appnexus": {Endpoint: "http://ib.adnxs.com/openrtb2?query=1"}},
+		HostAliases: map[string]config.BidderAlias{"hostAlias": {Bidder: "appnexus", GVLVendorID: 99}},
+		Syncers: map[openrtb_ext.BidderName]usersync.Usersyncer{
+			"appnexus": adapters.NewSyncer("adnxs", 32, template.Must(template.New("sync").Parse("//sync")), adapters.SyncTypeRedirect),
+		},
+		ParamsValidator: schemaValidator{"appnexus": `{"type":"object"}`},
+	}
+	endpoint := info.NewBidderDetailsEndpoint(bidderInfos, map[string]string{"hostAlias": "appnexus", "defaultAlias": "appnexus"}, metadata)
+
+	details := func(bidder string) string {
+		r := httptest.NewRecorder()
+		endpoint(r, httptest.NewRequest("GET", "/info/bidders/"+bidder, nil), httprouter.Params{{Key: "bidderName", Value: bidder}})
+		return r.Body.String()
+	}
+	assert.JSONEq(t, `{"status":"ACTIVE","maintainer":null,"capabilities":{"app":null,"site":null},"endpoint":"ib.adnxs.com",
+		"aliases":["defaultAlias","hostAlias"],"gvlVendorId":32,"usersync":{"type":"redirect"},"paramsSchema":{"type":"object"}}`, details("appnexus"))
+	assert.JSONEq(t, `{"status":"ACTIVE","maintainer":null,"capabilities":{"app":null,"site":null},"aliasOf":"appnexus","endpoint":"ib.adnxs.com",
+		"gvlVendorId":99,"usersync":{"type":"redirect"},"paramsSchema":{"type":"object"}}`, details("hostAlias"))
+	assert.JSONEq(t, `{"status":"ACTIVE","maintainer":null,"capabilities":{"app":null,"site":null},"aliasOf":"appnexus","endpoint":"ib.adnxs.com",
+		"gvlVendorId":32,"usersync":{"type":"redirect"},"paramsSchema":{"type":"object"}}`, details("defaultAlias"))
+}
+
+type schemaValidator map[openrtb_ext.BidderName]string
+
+func (v schemaValidator) Validate(name openrtb_ext.BidderName, ext json.RawMessage) error {
+	return nil
+}
+
+func (v schemaValidator) Schema(name openrtb_ext.BidderName) string {
+	return v[name]
+}
+
 // TestInfoFiles makes sure that static/bidder-info contains a .yaml file for every BidderName.
 func TestInfoFiles(t *testing.T) {
 	fileInfos, err := ioutil.ReadDir("../../static/bidder-info")
@@ -256,6 +352,10 @@ func validateInfo(info *adapters.BidderInfo) error {
 		return err
 	}
 
+	if info.OpenRTB != nil && info.OpenRTB.Version != "2.5" && info.OpenRTB.Version != "2.6" {
+		return fmt.Errorf("openrtb.version must be 2.5 or 2.6. Got %s", info.OpenRTB.Version)
+	}
+
 	return nil
 }
 
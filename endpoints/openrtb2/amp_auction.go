@@ -22,6 +22,7 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy"
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy/ccpa"
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy/gdpr"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/gpp"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/empty_fetcher"
 	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
@@ -140,7 +141,10 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 	}
 	defer cancel()
 
-	usersyncs := usersync.ParsePBSCookieFromRequest(r, &(deps.cfg.HostCookie))
+	usersyncs, err := usersync.ReadPBSCookieFromRequest(r, &(deps.cfg.HostCookie))
+	if err != nil {
+		deps.metricsEngine.RecordUIDCookieError(usersync.CookieErrorReason(err))
+	}
 	if usersyncs.LiveSyncCount() == 0 {
 		labels.CookieFlag = pbsmetrics.CookieFlagNo
 	} else {
@@ -165,7 +169,18 @@ func (deps *endpointDeps) AmpAuction(w http.ResponseWriter, r *http.Request, _ h
 		return
 	}
 
-	response, err := deps.ex.HoldAuction(ctx, req, usersyncs, labels, &deps.categories)
+	storedResponses, storedErrs := deps.processStoredResponses(ctx, req)
+	if len(storedErrs) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		for _, err := range storedErrs {
+			w.Write([]byte(fmt.Sprintf("Invalid request format: %s\n", err.Error())))
+		}
+		ao.Errors = append(ao.Errors, storedErrs...)
+		labels.RequestStatus = pbsmetrics.RequestStatusBadInput
+		return
+	}
+
+	response, err := deps.ex.HoldAuction(ctx, req, usersyncs, labels, &deps.categories, storedResponses)
 	ao.AuctionResponse = response
 
 	if err != nil {
@@ -302,7 +317,11 @@ func (deps *endpointDeps) loadRequestJSONForAmp(httpRequest *http.Request) (req
 	}
 
 	// The fetched config becomes the entire OpenRTB request
-	requestJSON := storedRequests[ampID]
+	requestJSON, err := openrtb_ext.ConvertDownTo25(storedRequests[ampID])
+	if err != nil {
+		errs = []error{err}
+		return
+	}
 	if err := json.Unmarshal(requestJSON, req); err != nil {
 		errs = []error{err}
 		return
@@ -335,7 +354,7 @@ func (deps *endpointDeps) loadRequestJSONForAmp(httpRequest *http.Request) (req
 		*req.Imp[0].Secure = 1
 	}
 
-	err := deps.overrideWithParams(httpRequest, req)
+	err = deps.overrideWithParams(httpRequest, req)
 	if err != nil {
 		errs = []error{err}
 	}
@@ -389,7 +408,15 @@ func (deps *endpointDeps) overrideWithParams(httpRequest *http.Request, req *ope
 		CCPA: ccpa.Policy{
 			Value: httpRequest.URL.Query().Get("us_privacy"),
 		},
+		GPP: gpp.Policy{
+			Value: httpRequest.URL.Query().Get("gpp"),
+		},
+	}
+	gppSectionIDs, err := gpp.ParseSectionIDs(httpRequest.URL.Query().Get("gpp_sid"))
+	if err != nil {
+		return err
 	}
+	privacyPolicies.GPP.SectionIDs = gppSectionIDs
 	if err := privacyPolicies.Write(req); err != nil {
 		return err
 	}
@@ -835,6 +835,57 @@ func TestCCPANotPresent(t *testing.T) {
 	}
 }
 
+func TestGPPPresent(t *testing.T) {
+	req, err := getTestBidRequest(false, false, "", "digitrustId")
+	if err != nil {
+		t.Fatalf("Failed to marshal the complete openrtb.BidRequest object %v", err)
+	}
+
+	reqStored := map[string]json.RawMessage{
+		"1": json.RawMessage(req),
+	}
+
+	theMetrics := pbsmetrics.NewMetrics(metrics.NewRegistry(), openrtb_ext.BidderList(), config.DisabledMetrics{})
+
+	exchange := &mockAmpExchange{}
+
+	endpoint, _ := NewAmpEndpoint(
+		exchange,
+		newParamsValidator(t),
+		&mockAmpStoredReqFetcher{reqStored},
+		empty_fetcher.EmptyFetcher{},
+		&config.Configuration{MaxRequestSize: maxSize},
+		theMetrics,
+		analyticsConf.NewPBSAnalytics(&config.Analytics{}),
+		map[string]string{},
+		[]byte{},
+		openrtb_ext.BidderMap,
+	)
+
+	gppString := "DBABLA~BAAa"
+	httpReq := httptest.NewRequest("GET", "/openrtb2/auction/amp?tag_id=1&gpp="+gppString+"&gpp_sid=7,8", nil)
+	httpRecorder := httptest.NewRecorder()
+	endpoint(httpRecorder, httpReq, nil)
+
+	if !assert.NotNil(t, exchange.lastRequest, "Endpoint responded with %d: %s", httpRecorder.Code, httpRecorder.Body.String()) {
+		return
+	}
+	if !assert.NotNil(t, exchange.lastRequest.Regs) {
+		return
+	}
+
+	var regs openrtb_ext.ExtRegs
+	err = json.Unmarshal(exchange.lastRequest.Regs.Ext, &regs)
+	assert.NoError(t, err)
+	assert.Equal(t, gppString, regs.GPP)
+	assert.Equal(t, []int8{7, 8}, regs.GPPSID)
+
+	exchange.lastRequest = nil
+	httpRecorder = httptest.NewRecorder()
+	endpoint(httpRecorder, httptest.NewRequest("GET", "/openrtb2/auction/amp?tag_id=1&gpp_sid=usnat", nil), nil)
+	assert.Nil(t, exchange.lastRequest, "A bad gpp_sid should be rejected")
+}
+
 type formatOverrideSpec struct {
 	width          uint64
 	height         uint64
@@ -902,7 +953,7 @@ type mockAmpExchange struct {
 	lastRequest *openrtb.BidRequest
 }
 
-func (m *mockAmpExchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidRequest, ids exchange.IdFetcher, labels pbsmetrics.Labels, categoriesFetcher *stored_requests.CategoryFetcher) (*openrtb.BidResponse, error) {
+func (m *mockAmpExchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidRequest, ids exchange.IdFetcher, labels pbsmetrics.Labels, categoriesFetcher *stored_requests.CategoryFetcher, storedResponses *exchange.StoredResponses) (*openrtb.BidResponse, error) {
 	m.lastRequest = bidRequest
 
 	response := &openrtb.BidResponse{
@@ -1,6 +1,7 @@
 package openrtb2
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -10,6 +11,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/PubMatic-OpenWrap/openrtb"
@@ -23,6 +25,7 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
 	"github.com/PubMatic-OpenWrap/prebid-server/prebid"
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy/ccpa"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/gpp"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/empty_fetcher"
 	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
@@ -116,7 +119,10 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		defer cancel()
 	}
 
-	usersyncs := usersync.ParsePBSCookieFromRequest(r, &(deps.cfg.HostCookie))
+	usersyncs, err := usersync.ReadPBSCookieFromRequest(r, &(deps.cfg.HostCookie))
+	if err != nil {
+		deps.metricsEngine.RecordUIDCookieError(usersync.CookieErrorReason(err))
+	}
 	if req.App != nil {
 		labels.Source = pbsmetrics.DemandApp
 		labels.RType = pbsmetrics.ReqTypeORTB2App
@@ -137,7 +143,12 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 		return
 	}
 
-	response, err := deps.ex.HoldAuction(ctx, req, usersyncs, labels, &deps.categories)
+	storedResponses, storedErrs := deps.processStoredResponses(ctx, req)
+	if writeError(storedErrs, w, &labels) {
+		return
+	}
+
+	response, err := deps.ex.HoldAuction(ctx, req, usersyncs, labels, &deps.categories, storedResponses)
 	ao.Request = req
 	ao.Response = response
 	if err != nil {
@@ -151,16 +162,34 @@ func (deps *endpointDeps) Auction(w http.ResponseWriter, r *http.Request, _ http
 	}
 
 	// Fixes #231
-	enc := json.NewEncoder(w)
+	buffer := &bytes.Buffer{}
+	enc := json.NewEncoder(buffer)
 	enc.SetEscapeHTML(false)
+	if err := enc.Encode(response); err != nil {
+		labels.RequestStatus = pbsmetrics.RequestStatusErr
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "Critical error while writing the response: %v", err)
+		ao.Status = http.StatusInternalServerError
+		ao.Errors = append(ao.Errors, err)
+		return
+	}
+
+	// The openrtb types can't hold the OpenRTB 2.6 fields of the bids, so they're moved out of bid.ext.bidder.
+	responseJSON, err := openrtb_ext.ConvertResponseUpTo26(buffer.Bytes())
+	if err != nil {
+		ao.Errors = append(ao.Errors, fmt.Errorf("/openrtb2/auction Failed to convert the response to OpenRTB 2.6: %v", err))
+		responseJSON = buffer.Bytes()
+	} else if !bytes.HasSuffix(responseJSON, []byte("\n")) {
+		responseJSON = append(responseJSON, '\n')
+	}
 
 	// Fixes #328
 	w.Header().Set("Content-Type", "application/json")
 
-	// If an error happens when encoding the response, there isn't much we can do.
+	// If an error happens when writing the response, there isn't much we can do.
 	// If we've sent _any_ bytes, then Go would have sent the 200 status code first.
 	// That status code can't be un-sent... so the best we can do is log the error.
-	if err := enc.Encode(response); err != nil {
+	if _, err := w.Write(responseJSON); err != nil {
 		labels.RequestStatus = pbsmetrics.RequestStatusNetworkErr
 		ao.Errors = append(ao.Errors, fmt.Errorf("/openrtb2/auction Failed to send response: %v", err))
 	}
@@ -207,6 +236,12 @@ func (deps *endpointDeps) parseRequest(httpRequest *http.Request) (req *openrtb.
 		return
 	}
 
+	// The openrtb types can't hold the OpenRTB 2.6 fields, so they're read from their 2.5 ext locations.
+	if requestJson, err = openrtb_ext.ConvertDownTo25(requestJson); err != nil {
+		errs = []error{err}
+		return
+	}
+
 	if err := json.Unmarshal(requestJson, req); err != nil {
 		errs = []error{err}
 		return
@@ -262,19 +297,9 @@ func (deps *endpointDeps) validateRequest(req *openrtb.BidRequest) []error {
 		errL = append(errL, &errortypes.Warning{Message: fmt.Sprintf("A prebid request can only process one currency. Taking the first currency in the list, %s, as the active currency", req.Cur[0])})
 	}
 
-	var aliases map[string]string
-	if bidExt, err := deps.parseBidExt(req.Ext); err != nil {
+	aliases, err := deps.validateBidExt(req.Ext)
+	if err != nil {
 		return []error{err}
-	} else if bidExt != nil {
-		aliases = bidExt.Prebid.Aliases
-
-		if err := deps.validateAliases(aliases); err != nil {
-			return []error{err}
-		}
-
-		if err := validateBidAdjustmentFactors(bidExt.Prebid.BidAdjustmentFactors, aliases); err != nil {
-			return []error{err}
-		}
 	}
 
 	if (req.Site == nil && req.App == nil) || (req.Site != nil && req.App != nil) {
@@ -312,6 +337,16 @@ func (deps *endpointDeps) validateRequest(req *openrtb.BidRequest) []error {
 		errL = append(errL, &errortypes.Warning{Message: fmt.Sprintf("CCPA value is invalid and will be ignored. (%s)", err.Error())})
 	}
 
+	gppPolicy, gppPolicyErr := gpp.ReadPolicy(req)
+	if gppPolicyErr != nil {
+		errL = append(errL, gppPolicyErr)
+		return errL
+	}
+
+	if err := gppPolicy.Validate(); err != nil {
+		errL = append(errL, &errortypes.Warning{Message: fmt.Sprintf("GPP value is invalid and will be ignored. (%s)", err.Error())})
+	}
+
 	impIDs := make(map[string]int, len(req.Imp))
 	for index := range req.Imp {
 		imp := &req.Imp[index]
@@ -331,6 +366,107 @@ func (deps *endpointDeps) validateRequest(req *openrtb.BidRequest) []error {
 	return errL
 }
 
+// validateBidExt checks request.ext, and returns the aliases which it defines.
+func (deps *endpointDeps) validateBidExt(ext json.RawMessage) (map[string]string, error) {
+	bidExt, err := deps.parseBidExt(ext)
+	if err != nil {
+		return nil, err
+	}
+	if bidExt == nil {
+		return deps.withHostAliases(nil), nil
+	}
+
+	if err := deps.validateAliases(bidExt.Prebid.Aliases); err != nil {
+		return nil, err
+	}
+	aliases := deps.withHostAliases(bidExt.Prebid.Aliases)
+
+	if err := validateAliasGVLIDs(bidExt.Prebid.AliasGVLIDs, aliases); err != nil {
+		return nil, err
+	}
+
+	if err := validateBidAdjustmentFactors(bidExt.Prebid.BidAdjustmentFactors, aliases); err != nil {
+		return nil, err
+	}
+
+	if err := validateFirstPartyData(&bidExt.Prebid, aliases); err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// validateFirstPartyData checks request.ext.prebid.data.bidders and request.ext.prebid.bidderconfig.
+func validateFirstPartyData(prebid *openrtb_ext.ExtRequestPrebid, aliases map[string]string) error {
+	if prebid.Data != nil {
+		for _, bidder := range prebid.Data.Bidders {
+			if !isBidderOrAlias(bidder, aliases) {
+				return fmt.Errorf("request.ext.prebid.data.bidders contains %s, which is not a known bidder or alias", bidder)
+			}
+		}
+		if err := validateEidPermissions(prebid.Data.EidPermissions, aliases); err != nil {
+			return err
+		}
+	}
+
+	for i, bidderConfig := range prebid.BidderConfigs {
+		if len(bidderConfig.Bidders) == 0 {
+			return fmt.Errorf("request.ext.prebid.bidderconfig[%d].bidders must contain at least one bidder", i)
+		}
+		for _, bidder := range bidderConfig.Bidders {
+			if !isBidderOrAlias(bidder, aliases) {
+				return fmt.Errorf("request.ext.prebid.bidderconfig[%d].bidders contains %s, which is not a known bidder or alias", i, bidder)
+			}
+		}
+		if bidderConfig.Config == nil || bidderConfig.Config.ORTB2 == nil {
+			return fmt.Errorf("request.ext.prebid.bidderconfig[%d].config.ortb2 is required", i)
+		}
+		ortb2 := bidderConfig.Config.ORTB2
+		names := []string{"site", "app", "user"}
+		for j, value := range []json.RawMessage{ortb2.Site, ortb2.App, ortb2.User} {
+			if len(value) == 0 {
+				continue
+			}
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal(value, &object); err != nil {
+				return fmt.Errorf("request.ext.prebid.bidderconfig[%d].config.ortb2.%s must be an object", i, names[j])
+			}
+		}
+	}
+	return nil
+}
+
+func validateEidPermissions(permissions []openrtb_ext.ExtRequestPrebidDataEidPermission, aliases map[string]string) error {
+	uniqueSources := make(map[string]struct{}, len(permissions))
+	for i, permission := range permissions {
+		source := strings.ToLower(strings.TrimSpace(permission.Source))
+		if source == "" {
+			return fmt.Errorf("request.ext.prebid.data.eidpermissions[%d] missing required field: \"source\"", i)
+		}
+		if _, ok := uniqueSources[source]; ok {
+			return fmt.Errorf("request.ext.prebid.data.eidpermissions must contain unique sources")
+		}
+		uniqueSources[source] = struct{}{}
+
+		if len(permission.Bidders) == 0 {
+			return fmt.Errorf("request.ext.prebid.data.eidpermissions[%d].bidders must contain at least one bidder", i)
+		}
+		for _, bidder := range permission.Bidders {
+			if bidder != "*" && !isBidderOrAlias(bidder, aliases) {
+				return fmt.Errorf("request.ext.prebid.data.eidpermissions[%d].bidders contains %s, which is not a known bidder or alias", i, bidder)
+			}
+		}
+	}
+	return nil
+}
+
+func isBidderOrAlias(bidder string, aliases map[string]string) bool {
+	if _, isBidder := openrtb_ext.BidderMap[bidder]; isBidder {
+		return true
+	}
+	_, isAlias := aliases[bidder]
+	return isAlias
+}
+
 func validateBidAdjustmentFactors(adjustmentFactors map[string]float64, aliases map[string]string) error {
 	for bidderToAdjust, adjustmentFactor := range adjustmentFactors {
 		if adjustmentFactor <= 0 {
@@ -362,8 +498,8 @@ func (deps *endpointDeps) validateImp(imp *openrtb.Imp, aliases map[string]strin
 		return []error{err}
 	}
 
-	if imp.Video != nil && len(imp.Video.MIMEs) < 1 {
-		return []error{fmt.Errorf("request.imp[%d].video.mimes must contain at least one supported MIME type", index)}
+	if err := validateVideo(imp.Video, index); err != nil {
+		return []error{err}
 	}
 
 	if imp.Audio != nil && len(imp.Audio.MIMEs) < 1 {
@@ -386,6 +522,44 @@ func (deps *endpointDeps) validateImp(imp *openrtb.Imp, aliases map[string]strin
 	return nil
 }
 
+func validateVideo(video *openrtb.Video, impIndex int) error {
+	if video == nil {
+		return nil
+	}
+	if len(video.MIMEs) < 1 {
+		return fmt.Errorf("request.imp[%d].video.mimes must contain at least one supported MIME type", impIndex)
+	}
+	if len(video.Ext) == 0 {
+		return nil
+	}
+
+	var videoExt openrtb_ext.ExtVideo
+	if err := json.Unmarshal(video.Ext, &videoExt); err != nil {
+		return fmt.Errorf("request.imp[%d].video.ext is invalid: %v", impIndex, err)
+	}
+	if videoExt.PodSeq < -1 || videoExt.PodSeq > 1 {
+		return fmt.Errorf("request.imp[%d].video.podseq must be -1, 0 or 1. Got %d", impIndex, videoExt.PodSeq)
+	}
+	if videoExt.SlotInPod < -1 || videoExt.SlotInPod > 2 {
+		return fmt.Errorf("request.imp[%d].video.slotinpod must be -1, 0, 1 or 2. Got %d", impIndex, videoExt.SlotInPod)
+	}
+	if videoExt.MaxSeq < 0 {
+		return fmt.Errorf("request.imp[%d].video.maxseq must be nonnegative. Got %d", impIndex, videoExt.MaxSeq)
+	}
+	if videoExt.PodDur < 0 {
+		return fmt.Errorf("request.imp[%d].video.poddur must be nonnegative. Got %d", impIndex, videoExt.PodDur)
+	}
+	for durIndex, duration := range videoExt.RqdDurs {
+		if duration <= 0 {
+			return fmt.Errorf("request.imp[%d].video.rqddurs[%d] must be positive. Got %d", impIndex, durIndex, duration)
+		}
+	}
+	if len(videoExt.RqdDurs) > 0 && (video.MinDuration != 0 || video.MaxDuration != 0) {
+		return fmt.Errorf("request.imp[%d].video.rqddurs can't be used with minduration or maxduration", impIndex)
+	}
+	return nil
+}
+
 func validateBanner(banner *openrtb.Banner, impIndex int) error {
 	if banner == nil {
 		return nil
@@ -800,6 +974,33 @@ func (deps *endpointDeps) validateAliases(aliases map[string]string) error {
 	return nil
 }
 
+// withHostAliases adds the host's bidder_aliases to the request's aliases. The request's own aliases win if the names clash.
+func (deps *endpointDeps) withHostAliases(aliases map[string]string) map[string]string {
+	if deps.cfg == nil || len(deps.cfg.BidderAliases) == 0 {
+		return aliases
+	}
+	merged := make(map[string]string, len(aliases)+len(deps.cfg.BidderAliases))
+	for aliasName, alias := range deps.cfg.BidderAliases {
+		merged[aliasName] = alias.Bidder
+	}
+	for aliasName, coreBidder := range aliases {
+		merged[aliasName] = coreBidder
+	}
+	return merged
+}
+
+func validateAliasGVLIDs(aliasGVLIDs map[string]uint16, aliases map[string]string) error {
+	for aliasName, vendorID := range aliasGVLIDs {
+		if _, isAlias := aliases[aliasName]; !isAlias {
+			return fmt.Errorf("request.ext.prebid.aliasgvlids. vendorId %d refers to unknown bidder alias: %s", vendorID, aliasName)
+		}
+		if vendorID < 1 {
+			return fmt.Errorf("request.ext.prebid.aliasgvlids. Invalid vendorId %d for alias: %s. Choose a different vendorId, or remove this entry.", vendorID, aliasName)
+		}
+	}
+	return nil
+}
+
 func (deps *endpointDeps) validateSite(site *openrtb.Site) error {
 	if site == nil {
 		return nil
@@ -869,25 +1070,24 @@ func validateUser(user *openrtb.User, aliases map[string]string) error {
 				}
 				uniqueSources := make(map[string]struct{}, len(userExt.Eids))
 				for eidIndex, eid := range userExt.Eids {
-					if eid.Source == "" {
+					source := strings.ToLower(strings.TrimSpace(eid.Source))
+					if source == "" {
 						return fmt.Errorf("request.user.ext.eids[%d] missing required field: \"source\"", eidIndex)
 					}
-					if _, ok := uniqueSources[eid.Source]; ok {
+					if _, ok := uniqueSources[source]; ok {
 						return fmt.Errorf("request.user.ext.eids must contain unique sources")
 					}
-					uniqueSources[eid.Source] = struct{}{}
+					uniqueSources[source] = struct{}{}
 
 					if eid.ID == "" && eid.Uids == nil {
 						return fmt.Errorf("request.user.ext.eids[%d] must contain either \"id\" or \"uids\" field", eidIndex)
 					}
-					if eid.ID == "" {
-						if len(eid.Uids) == 0 {
-							return fmt.Errorf("request.user.ext.eids[%d].uids must contain at least one element or be undefined", eidIndex)
-						}
-						for uidIndex, uid := range eid.Uids {
-							if uid.ID == "" {
-								return fmt.Errorf("request.user.ext.eids[%d].uids[%d] missing required field: \"id\"", eidIndex, uidIndex)
-							}
+					if eid.ID == "" && len(eid.Uids) == 0 {
+						return fmt.Errorf("request.user.ext.eids[%d].uids must contain at least one element or be undefined", eidIndex)
+					}
+					for uidIndex, uid := range eid.Uids {
+						if uid.ID == "" {
+							return fmt.Errorf("request.user.ext.eids[%d].uids[%d] missing required field: \"id\"", eidIndex, uidIndex)
 						}
 					}
 				}
@@ -14,6 +14,7 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/exchange"
 	"github.com/PubMatic-OpenWrap/prebid-server/gdpr"
+	"github.com/PubMatic-OpenWrap/prebid-server/geolocation"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
 	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/empty_fetcher"
@@ -78,6 +79,7 @@ func BenchmarkOpenrtbEndpoint(b *testing.B) {
 			infos,
 			gdpr.AlwaysAllow{},
 			currencies.NewRateConverterDefault(),
+			geolocation.NilGeoLocation{},
 		),
 		paramValidator,
 		empty_fetcher.EmptyFetcher{},
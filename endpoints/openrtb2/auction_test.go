@@ -709,6 +709,39 @@ func TestNoEncoding(t *testing.T) {
 	}
 }
 
+// TestOpenRTB26Response makes sure the OpenRTB 2.6 fields of the bids are moved out of bid.ext.bidder.
+func TestOpenRTB26Response(t *testing.T) {
+	endpoint, _ := NewEndpoint(
+		&mockExchange{},
+		newParamsValidator(t),
+		&mockStoredReqFetcher{},
+		empty_fetcher.EmptyFetcher{},
+		&config.Configuration{MaxRequestSize: maxSize},
+		pbsmetrics.NewMetrics(metrics.NewRegistry(), openrtb_ext.BidderList(), config.DisabledMetrics{}),
+		analyticsConf.NewPBSAnalytics(&config.Analytics{}),
+		map[string]string{},
+		[]byte{},
+		openrtb_ext.BidderMap,
+	)
+	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(validRequest(t, "site.json")))
+	recorder := httptest.NewRecorder()
+	endpoint(recorder, request, nil)
+
+	var response struct {
+		SeatBid []struct {
+			Bid []struct {
+				MType int             `json:"mtype"`
+				Ext   json.RawMessage `json:"ext"`
+			} `json:"bid"`
+		} `json:"seatbid"`
+	}
+	if assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response)) && assert.Len(t, response.SeatBid, 1) && assert.Len(t, response.SeatBid[0].Bid, 1) {
+		assert.Equal(t, 1, response.SeatBid[0].Bid[0].MType)
+		assert.JSONEq(t, `{"prebid":{"type":"banner"}}`, string(response.SeatBid[0].Bid[0].Ext))
+	}
+	assert.True(t, strings.HasSuffix(recorder.Body.String(), "\n"), "The response should end with a newline, as it did before")
+}
+
 // TestTimeoutParser makes sure we parse tmax properly.
 func TestTimeoutParser(t *testing.T) {
 	reqJson := json.RawMessage(`{"tmax":22}`)
@@ -846,6 +879,29 @@ func TestValidateImpExtDisabledBidder(t *testing.T) {
 	assert.Equal(t, []error{&errortypes.BidderTemporarilyDisabled{Message: "The biddder 'unknownbidder' has been disabled."}}, errs)
 }
 
+func TestValidateVideo(t *testing.T) {
+	testCases := []struct {
+		ext      string
+		expected string
+	}{
+		{ext: `{"podid":"1","podseq":-1,"slotinpod":2,"maxseq":3,"poddur":60,"rqddurs":[15,30]}`},
+		{ext: `{"podseq":2}`, expected: "request.imp[0].video.podseq must be -1, 0 or 1. Got 2"},
+		{ext: `{"slotinpod":-2}`, expected: "request.imp[0].video.slotinpod must be -1, 0, 1 or 2. Got -2"},
+		{ext: `{"maxseq":-1}`, expected: "request.imp[0].video.maxseq must be nonnegative. Got -1"},
+		{ext: `{"poddur":-1}`, expected: "request.imp[0].video.poddur must be nonnegative. Got -1"},
+		{ext: `{"rqddurs":[15,0]}`, expected: "request.imp[0].video.rqddurs[1] must be positive. Got 0"},
+		{ext: `{"podid":1}`, expected: "request.imp[0].video.ext is invalid: json: cannot unmarshal number into Go struct field ExtVideo.podid of type string"},
+	}
+	for _, test := range testCases {
+		err := validateVideo(&openrtb.Video{MIMEs: []string{"video/mp4"}, Ext: json.RawMessage(test.ext)}, 0)
+		if test.expected == "" {
+			assert.NoError(t, err, test.ext)
+		} else {
+			assert.EqualError(t, err, test.expected, test.ext)
+		}
+	}
+}
+
 func TestEffectivePubID(t *testing.T) {
 	var pub openrtb.Publisher
 	assert.Equal(t, pbsmetrics.PublisherUnknown, effectivePubID(nil), "effectivePubID failed for nil Publisher.")
@@ -948,13 +1004,58 @@ func TestCCPAInvalidValueWarning(t *testing.T) {
 	assert.ElementsMatch(t, errL, []error{&expectedError})
 }
 
+func TestGPPInvalidValueWarning(t *testing.T) {
+	deps := &endpointDeps{
+		&nobidExchange{},
+		newParamsValidator(t),
+		&mockStoredReqFetcher{},
+		empty_fetcher.EmptyFetcher{},
+		empty_fetcher.EmptyFetcher{},
+		&config.Configuration{},
+		pbsmetrics.NewMetrics(metrics.NewRegistry(), openrtb_ext.BidderList(), config.DisabledMetrics{}),
+		analyticsConf.NewPBSAnalytics(&config.Analytics{}),
+		map[string]string{},
+		false,
+		[]byte{},
+		openrtb_ext.BidderMap,
+	}
+
+	ui := uint64(1)
+	req := openrtb.BidRequest{
+		ID: "someID",
+		Imp: []openrtb.Imp{
+			{
+				ID: "imp-ID",
+				Banner: &openrtb.Banner{
+					W: &ui,
+					H: &ui,
+				},
+				Ext: json.RawMessage("{\"appnexus\": {\"placementId\": 5667}}"),
+			},
+		},
+		Site: &openrtb.Site{
+			ID: "myID",
+		},
+		Regs: &openrtb.Regs{
+			Ext: json.RawMessage("{\"gpp\":\"DBACNYA~only-one-section\",\"gpp_sid\":[2,6]}"),
+		},
+	}
+
+	errL := deps.validateRequest(&req)
+
+	expectedError := errortypes.Warning{Message: "GPP value is invalid and will be ignored. (the GPP header doesn't match the number of sections)"}
+	assert.ElementsMatch(t, errL, []error{&expectedError})
+}
+
 // nobidExchange is a well-behaved exchange which always bids "no bid".
 type nobidExchange struct {
-	gotRequest *openrtb.BidRequest
+	gotRequest         *openrtb.BidRequest
+	gotStoredResponses *exchange.StoredResponses
 }
 
-func (e *nobidExchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidRequest, ids exchange.IdFetcher, labels pbsmetrics.Labels, categoriesFetcher *stored_requests.CategoryFetcher) (*openrtb.BidResponse, error) {
+func (e *nobidExchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidRequest, ids exchange.IdFetcher, labels pbsmetrics.Labels, categoriesFetcher *stored_requests.CategoryFetcher, storedResponses *exchange.StoredResponses) (*openrtb.BidResponse, error) {
 	e.gotRequest = bidRequest
+	e.gotStoredResponses = storedResponses
 	return &openrtb.BidResponse{
 		ID:    bidRequest.ID,
 		BidID: "test bid id",
@@ -964,7 +1065,7 @@ func (e *nobidExchange) HoldAuction(ctx context.Context, bidRequest *openrtb.Bid
 
 type brokenExchange struct{}
 
-func (e *brokenExchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidRequest, ids exchange.IdFetcher, labels pbsmetrics.Labels, categoriesFetcher *stored_requests.CategoryFetcher) (*openrtb.BidResponse, error) {
+func (e *brokenExchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidRequest, ids exchange.IdFetcher, labels pbsmetrics.Labels, categoriesFetcher *stored_requests.CategoryFetcher, storedResponses *exchange.StoredResponses) (*openrtb.BidResponse, error) {
 	return nil, errors.New("Critical, unrecoverable error.")
 }
 
@@ -1324,12 +1425,13 @@ type mockExchange struct {
 	lastRequest *openrtb.BidRequest
 }
 
-func (m *mockExchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidRequest, ids exchange.IdFetcher, labels pbsmetrics.Labels, categoriesFetcher *stored_requests.CategoryFetcher) (*openrtb.BidResponse, error) {
+func (m *mockExchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidRequest, ids exchange.IdFetcher, labels pbsmetrics.Labels, categoriesFetcher *stored_requests.CategoryFetcher, storedResponses *exchange.StoredResponses) (*openrtb.BidResponse, error) {
 	m.lastRequest = bidRequest
 	return &openrtb.BidResponse{
 		SeatBid: []openrtb.SeatBid{{
 			Bid: []openrtb.Bid{{
 				AdM: "<script></script>",
+				Ext: json.RawMessage(`{"bidder":{"mtype":1},"prebid":{"type":"banner"}}`),
 			}},
 		}},
 	}, nil
@@ -0,0 +1,134 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+)
+
+// These stand in for the required fields which a Stored Request or Imp can leave to the HTTP request.
+const (
+	storedDataPlaceholderID   = "stored-data-validation"
+	storedDataPlaceholderPage = "https://stored-data-validation.invalid/"
+)
+
+// StoredDataValidator checks Stored Requests and Imps with the same rules which the endpoints apply to
+// the requests they receive, so that bad data can be rejected before it gets saved.
+//
+// Each method returns the errors which would fail the auction. Warnings are left out.
+type StoredDataValidator struct {
+	deps *endpointDeps
+}
+
+// NewStoredDataValidator builds a StoredDataValidator which checks the bidder params with validator.
+func NewStoredDataValidator(validator openrtb_ext.BidderParamValidator, cfg *config.Configuration, bidderMap map[string]openrtb_ext.BidderName) *StoredDataValidator {
+	return &StoredDataValidator{
+		deps: &endpointDeps{
+			paramsValidator: validator,
+			cfg:             cfg,
+			bidderMap:       bidderMap,
+		},
+	}
+}
+
+// ValidateRequest checks a Stored Request for /openrtb2/auction.
+//
+// The HTTP request gets merged on top of the Stored Request, so a missing ID is allowed. If the Stored Request
+// has no Imps, they must all come from the HTTP request and only the request-level fields get checked.
+func (v *StoredDataValidator) ValidateRequest(data json.RawMessage) []error {
+	req := &openrtb.BidRequest{}
+	if err := json.Unmarshal(data, req); err != nil {
+		return []error{err}
+	}
+	if req.ID == "" {
+		req.ID = storedDataPlaceholderID
+	}
+	if len(req.Imp) > 0 {
+		return fatalErrors(v.deps.validateRequest(req))
+	}
+
+	if _, err := v.deps.validateBidExt(req.Ext); err != nil {
+		return []error{err}
+	}
+	if req.Site != nil && req.App != nil {
+		return []error{errors.New("request.site or request.app must be defined, but not both.")}
+	}
+	if err := v.deps.validateApp(req.App); err != nil {
+		return []error{err}
+	}
+	return nil
+}
+
+// ValidateImp checks a Stored Imp. The ID may be left to the HTTP request.
+func (v *StoredDataValidator) ValidateImp(data json.RawMessage) []error {
+	imp := &openrtb.Imp{}
+	if err := json.Unmarshal(data, imp); err != nil {
+		return []error{err}
+	}
+	if imp.ID == "" {
+		imp.ID = storedDataPlaceholderID
+	}
+	return fatalErrors(v.deps.validateImp(imp, nil, 0))
+}
+
+// ValidateAmpRequest checks an AMP config, which is the entire OpenRTB request for its tag_id.
+// The site page is allowed to be missing, since /openrtb2/amp can fill it in from the HTTP request.
+func (v *StoredDataValidator) ValidateAmpRequest(data json.RawMessage) []error {
+	req := &openrtb.BidRequest{}
+	if err := json.Unmarshal(data, req); err != nil {
+		return []error{err}
+	}
+	if len(req.Imp) != 1 {
+		return []error{fmt.Errorf("AMP configs must include exactly one imp element. Got %d", len(req.Imp))}
+	}
+	if req.App != nil {
+		return []error{errors.New("request.app must not exist in AMP stored requests.")}
+	}
+	if req.Site == nil {
+		req.Site = &openrtb.Site{}
+	}
+	if req.Site.ID == "" && req.Site.Page == "" {
+		req.Site.Page = storedDataPlaceholderPage
+	}
+	return fatalErrors(v.deps.validateRequest(req))
+}
+
+// ValidateVideoRequest checks a Stored Request for /openrtb2/video.
+//
+// The HTTP request gets merged on top of the Stored Request, so only the pods and the site or app
+// which it defines get checked.
+func (v *StoredDataValidator) ValidateVideoRequest(data json.RawMessage) []error {
+	req := &openrtb_ext.BidRequestVideo{}
+	if err := json.Unmarshal(data, req); err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	_, podErrors := v.deps.validateVideoRequest(req)
+	for _, podError := range podErrors {
+		for _, message := range podError.ErrMsgs {
+			errs = append(errs, errors.New(message))
+		}
+	}
+	if req.Site != nil && req.App != nil {
+		errs = append(errs, errors.New("request.site or request.app must be defined, but not both"))
+	} else if err := v.deps.validateApp(req.App); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// fatalErrors drops the errors which don't fail the auction.
+func fatalErrors(errL []error) []error {
+	var fatal []error
+	for _, err := range errL {
+		if fatalError([]error{err}) {
+			fatal = append(fatal, err)
+		}
+	}
+	return fatal
+}
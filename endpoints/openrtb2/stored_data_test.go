@@ -0,0 +1,63 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStoredDataValidator(t *testing.T) *StoredDataValidator {
+	return NewStoredDataValidator(newParamsValidator(t), &config.Configuration{BlacklistedAppMap: map[string]bool{"spam_app": true}}, openrtb_ext.BidderMap)
+}
+
+func TestValidateStoredRequest(t *testing.T) {
+	v := newTestStoredDataValidator(t)
+	testCases := []struct {
+		description string
+		data        string
+		valid       bool
+	}{
+		{"Complete request", `{"id":"req","site":{"page":"prebid.org"},"imp":[{"id":"imp","banner":{"format":[{"w":300,"h":250}]},"ext":{"appnexus":{"placementId":12883451}}}]}`, true},
+		{"Request-level fields only", `{"tmax":500,"ext":{"prebid":{"aliases":{"foo":"appnexus"}}}}`, true},
+		{"Invalid JSON", `{"id":`, false},
+		{"Bad bidder params", `{"site":{"page":"prebid.org"},"imp":[{"id":"imp","banner":{"format":[{"w":300,"h":250}]},"ext":{"appnexus":{"placementId":"bad"}}}]}`, false},
+		{"Alias of an unknown bidder", `{"ext":{"prebid":{"aliases":{"foo":"unknown"}}}}`, false},
+		{"Site and app", `{"site":{"page":"prebid.org"},"app":{"id":"app"}}`, false},
+		{"Blacklisted app", `{"app":{"id":"spam_app"}}`, false},
+	}
+	for _, test := range testCases {
+		errs := v.ValidateRequest(json.RawMessage(test.data))
+		assert.Equal(t, test.valid, len(errs) == 0, "%s: %v", test.description, errs)
+	}
+}
+
+func TestValidateStoredImp(t *testing.T) {
+	v := newTestStoredDataValidator(t)
+
+	assert.Empty(t, v.ValidateImp(json.RawMessage(`{"banner":{"format":[{"w":300,"h":250}]},"ext":{"appnexus":{"placementId":12883451}}}`)))
+	assert.NotEmpty(t, v.ValidateImp(json.RawMessage(`{"banner":{"format":[{"w":300,"h":250}]},"ext":{"appnexus":{}}}`)), "The bidder params should be validated")
+	assert.NotEmpty(t, v.ValidateImp(json.RawMessage(`{"ext":{"appnexus":{"placementId":12883451}}}`)), "An imp needs a media type")
+	assert.NotEmpty(t, v.ValidateImp(json.RawMessage(`[]`)))
+}
+
+func TestValidateStoredAmpRequest(t *testing.T) {
+	v := newTestStoredDataValidator(t)
+	imp := `{"id":"imp","banner":{"format":[{"w":300,"h":250}]},"ext":{"appnexus":{"placementId":12883451}}}`
+
+	assert.Empty(t, v.ValidateAmpRequest(json.RawMessage(`{"id":"req","imp":[`+imp+`]}`)))
+	assert.NotEmpty(t, v.ValidateAmpRequest(json.RawMessage(`{"imp":[`+imp+`]}`)), "AMP configs need an ID")
+	assert.NotEmpty(t, v.ValidateAmpRequest(json.RawMessage(`{"id":"req","imp":[`+imp+`,`+imp+`]}`)), "AMP configs need exactly one imp")
+	assert.NotEmpty(t, v.ValidateAmpRequest(json.RawMessage(`{"id":"req","app":{"id":"app"},"imp":[`+imp+`]}`)), "AMP configs can't have an app")
+}
+
+func TestValidateStoredVideoRequest(t *testing.T) {
+	v := newTestStoredDataValidator(t)
+
+	assert.Empty(t, v.ValidateVideoRequest(json.RawMessage(`{"podconfig":{"pods":[{"podid":1,"adpoddurationsec":30,"configid":"fba10607-0c12-43d1-ad07-b8a513bc75d6"}]},"site":{"page":"prebid.org"}}`)))
+	assert.NotEmpty(t, v.ValidateVideoRequest(json.RawMessage(`{"podconfig":{"pods":[{"podid":1,"adpoddurationsec":30}]}}`)), "Pods need a config ID")
+	assert.NotEmpty(t, v.ValidateVideoRequest(json.RawMessage(`{"app":{"id":"spam_app"}}`)))
+	assert.NotEmpty(t, v.ValidateVideoRequest(json.RawMessage(`{"podconfig":"bad"}`)))
+}
@@ -0,0 +1,121 @@
+package openrtb2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/exchange"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
+)
+
+// processStoredResponses fetches the Stored Responses which the imps ask for through imp.ext.prebid.storedauctionresponse
+// and imp.ext.prebid.storedbidresponse. It returns nil if no imp asks for one.
+//
+// The request must have been validated already, so that each imp.ext holds its bidders at the top level.
+func (deps *endpointDeps) processStoredResponses(ctx context.Context, req *openrtb.BidRequest) (*exchange.StoredResponses, []error) {
+	auctionResponseIDs := make(map[string]string)
+	bidResponseIDs := make(map[string]map[openrtb_ext.BidderName]string)
+	var ids []string
+	seen := make(map[string]struct{})
+	addID := func(id string) {
+		if _, ok := seen[id]; !ok {
+			seen[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+
+	for i, imp := range req.Imp {
+		var impExt map[string]json.RawMessage
+		if err := json.Unmarshal(imp.Ext, &impExt); err != nil {
+			return nil, []error{err}
+		}
+		rawPrebidExt, ok := impExt[openrtb_ext.PrebidExtKey]
+		if !ok {
+			continue
+		}
+		var prebidExt openrtb_ext.ExtImpPrebid
+		if err := json.Unmarshal(rawPrebidExt, &prebidExt); err != nil {
+			return nil, []error{fmt.Errorf("request.imp[%d].ext.prebid is invalid: %v", i, err)}
+		}
+
+		if prebidExt.StoredAuctionResponse != nil {
+			if prebidExt.StoredAuctionResponse.ID == "" {
+				return nil, []error{fmt.Errorf("request.imp[%d].ext.prebid.storedauctionresponse.id is required", i)}
+			}
+			if len(prebidExt.StoredBidResponse) > 0 {
+				return nil, []error{fmt.Errorf("request.imp[%d].ext.prebid must not define both storedauctionresponse and storedbidresponse", i)}
+			}
+			auctionResponseIDs[imp.ID] = prebidExt.StoredAuctionResponse.ID
+			addID(prebidExt.StoredAuctionResponse.ID)
+		}
+
+		for j, bidResponse := range prebidExt.StoredBidResponse {
+			if bidResponse.Bidder == "" || bidResponse.ID == "" {
+				return nil, []error{fmt.Errorf("request.imp[%d].ext.prebid.storedbidresponse[%d] must define a bidder and an id", i, j)}
+			}
+			if _, isImpBidder := impExt[bidResponse.Bidder]; !isImpBidder || bidResponse.Bidder == openrtb_ext.PrebidExtKey {
+				return nil, []error{fmt.Errorf("request.imp[%d].ext.prebid.storedbidresponse[%d].bidder %s is not one of the imp's bidders", i, j, bidResponse.Bidder)}
+			}
+			if bidResponseIDs[imp.ID] == nil {
+				bidResponseIDs[imp.ID] = make(map[openrtb_ext.BidderName]string)
+			}
+			bidResponseIDs[imp.ID][openrtb_ext.BidderName(bidResponse.Bidder)] = bidResponse.ID
+			addID(bidResponse.ID)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	fetcher, ok := deps.storedReqFetcher.(stored_requests.ResponseFetcher)
+	if !ok {
+		return nil, []error{errors.New("Stored Responses are not supported by this host's Stored Request backends")}
+	}
+	data, errs := fetcher.FetchResponses(ctx, ids)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	storedResponses := &exchange.StoredResponses{
+		AuctionResponses: make(map[string]exchange.StoredAuctionResponse, len(auctionResponseIDs)),
+		BidResponses:     make(map[string]map[openrtb_ext.BidderName]json.RawMessage, len(bidResponseIDs)),
+	}
+	for impID, id := range auctionResponseIDs {
+		storedResponse, err := parseStoredAuctionResponse(data[id])
+		if err != nil {
+			return nil, []error{fmt.Errorf("Stored Auction Response %s must be an array of SeatBids or a BidResponse: %v", id, err)}
+		}
+		storedResponses.AuctionResponses[impID] = storedResponse
+	}
+	for impID, bidderIDs := range bidResponseIDs {
+		storedResponses.BidResponses[impID] = make(map[openrtb_ext.BidderName]json.RawMessage, len(bidderIDs))
+		for bidder, id := range bidderIDs {
+			storedResponses.BidResponses[impID][bidder] = data[id]
+		}
+	}
+	return storedResponses, nil
+}
+
+// parseStoredAuctionResponse reads a Stored Auction Response. It may be a BidResponse, whose cur applies to its bids,
+// or just the array of SeatBids, whose prices are in USD.
+func parseStoredAuctionResponse(data json.RawMessage) (exchange.StoredAuctionResponse, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var bidResponse openrtb.BidResponse
+		if err := json.Unmarshal(trimmed, &bidResponse); err != nil {
+			return exchange.StoredAuctionResponse{}, err
+		}
+		return exchange.StoredAuctionResponse{SeatBids: bidResponse.SeatBid, Currency: bidResponse.Cur}, nil
+	}
+
+	var seatBids []openrtb.SeatBid
+	if err := json.Unmarshal(data, &seatBids); err != nil {
+		return exchange.StoredAuctionResponse{}, err
+	}
+	return exchange.StoredAuctionResponse{SeatBids: seatBids}, nil
+}
@@ -0,0 +1,183 @@
+package openrtb2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	analyticsConf "github.com/PubMatic-OpenWrap/prebid-server/analytics/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/empty_fetcher"
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessStoredResponses(t *testing.T) {
+	deps := &endpointDeps{storedReqFetcher: &mockStoredResponseFetcher{}}
+	req := &openrtb.BidRequest{
+		Imp: []openrtb.Imp{{
+			ID:  "auction",
+			Ext: json.RawMessage(`{"appnexus":{},"prebid":{"storedauctionresponse":{"id":"seatbids"}}}`),
+		}, {
+			ID:  "bid",
+			Ext: json.RawMessage(`{"appnexus":{},"rubicon":{},"prebid":{"storedbidresponse":[{"bidder":"appnexus","id":"raw"}]}}`),
+		}, {
+			ID:  "live",
+			Ext: json.RawMessage(`{"appnexus":{}}`),
+		}},
+	}
+
+	storedResponses, errs := deps.processStoredResponses(context.Background(), req)
+
+	assert.Empty(t, errs)
+	if assert.NotNil(t, storedResponses) {
+		if assert.Len(t, storedResponses.AuctionResponses["auction"].SeatBids, 1) {
+			assert.Equal(t, "appnexus", storedResponses.AuctionResponses["auction"].SeatBids[0].Seat)
+		}
+		assert.Empty(t, storedResponses.AuctionResponses["auction"].Currency, "An array of SeatBids has no currency of its own")
+		assert.Equal(t, map[string]map[openrtb_ext.BidderName]json.RawMessage{
+			"bid": {"appnexus": json.RawMessage(`{"raw":true}`)},
+		}, storedResponses.BidResponses)
+	}
+}
+
+func TestProcessStoredResponsesBidResponse(t *testing.T) {
+	deps := &endpointDeps{storedReqFetcher: &mockStoredResponseFetcher{}}
+	req := &openrtb.BidRequest{
+		Imp: []openrtb.Imp{{ID: "imp", Ext: json.RawMessage(`{"rubicon":{},"prebid":{"storedauctionresponse":{"id":"response"}}}`)}},
+	}
+
+	storedResponses, errs := deps.processStoredResponses(context.Background(), req)
+
+	assert.Empty(t, errs)
+	if assert.NotNil(t, storedResponses) {
+		storedResponse := storedResponses.AuctionResponses["imp"]
+		assert.Equal(t, "EUR", storedResponse.Currency, "A BidResponse's cur should apply to its bids")
+		if assert.Len(t, storedResponse.SeatBids, 1) {
+			assert.Equal(t, "rubicon", storedResponse.SeatBids[0].Seat)
+		}
+	}
+}
+
+func TestProcessStoredResponsesNone(t *testing.T) {
+	deps := &endpointDeps{storedReqFetcher: empty_fetcher.EmptyFetcher{}}
+	req := &openrtb.BidRequest{
+		Imp: []openrtb.Imp{{ID: "imp", Ext: json.RawMessage(`{"appnexus":{},"prebid":{}}`)}},
+	}
+
+	storedResponses, errs := deps.processStoredResponses(context.Background(), req)
+	assert.Empty(t, errs)
+	assert.Nil(t, storedResponses)
+}
+
+func TestProcessStoredResponsesErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		impExt      string
+		expected    string
+	}{
+		{
+			description: "Missing auction response ID",
+			impExt:      `{"appnexus":{},"prebid":{"storedauctionresponse":{}}}`,
+			expected:    "request.imp[0].ext.prebid.storedauctionresponse.id is required",
+		},
+		{
+			description: "Both kinds of response",
+			impExt:      `{"appnexus":{},"prebid":{"storedauctionresponse":{"id":"seatbids"},"storedbidresponse":[{"bidder":"appnexus","id":"raw"}]}}`,
+			expected:    "request.imp[0].ext.prebid must not define both storedauctionresponse and storedbidresponse",
+		},
+		{
+			description: "Incomplete bid response",
+			impExt:      `{"appnexus":{},"prebid":{"storedbidresponse":[{"bidder":"appnexus"}]}}`,
+			expected:    "request.imp[0].ext.prebid.storedbidresponse[0] must define a bidder and an id",
+		},
+		{
+			description: "Bid response for a bidder outside the imp",
+			impExt:      `{"appnexus":{},"prebid":{"storedbidresponse":[{"bidder":"rubicon","id":"raw"}]}}`,
+			expected:    "request.imp[0].ext.prebid.storedbidresponse[0].bidder rubicon is not one of the imp's bidders",
+		},
+		{
+			description: "Unknown ID",
+			impExt:      `{"appnexus":{},"prebid":{"storedauctionresponse":{"id":"unknown"}}}`,
+			expected:    `Stored Response with ID="unknown" not found.`,
+		},
+		{
+			description: "Auction response which isn't made of SeatBids",
+			impExt:      `{"appnexus":{},"prebid":{"storedauctionresponse":{"id":"string"}}}`,
+			expected:    "Stored Auction Response string must be an array of SeatBids or a BidResponse: json: cannot unmarshal string into Go value of type []openrtb.SeatBid",
+		},
+	}
+
+	deps := &endpointDeps{storedReqFetcher: &mockStoredResponseFetcher{}}
+	for _, test := range testCases {
+		req := &openrtb.BidRequest{
+			Imp: []openrtb.Imp{{ID: "imp", Ext: json.RawMessage(test.impExt)}},
+		}
+		storedResponses, errs := deps.processStoredResponses(context.Background(), req)
+		assert.Nil(t, storedResponses, test.description)
+		if assert.Len(t, errs, 1, test.description) {
+			assert.Equal(t, test.expected, errs[0].Error(), test.description)
+		}
+	}
+}
+
+func TestProcessStoredResponsesUnsupported(t *testing.T) {
+	deps := &endpointDeps{storedReqFetcher: &mockStoredReqFetcher{}}
+	req := &openrtb.BidRequest{
+		Imp: []openrtb.Imp{{ID: "imp", Ext: json.RawMessage(`{"appnexus":{},"prebid":{"storedauctionresponse":{"id":"seatbids"}}}`)}},
+	}
+
+	_, errs := deps.processStoredResponses(context.Background(), req)
+	assert.Len(t, errs, 1, "Stored Responses should be rejected if the backends can't fetch them")
+}
+
+func TestAuctionWithStoredResponses(t *testing.T) {
+	ex := &nobidExchange{}
+	theMetrics := pbsmetrics.NewMetrics(metrics.NewRegistry(), openrtb_ext.BidderList(), config.DisabledMetrics{})
+	endpoint, _ := NewEndpoint(ex, newParamsValidator(t), &mockStoredResponseFetcher{}, empty_fetcher.EmptyFetcher{}, &config.Configuration{MaxRequestSize: maxSize}, theMetrics, analyticsConf.NewPBSAnalytics(&config.Analytics{}), map[string]string{}, []byte{}, openrtb_ext.BidderMap)
+
+	body := `{"id":"some-request-id","site":{"page":"prebid.org"},"imp":[{"id":"some-impression-id","banner":{"format":[{"w":300,"h":250}]},"ext":{"appnexus":{"placementId":12883451},"prebid":{"storedauctionresponse":{"id":"%s"}}}}]}`
+	request := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(strings.Replace(body, "%s", "seatbids", 1)))
+	recorder := httptest.NewRecorder()
+	endpoint(recorder, request, nil)
+
+	assert.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	if assert.NotNil(t, ex.gotStoredResponses) {
+		assert.Len(t, ex.gotStoredResponses.AuctionResponses["some-impression-id"].SeatBids, 1)
+	}
+
+	request = httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader(strings.Replace(body, "%s", "unknown", 1)))
+	recorder = httptest.NewRecorder()
+	endpoint(recorder, request, nil)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "Invalid request: Stored Response with ID=\"unknown\" not found.\n", recorder.Body.String())
+}
+
+// mockStoredResponseFetcher has no Stored Requests, and two Stored Responses.
+type mockStoredResponseFetcher struct {
+	empty_fetcher.EmptyFetcher
+}
+
+func (f *mockStoredResponseFetcher) FetchResponses(ctx context.Context, ids []string) (map[string]json.RawMessage, []error) {
+	responses := map[string]json.RawMessage{
+		"seatbids": json.RawMessage(`[{"seat":"appnexus","bid":[{"id":"bid","impid":"imp","price":1}]}]`),
+		"response": json.RawMessage(`{"id":"stored","cur":"EUR","seatbid":[{"seat":"rubicon","bid":[{"id":"bid","impid":"imp","price":1}]}]}`),
+		"raw":      json.RawMessage(`{"raw":true}`),
+		"string":   json.RawMessage(`"not a response"`),
+	}
+	var errs []error
+	for _, id := range ids {
+		if _, ok := responses[id]; !ok {
+			errs = append(errs, stored_requests.NotFoundError{ID: id, DataType: "Response"})
+		}
+	}
+	return responses, errs
+}
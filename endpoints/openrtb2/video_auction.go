@@ -119,6 +119,13 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 			return
 		}
 	}
+
+	// The openrtb types can't hold the OpenRTB 2.6 fields, so they're read from their 2.5 ext locations.
+	if resolvedRequest, err = openrtb_ext.ConvertDownTo25(resolvedRequest); err != nil {
+		handleError(&labels, w, []error{err}, &vo)
+		return
+	}
+
 	//unmarshal and validate combined result
 	videoBidReq, errL, podErrors := deps.parseVideoRequest(resolvedRequest)
 	if len(errL) > 0 {
@@ -130,7 +137,11 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 
 	var bidReq = &openrtb.BidRequest{}
 	if deps.defaultRequest {
-		if err := json.Unmarshal(deps.defReqJSON, bidReq); err != nil {
+		defReqJSON, err := openrtb_ext.ConvertDownTo25(deps.defReqJSON)
+		if err == nil {
+			err = json.Unmarshal(defReqJSON, bidReq)
+		}
+		if err != nil {
 			err = fmt.Errorf("Invalid JSON in Default Request Settings: %s", err)
 			handleError(&labels, w, []error{err}, &vo)
 			return
@@ -180,7 +191,10 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		defer cancel()
 	}
 
-	usersyncs := usersync.ParsePBSCookieFromRequest(r, &(deps.cfg.HostCookie))
+	usersyncs, err := usersync.ReadPBSCookieFromRequest(r, &(deps.cfg.HostCookie))
+	if err != nil {
+		deps.metricsEngine.RecordUIDCookieError(usersync.CookieErrorReason(err))
+	}
 	if bidReq.App != nil {
 		labels.Source = pbsmetrics.DemandApp
 		labels.PubID = effectivePubID(bidReq.App.Publisher)
@@ -199,8 +213,14 @@ func (deps *endpointDeps) VideoAuctionEndpoint(w http.ResponseWriter, r *http.Re
 		handleError(&labels, w, errL, &vo)
 		return
 	}
+	storedResponses, errL := deps.processStoredResponses(ctx, bidReq)
+	if len(errL) > 0 {
+		handleError(&labels, w, errL, &vo)
+		return
+	}
+
 	//execute auction logic
-	response, err := deps.ex.HoldAuction(ctx, bidReq, usersyncs, labels, &deps.categories)
+	response, err := deps.ex.HoldAuction(ctx, bidReq, usersyncs, labels, &deps.categories, storedResponses)
 	vo.Request = bidReq
 	vo.Response = response
 	if err != nil {
@@ -347,7 +367,11 @@ func (deps *endpointDeps) loadStoredImp(storedImpId string) (openrtb.Imp, []erro
 		return impr, err
 	}
 
-	if err := json.Unmarshal(imp[storedImpId], &impr); err != nil {
+	impJSON, convertErr := openrtb_ext.ConvertImpDownTo25(imp[storedImpId])
+	if convertErr != nil {
+		return impr, []error{convertErr}
+	}
+	if err := json.Unmarshal(impJSON, &impr); err != nil {
 		return impr, []error{err}
 	}
 	return impr, nil
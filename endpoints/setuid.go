@@ -3,6 +3,7 @@ package endpoints
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -14,6 +15,9 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/gdpr"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
 	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/activity"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/ccpa"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/gpp"
 	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
 	"github.com/julienschmidt/httprouter"
 )
@@ -26,8 +30,23 @@ const (
 	chromeiOSStrLen = len(chromeiOSStr)
 )
 
-func NewSetUIDEndpoint(cfg config.HostCookie, syncers map[openrtb_ext.BidderName]usersync.Usersyncer, perms gdpr.Permissions, pbsanalytics analytics.PBSAnalyticsModule, metrics pbsmetrics.MetricsEngine) httprouter.Handle {
-	cookieTTL := time.Duration(cfg.TTL) * 24 * time.Hour
+// The values of the "f" query param, which picks the body of a successful /setuid response.
+const (
+	setUIDFormatBlank = "b"
+	setUIDFormatImage = "i"
+)
+
+// trackingPixel is a transparent 1x1 PNG.
+var trackingPixel = []byte{
+	0x89, 0x50, 0x4e, 0x47, 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d, 0x49, 0x48, 0x44, 0x52,
+	0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x08, 0x06, 0x00, 0x00, 0x00, 0x1f, 0x15, 0xc4,
+	0x89, 0x00, 0x00, 0x00, 0x12, 0x49, 0x44, 0x41, 0x54, 0x78, 0x9c, 0x00, 0x05, 0x00, 0xfa, 0xff,
+	0x02, 0x00, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x0f, 0x00, 0x03, 0x42, 0xa7, 0xf5, 0x0e, 0x00,
+	0x00, 0x00, 0x00, 0x49, 0x45, 0x4e, 0x44, 0xae, 0x42, 0x60, 0x82,
+}
+
+func NewSetUIDEndpoint(cfg *config.Configuration, syncers map[openrtb_ext.BidderName]usersync.Usersyncer, perms gdpr.Permissions, pbsanalytics analytics.PBSAnalyticsModule, metrics pbsmetrics.MetricsEngine) httprouter.Handle {
+	cookieTTL := time.Duration(cfg.HostCookie.TTL) * 24 * time.Hour
 
 	validFamilyNameMap := make(map[string]struct{})
 	for _, s := range syncers {
@@ -43,7 +62,10 @@ func NewSetUIDEndpoint(cfg config.HostCookie, syncers map[openrtb_ext.BidderName
 
 		defer pbsanalytics.LogSetUIDObject(&so)
 
-		pc := usersync.ParsePBSCookieFromRequest(r, &cfg)
+		pc, err := usersync.ReadPBSCookieFromRequest(r, &cfg.HostCookie)
+		if err != nil {
+			metrics.RecordUIDCookieError(usersync.CookieErrorReason(err))
+		}
 		if !pc.AllowSyncs() {
 			w.WriteHeader(http.StatusUnauthorized)
 			metrics.RecordUserIDSet(pbsmetrics.UserLabels{
@@ -67,6 +89,20 @@ func NewSetUIDEndpoint(cfg config.HostCookie, syncers map[openrtb_ext.BidderName
 		}
 		so.Bidder = familyName
 
+		format, err := getResponseFormat(query)
+		if err == nil {
+			err = validateRedirect(query, cfg.SetUID.RedirectSecret, cfg.SetUID.RedirectMaxAge())
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			metrics.RecordUserIDSet(pbsmetrics.UserLabels{
+				Action: pbsmetrics.RequestActionErr,
+			})
+			so.Status = http.StatusBadRequest
+			return
+		}
+
 		if shouldReturn, status, body := preventSyncsGDPR(query.Get("gdpr"), query.Get("gdpr_consent"), perms); shouldReturn {
 			w.WriteHeader(status)
 			w.Write([]byte(body))
@@ -78,9 +114,45 @@ func NewSetUIDEndpoint(cfg config.HostCookie, syncers map[openrtb_ext.BidderName
 			return
 		}
 
+		if cfg.CCPA.Enforce && (ccpa.Policy{Value: query.Get("us_privacy")}).ShouldEnforce() {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("The us_privacy string prevents cookies from being saved"))
+			metrics.RecordUserIDSet(pbsmetrics.UserLabels{
+				Action: pbsmetrics.RequestActionCCPA,
+				Bidder: openrtb_ext.BidderName(familyName),
+			})
+			so.Status = http.StatusOK
+			so.Errors = append(so.Errors, errors.New("the us_privacy string prevents cookies from being saved"))
+			return
+		}
+
+		component := activity.Component{Type: activity.ComponentBidder, Name: familyName}
+		if !activity.NewControls(&cfg.ActivityControls, query.Get("account")).Allow(activity.SyncUser, component, setUIDActivitySignals(query)) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("The activity controls prevent cookies from being saved"))
+			metrics.RecordUserIDSet(pbsmetrics.UserLabels{
+				Action: pbsmetrics.RequestActionActivity,
+				Bidder: openrtb_ext.BidderName(familyName),
+			})
+			so.Status = http.StatusOK
+			so.Errors = append(so.Errors, errors.New("the activity controls prevent cookies from being saved"))
+			return
+		}
+
 		uid := query.Get("uid")
 		so.UID = uid
 
+		if overwriteTooSoon(pc, familyName, uid, cfg.SetUID.OverwriteInterval(familyName)) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte("The uid for this bidder was changed too recently"))
+			metrics.RecordUserIDSet(pbsmetrics.UserLabels{
+				Action: pbsmetrics.RequestActionRateLimited,
+				Bidder: openrtb_ext.BidderName(familyName),
+			})
+			so.Status = http.StatusTooManyRequests
+			return
+		}
+
 		if uid == "" {
 			pc.Unsync(familyName)
 		} else {
@@ -99,10 +171,72 @@ func NewSetUIDEndpoint(cfg config.HostCookie, syncers map[openrtb_ext.BidderName
 		setSiteCookie := siteCookieCheck(r.UserAgent())
 
 		secParam := r.URL.Query().Get("sec")
-		pc.SetCookieOnResponse(w, setSiteCookie, secParam, &cfg, cookieTTL)
+		pc.SetCookieOnResponse(w, setSiteCookie, secParam, &cfg.HostCookie, cookieTTL)
+
+		if redirect := query.Get("redirect"); redirect != "" {
+			http.Redirect(w, r, redirect, http.StatusFound)
+			so.Status = http.StatusFound
+			return
+		}
+		switch format {
+		case setUIDFormatBlank:
+			w.Header().Set("Content-Type", "text/html")
+		case setUIDFormatImage:
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(trackingPixel)
+		}
 	})
 }
 
+func getResponseFormat(query url.Values) (string, error) {
+	format := query.Get("f")
+	if format != "" && format != setUIDFormatBlank && format != setUIDFormatImage {
+		return "", errors.New(`"f" query param must be "b" or "i"`)
+	}
+	return format, nil
+}
+
+// validateRedirect checks the "redirect" query param, which sends the user on to another sync URL once the uid is set.
+// It must be signed with the host's secret, so that nobody else can use /setuid to redirect users to their own sites,
+// and the signature must be recent, so that a leaked one can't be used for long.
+func validateRedirect(query url.Values, secret string, maxAge time.Duration) error {
+	redirect := query.Get("redirect")
+	if redirect == "" {
+		return nil
+	}
+	if err := usersync.VerifyRedirect(secret, redirect, query.Get("sig"), maxAge); err != nil {
+		return fmt.Errorf(`"redirect" query param must be signed by the host in the "sig" query param: %v`, err)
+	}
+	if redirectURL, err := url.Parse(redirect); err != nil || redirectURL.Host == "" || (redirectURL.Scheme != "http" && redirectURL.Scheme != "https") {
+		return errors.New(`"redirect" query param must be an absolute http or https URL`)
+	}
+	return nil
+}
+
+// overwriteTooSoon returns true if the uid would replace a different one, which was set less than interval ago.
+func overwriteTooSoon(pc *usersync.PBSCookie, familyName string, uid string, interval time.Duration) bool {
+	if interval <= 0 || uid == "" {
+		return false
+	}
+	existing, hadUID, _ := pc.GetUID(familyName)
+	if !hadUID || existing == uid {
+		return false
+	}
+	syncedAt, _ := pc.SyncedAt(familyName)
+	return time.Since(syncedAt) < interval
+}
+
+// setUIDActivitySignals reads the signals which the syncUser activity rules match from the gdpr and gpp_sid query params.
+// A malformed gpp_sid is ignored, since it doesn't stop the sync anywhere else either.
+func setUIDActivitySignals(query url.Values) activity.Signals {
+	signals := activity.Signals{GDPR: query.Get("gdpr") == "1"}
+	sectionIDs, _ := gpp.ParseSectionIDs(query.Get("gpp_sid"))
+	for _, id := range sectionIDs {
+		signals.GPPSIDs = append(signals.GPPSIDs, int8(id))
+	}
+	return signals
+}
+
 func getFamilyName(query url.Values, validFamilyNameMap map[string]struct{}) (string, error) {
 	// The family name is bound to the 'bidder' query param. In most cases, these values are the same.
 	familyName := query.Get("bidder")
@@ -19,6 +19,7 @@ import (
 
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
 
+	"github.com/PubMatic-OpenWrap/prebid-server/analytics"
 	analyticsConf "github.com/PubMatic-OpenWrap/prebid-server/analytics/config"
 	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
 )
@@ -291,6 +292,161 @@ func TestOptedOut(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, response.Code)
 }
 
+func TestSetUIDResponseFormats(t *testing.T) {
+	testCases := []struct {
+		uri                 string
+		expectedCode        int
+		expectedContentType string
+		expectedBody        []byte
+		description         string
+	}{
+		{
+			uri:          "/setuid?bidder=pubmatic&uid=123",
+			expectedCode: http.StatusOK,
+			description:  "No format should return an empty body",
+		},
+		{
+			uri:                 "/setuid?bidder=pubmatic&uid=123&f=b",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "text/html",
+			description:         "f=b should return an empty page",
+		},
+		{
+			uri:                 "/setuid?bidder=pubmatic&uid=123&f=i",
+			expectedCode:        http.StatusOK,
+			expectedContentType: "image/png",
+			expectedBody:        trackingPixel,
+			description:         "f=i should return a tracking pixel",
+		},
+		{
+			uri:          "/setuid?bidder=pubmatic&uid=123&f=x",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: []byte(`"f" query param must be "b" or "i"`),
+			description:  "An unknown format should be rejected",
+		},
+	}
+
+	for _, test := range testCases {
+		response := doRequest(makeRequest(test.uri, nil, false), &metricsConf.DummyMetricsEngine{}, []string{"pubmatic"}, true, false)
+
+		assert.Equal(t, test.expectedCode, response.Code, test.description)
+		assert.Equal(t, test.expectedContentType, response.Header().Get("Content-Type"), test.description)
+		assert.Equal(t, string(test.expectedBody), response.Body.String(), test.description)
+	}
+}
+
+func TestSetUIDRedirect(t *testing.T) {
+	cfg := config.Configuration{SetUID: config.SetUID{RedirectSecret: "secret", RedirectMaxAgeSeconds: 300}}
+	redirect := "https://sync.example.com/next?uid=$UID"
+	validURI := "/setuid?bidder=pubmatic&uid=123&redirect=" + url.QueryEscape(redirect) + "&sig=" + usersync.SignRedirect("secret", redirect, time.Now())
+
+	response := doRequestWithConfig(makeRequest(validURI, nil, false), cfg, &metricsConf.DummyMetricsEngine{}, []string{"pubmatic"}, true, false)
+	assert.Equal(t, http.StatusFound, response.Code)
+	assert.Equal(t, redirect, response.Header().Get("Location"))
+	assertHasSyncs(t, "Signed redirect", response, map[string]string{"pubmatic": "123"})
+
+	badSigURI := "/setuid?bidder=pubmatic&uid=123&redirect=" + url.QueryEscape(redirect) + "&sig=" + usersync.SignRedirect("other", redirect, time.Now())
+	response = doRequestWithConfig(makeRequest(badSigURI, nil, false), cfg, &metricsConf.DummyMetricsEngine{}, []string{"pubmatic"}, true, false)
+	assert.Equal(t, http.StatusBadRequest, response.Code, "A redirect signed with another secret should be rejected")
+	assert.Empty(t, response.Header().Get("Set-Cookie"))
+
+	expiredURI := "/setuid?bidder=pubmatic&uid=123&redirect=" + url.QueryEscape(redirect) + "&sig=" + usersync.SignRedirect("secret", redirect, time.Now().Add(-10*time.Minute))
+	response = doRequestWithConfig(makeRequest(expiredURI, nil, false), cfg, &metricsConf.DummyMetricsEngine{}, []string{"pubmatic"}, true, false)
+	assert.Equal(t, http.StatusBadRequest, response.Code, "A redirect signed too long ago should be rejected")
+	assert.Equal(t, `"redirect" query param must be signed by the host in the "sig" query param: the signature has expired`, response.Body.String())
+
+	response = doRequest(makeRequest(validURI, nil, false), &metricsConf.DummyMetricsEngine{}, []string{"pubmatic"}, true, false)
+	assert.Equal(t, http.StatusBadRequest, response.Code, "Redirects should be rejected if the host has no secret")
+
+	javascript := "javascript:alert(1)"
+	schemeURI := "/setuid?bidder=pubmatic&uid=123&redirect=" + url.QueryEscape(javascript) + "&sig=" + usersync.SignRedirect("secret", javascript, time.Now())
+	response = doRequestWithConfig(makeRequest(schemeURI, nil, false), cfg, &metricsConf.DummyMetricsEngine{}, []string{"pubmatic"}, true, false)
+	assert.Equal(t, http.StatusBadRequest, response.Code, "Only http and https redirects should be allowed")
+}
+
+func TestSetUIDCCPA(t *testing.T) {
+	cfg := config.Configuration{CCPA: config.CCPA{Enforce: true}}
+	metrics := &pbsmetrics.MetricsEngineMock{}
+	metrics.On("RecordUserIDSet", pbsmetrics.UserLabels{Action: pbsmetrics.RequestActionCCPA, Bidder: "pubmatic"}).Once()
+
+	logged := &setUIDAnalytics{}
+	endpoint := NewSetUIDEndpoint(&cfg, map[openrtb_ext.BidderName]usersync.Usersyncer{"pubmatic": newFakeSyncer("pubmatic")}, &mockPermsSetUID{allowHost: true, allowPI: true}, logged, metrics)
+	response := httptest.NewRecorder()
+	endpoint(response, makeRequest("/setuid?bidder=pubmatic&uid=123&us_privacy=1-Y-", nil, false), nil)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "The us_privacy string prevents cookies from being saved", response.Body.String())
+	assert.Empty(t, response.Header().Get("Set-Cookie"))
+	metrics.AssertExpectations(t)
+	if assert.NotNil(t, logged.setUID) {
+		assert.Equal(t, http.StatusOK, logged.setUID.Status)
+		assert.False(t, logged.setUID.Success)
+		assert.Len(t, logged.setUID.Errors, 1, "The analytics should say why the cookie wasn't saved")
+	}
+
+	response = doRequest(makeRequest("/setuid?bidder=pubmatic&uid=123&us_privacy=1-Y-", nil, false), &metricsConf.DummyMetricsEngine{}, []string{"pubmatic"}, true, false)
+	assertHasSyncs(t, "CCPA not enforced", response, map[string]string{"pubmatic": "123"})
+}
+
+func TestSetUIDActivityControls(t *testing.T) {
+	cfg := config.Configuration{ActivityControls: config.ActivityControls{
+		Accounts: map[string]config.Activities{"1001": {SyncUser: config.Activity{Rules: []config.ActivityRule{
+			{Allow: false, Condition: config.ActivityCondition{GPPSID: []int8{7}}},
+		}}}},
+	}}
+	metrics := &pbsmetrics.MetricsEngineMock{}
+	metrics.On("RecordUserIDSet", pbsmetrics.UserLabels{Action: pbsmetrics.RequestActionActivity, Bidder: "pubmatic"}).Once()
+
+	response := doRequestWithConfig(makeRequest("/setuid?bidder=pubmatic&uid=123&account=1001&gpp_sid=7", nil, false), cfg, metrics, []string{"pubmatic"}, true, false)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "The activity controls prevent cookies from being saved", response.Body.String())
+	assert.Empty(t, response.Header().Get("Set-Cookie"))
+	metrics.AssertExpectations(t)
+
+	response = doRequestWithConfig(makeRequest("/setuid?bidder=pubmatic&uid=123&account=1001&gpp_sid=6", nil, false), cfg, &metricsConf.DummyMetricsEngine{}, []string{"pubmatic"}, true, false)
+	assertHasSyncs(t, "Rule doesn't match", response, map[string]string{"pubmatic": "123"})
+
+	response = doRequestWithConfig(makeRequest("/setuid?bidder=pubmatic&uid=123&gpp_sid=7", nil, false), cfg, &metricsConf.DummyMetricsEngine{}, []string{"pubmatic"}, true, false)
+	assertHasSyncs(t, "Other account", response, map[string]string{"pubmatic": "123"})
+}
+
+func TestSetUIDOverwriteRateLimit(t *testing.T) {
+	cfg := config.Configuration{SetUID: config.SetUID{
+		OverwriteIntervalSeconds:       60,
+		BidderOverwriteIntervalSeconds: map[string]int{"rubicon": 0},
+	}}
+	existing := map[string]string{"pubmatic": "123", "rubicon": "456"}
+	metrics := &pbsmetrics.MetricsEngineMock{}
+	metrics.On("RecordUserIDSet", pbsmetrics.UserLabels{Action: pbsmetrics.RequestActionRateLimited, Bidder: "pubmatic"}).Once()
+
+	response := doRequestWithConfig(makeRequest("/setuid?bidder=pubmatic&uid=789", existing, false), cfg, metrics, []string{"pubmatic", "rubicon"}, true, false)
+	assert.Equal(t, http.StatusTooManyRequests, response.Code)
+	assert.Empty(t, response.Header().Get("Set-Cookie"))
+	metrics.AssertExpectations(t)
+
+	response = doRequestWithConfig(makeRequest("/setuid?bidder=pubmatic&uid=123", existing, false), cfg, &metricsConf.DummyMetricsEngine{}, []string{"pubmatic", "rubicon"}, true, false)
+	assert.Equal(t, http.StatusOK, response.Code, "Setting the same uid again isn't an overwrite")
+
+	response = doRequestWithConfig(makeRequest("/setuid?bidder=rubicon&uid=789", existing, false), cfg, &metricsConf.DummyMetricsEngine{}, []string{"pubmatic", "rubicon"}, true, false)
+	assertHasSyncs(t, "Bidder without a limit", response, map[string]string{"pubmatic": "123", "rubicon": "789"})
+}
+
+func TestSetUIDTamperedCookie(t *testing.T) {
+	cfg := config.Configuration{HostCookie: config.HostCookie{Security: config.CookieSecurity{
+		Keys: []config.CookieKey{{ID: "key", Secret: "0123456789abcdef"}},
+	}}}
+	metrics := &pbsmetrics.MetricsEngineMock{}
+	metrics.On("RecordUIDCookieError", pbsmetrics.CookieErrorTampered).Once()
+	metrics.On("RecordUserIDSet", pbsmetrics.UserLabels{Action: pbsmetrics.RequestActionSet, Bidder: "pubmatic"}).Once()
+
+	response := doRequestWithConfig(makeRequest("/setuid?bidder=pubmatic&uid=123", map[string]string{"rubicon": "456"}, false), cfg, metrics, []string{"pubmatic", "rubicon"}, true, false)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Header().Get("Set-Cookie"), "uids=s.key.", "The new cookie should be signed")
+	metrics.AssertExpectations(t)
+}
+
 func TestSiteCookieCheck(t *testing.T) {
 	testCases := []struct {
 		ua             string
@@ -391,7 +547,10 @@ func makeRequest(uri string, existingSyncs map[string]string, addSecParam bool)
 }
 
 func doRequest(req *http.Request, metrics pbsmetrics.MetricsEngine, validFamilyNames []string, gdprAllowsHostCookies bool, gdprReturnsError bool) *httptest.ResponseRecorder {
-	cfg := config.Configuration{}
+	return doRequestWithConfig(req, config.Configuration{}, metrics, validFamilyNames, gdprAllowsHostCookies, gdprReturnsError)
+}
+
+func doRequestWithConfig(req *http.Request, cfg config.Configuration, metrics pbsmetrics.MetricsEngine, validFamilyNames []string, gdprAllowsHostCookies bool, gdprReturnsError bool) *httptest.ResponseRecorder {
 	perms := &mockPermsSetUID{
 		allowHost: gdprAllowsHostCookies,
 		errorHost: gdprReturnsError,
@@ -403,12 +562,24 @@ func doRequest(req *http.Request, metrics pbsmetrics.MetricsEngine, validFamilyN
 		syncers[openrtb_ext.BidderName(name)] = newFakeSyncer(name)
 	}
 
-	endpoint := NewSetUIDEndpoint(cfg.HostCookie, syncers, perms, analytics, metrics)
+	endpoint := NewSetUIDEndpoint(&cfg, syncers, perms, analytics, metrics)
 	response := httptest.NewRecorder()
 	endpoint(response, req, nil)
 	return response
 }
 
+// setUIDAnalytics remembers the SetUIDObject which it's given.
+type setUIDAnalytics struct {
+	setUID *analytics.SetUIDObject
+}
+
+func (a *setUIDAnalytics) LogAuctionObject(ao *analytics.AuctionObject)        {}
+func (a *setUIDAnalytics) LogVideoObject(vo *analytics.VideoObject)            {}
+func (a *setUIDAnalytics) LogCookieSyncObject(cso *analytics.CookieSyncObject) {}
+func (a *setUIDAnalytics) LogSetUIDObject(so *analytics.SetUIDObject)          { a.setUID = so }
+func (a *setUIDAnalytics) LogAmpObject(ao *analytics.AmpObject)                {}
+func (a *setUIDAnalytics) LogOptOutObject(oo *analytics.OptOutObject)          {}
+
 func addCookie(req *http.Request, cookie *usersync.PBSCookie) {
 	req.AddCookie(cookie.ToHTTPCookie(time.Duration(1) * time.Hour))
 }
@@ -423,7 +594,9 @@ func parseCookieString(t *testing.T, response *httptest.ResponseRecorder) *users
 		Name:  "uids",
 		Value: res[1],
 	}
-	return usersync.ParsePBSCookie(&httpCookie)
+	cookie, err := usersync.ParsePBSCookie(&httpCookie, nil)
+	assert.NoError(t, err)
+	return cookie
 }
 
 type mockPermsSetUID struct {
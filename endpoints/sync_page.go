@@ -0,0 +1,251 @@
+package endpoints
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/analytics"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/gdpr"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
+	"github.com/golang/glog"
+	"github.com/julienschmidt/httprouter"
+)
+
+// syncPageTemplate runs the syncs in the browser, at most maxConcurrent at a time. It posts a "pbs_sync" message to
+// the parent frame as each sync finishes, and a "pbs_sync_done" message once they all have. The messages only go to
+// the publisher's origin, so that the page can't leak which bidders synced to whoever frames it. Without an origin,
+// no messages are posted.
+var syncPageTemplate = template.Must(template.New("sync").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>User Sync</title></head>
+<body>
+<script>
+(function() {
+  var syncs = {{.Syncs}};
+  var maxConcurrent = {{.MaxConcurrent}} || syncs.length;
+  var timeout = {{.TimeoutMillis}};
+  var targetOrigin = {{.TargetOrigin}};
+  var next = 0, running = 0, finished = 0;
+
+  function post(message) {
+    if (targetOrigin && window.parent && window.parent !== window) {
+      window.parent.postMessage(JSON.stringify(message), targetOrigin);
+    }
+  }
+
+  function start(sync) {
+    var done = false, timer;
+    function finish(status) {
+      if (done) { return; }
+      done = true;
+      if (timer) { clearTimeout(timer); }
+      running--;
+      finished++;
+      post({type: "pbs_sync", bidder: sync.bidder, status: status});
+      if (finished === syncs.length) {
+        post({type: "pbs_sync_done", count: finished});
+      } else {
+        fill();
+      }
+    }
+    running++;
+    if (timeout > 0) {
+      timer = setTimeout(function() { finish("timeout"); }, timeout);
+    }
+    if (sync.type === "iframe") {
+      var frame = document.createElement("iframe");
+      frame.style.display = "none";
+      frame.onload = function() { finish("loaded"); };
+      frame.src = sync.url;
+      document.body.appendChild(frame);
+    } else {
+      var img = new Image();
+      img.onload = function() { finish("loaded"); };
+      img.onerror = function() { finish("error"); };
+      img.src = sync.url;
+    }
+  }
+
+  function fill() {
+    while (running < maxConcurrent && next < syncs.length) {
+      start(syncs[next++]);
+    }
+  }
+
+  if (syncs.length === 0) {
+    post({type: "pbs_sync_done", count: 0});
+  } else {
+    fill();
+  }
+})();
+</script>
+</body>
+</html>
+`))
+
+// NewSyncPageEndpoint returns the /sync endpoint. It serves an HTML page which runs the syncs that /cookie_sync would
+// return, so that pages without Prebid.js, like AMP pages with an amp-iframe, can sync users in one round trip.
+func NewSyncPageEndpoint(syncers map[openrtb_ext.BidderName]usersync.Usersyncer, cfg *config.Configuration, syncPermissions gdpr.Permissions, metrics pbsmetrics.MetricsEngine, pbsAnalytics analytics.PBSAnalyticsModule) httprouter.Handle {
+	deps := &syncPageDeps{
+		cookieSyncDeps: cookieSyncDeps{
+			syncers:         syncers,
+			hostCookie:      &cfg.HostCookie,
+			gDPR:            &cfg.GDPR,
+			syncPermissions: syncPermissions,
+			metrics:         metrics,
+			pbsAnalytics:    pbsAnalytics,
+			enforceCCPA:     cfg.CCPA.Enforce,
+			activities:      &cfg.ActivityControls,
+		},
+		maxConcurrentSyncs: cfg.SyncPage.MaxConcurrentSyncs,
+		timeoutMillis:      cfg.SyncPage.TimeoutMillis,
+	}
+	return deps.Endpoint
+}
+
+type syncPageDeps struct {
+	cookieSyncDeps
+	maxConcurrentSyncs int
+	timeoutMillis      int
+}
+
+// syncPageSync is what the page needs to know about each sync.
+type syncPageSync struct {
+	Bidder string `json:"bidder"`
+	URL    string `json:"url"`
+	Type   string `json:"type"`
+}
+
+func (deps *syncPageDeps) Endpoint(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	co := analytics.CookieSyncObject{
+		Status:       http.StatusOK,
+		Errors:       make([]error, 0),
+		BidderStatus: make([]*usersync.CookieSyncBidders, 0),
+	}
+
+	defer deps.pbsAnalytics.LogCookieSyncObject(&co)
+
+	deps.metrics.RecordCookieSync()
+	userSyncCookie, err := usersync.ReadPBSCookieFromRequest(r, deps.hostCookie)
+	if err != nil {
+		deps.metrics.RecordUIDCookieError(usersync.CookieErrorReason(err))
+	}
+	if !userSyncCookie.AllowSyncs() {
+		http.Error(w, "User has opted out", http.StatusUnauthorized)
+		co.Status = http.StatusUnauthorized
+		co.Errors = append(co.Errors, fmt.Errorf("user has opted out"))
+		return
+	}
+
+	query := r.URL.Query()
+	parsedReq, err := parseSyncPageRequest(query)
+	if err == nil {
+		err = parsedReq.resolveGDPR(deps.gDPR.UsersyncIfAmbiguous)
+	}
+	var targetOrigin string
+	if err == nil {
+		targetOrigin, err = syncPageOrigin(query.Get("origin"), r.Referer())
+	}
+	if err != nil {
+		co.Status = http.StatusBadRequest
+		co.Errors = append(co.Errors, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if parsedReq.Bidders == nil {
+		parsedReq.Bidders = deps.allBidders()
+	}
+
+	bidderStatus := deps.bidderSyncs(r, parsedReq, userSyncCookie)
+	co.BidderStatus = append(co.BidderStatus, bidderStatus...)
+
+	syncs := make([]syncPageSync, 0, len(bidderStatus))
+	for _, status := range bidderStatus {
+		syncs = append(syncs, syncPageSync{
+			Bidder: status.BidderCode,
+			URL:    status.UsersyncInfo.URL,
+			Type:   status.UsersyncInfo.Type,
+		})
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err = syncPageTemplate.Execute(w, struct {
+		Syncs         []syncPageSync
+		MaxConcurrent int
+		TimeoutMillis int
+		TargetOrigin  string
+	}{syncs, deps.maxConcurrentSyncs, deps.timeoutMillis, targetOrigin})
+	if err != nil {
+		glog.Errorf("Failed to render the /sync page: %v", err)
+	}
+}
+
+// parseSyncPageRequest reads the /sync query params into the same request as a /cookie_sync body.
+// If the bidders param is missing, the request's Bidders are nil. If it's empty, they're empty.
+func parseSyncPageRequest(query url.Values) (*cookieSyncRequest, error) {
+	parsedReq := &cookieSyncRequest{
+		Consent:   query.Get("gdpr_consent"),
+		USPrivacy: query.Get("us_privacy"),
+		GPP:       query.Get("gpp"),
+		GPPSID:    query.Get("gpp_sid"),
+		Account:   query.Get("account"),
+	}
+
+	if _, ok := query["bidders"]; ok {
+		parsedReq.Bidders = make([]string, 0)
+		for _, bidder := range strings.Split(query.Get("bidders"), ",") {
+			if bidder = strings.TrimSpace(bidder); bidder != "" {
+				parsedReq.Bidders = append(parsedReq.Bidders, bidder)
+			}
+		}
+	}
+
+	switch gdprSignal := query.Get("gdpr"); gdprSignal {
+	case "":
+	case "0", "1":
+		gdprValue, _ := strconv.Atoi(gdprSignal)
+		parsedReq.GDPR = &gdprValue
+	default:
+		return nil, errors.New("the gdpr query param must be either 0 or 1")
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		var err error
+		if parsedReq.Limit, err = strconv.Atoi(limit); err != nil {
+			return nil, errors.New("the limit query param must be an integer")
+		}
+	}
+
+	return parsedReq, nil
+}
+
+// syncPageOrigin returns the origin which the page posts its messages to. It's the origin query param if there is one,
+// or else the origin of the page which framed it, from the Referer header. It's empty if neither is known.
+func syncPageOrigin(originParam string, referer string) (string, error) {
+	if originParam != "" {
+		origin, ok := httpOrigin(originParam)
+		if !ok || strings.TrimSuffix(originParam, "/") != origin {
+			return "", errors.New("the origin query param must be an http or https origin, like https://www.example.com")
+		}
+		return origin, nil
+	}
+	origin, _ := httpOrigin(referer)
+	return origin, nil
+}
+
+// httpOrigin returns the scheme, host and port of an absolute http or https URL.
+func httpOrigin(rawURL string) (string, bool) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", false
+	}
+	return parsed.Scheme + "://" + parsed.Host, true
+}
@@ -0,0 +1,169 @@
+package endpoints
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	analyticsConf "github.com/PubMatic-OpenWrap/prebid-server/analytics/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncPage(t *testing.T) {
+	rr := doSyncPageRequest("bidders=appnexus,pubmatic,random&gdpr=0&sec=1", map[string]string{"pubmatic": "id"})
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rr.Header().Get("Content-Type"))
+	body := rr.Body.String()
+	assert.Contains(t, body, `"bidder":"appnexus"`)
+	assert.Contains(t, body, `someurl.com?sec=1`, "The syncs should get the same URLs as /cookie_sync")
+	assert.NotContains(t, body, `"bidder":"pubmatic"`, "A bidder which the cookie already has shouldn't be synced")
+	assert.NotContains(t, body, `"bidder":"random"`)
+	assert.Regexp(t, `var maxConcurrent = +2 `, body)
+	assert.Regexp(t, `var timeout = +1000 ;`, body)
+}
+
+func TestSyncPageOrigin(t *testing.T) {
+	testCases := []struct {
+		description string
+		query       string
+		referer     string
+		expected    string
+	}{
+		{
+			description: "Origin Param",
+			query:       "gdpr=0&origin=https%3A%2F%2Fpublisher.example.com",
+			referer:     "https://other.example.com/page",
+			expected:    `var targetOrigin = "https://publisher.example.com";`,
+		},
+		{
+			description: "Referer",
+			query:       "gdpr=0",
+			referer:     "https://publisher.example.com:8443/articles/1?x=y",
+			expected:    `var targetOrigin = "https://publisher.example.com:8443";`,
+		},
+		{
+			description: "Unknown",
+			query:       "gdpr=0",
+			expected:    `var targetOrigin = "";`,
+		},
+	}
+
+	for _, test := range testCases {
+		req := httptest.NewRequest("GET", "/sync?"+test.query, nil)
+		if test.referer != "" {
+			req.Header.Set("Referer", test.referer)
+		}
+		rr := httptest.NewRecorder()
+		testableSyncPage()(rr, req, nil)
+
+		assert.Equal(t, http.StatusOK, rr.Code, test.description)
+		assert.Contains(t, rr.Body.String(), test.expected, test.description)
+		assert.NotContains(t, rr.Body.String(), `"*"`, test.description)
+	}
+}
+
+func TestSyncPageAllBidders(t *testing.T) {
+	body := doSyncPageRequest("gdpr=0", nil).Body.String()
+	for _, bidder := range []string{"appnexus", "audienceNetwork", "lifestreet", "pubmatic"} {
+		assert.Contains(t, body, `"bidder":"`+bidder+`"`)
+	}
+
+	assert.NotContains(t, doSyncPageRequest("bidders=&gdpr=0", nil).Body.String(), `"bidder":`, "An empty bidders param should sync nobody")
+}
+
+func TestSyncPageLimit(t *testing.T) {
+	body := doSyncPageRequest("gdpr=0&limit=1", nil).Body.String()
+	assert.Len(t, syncPageBidders(body), 1)
+}
+
+func TestSyncPagePrivacy(t *testing.T) {
+	assert.NotContains(t, doSyncPageRequest("bidders=appnexus&gdpr=1&gdpr_consent=abc", nil).Body.String(), `"bidder":`,
+		"The host doesn't have consent, so nobody should be synced")
+	assert.NotContains(t, doSyncPageRequest("bidders=appnexus&gdpr=0&us_privacy=1-Y-", nil).Body.String(), `"bidder":`,
+		"A CCPA opt out should block the syncs")
+	assert.NotContains(t, doSyncPageRequest("bidders=appnexus&gdpr=0&gpp=DBABLA~BAAa&gpp_sid=7", nil).Body.String(), `"bidder":`,
+		"A GPP US National opt out should block the syncs")
+}
+
+func TestSyncPageActivityControls(t *testing.T) {
+	assert.Contains(t, doSyncPageRequest("bidders=appnexus&gdpr=0&account=1002", nil).Body.String(), `"bidder":"appnexus"`)
+	assert.NotContains(t, doSyncPageRequest("bidders=appnexus&gdpr=0&account=1001", nil).Body.String(), `"bidder":`,
+		"The account's activity controls deny syncUser")
+}
+
+func TestSyncPageBadRequests(t *testing.T) {
+	testCases := []struct {
+		query   string
+		message string
+	}{
+		{query: "gdpr=1", message: "gdpr_consent is required if gdpr=1\n"},
+		{query: "gdpr=2", message: "the gdpr query param must be either 0 or 1\n"},
+		{query: "gdpr=0&limit=x", message: "the limit query param must be an integer\n"},
+		{query: "gdpr=0&gpp_sid=2,x", message: "gpp_sid must be a comma separated list of section IDs, but it contains \"x\"\n"},
+		{query: "gdpr=0&origin=javascript:alert(1)", message: "the origin query param must be an http or https origin, like https://www.example.com\n"},
+		{query: "gdpr=0&origin=https://www.example.com/page", message: "the origin query param must be an http or https origin, like https://www.example.com\n"},
+	}
+
+	for _, test := range testCases {
+		rr := doSyncPageRequest(test.query, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, test.query)
+		assert.Equal(t, test.message, rr.Body.String(), test.query)
+	}
+}
+
+func TestSyncPageOptOut(t *testing.T) {
+	endpoint := testableSyncPage()
+	req := httptest.NewRequest("GET", "/sync?gdpr=0", nil)
+	pc := usersync.NewPBSCookie()
+	pc.SetPreference(false)
+	req.AddCookie(pc.ToHTTPCookie(time.Hour))
+
+	rr := httptest.NewRecorder()
+	endpoint(rr, req, nil)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func doSyncPageRequest(query string, existingSyncs map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/sync?"+query, nil)
+	if len(existingSyncs) > 0 {
+		pc := usersync.NewPBSCookie()
+		for bidder, uid := range existingSyncs {
+			pc.TrySync(bidder, uid)
+		}
+		req.AddCookie(pc.ToHTTPCookie(time.Hour))
+	}
+
+	rr := httptest.NewRecorder()
+	testableSyncPage()(rr, req, nil)
+	return rr
+}
+
+func testableSyncPage() httprouter.Handle {
+	deny := false
+	cfg := &config.Configuration{
+		CCPA:     config.CCPA{Enforce: true},
+		SyncPage: config.SyncPage{MaxConcurrentSyncs: 2, TimeoutMillis: 1000},
+		ActivityControls: config.ActivityControls{
+			Accounts: map[string]config.Activities{"1001": {SyncUser: config.Activity{Default: &deny}}},
+		},
+	}
+	return NewSyncPageEndpoint(syncersForTest(), cfg, mockPermissions(false, nil), &metricsConf.DummyMetricsEngine{}, analyticsConf.NewPBSAnalytics(&config.Analytics{}))
+}
+
+// syncPageBidders finds the bidders which the page will sync.
+func syncPageBidders(body string) []string {
+	var bidders []string
+	for _, bidder := range []string{"appnexus", "audienceNetwork", "lifestreet", "pubmatic"} {
+		if strings.Contains(body, `"bidder":"`+bidder+`"`) {
+			bidders = append(bidders, bidder)
+		}
+	}
+	return bidders
+}
@@ -60,12 +60,14 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters/yieldmo"
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
 )
 
 // The newAdapterMap function is segregated to its own file to make it a simple and clean location for each Adapter
 // to register itself. No wading through Exchange code to find it.
 
-func newAdapterMap(client *http.Client, cfg *config.Configuration, infos adapters.BidderInfos) map[openrtb_ext.BidderName]adaptedBidder {
+// It also returns the clients which it built for the bidders that override the host's transport settings.
+func newAdapterMap(client *http.Client, cfg *config.Configuration, infos adapters.BidderInfos, me pbsmetrics.MetricsEngine) (map[openrtb_ext.BidderName]adaptedBidder, []*http.Client) {
 	ortbBidders := map[openrtb_ext.BidderName]adapters.Bidder{
 		openrtb_ext.Bidder33Across:     ttx.New33AcrossBidder(cfg.Adapters[string(openrtb_ext.Bidder33Across)].Endpoint),
 		openrtb_ext.BidderAdform:       adform.NewAdformBidder(client, cfg.Adapters[string(openrtb_ext.BidderAdform)].Endpoint),
@@ -138,6 +140,7 @@ func newAdapterMap(client *http.Client, cfg *config.Configuration, infos adapter
 	}
 
 	allBidders := make(map[openrtb_ext.BidderName]adaptedBidder, len(ortbBidders)+len(legacyBidders))
+	var bidderClients []*http.Client
 
 	// Wrap legacy and openrtb Bidders behind a common interface, so that the Exchange doesn't need to concern
 	// itself with the differences.
@@ -150,16 +153,27 @@ func newAdapterMap(client *http.Client, cfg *config.Configuration, infos adapter
 	for name, bidder := range ortbBidders {
 		// Clean out any disabled bidders
 		if infos[string(name)].Status == adapters.StatusActive {
-			allBidders[name] = adaptBidder(adapters.EnforceBidderInfo(bidder, infos[string(name)]), client)
+			adapterCfg := cfg.Adapters[strings.ToLower(string(name))]
+			bidderClient := newBidderClient(client, cfg.Client, adapterCfg.HTTPClient)
+			if bidderClient != client {
+				bidderClients = append(bidderClients, bidderClient)
+			}
+			allBidders[name] = adaptBidder(adapters.EnforceBidderInfo(bidder, infos[string(name)]), bidderClient, bidderAdapterConfig{
+				MaxImps:                 adapterCfg.MaxImps,
+				MaxHTTPCalls:            adapterCfg.MaxHTTPCalls,
+				GzipRequests:            adapterCfg.HTTPClient.GzipRequests,
+				OpenRTB26:               infos.SupportsOpenRTB26(name),
+				RecordConnectionMetrics: !cfg.Metrics.Disabled.AdapterConnectionMetrics,
+			}, me, name)
 		}
 	}
 
 	// Apply any middleware used for global Bidder logic.
 	for name, bidder := range allBidders {
-		allBidders[name] = ensureValidBids(bidder)
+		allBidders[name] = ensureValidBids(bidder, name, cfg, me)
 	}
 
-	return allBidders
+	return allBidders, bidderClients
 }
 
 // DisableBidders get all bidders but disabled ones
@@ -1,17 +1,20 @@
 package exchange
 
 import (
+	"net/http"
 	"strings"
 	"testing"
 
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters"
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewAdapterMap(t *testing.T) {
 	cfg := &config.Configuration{Adapters: blankAdapterConfig(openrtb_ext.BidderList())}
-	adapterMap := newAdapterMap(nil, cfg, adapters.ParseBidderInfos(cfg.Adapters, "../static/bidder-info", openrtb_ext.BidderList()))
+	adapterMap, _ := newAdapterMap(nil, cfg, adapters.ParseBidderInfos(cfg.Adapters, "../static/bidder-info", openrtb_ext.BidderList()), &metricsConf.DummyMetricsEngine{})
 	for _, bidderName := range openrtb_ext.BidderMap {
 		if bidder, ok := adapterMap[bidderName]; bidder == nil || !ok {
 			t.Errorf("adapterMap missing expected Bidder: %s", string(bidderName))
@@ -38,7 +41,7 @@ func TestNewAdapterMapDisabledAdapters(t *testing.T) {
 			}
 		}
 	}
-	adapterMap := newAdapterMap(nil, &config.Configuration{Adapters: cfgAdapters}, adapters.ParseBidderInfos(cfgAdapters, "../static/bidder-info", bidderList))
+	adapterMap, _ := newAdapterMap(nil, &config.Configuration{Adapters: cfgAdapters}, adapters.ParseBidderInfos(cfgAdapters, "../static/bidder-info", bidderList), &metricsConf.DummyMetricsEngine{})
 	for _, bidderName := range openrtb_ext.BidderMap {
 		if bidder, ok := adapterMap[bidderName]; bidder == nil || !ok {
 			if inList(bidderList, bidderName) {
@@ -52,6 +55,20 @@ func TestNewAdapterMapDisabledAdapters(t *testing.T) {
 	}
 }
 
+func TestNewAdapterMapBidderClients(t *testing.T) {
+	cfgAdapters := blankAdapterConfig(openrtb_ext.BidderList())
+	appnexus := cfgAdapters[string(openrtb_ext.BidderAppnexus)]
+	appnexus.HTTPClient.DisableKeepAlives = true
+	cfgAdapters[string(openrtb_ext.BidderAppnexus)] = appnexus
+	hostClient := &http.Client{}
+
+	_, bidderClients := newAdapterMap(hostClient, &config.Configuration{Adapters: cfgAdapters}, adapters.ParseBidderInfos(cfgAdapters, "../static/bidder-info", openrtb_ext.BidderList()), &metricsConf.DummyMetricsEngine{})
+
+	if assert.Len(t, bidderClients, 1, "Only the bidders with their own transport settings should get a client of their own") {
+		assert.False(t, bidderClients[0] == hostClient)
+	}
+}
+
 func inList(list []openrtb_ext.BidderName, name openrtb_ext.BidderName) bool {
 	for _, v := range list {
 		if v == name {
@@ -0,0 +1,79 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/gdpr"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/buger/jsonparser"
+)
+
+// mergeHostAliases adds the host's bidder_aliases to the aliases which the request defines.
+// The request's own aliases win if the names clash.
+func mergeHostAliases(aliases map[string]string, hostAliases map[string]config.BidderAlias) map[string]string {
+	if len(hostAliases) == 0 {
+		return aliases
+	}
+	merged := make(map[string]string, len(aliases)+len(hostAliases))
+	for name, alias := range hostAliases {
+		merged[name] = alias.Bidder
+	}
+	for name, coreBidder := range aliases {
+		merged[name] = coreBidder
+	}
+	return merged
+}
+
+// parseAliasGVLIDs parses the Global Vendor List IDs from request.ext.prebid.aliasgvlids.
+func parseAliasGVLIDs(orig *openrtb.BidRequest) (map[string]uint16, []error) {
+	var gvlIDs map[string]uint16
+	if value, dataType, _, err := jsonparser.Get(orig.Ext, openrtb_ext.PrebidExtKey, "aliasgvlids"); dataType == jsonparser.Object && err == nil {
+		if err := json.Unmarshal(value, &gvlIDs); err != nil {
+			return nil, []error{err}
+		}
+	} else if dataType != jsonparser.NotExist && err != jsonparser.KeyPathNotFoundError {
+		return nil, []error{err}
+	}
+	return gvlIDs, nil
+}
+
+// isHostAlias returns true if the bidder is one of the host's bidder_aliases, and the request didn't redefine it.
+func isHostAlias(bidder string, aliases map[string]string, hostAliases map[string]config.BidderAlias) bool {
+	hostAlias, ok := hostAliases[bidder]
+	return ok && aliases[bidder] == hostAlias.Bidder
+}
+
+// syncerBidder returns the name which the bidder's user ID is stored under in the cookie. A host alias with a
+// usersync_url has its own cookie family. Every other alias shares its core bidder's.
+func syncerBidder(bidder string, coreBidder openrtb_ext.BidderName, aliases map[string]string, hostAliases map[string]config.BidderAlias) openrtb_ext.BidderName {
+	if isHostAlias(bidder, aliases, hostAliases) && hostAliases[bidder].UserSyncURL != "" {
+		return openrtb_ext.BidderName(bidder)
+	}
+	return coreBidder
+}
+
+// metricsBidder returns the name which the bidder's metrics are recorded under. The host's aliases get their own,
+// while the request's aliases are counted with their core bidder so that requests can't add new labels.
+func metricsBidder(bidder string, aliases map[string]string, hostAliases map[string]config.BidderAlias) openrtb_ext.BidderName {
+	if isHostAlias(bidder, aliases, hostAliases) {
+		return openrtb_ext.BidderName(bidder)
+	}
+	return resolveBidder(bidder, aliases)
+}
+
+// personalInfoAllowed checks the GDPR consent for the bidder. An alias with a vendor ID of its own, from
+// request.ext.prebid.aliasgvlids or the host's bidder_aliases, is checked with that ID. Every other bidder
+// is checked as its core bidder.
+func personalInfoAllowed(ctx context.Context, perms gdpr.Permissions, bidder string, aliases map[string]string, aliasGVLIDs map[string]uint16, hostAliases map[string]config.BidderAlias, publisherID string, consent string) (bool, error) {
+	vendorID, ok := aliasGVLIDs[bidder]
+	if !ok && isHostAlias(bidder, aliases, hostAliases) {
+		vendorID = hostAliases[bidder].GVLVendorID
+	}
+	if vendorPerms, ok := perms.(gdpr.VendorPermissions); ok && vendorID != 0 {
+		return vendorPerms.VendorPersonalInfoAllowed(ctx, vendorID, publisherID, consent)
+	}
+	return perms.PersonalInfoAllowed(ctx, resolveBidder(bidder, aliases), publisherID, consent)
+}
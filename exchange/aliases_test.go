@@ -0,0 +1,69 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	"github.com/stretchr/testify/assert"
+)
+
+// vendorPermissionsMock allows the core bidders like permissionsMock does, and the vendor IDs in allowed.
+type vendorPermissionsMock struct {
+	permissionsMock
+	allowed map[uint16]bool
+}
+
+func (p *vendorPermissionsMock) VendorPersonalInfoAllowed(ctx context.Context, vendorID uint16, PublisherID string, consent string) (bool, error) {
+	return p.allowed[vendorID], nil
+}
+
+func TestCleanOpenRTBRequestsHostAliases(t *testing.T) {
+	hostAliases := map[string]config.BidderAlias{
+		"hostalias": {Bidder: "appnexus", GVLVendorID: 32, UserSyncURL: "http://sync.example.com"},
+		"nosync":    {Bidder: "appnexus"},
+	}
+	req := &openrtb.BidRequest{
+		ID:   "request",
+		Site: &openrtb.Site{Page: "http://www.example.com"},
+		Regs: &openrtb.Regs{Ext: json.RawMessage(`{"gdpr":1}`)},
+		User: &openrtb.User{Ext: json.RawMessage(`{"consent":"BONciguONcjGKADACHENAOLS1rAHDAFAAEAASABQAMwAeACEAFw"}`)},
+		Imp: []openrtb.Imp{{
+			ID:     "imp",
+			Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 300, H: 250}}},
+			Ext:    json.RawMessage(`{"hostalias":{"placementId":1},"nosync":{"placementId":1},"reqalias":{"placementId":1}}`),
+		}},
+		Ext: json.RawMessage(`{"prebid":{"aliases":{"reqalias":"appnexus"},"aliasgvlids":{"reqalias":99}}}`),
+	}
+	usersyncs := &mockUsersync{syncs: map[string]string{"hostalias": "host-uid", "appnexus": "appnexus-uid"}}
+	perms := &vendorPermissionsMock{allowed: map[uint16]bool{32: true}}
+	blabels := map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels{}
+
+	requests, aliases, errs := cleanOpenRTBRequests(context.Background(), req, usersyncs, blabels, pbsmetrics.Labels{}, perms, true, false, nil, hostAliases, nil)
+
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]string{"hostalias": "appnexus", "nosync": "appnexus", "reqalias": "appnexus"}, aliases)
+	if assert.Len(t, requests, 3) {
+		assert.Equal(t, "host-uid", requests["hostalias"].User.BuyerUID, "A host alias with a usersync_url should use its own cookie family")
+		assert.Equal(t, "appnexus-uid", requests["nosync"].User.BuyerUID, "A host alias without a usersync_url should use its core bidder's cookie family")
+		assert.Empty(t, requests["reqalias"].User.BuyerUID, "The request's aliasgvlids vendor wasn't allowed, so the user ID should be removed")
+	}
+	assert.Contains(t, blabels, openrtb_ext.BidderName("hostalias"))
+	assert.Contains(t, blabels, openrtb_ext.BidderName("nosync"))
+	assert.Contains(t, blabels, openrtb_ext.BidderName("appnexus"), "The request's aliases should be counted with their core bidder")
+	assert.NotContains(t, blabels, openrtb_ext.BidderName("reqalias"))
+}
+
+func TestMergeHostAliasesRequestWins(t *testing.T) {
+	merged := mergeHostAliases(map[string]string{"myalias": "rubicon"}, map[string]config.BidderAlias{
+		"myalias":    {Bidder: "appnexus", GVLVendorID: 32},
+		"otheralias": {Bidder: "openx"},
+	})
+
+	assert.Equal(t, map[string]string{"myalias": "rubicon", "otheralias": "openx"}, merged)
+	assert.False(t, isHostAlias("myalias", merged, map[string]config.BidderAlias{"myalias": {Bidder: "appnexus"}}))
+}
@@ -15,21 +15,20 @@ import (
 	"github.com/golang/glog"
 )
 
-func newAuction(seatBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, numImps int) *auction {
+func newAuction(seatBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, numImps int, preferDeals bool) *auction {
 	winningBids := make(map[string]*pbsOrtbBid, numImps)
 	winningBidsByBidder := make(map[string]map[openrtb_ext.BidderName]*pbsOrtbBid, numImps)
 
 	for bidderName, seatBid := range seatBids {
 		if seatBid != nil {
 			for _, bid := range seatBid.bids {
-				cpm := bid.bid.Price
 				wbid, ok := winningBids[bid.bid.ImpID]
-				if !ok || cpm > wbid.bid.Price {
+				if !ok || isNewWinningBid(bid.bid, wbid.bid, preferDeals) {
 					winningBids[bid.bid.ImpID] = bid
 				}
 				if bidMap, ok := winningBidsByBidder[bid.bid.ImpID]; ok {
 					bestSoFar, ok := bidMap[bidderName]
-					if !ok || cpm > bestSoFar.bid.Price {
+					if !ok || isNewWinningBid(bid.bid, bestSoFar.bid, preferDeals) {
 						bidMap[bidderName] = bid
 					}
 				} else {
@@ -46,18 +45,46 @@ func newAuction(seatBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, numImps int
 	}
 }
 
-func (a *auction) setRoundedPrices(priceGranularity openrtb_ext.PriceGranularity) {
+// isNewWinningBid returns true if bid should replace the current best bid wbid.
+// If preferDeals is set, a bid with a deal always beats a bid without one. Otherwise the highest price wins.
+func isNewWinningBid(bid, wbid *openrtb.Bid, preferDeals bool) bool {
+	if preferDeals {
+		if len(wbid.DealID) > 0 && len(bid.DealID) == 0 {
+			return false
+		}
+		if len(wbid.DealID) == 0 && len(bid.DealID) > 0 {
+			return true
+		}
+	}
+	return bid.Price > wbid.Price
+}
+
+// setRoundedPrices rounds the price of each bidder's top bid. Bids with a deal are also rounded with
+// dealPriceGranularity, falling back to priceGranularity if the request didn't define one.
+func (a *auction) setRoundedPrices(priceGranularity openrtb_ext.PriceGranularity, dealPriceGranularity *openrtb_ext.PriceGranularity) {
+	if dealPriceGranularity == nil {
+		dealPriceGranularity = &priceGranularity
+	}
 	roundedPrices := make(map[*pbsOrtbBid]string, 5*len(a.winningBids))
+	roundedDealPrices := make(map[*pbsOrtbBid]string)
 	for _, topBidsPerImp := range a.winningBidsByBidder {
 		for _, topBidPerBidder := range topBidsPerImp {
-			roundedPrice, err := GetCpmStringValue(topBidPerBidder.bid.Price, priceGranularity)
-			if err != nil {
-				glog.Errorf(`Error rounding price according to granularity. This shouldn't happen unless /openrtb2 input validation is buggy. Granularity was "%v".`, priceGranularity)
+			roundedPrices[topBidPerBidder] = roundPrice(topBidPerBidder.bid.Price, priceGranularity)
+			if len(topBidPerBidder.bid.DealID) > 0 {
+				roundedDealPrices[topBidPerBidder] = roundPrice(topBidPerBidder.bid.Price, *dealPriceGranularity)
 			}
-			roundedPrices[topBidPerBidder] = roundedPrice
 		}
 	}
 	a.roundedPrices = roundedPrices
+	a.roundedDealPrices = roundedDealPrices
+}
+
+func roundPrice(price float64, priceGranularity openrtb_ext.PriceGranularity) string {
+	roundedPrice, err := GetCpmStringValue(price, priceGranularity)
+	if err != nil {
+		glog.Errorf(`Error rounding price according to granularity. This shouldn't happen unless /openrtb2 input validation is buggy. Granularity was "%v".`, priceGranularity)
+	}
+	return roundedPrice
 }
 
 func (a *auction) doCache(ctx context.Context, cache prebid_cache_client.Client, targData *targetData, bidRequest *openrtb.BidRequest, ttlBuffer int64, defaultTTLs *config.DefaultTTLs, bidCategory map[string]string) []error {
@@ -245,11 +272,14 @@ func defTTL(bidType openrtb_ext.BidType, defaultTTLs *config.DefaultTTLs) (ttl i
 
 type auction struct {
 	// winningBids is a map from imp.id to the highest overall CPM bid in that imp.
+	// If the request prefers deals, a deal bid wins over any non-deal bid regardless of CPM.
 	winningBids map[string]*pbsOrtbBid
 	// winningBidsByBidder stores the highest bid on each imp by each bidder.
 	winningBidsByBidder map[string]map[openrtb_ext.BidderName]*pbsOrtbBid
 	// roundedPrices stores the price strings rounded for each bid according to the price granularity.
 	roundedPrices map[*pbsOrtbBid]string
+	// roundedDealPrices stores the price strings of the bids with a deal, rounded according to the deal price granularity.
+	roundedDealPrices map[*pbsOrtbBid]string
 	// cacheIds stores the UUIDs from Prebid Cache for fetching the full bid JSON.
 	cacheIds map[*openrtb.Bid]string
 	// vastCacheIds stores UUIDS from Prebid cache for fetching the VAST markup to video bids.
@@ -260,3 +260,88 @@ func (c *mockCache) PutJson(ctx context.Context, values []prebid_cache_client.Ca
 	c.items = values
 	return []string{"", "", "", "", ""}, nil
 }
+
+func TestIsNewWinningBid(t *testing.T) {
+	testCases := []struct {
+		description string
+		bid         *openrtb.Bid
+		wbid        *openrtb.Bid
+		preferDeals bool
+		expected    bool
+	}{
+		{
+			description: "Higher price wins without preferdeals",
+			bid:         &openrtb.Bid{Price: 2},
+			wbid:        &openrtb.Bid{Price: 1, DealID: "deal"},
+			preferDeals: false,
+			expected:    true,
+		},
+		{
+			description: "Deal bid wins over higher non-deal bid with preferdeals",
+			bid:         &openrtb.Bid{Price: 1, DealID: "deal"},
+			wbid:        &openrtb.Bid{Price: 2},
+			preferDeals: true,
+			expected:    true,
+		},
+		{
+			description: "Non-deal bid can't replace a deal bid with preferdeals",
+			bid:         &openrtb.Bid{Price: 2},
+			wbid:        &openrtb.Bid{Price: 1, DealID: "deal"},
+			preferDeals: true,
+			expected:    false,
+		},
+		{
+			description: "Higher price wins between two deal bids with preferdeals",
+			bid:         &openrtb.Bid{Price: 2, DealID: "deal-1"},
+			wbid:        &openrtb.Bid{Price: 1, DealID: "deal-2"},
+			preferDeals: true,
+			expected:    true,
+		},
+		{
+			description: "Lower price loses between two non-deal bids with preferdeals",
+			bid:         &openrtb.Bid{Price: 1},
+			wbid:        &openrtb.Bid{Price: 2},
+			preferDeals: true,
+			expected:    false,
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, isNewWinningBid(test.bid, test.wbid, test.preferDeals), test.description)
+	}
+}
+
+func TestNewAuctionPreferDeals(t *testing.T) {
+	dealBid := &pbsOrtbBid{bid: &openrtb.Bid{ID: "deal-bid", ImpID: "imp", Price: 1, DealID: "deal"}}
+	openBid := &pbsOrtbBid{bid: &openrtb.Bid{ID: "open-bid", ImpID: "imp", Price: 5}}
+	seatBids := map[openrtb_ext.BidderName]*pbsOrtbSeatBid{
+		openrtb_ext.BidderAppnexus: {bids: []*pbsOrtbBid{dealBid}},
+		openrtb_ext.BidderRubicon:  {bids: []*pbsOrtbBid{openBid}},
+	}
+
+	auc := newAuction(seatBids, 1, false)
+	assert.Equal(t, openBid, auc.winningBids["imp"], "The highest price should win without preferdeals")
+
+	auc = newAuction(seatBids, 1, true)
+	assert.Equal(t, dealBid, auc.winningBids["imp"], "The deal bid should win with preferdeals")
+}
+
+func TestSetRoundedDealPrices(t *testing.T) {
+	dealBid := &pbsOrtbBid{bid: &openrtb.Bid{ID: "deal-bid", ImpID: "imp", Price: 1.37, DealID: "deal"}}
+	openBid := &pbsOrtbBid{bid: &openrtb.Bid{ID: "open-bid", ImpID: "imp", Price: 5.37}}
+	seatBids := map[openrtb_ext.BidderName]*pbsOrtbSeatBid{
+		openrtb_ext.BidderAppnexus: {bids: []*pbsOrtbBid{dealBid}},
+		openrtb_ext.BidderRubicon:  {bids: []*pbsOrtbBid{openBid}},
+	}
+	dealGranularity := openrtb_ext.PriceGranularityFromString("low")
+
+	auc := newAuction(seatBids, 1, true)
+	auc.setRoundedPrices(openrtb_ext.PriceGranularityFromString("med"), &dealGranularity)
+	assert.Equal(t, "1.30", auc.roundedPrices[dealBid])
+	assert.Equal(t, "5.30", auc.roundedPrices[openBid])
+	assert.Equal(t, "1.00", auc.roundedDealPrices[dealBid])
+	assert.NotContains(t, auc.roundedDealPrices, openBid)
+
+	auc.setRoundedPrices(openrtb_ext.PriceGranularityFromString("med"), nil)
+	assert.Equal(t, "1.30", auc.roundedDealPrices[dealBid], "The price granularity should be used if no deal price granularity was given")
+}
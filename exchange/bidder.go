@@ -2,12 +2,16 @@ package exchange
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptrace"
+	"time"
 
 	"github.com/PubMatic-OpenWrap/openrtb"
 	nativeRequests "github.com/PubMatic-OpenWrap/openrtb/native/request"
@@ -16,6 +20,7 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/currencies"
 	"github.com/PubMatic-OpenWrap/prebid-server/errortypes"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
 	"golang.org/x/net/context/ctxhttp"
 )
 
@@ -79,22 +84,51 @@ type pbsOrtbSeatBid struct {
 //
 // The name refers to the "Adapter" architecture pattern, and should not be confused with a Prebid "Adapter"
 // (which is being phased out and replaced by Bidder for OpenRTB auctions)
-func adaptBidder(bidder adapters.Bidder, client *http.Client) adaptedBidder {
+func adaptBidder(bidder adapters.Bidder, client *http.Client, config bidderAdapterConfig, me pbsmetrics.MetricsEngine, name openrtb_ext.BidderName) adaptedBidder {
 	return &bidderAdapter{
-		Bidder: bidder,
-		Client: client,
+		Bidder:     bidder,
+		BidderName: name,
+		Client:     client,
+		config:     config,
+		me:         me,
 	}
 }
 
 type bidderAdapter struct {
-	Bidder adapters.Bidder
-	Client *http.Client
+	Bidder     adapters.Bidder
+	BidderName openrtb_ext.BidderName
+	Client     *http.Client
+	config     bidderAdapterConfig
+	me         pbsmetrics.MetricsEngine
+}
+
+// bidderAdapterConfig holds the host's limits on the requests a Bidder makes. A zero value means "no limit".
+type bidderAdapterConfig struct {
+	// MaxImps is the most imps the Bidder gets in a single call to MakeRequests.
+	// Requests with more imps are split into chunks of at most MaxImps imps.
+	MaxImps int
+	// MaxHTTPCalls is the most HTTP calls the Bidder may have in flight at once for a single auction.
+	// The others wait for one of them to finish.
+	MaxHTTPCalls int
+	// GzipRequests compresses the body of every HTTP call made to the Bidder.
+	GzipRequests bool
+	// RecordConnectionMetrics traces every HTTP call to record whether it reused a connection.
+	RecordConnectionMetrics bool
+	// OpenRTB26 moves the OpenRTB 2.6 fields of the Bidder's requests out of their OpenRTB 2.5 ext locations.
+	OpenRTB26 bool
 }
 
 func (bidder *bidderAdapter) requestBid(ctx context.Context, request *openrtb.BidRequest, name openrtb_ext.BidderName, bidAdjustment float64, conversions currencies.Conversions, reqInfo *adapters.ExtraRequestInfo, debug bool) (*pbsOrtbSeatBid, []error) {
-	reqData, errs := bidder.Bidder.MakeRequests(request, reqInfo)
+	liveRequest, storedCalls, errs := bidder.makeStoredCalls(request, reqInfo)
 
-	if len(reqData) == 0 {
+	var reqData []*adapters.RequestData
+	if allStored := len(request.Imp) > 0 && len(liveRequest.Imp) == 0; !allStored {
+		var moreErrs []error
+		reqData, moreErrs = bidder.makeRequests(liveRequest, reqInfo)
+		errs = append(errs, moreErrs...)
+	}
+
+	if len(reqData) == 0 && len(storedCalls) == 0 {
 		// If the adapter failed to generate both requests and errors, this is an error.
 		if len(errs) == 0 {
 			errs = append(errs, &errortypes.FailedToRequestBids{Message: "The adapter failed to generate any bid requests, but also failed to generate an error explaining why"})
@@ -104,27 +138,46 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, request *openrtb.Bi
 
 	// Make any HTTP requests in parallel.
 	// If the bidder only needs to make one, save some cycles by just using the current one.
-	responseChannel := make(chan *httpCallInfo, len(reqData))
+	// The Stored Bid Responses are already there, as if their calls had been made.
+	responseChannel := make(chan *httpCallInfo, len(reqData)+len(storedCalls))
+	for _, call := range storedCalls {
+		responseChannel <- call
+	}
+	// If the host limits the calls in flight, the semaphore holds back the others until one finishes.
+	var inFlight chan struct{}
+	if maxCalls := bidder.config.MaxHTTPCalls; maxCalls > 0 && len(reqData) > maxCalls {
+		inFlight = make(chan struct{}, maxCalls)
+	}
 	if len(reqData) == 1 {
 		responseChannel <- bidder.doRequest(ctx, reqData[0])
 	} else {
 		for _, oneReqData := range reqData {
 			go func(data *adapters.RequestData) {
+				if inFlight != nil {
+					select {
+					case inFlight <- struct{}{}:
+					case <-ctx.Done():
+						responseChannel <- &httpCallInfo{request: data, err: ctx.Err()}
+						return
+					}
+					defer func() { <-inFlight }()
+				}
 				responseChannel <- bidder.doRequest(ctx, data)
 			}(oneReqData) // Method arg avoids a race condition on oneReqData
 		}
 	}
 
+	numCalls := len(reqData) + len(storedCalls)
 	defaultCurrency := "USD"
 	seatBid := &pbsOrtbSeatBid{
-		bids:      make([]*pbsOrtbBid, 0, len(reqData)),
+		bids:      make([]*pbsOrtbBid, 0, numCalls),
 		currency:  defaultCurrency,
-		httpCalls: make([]*openrtb_ext.ExtHttpCall, 0, len(reqData)),
+		httpCalls: make([]*openrtb_ext.ExtHttpCall, 0, numCalls),
 	}
 
 	// If the bidder made multiple requests, we still want them to enter as many bids as possible...
 	// even if the timeout occurs sometime halfway through.
-	for i := 0; i < len(reqData); i++ {
+	for i := 0; i < numCalls; i++ {
 		httpInfo := <-responseChannel
 		// If this is a test bid, capture debugging info from the requests.
 		if debug {
@@ -132,6 +185,9 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, request *openrtb.Bi
 		}
 
 		if httpInfo.err == nil {
+			if bidder.config.OpenRTB26 {
+				errs = append(errs, convertResponseDownTo25(httpInfo.response)...)
+			}
 			bidResponse, moreErrs := bidder.Bidder.MakeBids(request, httpInfo.request, httpInfo.response)
 			errs = append(errs, moreErrs...)
 
@@ -201,6 +257,160 @@ func (bidder *bidderAdapter) requestBid(ctx context.Context, request *openrtb.Bi
 	return seatBid, errs
 }
 
+// convertResponseDownTo25 moves the OpenRTB 2.6 fields of the bids in the response to bid.ext, where the
+// Bidder's openrtb types can keep them. A response which can't be converted is left alone, with a warning.
+func convertResponseDownTo25(response *adapters.ResponseData) []error {
+	if response == nil || response.StatusCode != http.StatusOK || len(response.Body) == 0 {
+		return nil
+	}
+	converted, err := openrtb_ext.ConvertBidderResponseDownTo25(response.Body)
+	if err != nil {
+		return []error{&errortypes.Warning{
+			Message: fmt.Sprintf("The response wasn't converted from OpenRTB 2.6: %v", err),
+		}}
+	}
+	response.Body = converted
+	return nil
+}
+
+// makeStoredCalls takes the imps which have a Stored Bid Response out of the request, and pairs each one
+// with the call the Bidder would have made for it. The Stored Bid Response stands in for the server's response.
+//
+// It returns the request for the remaining imps, which still go to the server.
+func (bidder *bidderAdapter) makeStoredCalls(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) (*openrtb.BidRequest, []*httpCallInfo, []error) {
+	if len(reqInfo.StoredBidResponses) == 0 {
+		return request, nil, nil
+	}
+
+	var storedCalls []*httpCallInfo
+	var errs []error
+	liveRequest := *request
+	liveRequest.Imp = make([]openrtb.Imp, 0, len(request.Imp))
+	for _, imp := range request.Imp {
+		storedResponse, ok := reqInfo.StoredBidResponses[imp.ID]
+		if !ok {
+			liveRequest.Imp = append(liveRequest.Imp, imp)
+			continue
+		}
+
+		// The Bidder's own request lets MakeBids check the response against it, as it would for a live call.
+		// It's built the way a live call is, but never sent, so the limit on the calls in flight doesn't apply.
+		impRequest := *request
+		impRequest.Imp = []openrtb.Imp{imp}
+		reqData, reqErrs := bidder.makeRequests(&impRequest, reqInfo)
+		errs = append(errs, reqErrs...)
+		var calls []*adapters.RequestData
+		for _, data := range reqData {
+			if data != nil {
+				calls = append(calls, data)
+			}
+		}
+		if len(calls) == 0 {
+			continue
+		}
+		if len(calls) > 1 {
+			errs = append(errs, &errortypes.Warning{
+				Message: fmt.Sprintf("The Bidder made %d calls for imp %s, but its Stored Bid Response only answers the first", len(calls), imp.ID),
+			})
+		}
+		storedCalls = append(storedCalls, &httpCallInfo{
+			request: calls[0],
+			response: &adapters.ResponseData{
+				StatusCode: http.StatusOK,
+				Body:       storedResponse,
+				Headers:    http.Header{},
+			},
+		})
+	}
+	return &liveRequest, storedCalls, errs
+}
+
+// makeRequests asks the Bidder for the HTTP calls it needs to make, splitting the request by the host's MaxImps.
+// Identical calls are only made once, since they would only return the same bids twice.
+func (bidder *bidderAdapter) makeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	var reqData []*adapters.RequestData
+	var errs []error
+	for _, chunk := range chunkImps(request, bidder.config.MaxImps) {
+		chunkData, chunkErrs := bidder.Bidder.MakeRequests(chunk, reqInfo)
+		reqData = append(reqData, chunkData...)
+		errs = append(errs, chunkErrs...)
+	}
+
+	reqData = removeDuplicateRequests(reqData)
+
+	if bidder.config.OpenRTB26 {
+		for _, data := range reqData {
+			// A body which can't be converted is sent as it is, with a warning.
+			converted, err := openrtb_ext.ConvertUpTo26(data.Body)
+			if err != nil {
+				errs = append(errs, &errortypes.Warning{
+					Message: fmt.Sprintf("The request to %s wasn't converted to OpenRTB 2.6: %v", data.Uri, err),
+				})
+				continue
+			}
+			data.Body = converted
+		}
+	}
+	return reqData, errs
+}
+
+// chunkImps splits the request into copies holding at most maxImps imps each.
+// If maxImps isn't positive, or the request is small enough already, it is returned as is.
+func chunkImps(request *openrtb.BidRequest, maxImps int) []*openrtb.BidRequest {
+	if maxImps <= 0 || len(request.Imp) <= maxImps {
+		return []*openrtb.BidRequest{request}
+	}
+
+	chunks := make([]*openrtb.BidRequest, 0, (len(request.Imp)+maxImps-1)/maxImps)
+	for start := 0; start < len(request.Imp); start += maxImps {
+		end := start + maxImps
+		if end > len(request.Imp) {
+			end = len(request.Imp)
+		}
+		chunk := *request
+		chunk.Imp = request.Imp[start:end:end]
+		chunks = append(chunks, &chunk)
+	}
+	return chunks
+}
+
+// removeDuplicateRequests drops the calls which have the same method, URI, headers and body as an earlier one.
+func removeDuplicateRequests(reqData []*adapters.RequestData) []*adapters.RequestData {
+	if len(reqData) < 2 {
+		return reqData
+	}
+
+	seen := make(map[string]struct{}, len(reqData))
+	unique := make([]*adapters.RequestData, 0, len(reqData))
+	for _, data := range reqData {
+		if data == nil {
+			unique = append(unique, data)
+			continue
+		}
+		key := data.Method + " " + data.Uri + "\n" + headersKey(data.Headers) + "\n" + string(data.Body)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		unique = append(unique, data)
+	}
+	return unique
+}
+
+// headersKey writes the headers in a canonical order, so that the same headers make the same key
+// however the adapter set them.
+func headersKey(headers http.Header) string {
+	canonical := make(http.Header, len(headers))
+	for name, values := range headers {
+		for _, value := range values {
+			canonical.Add(name, value)
+		}
+	}
+	var buf bytes.Buffer
+	canonical.Write(&buf)
+	return buf.String()
+}
+
 func addNativeTypes(bid *openrtb.Bid, request *openrtb.BidRequest) (*nativeResponse.Response, []error) {
 	var errs []error
 	var nativeMarkup *nativeResponse.Response
@@ -283,14 +493,33 @@ func makeExt(httpInfo *httpCallInfo) *openrtb_ext.ExtHttpCall {
 // doRequest makes a request, handles the response, and returns the data needed by the
 // Bidder interface.
 func (bidder *bidderAdapter) doRequest(ctx context.Context, req *adapters.RequestData) *httpCallInfo {
-	httpReq, err := http.NewRequest(req.Method, req.Uri, bytes.NewBuffer(req.Body))
+	body := req.Body
+	headers := req.Headers
+	if bidder.config.GzipRequests && len(req.Body) > 0 {
+		compressed, err := gzipBody(req.Body)
+		if err != nil {
+			return &httpCallInfo{
+				request: req,
+				err:     err,
+			}
+		}
+		body = compressed
+		headers = copyHeaders(req.Headers)
+		headers.Set("Content-Encoding", "gzip")
+	}
+
+	httpReq, err := http.NewRequest(req.Method, req.Uri, bytes.NewBuffer(body))
 	if err != nil {
 		return &httpCallInfo{
 			request: req,
 			err:     err,
 		}
 	}
-	httpReq.Header = req.Headers
+	httpReq.Header = headers
+
+	if bidder.config.RecordConnectionMetrics {
+		ctx = bidder.addClientTrace(ctx)
+	}
 
 	httpResp, err := ctxhttp.Do(ctx, bidder.Client, httpReq)
 	if err != nil {
@@ -303,7 +532,7 @@ func (bidder *bidderAdapter) doRequest(ctx context.Context, req *adapters.Reques
 		}
 	}
 
-	respBody, err := ioutil.ReadAll(httpResp.Body)
+	respBody, err := readResponseBody(httpResp)
 	if err != nil {
 		return &httpCallInfo{
 			request: req,
@@ -329,6 +558,57 @@ func (bidder *bidderAdapter) doRequest(ctx context.Context, req *adapters.Reques
 	}
 }
 
+// addClientTrace records, for every connection the call gets, whether it was reused and how long it took to get it.
+func (bidder *bidderAdapter) addClientTrace(ctx context.Context) context.Context {
+	var getConnStart time.Time
+	trace := &httptrace.ClientTrace{
+		GetConn: func(hostPort string) {
+			getConnStart = time.Now()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			bidder.me.RecordAdapterConnections(bidder.BidderName, info.Reused, time.Since(getConnStart))
+		},
+	}
+	return httptrace.WithClientTrace(ctx, trace)
+}
+
+// readResponseBody reads the whole body of the response. Go only decompresses the responses to the requests
+// where it set "Accept-Encoding: gzip" itself, so the gzipped responses to the Bidders which set it on their
+// own are decompressed here.
+func readResponseBody(httpResp *http.Response) ([]byte, error) {
+	var body io.Reader = httpResp.Body
+	if !httpResp.Uncompressed && httpResp.Header.Get("Content-Encoding") == "gzip" {
+		gzipReader, err := gzip.NewReader(httpResp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		body = gzipReader
+	}
+	return ioutil.ReadAll(body)
+}
+
+func gzipBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// copyHeaders returns a copy of the headers, so that they can be changed without affecting the Bidder's RequestData.
+func copyHeaders(headers http.Header) http.Header {
+	headersCopy := make(http.Header, len(headers)+1)
+	for key, values := range headers {
+		headersCopy[key] = append([]string(nil), values...)
+	}
+	return headersCopy
+}
+
 type httpCallInfo struct {
 	request  *adapters.RequestData
 	response *adapters.ResponseData
@@ -0,0 +1,74 @@
+package exchange
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"time"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/golang/glog"
+	"golang.org/x/net/http2"
+)
+
+// newBidderClient returns the HTTP client which a bidder should use to reach its servers.
+//
+// Bidders which don't override any of the host's transport settings share the host's client, and so its
+// connection pool. The others get a transport of their own, built from the host's settings and their overrides.
+func newBidderClient(hostClient *http.Client, hostCfg config.HTTPClient, bidderCfg config.AdapterHTTPClient) *http.Client {
+	if !hasTransportOverrides(bidderCfg) {
+		return hostClient
+	}
+
+	transport := &http.Transport{
+		MaxIdleConns:        overrideInt(bidderCfg.MaxIdleConns, hostCfg.MaxIdleConns),
+		MaxIdleConnsPerHost: overrideInt(bidderCfg.MaxIdleConnsPerHost, hostCfg.MaxIdleConnsPerHost),
+		IdleConnTimeout:     time.Duration(overrideInt(bidderCfg.IdleConnTimeout, hostCfg.IdleConnTimeout)) * time.Second,
+		DisableKeepAlives:   bidderCfg.DisableKeepAlives,
+		TLSClientConfig: &tls.Config{
+			RootCAs:    hostRootCAs(hostClient),
+			MinVersion: bidderCfg.TLSVersion(),
+		},
+	}
+	if bidderCfg.EnableHTTP2 {
+		if err := http2.ConfigureTransport(transport); err != nil {
+			glog.Errorf("Failed to enable HTTP/2 for a bidder client. Falling back to HTTP/1.1: %v", err)
+		}
+	}
+
+	client := &http.Client{Transport: transport}
+	if hostClient != nil {
+		client.Timeout = hostClient.Timeout
+	}
+	return client
+}
+
+// hasTransportOverrides returns true if the bidder config needs a transport other than the host's.
+// Request compression happens above the transport, so it doesn't count.
+func hasTransportOverrides(cfg config.AdapterHTTPClient) bool {
+	return cfg.MaxIdleConns != 0 ||
+		cfg.MaxIdleConnsPerHost != 0 ||
+		cfg.IdleConnTimeout != 0 ||
+		cfg.DisableKeepAlives ||
+		cfg.EnableHTTP2 ||
+		cfg.TLSMinVersion != ""
+}
+
+func overrideInt(bidderValue int, hostValue int) int {
+	if bidderValue > 0 {
+		return bidderValue
+	}
+	return hostValue
+}
+
+// hostRootCAs returns the certificate pool which the host's client trusts, so that bidders with their own
+// transport trust the same certificates.
+func hostRootCAs(hostClient *http.Client) *x509.CertPool {
+	if hostClient == nil {
+		return nil
+	}
+	if transport, ok := hostClient.Transport.(*http.Transport); ok && transport.TLSClientConfig != nil {
+		return transport.TLSClientConfig.RootCAs
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package exchange
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBidderClientSharesHostClient(t *testing.T) {
+	hostClient := &http.Client{}
+	bidderClient := newBidderClient(hostClient, config.HTTPClient{}, config.AdapterHTTPClient{GzipRequests: true})
+	assert.True(t, hostClient == bidderClient, "Bidders without transport overrides should share the host's client")
+}
+
+func TestNewBidderClientOverrides(t *testing.T) {
+	rootCAs := x509.NewCertPool()
+	hostClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: rootCAs},
+		},
+		Timeout: 5 * time.Second,
+	}
+	hostCfg := config.HTTPClient{
+		MaxIdleConns:        400,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     60,
+	}
+	bidderCfg := config.AdapterHTTPClient{
+		MaxIdleConnsPerHost: 50,
+		DisableKeepAlives:   true,
+		TLSMinVersion:       "1.2",
+	}
+
+	bidderClient := newBidderClient(hostClient, hostCfg, bidderCfg)
+
+	assert.False(t, hostClient == bidderClient, "Bidders with transport overrides should get their own client")
+	assert.Equal(t, 5*time.Second, bidderClient.Timeout)
+	transport, ok := bidderClient.Transport.(*http.Transport)
+	if !assert.True(t, ok, "The bidder client should use an *http.Transport") {
+		return
+	}
+	assert.Equal(t, 400, transport.MaxIdleConns)
+	assert.Equal(t, 50, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 60*time.Second, transport.IdleConnTimeout)
+	assert.True(t, transport.DisableKeepAlives)
+	assert.True(t, rootCAs == transport.TLSClientConfig.RootCAs, "The bidder client should trust the host's certificates")
+	assert.Equal(t, uint16(tls.VersionTLS12), transport.TLSClientConfig.MinVersion)
+	assert.NotContains(t, transport.TLSNextProto, "h2")
+}
+
+func TestNewBidderClientHTTP2(t *testing.T) {
+	bidderClient := newBidderClient(&http.Client{}, config.HTTPClient{}, config.AdapterHTTPClient{EnableHTTP2: true})
+
+	transport, ok := bidderClient.Transport.(*http.Transport)
+	if assert.True(t, ok, "The bidder client should use an *http.Transport") {
+		assert.Contains(t, transport.TLSNextProto, "h2")
+	}
+}
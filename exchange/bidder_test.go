@@ -1,20 +1,28 @@
 package exchange
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/PubMatic-OpenWrap/openrtb"
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters"
 	"github.com/PubMatic-OpenWrap/prebid-server/currencies"
+	"github.com/PubMatic-OpenWrap/prebid-server/errortypes"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // TestSingleBidder makes sure that the following things work if the Bidder needs only one request.
@@ -59,7 +67,7 @@ func TestSingleBidder(t *testing.T) {
 		},
 		bidResponse: mockBidderResponse,
 	}
-	bidder := adaptBidder(bidderImpl, server.Client())
+	bidder := adaptBidder(bidderImpl, server.Client(), bidderAdapterConfig{}, &metricsConf.DummyMetricsEngine{}, "test")
 	currencyConverter := currencies.NewRateConverterDefault()
 	seatBid, errs := bidder.requestBid(context.Background(), &openrtb.BidRequest{}, "test", bidAdjustment, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, false)
 
@@ -144,7 +152,7 @@ func TestMultiBidder(t *testing.T) {
 			}},
 		bidResponse: mockBidderResponse,
 	}
-	bidder := adaptBidder(bidderImpl, server.Client())
+	bidder := adaptBidder(bidderImpl, server.Client(), bidderAdapterConfig{}, &metricsConf.DummyMetricsEngine{}, "test")
 	currencyConverter := currencies.NewRateConverterDefault()
 	seatBid, errs := bidder.requestBid(context.Background(), &openrtb.BidRequest{}, "test", 1.0, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, false)
 
@@ -484,7 +492,7 @@ func TestMultiCurrencies(t *testing.T) {
 			bidderImpl.httpRequest[i] = &adapters.RequestData{
 				Method:  "POST",
 				Uri:     server.URL,
-				Body:    []byte("{\"key\":\"val\"}"),
+				Body:    []byte(fmt.Sprintf("{\"key\":\"val%d\"}", i)),
 				Headers: http.Header{},
 			}
 		}
@@ -502,7 +510,7 @@ func TestMultiCurrencies(t *testing.T) {
 		)
 
 		// Execute:
-		bidder := adaptBidder(bidderImpl, server.Client())
+		bidder := adaptBidder(bidderImpl, server.Client(), bidderAdapterConfig{}, &metricsConf.DummyMetricsEngine{}, "test")
 		currencyConverter := currencies.NewRateConverter(
 			&http.Client{},
 			mockedHTTPServer.URL,
@@ -645,13 +653,13 @@ func TestMultiCurrencies_RateConverterNotSet(t *testing.T) {
 			bidderImpl.httpRequest[i] = &adapters.RequestData{
 				Method:  "POST",
 				Uri:     server.URL,
-				Body:    []byte("{\"key\":\"val\"}"),
+				Body:    []byte(fmt.Sprintf("{\"key\":\"val%d\"}", i)),
 				Headers: http.Header{},
 			}
 		}
 
 		// Execute:
-		bidder := adaptBidder(bidderImpl, server.Client())
+		bidder := adaptBidder(bidderImpl, server.Client(), bidderAdapterConfig{}, &metricsConf.DummyMetricsEngine{}, "test")
 		currencyConverter := currencies.NewRateConverterDefault()
 		seatBid, errs := bidder.requestBid(
 			context.Background(),
@@ -818,7 +826,7 @@ func TestMultiCurrencies_RequestCurrencyPick(t *testing.T) {
 		}
 
 		// Execute:
-		bidder := adaptBidder(bidderImpl, server.Client())
+		bidder := adaptBidder(bidderImpl, server.Client(), bidderAdapterConfig{}, &metricsConf.DummyMetricsEngine{}, "test")
 		currencyConverter := currencies.NewRateConverter(
 			&http.Client{},
 			mockedHTTPServer.URL,
@@ -934,7 +942,7 @@ func TestServerCallDebugging(t *testing.T) {
 			Headers: http.Header{},
 		},
 	}
-	bidder := adaptBidder(bidderImpl, server.Client())
+	bidder := adaptBidder(bidderImpl, server.Client(), bidderAdapterConfig{}, &metricsConf.DummyMetricsEngine{}, "test")
 	currencyConverter := currencies.NewRateConverterDefault()
 
 	bids, _ := bidder.requestBid(
@@ -1047,7 +1055,7 @@ func TestMobileNativeTypes(t *testing.T) {
 			},
 			bidResponse: tc.mockBidderResponse,
 		}
-		bidder := adaptBidder(bidderImpl, server.Client())
+		bidder := adaptBidder(bidderImpl, server.Client(), bidderAdapterConfig{}, &metricsConf.DummyMetricsEngine{}, "test")
 		currencyConverter := currencies.NewRateConverterDefault()
 
 		seatBids, _ := bidder.requestBid(
@@ -1069,7 +1077,7 @@ func TestMobileNativeTypes(t *testing.T) {
 }
 
 func TestErrorReporting(t *testing.T) {
-	bidder := adaptBidder(&bidRejector{}, nil)
+	bidder := adaptBidder(&bidRejector{}, nil, bidderAdapterConfig{}, &metricsConf.DummyMetricsEngine{}, "test")
 	currencyConverter := currencies.NewRateConverterDefault()
 	bids, errs := bidder.requestBid(context.Background(), &openrtb.BidRequest{}, "test", 1.0, currencyConverter.Rates(), &adapters.ExtraRequestInfo{}, false)
 	if bids != nil {
@@ -1083,6 +1091,414 @@ func TestErrorReporting(t *testing.T) {
 	}
 }
 
+func TestMakeRequestsMaxImps(t *testing.T) {
+	bidderImpl := &perImpBidder{}
+	bidder := &bidderAdapter{
+		Bidder: bidderImpl,
+		config: bidderAdapterConfig{MaxImps: 2},
+	}
+	request := &openrtb.BidRequest{
+		ID:  "request",
+		Imp: []openrtb.Imp{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}, {ID: "5"}},
+	}
+
+	reqData, errs := bidder.makeRequests(request, &adapters.ExtraRequestInfo{})
+
+	assert.Empty(t, errs)
+	assert.Len(t, reqData, 5)
+	if assert.Len(t, bidderImpl.requests, 3, "MakeRequests should be called once per chunk") {
+		assert.Equal(t, []openrtb.Imp{{ID: "1"}, {ID: "2"}}, bidderImpl.requests[0].Imp)
+		assert.Equal(t, []openrtb.Imp{{ID: "3"}, {ID: "4"}}, bidderImpl.requests[1].Imp)
+		assert.Equal(t, []openrtb.Imp{{ID: "5"}}, bidderImpl.requests[2].Imp)
+		assert.Equal(t, "request", bidderImpl.requests[2].ID)
+	}
+	assert.Len(t, request.Imp, 5, "The original request should not be modified")
+}
+
+func TestRequestBidMaxHTTPCalls(t *testing.T) {
+	var lock sync.Mutex
+	var inFlight, maxInFlight, served int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		lock.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		lock.Lock()
+		inFlight--
+		served++
+		lock.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	bidderImpl := &goodMultiHTTPCallsBidder{}
+	for i := 0; i < 5; i++ {
+		bidderImpl.httpRequest = append(bidderImpl.httpRequest, &adapters.RequestData{
+			Method:  "POST",
+			Uri:     server.URL + "/" + strconv.Itoa(i),
+			Body:    []byte("{}"),
+			Headers: http.Header{},
+		})
+		bidderImpl.bidResponses = append(bidderImpl.bidResponses, nil)
+	}
+	bidder := adaptBidder(bidderImpl, server.Client(), bidderAdapterConfig{MaxHTTPCalls: 2}, &metricsConf.DummyMetricsEngine{}, "test")
+
+	_, errs := bidder.requestBid(context.Background(), &openrtb.BidRequest{}, "test", 1.0, currencies.NewRateConverterDefault().Rates(), &adapters.ExtraRequestInfo{}, false)
+
+	assert.Empty(t, errs)
+	assert.Equal(t, 5, served, "Every call should be made")
+	assert.True(t, maxInFlight <= 2, "At most 2 calls should be in flight at once. Got %d", maxInFlight)
+}
+
+func TestMakeRequestsRemovesDuplicates(t *testing.T) {
+	bidder := &bidderAdapter{
+		Bidder: &perImpBidder{},
+	}
+	request := &openrtb.BidRequest{
+		Imp: []openrtb.Imp{{ID: "1"}, {ID: "2"}, {ID: "1"}},
+	}
+
+	reqData, errs := bidder.makeRequests(request, &adapters.ExtraRequestInfo{})
+
+	assert.Empty(t, errs)
+	if assert.Len(t, reqData, 2) {
+		assert.Equal(t, "http://bidder.com/1", reqData[0].Uri)
+		assert.Equal(t, "http://bidder.com/2", reqData[1].Uri)
+	}
+}
+
+func TestRemoveDuplicateRequests(t *testing.T) {
+	newRequest := func(headers http.Header) *adapters.RequestData {
+		return &adapters.RequestData{Method: "POST", Uri: "http://bidder.com", Body: []byte("{}"), Headers: headers}
+	}
+	testCases := []struct {
+		description string
+		reqData     []*adapters.RequestData
+		expected    int
+	}{
+		{
+			description: "Same headers",
+			reqData:     []*adapters.RequestData{newRequest(http.Header{"X-Seat": {"1"}}), newRequest(http.Header{"X-Seat": {"1"}})},
+			expected:    1,
+		},
+		{
+			description: "Same headers with different cases",
+			reqData:     []*adapters.RequestData{newRequest(http.Header{"X-Seat": {"1"}, "Accept": {"json"}}), newRequest(http.Header{"x-seat": {"1"}, "accept": {"json"}})},
+			expected:    1,
+		},
+		{
+			description: "Different headers",
+			reqData:     []*adapters.RequestData{newRequest(http.Header{"X-Seat": {"1"}}), newRequest(http.Header{"X-Seat": {"2"}})},
+			expected:    2,
+		},
+		{
+			description: "Missing headers",
+			reqData:     []*adapters.RequestData{newRequest(http.Header{"X-Seat": {"1"}}), newRequest(nil)},
+			expected:    2,
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Len(t, removeDuplicateRequests(test.reqData), test.expected, test.description)
+	}
+}
+
+func TestMakeRequestsOpenRTB26(t *testing.T) {
+	ortb25Body := []byte(`{"id":"req","regs":{"ext":{"gpp":"abc","gpp_sid":[2]}},"source":{"ext":{"schain":{"ver":"1.0"}}}}`)
+	for _, supports26 := range []bool{false, true} {
+		bidder := &bidderAdapter{
+			Bidder: &goodSingleBidder{httpRequest: &adapters.RequestData{Method: "POST", Uri: "http://bidder.com", Body: ortb25Body}},
+			config: bidderAdapterConfig{OpenRTB26: supports26},
+		}
+
+		reqData, errs := bidder.makeRequests(&openrtb.BidRequest{}, &adapters.ExtraRequestInfo{})
+
+		assert.Empty(t, errs)
+		if !assert.Len(t, reqData, 1) {
+			continue
+		}
+		if supports26 {
+			assert.JSONEq(t, `{"id":"req","regs":{"gpp":"abc","gpp_sid":[2]},"source":{"schain":{"ver":"1.0"}}}`, string(reqData[0].Body),
+				"A bidder which reads OpenRTB 2.6 should get the fields in their 2.6 locations")
+		} else {
+			assert.JSONEq(t, string(ortb25Body), string(reqData[0].Body))
+		}
+	}
+
+	bidder := &bidderAdapter{
+		Bidder: &goodSingleBidder{httpRequest: &adapters.RequestData{Method: "GET", Uri: "http://bidder.com", Body: []byte("a=b")}},
+		config: bidderAdapterConfig{OpenRTB26: true},
+	}
+	reqData, errs := bidder.makeRequests(&openrtb.BidRequest{}, &adapters.ExtraRequestInfo{})
+	assert.Equal(t, "a=b", string(reqData[0].Body), "A body which isn't JSON should be sent as it is")
+	if assert.Len(t, errs, 1) {
+		assert.IsType(t, &errortypes.Warning{}, errs[0])
+		assert.Contains(t, errs[0].Error(), "The request to http://bidder.com wasn't converted to OpenRTB 2.6")
+	}
+}
+
+func TestRequestBidOpenRTB26Response(t *testing.T) {
+	testCases := []struct {
+		description      string
+		responseBody     string
+		expectedBody     string
+		expectedWarnings int
+	}{
+		{
+			description:  "2.6 Fields Moved To Ext",
+			responseBody: `{"seatbid":[{"bid":[{"id":"1","price":1,"mtype":2,"dur":30}]}]}`,
+			expectedBody: `{"seatbid":[{"bid":[{"id":"1","price":1,"ext":{"mtype":2,"dur":30}}]}]}`,
+		},
+		{
+			description:      "Not JSON",
+			responseBody:     `not json`,
+			expectedBody:     `not json`,
+			expectedWarnings: 1,
+		},
+	}
+
+	for _, test := range testCases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(test.responseBody))
+		}))
+
+		bidderImpl := &goodSingleBidder{
+			httpRequest: &adapters.RequestData{Method: "POST", Uri: server.URL, Body: []byte(`{}`), Headers: http.Header{}},
+			bidResponse: &adapters.BidderResponse{},
+		}
+		bidder := adaptBidder(bidderImpl, server.Client(), bidderAdapterConfig{OpenRTB26: true}, &metricsConf.DummyMetricsEngine{}, "test")
+		_, errs := bidder.requestBid(context.Background(), &openrtb.BidRequest{}, "test", 1.0, currencies.NewRateConverterDefault().Rates(), &adapters.ExtraRequestInfo{}, false)
+		server.Close()
+
+		assert.Len(t, errs, test.expectedWarnings, test.description)
+		if !assert.NotNil(t, bidderImpl.httpResponse, test.description) {
+			continue
+		}
+		if test.expectedWarnings > 0 {
+			assert.Equal(t, test.expectedBody, string(bidderImpl.httpResponse.Body), test.description)
+		} else {
+			assert.JSONEq(t, test.expectedBody, string(bidderImpl.httpResponse.Body), test.description)
+		}
+	}
+}
+
+func TestGzipRequests(t *testing.T) {
+	var receivedEncoding string
+	var receivedBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedEncoding = r.Header.Get("Content-Encoding")
+		gzipReader, err := gzip.NewReader(r.Body)
+		if err == nil {
+			receivedBody, _ = ioutil.ReadAll(gzipReader)
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	bidder := &bidderAdapter{
+		Bidder: &mixedMultiBidder{},
+		Client: server.Client(),
+		config: bidderAdapterConfig{GzipRequests: true},
+	}
+	reqData := &adapters.RequestData{
+		Method:  "POST",
+		Uri:     server.URL,
+		Body:    []byte(`{"id":"request"}`),
+		Headers: http.Header{"Content-Type": []string{"application/json"}},
+	}
+
+	callInfo := bidder.doRequest(context.Background(), reqData)
+
+	assert.NoError(t, callInfo.err)
+	assert.Equal(t, "gzip", receivedEncoding)
+	assert.Equal(t, `{"id":"request"}`, string(receivedBody))
+	assert.Empty(t, reqData.Headers.Get("Content-Encoding"), "The bidder's headers should not be modified")
+}
+
+func TestGzipResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		gzipWriter := gzip.NewWriter(w)
+		gzipWriter.Write([]byte(`{"seatbid":[]}`))
+		gzipWriter.Close()
+	}))
+	defer server.Close()
+
+	bidder := &bidderAdapter{
+		Bidder: &mixedMultiBidder{},
+		Client: server.Client(),
+	}
+
+	// Go only decompresses the response by itself if the caller didn't ask for gzip explicitly.
+	callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{
+		Method:  "GET",
+		Uri:     server.URL,
+		Headers: http.Header{"Accept-Encoding": []string{"gzip"}},
+	})
+
+	assert.NoError(t, callInfo.err)
+	if assert.NotNil(t, callInfo.response) {
+		assert.Equal(t, `{"seatbid":[]}`, string(callInfo.response.Body))
+	}
+}
+
+func TestConnectionMetrics(t *testing.T) {
+	server := httptest.NewServer(mockHandler(200, "getBody", "postBody"))
+	defer server.Close()
+
+	metricsMock := &pbsmetrics.MetricsEngineMock{}
+	metricsMock.On("RecordAdapterConnections", openrtb_ext.BidderAppnexus, false, mock.Anything).Return()
+	metricsMock.On("RecordAdapterConnections", openrtb_ext.BidderAppnexus, true, mock.Anything).Return()
+
+	bidder := &bidderAdapter{
+		Bidder:     &mixedMultiBidder{},
+		BidderName: openrtb_ext.BidderAppnexus,
+		Client:     server.Client(),
+		config:     bidderAdapterConfig{RecordConnectionMetrics: true},
+		me:         metricsMock,
+	}
+	for i := 0; i < 2; i++ {
+		callInfo := bidder.doRequest(context.Background(), &adapters.RequestData{
+			Method: "GET",
+			Uri:    server.URL,
+		})
+		assert.NoError(t, callInfo.err)
+	}
+
+	metricsMock.AssertCalled(t, "RecordAdapterConnections", openrtb_ext.BidderAppnexus, false, mock.Anything)
+	metricsMock.AssertCalled(t, "RecordAdapterConnections", openrtb_ext.BidderAppnexus, true, mock.Anything)
+}
+
+func TestStoredBidResponses(t *testing.T) {
+	var calledPaths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calledPaths = append(calledPaths, r.URL.Path)
+		w.Write([]byte(`{"price":1}`))
+	}))
+	defer server.Close()
+
+	bidderImpl := &pricedBidder{uri: server.URL}
+	bidder := adaptBidder(bidderImpl, server.Client(), bidderAdapterConfig{}, &metricsConf.DummyMetricsEngine{}, "test")
+	request := &openrtb.BidRequest{
+		Imp: []openrtb.Imp{{ID: "live"}, {ID: "stored"}},
+	}
+	reqInfo := &adapters.ExtraRequestInfo{
+		StoredBidResponses: map[string]json.RawMessage{"stored": json.RawMessage(`{"price":2}`)},
+	}
+
+	seatBid, errs := bidder.requestBid(context.Background(), request, "test", 1.0, currencies.NewConstantRates(), reqInfo, true)
+
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"/live"}, calledPaths, "Only the imps without a Stored Bid Response should reach the server")
+	prices := make(map[string]float64)
+	for _, bid := range seatBid.bids {
+		prices[bid.bid.ImpID] = bid.bid.Price
+	}
+	assert.Equal(t, map[string]float64{"live": 1, "stored": 2}, prices, "The Stored Bid Response should go through MakeBids")
+	assert.Len(t, seatBid.httpCalls, 2, "The Stored Bid Response should show up in the debug output")
+
+	calledPaths = nil
+	seatBid, errs = bidder.requestBid(context.Background(), &openrtb.BidRequest{Imp: request.Imp[1:]}, "test", 1.0, currencies.NewConstantRates(), reqInfo, false)
+	assert.Empty(t, errs)
+	assert.Empty(t, calledPaths, "The server shouldn't be called if every imp has a Stored Bid Response")
+	assert.Len(t, seatBid.bids, 1)
+}
+
+func TestStoredBidResponsesMakeRequests(t *testing.T) {
+	bidderImpl := &goodMultiHTTPCallsBidder{
+		httpRequest: []*adapters.RequestData{
+			{Method: "POST", Uri: "http://bidder.com/first", Body: []byte(`{"regs":{"ext":{"gdpr":1}}}`)},
+			{Method: "POST", Uri: "http://bidder.com/first", Body: []byte(`{"regs":{"ext":{"gdpr":1}}}`)},
+			{Method: "POST", Uri: "http://bidder.com/second", Body: []byte(`{}`)},
+		},
+		bidResponses: []*adapters.BidderResponse{{
+			Bids: []*adapters.TypedBid{{Bid: &openrtb.Bid{ID: "stored", ImpID: "stored", Price: 2}, BidType: openrtb_ext.BidTypeBanner}},
+		}},
+	}
+	bidder := adaptBidder(bidderImpl, http.DefaultClient, bidderAdapterConfig{OpenRTB26: true}, &metricsConf.DummyMetricsEngine{}, "test")
+	request := &openrtb.BidRequest{Imp: []openrtb.Imp{{ID: "stored"}}}
+	reqInfo := &adapters.ExtraRequestInfo{
+		StoredBidResponses: map[string]json.RawMessage{"stored": json.RawMessage(`{}`)},
+	}
+
+	seatBid, errs := bidder.requestBid(context.Background(), request, "test", 1.0, currencies.NewConstantRates(), reqInfo, true)
+
+	if assert.Len(t, errs, 1, "The calls which the Stored Bid Response doesn't answer should be reported") {
+		assert.IsType(t, &errortypes.Warning{}, errs[0])
+		assert.Equal(t, "The Bidder made 2 calls for imp stored, but its Stored Bid Response only answers the first", errs[0].Error())
+	}
+	assert.Len(t, seatBid.bids, 1)
+	if assert.Len(t, seatBid.httpCalls, 1) {
+		assert.Equal(t, "http://bidder.com/first", seatBid.httpCalls[0].Uri)
+		assert.JSONEq(t, `{"regs":{"gdpr":1}}`, seatBid.httpCalls[0].RequestBody, "The stored call should be converted like a live one")
+	}
+}
+
+// pricedBidder makes one HTTP call per imp to {uri}/{imp.id}, and bids the price from the response body.
+type pricedBidder struct {
+	uri string
+}
+
+func (bidder *pricedBidder) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	reqData := make([]*adapters.RequestData, 0, len(request.Imp))
+	for _, imp := range request.Imp {
+		reqData = append(reqData, &adapters.RequestData{
+			Method: "POST",
+			Uri:    bidder.uri + "/" + imp.ID,
+			Body:   []byte(`{"imp":"` + imp.ID + `"}`),
+		})
+	}
+	return reqData, nil
+}
+
+func (bidder *pricedBidder) MakeBids(internalRequest *openrtb.BidRequest, externalRequest *adapters.RequestData, response *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	var sent struct {
+		Imp string `json:"imp"`
+	}
+	var received struct {
+		Price float64 `json:"price"`
+	}
+	if err := json.Unmarshal(externalRequest.Body, &sent); err != nil {
+		return nil, []error{err}
+	}
+	if err := json.Unmarshal(response.Body, &received); err != nil {
+		return nil, []error{err}
+	}
+	return &adapters.BidderResponse{
+		Bids: []*adapters.TypedBid{{
+			Bid:     &openrtb.Bid{ID: sent.Imp, ImpID: sent.Imp, Price: received.Price},
+			BidType: openrtb_ext.BidTypeBanner,
+		}},
+	}, nil
+}
+
+// perImpBidder makes one HTTP call per imp, and remembers the requests it was called with.
+type perImpBidder struct {
+	requests []*openrtb.BidRequest
+}
+
+func (bidder *perImpBidder) MakeRequests(request *openrtb.BidRequest, reqInfo *adapters.ExtraRequestInfo) ([]*adapters.RequestData, []error) {
+	bidder.requests = append(bidder.requests, request)
+	reqData := make([]*adapters.RequestData, 0, len(request.Imp))
+	for _, imp := range request.Imp {
+		reqData = append(reqData, &adapters.RequestData{
+			Method: "POST",
+			Uri:    "http://bidder.com/" + imp.ID,
+			Body:   []byte(`{"imp":"` + imp.ID + `"}`),
+		})
+	}
+	return reqData, nil
+}
+
+func (bidder *perImpBidder) MakeBids(internalRequest *openrtb.BidRequest, externalRequest *adapters.RequestData, response *adapters.ResponseData) (*adapters.BidderResponse, []error) {
+	return nil, nil
+}
+
 type goodSingleBidder struct {
 	bidRequest   *openrtb.BidRequest
 	httpRequest  *adapters.RequestData
@@ -2,13 +2,18 @@ package exchange
 
 import (
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
+	"regexp"
 	"strings"
 
 	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/currencies"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
 
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters"
 
@@ -20,14 +25,26 @@ import (
 //
 // The goal here is to make sure that the response contains Bids which are valid given the initial Request,
 // so that Publishers can trust the Bids they get from Prebid Server.
-func ensureValidBids(bidder adaptedBidder) adaptedBidder {
+//
+// If the host enables bid blocking, bids which violate the request's or the account's block lists are removed too.
+// The same goes for the creative validation checks which the host or the account enforces. The checks which are
+// only in "warn" mode keep the bid. Each of those is counted in the metrics under the coreBidder name.
+func ensureValidBids(bidder adaptedBidder, coreBidder openrtb_ext.BidderName, cfg *config.Configuration, me pbsmetrics.MetricsEngine) adaptedBidder {
 	return &validatedBidder{
-		bidder: bidder,
+		bidder:             bidder,
+		coreBidder:         coreBidder,
+		bidBlocking:        cfg.BidBlocking,
+		creativeValidation: cfg.CreativeValidation,
+		me:                 me,
 	}
 }
 
 type validatedBidder struct {
-	bidder adaptedBidder
+	bidder             adaptedBidder
+	coreBidder         openrtb_ext.BidderName
+	bidBlocking        config.BidBlocking
+	creativeValidation config.CreativeValidation
+	me                 pbsmetrics.MetricsEngine
 }
 
 func (v *validatedBidder) requestBid(ctx context.Context, request *openrtb.BidRequest, name openrtb_ext.BidderName, bidAdjustment float64, conversions currencies.Conversions, reqInfo *adapters.ExtraRequestInfo, debug bool) (*pbsOrtbSeatBid, []error) {
@@ -35,9 +52,260 @@ func (v *validatedBidder) requestBid(ctx context.Context, request *openrtb.BidRe
 	if validationErrors := removeInvalidBids(request, seatBid); len(validationErrors) > 0 {
 		errs = append(errs, validationErrors...)
 	}
+	if v.bidBlocking.Enforce {
+		if blockingErrors := v.removeBlockedBids(request, seatBid); len(blockingErrors) > 0 {
+			errs = append(errs, blockingErrors...)
+		}
+	}
+	if creativeErrors := v.validateCreatives(request, seatBid); len(creativeErrors) > 0 {
+		errs = append(errs, creativeErrors...)
+	}
 	return seatBid, errs
 }
 
+// validateCreatives runs the creative validation checks which apply to the request's account.
+// Bids which fail an enforced check are excised. Bids which fail a check in "warn" mode are kept, and only counted.
+func (v *validatedBidder) validateCreatives(request *openrtb.BidRequest, seatBid *pbsOrtbSeatBid) []error {
+	if seatBid == nil || len(seatBid.bids) == 0 {
+		return nil
+	}
+	modes := v.creativeValidation.ForAccount(openrtb_ext.AccountID(request))
+	if !isActive(modes.SecureMarkup) && !isActive(modes.BannerSize) && !isActive(modes.VastXML) {
+		return nil
+	}
+
+	imps := make(map[string]*openrtb.Imp, len(request.Imp))
+	for i := range request.Imp {
+		imps[request.Imp[i].ID] = &request.Imp[i]
+	}
+
+	var errs []error
+	validBids := make([]*pbsOrtbBid, 0, len(seatBid.bids))
+	for _, bid := range seatBid.bids {
+		imp, ok := imps[bid.bid.ImpID]
+		if !ok {
+			validBids = append(validBids, bid)
+			continue
+		}
+
+		checks := []struct {
+			mode   config.ValidationMode
+			reason pbsmetrics.BidRejectReason
+			check  func(bid *pbsOrtbBid, imp *openrtb.Imp) error
+		}{
+			{modes.SecureMarkup, pbsmetrics.BidRejectInsecureCreative, validateSecureMarkup},
+			{modes.BannerSize, pbsmetrics.BidRejectInvalidSize, validateBannerSize},
+			{modes.VastXML, pbsmetrics.BidRejectInvalidVast, validateVastXML},
+		}
+
+		rejected := false
+		for _, c := range checks {
+			if !isActive(c.mode) {
+				continue
+			}
+			if berr := c.check(bid, imp); berr != nil {
+				if c.mode == config.ValidationEnforce {
+					v.me.RecordAdapterBidRejected(v.coreBidder, c.reason)
+					errs = append(errs, berr)
+					rejected = true
+					break
+				}
+				v.me.RecordAdapterBidWarned(v.coreBidder, c.reason)
+			}
+		}
+		if !rejected {
+			validBids = append(validBids, bid)
+		}
+	}
+	seatBid.bids = validBids
+	return errs
+}
+
+func isActive(mode config.ValidationMode) bool {
+	return mode == config.ValidationWarn || mode == config.ValidationEnforce
+}
+
+// insecureResources match the places where markup loads a resource over http: the HTML attributes which load
+// resources, CSS url()s, and the URIs of the VAST elements which the player fetches. Text which only mentions an
+// http URL, such as an xmlns or the href of a link which the user might click, doesn't load anything.
+var insecureResources = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(src|srcset|poster|background|data)\s*=\s*\\?["']?\s*http://`),
+	regexp.MustCompile(`(?i)<(link|image|use|feImage)\b[^>]*\bhref\s*=\s*\\?["']?\s*http://`),
+	regexp.MustCompile(`(?i)url\(\s*\\?["']?\s*http://`),
+	regexp.MustCompile(`(?i)<(MediaFile|Tracking|Impression|Error|VASTAdTagURI|StaticResource|IFrameResource|JavaScriptResource|ExecutableResource|ClickTracking|CompanionClickTracking|NonLinearClickTracking|IconViewTracking|IconClickTracking)\b[^>]*>\s*(<!\[CDATA\[\s*)?http://`),
+}
+
+// validateSecureMarkup makes sure that a bid for a secure imp doesn't load any resource over http.
+func validateSecureMarkup(bid *pbsOrtbBid, imp *openrtb.Imp) error {
+	if imp.Secure == nil || *imp.Secure != 1 {
+		return nil
+	}
+	if loadsInsecureResource(bid.bid.AdM) {
+		return fmt.Errorf("Bid \"%s\" has insecure creative markup, but imp \"%s\" requires secure creatives", bid.bid.ID, imp.ID)
+	}
+	return nil
+}
+
+func loadsInsecureResource(markup string) bool {
+	for _, resource := range insecureResources {
+		if resource.MatchString(markup) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateBannerSize makes sure that the size of a banner bid is one of the sizes the imp asked for.
+// The banner's format takes precedence over its w and h, like it does in the OpenRTB spec.
+func validateBannerSize(bid *pbsOrtbBid, imp *openrtb.Imp) error {
+	if bid.bidType != openrtb_ext.BidTypeBanner || imp.Banner == nil {
+		return nil
+	}
+	formats := imp.Banner.Format
+	if len(formats) == 0 {
+		if imp.Banner.W == nil || imp.Banner.H == nil {
+			return nil
+		}
+		formats = []openrtb.Format{{W: *imp.Banner.W, H: *imp.Banner.H}}
+	}
+	for _, format := range formats {
+		if bid.bid.W == format.W && bid.bid.H == format.H {
+			return nil
+		}
+	}
+	return fmt.Errorf("Bid \"%s\" has size %dx%d, which doesn't match any size requested by imp \"%s\"", bid.bid.ID, bid.bid.W, bid.bid.H, imp.ID)
+}
+
+// validateVastXML makes sure that the adm of a video bid is well-formed XML. Bids which deliver their VAST
+// through the nurl have nothing to check.
+func validateVastXML(bid *pbsOrtbBid, imp *openrtb.Imp) error {
+	if bid.bidType != openrtb_ext.BidTypeVideo || bid.bid.AdM == "" {
+		return nil
+	}
+	if !isWellFormedXML(bid.bid.AdM) {
+		return fmt.Errorf("Bid \"%s\" has a VAST adm which is not well-formed XML", bid.bid.ID)
+	}
+	return nil
+}
+
+// isWellFormedXML returns true if the markup holds at least one element and parses without errors.
+func isWellFormedXML(markup string) bool {
+	decoder := xml.NewDecoder(strings.NewReader(markup))
+	hasElement := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return hasElement
+		}
+		if err != nil {
+			return false
+		}
+		if _, ok := token.(xml.StartElement); ok {
+			hasElement = true
+		}
+	}
+}
+
+// removeBlockedBids excises the bids whose adomain, cat or attr are blocked by the request or the account.
+// If the host requires it, bids without any adomain are removed as well.
+func (v *validatedBidder) removeBlockedBids(request *openrtb.BidRequest, seatBid *pbsOrtbSeatBid) []error {
+	if seatBid == nil || len(seatBid.bids) == 0 {
+		return nil
+	}
+
+	accountLists := v.bidBlocking.Accounts[openrtb_ext.AccountID(request)]
+	blockedAdvertisers := append(append([]string(nil), request.BAdv...), accountLists.BAdv...)
+	blockedCategories := append(append([]string(nil), request.BCat...), accountLists.BCat...)
+	blockedAttributes := blockedAttributesByImp(request, accountLists.BAttr)
+
+	var errs []error
+	validBids := make([]*pbsOrtbBid, 0, len(seatBid.bids))
+	for _, bid := range seatBid.bids {
+		var reason pbsmetrics.BidRejectReason
+		var berr error
+		if len(bid.bid.ADomain) == 0 && v.bidBlocking.RequireAdomain {
+			reason, berr = pbsmetrics.BidRejectMissingAdomain, fmt.Errorf("Bid \"%s\" missing required field 'adomain'", bid.bid.ID)
+		} else if domain, blocked := findBlockedAdvertiser(bid.bid.ADomain, blockedAdvertisers); blocked {
+			reason, berr = pbsmetrics.BidRejectBlockedAdvertiser, fmt.Errorf("Bid \"%s\" has blocked advertiser domain '%s'", bid.bid.ID, domain)
+		} else if cat, blocked := findBlockedCategory(bid.bid.Cat, blockedCategories); blocked {
+			reason, berr = pbsmetrics.BidRejectBlockedCategory, fmt.Errorf("Bid \"%s\" has blocked category '%s'", bid.bid.ID, cat)
+		} else if attr, blocked := findBlockedAttribute(bid.bid.Attr, blockedAttributes[bid.bid.ImpID]); blocked {
+			reason, berr = pbsmetrics.BidRejectBlockedAttribute, fmt.Errorf("Bid \"%s\" has blocked creative attribute %d", bid.bid.ID, attr)
+		}
+
+		if berr != nil {
+			v.me.RecordAdapterBidRejected(v.coreBidder, reason)
+			errs = append(errs, berr)
+		} else {
+			validBids = append(validBids, bid)
+		}
+	}
+	seatBid.bids = validBids
+	return errs
+}
+
+// blockedAttributesByImp maps each imp ID to the creative attributes blocked by its banner, video and audio objects,
+// plus the ones blocked by the account.
+func blockedAttributesByImp(request *openrtb.BidRequest, accountAttributes []int) map[string][]openrtb.CreativeAttribute {
+	blocked := make(map[string][]openrtb.CreativeAttribute, len(request.Imp))
+	for _, imp := range request.Imp {
+		var attributes []openrtb.CreativeAttribute
+		if imp.Banner != nil {
+			attributes = append(attributes, imp.Banner.BAttr...)
+		}
+		if imp.Video != nil {
+			attributes = append(attributes, imp.Video.BAttr...)
+		}
+		if imp.Audio != nil {
+			attributes = append(attributes, imp.Audio.BAttr...)
+		}
+		for _, attr := range accountAttributes {
+			attributes = append(attributes, openrtb.CreativeAttribute(attr))
+		}
+		blocked[imp.ID] = attributes
+	}
+	return blocked
+}
+
+// findBlockedAdvertiser returns the first advertiser domain which matches a blocked domain, or one of its subdomains.
+func findBlockedAdvertiser(adomains []string, blocked []string) (string, bool) {
+	for _, adomain := range adomains {
+		adomain = strings.ToLower(adomain)
+		for _, blockedDomain := range blocked {
+			blockedDomain = strings.ToLower(blockedDomain)
+			if adomain == blockedDomain || strings.HasSuffix(adomain, "."+blockedDomain) {
+				return adomain, true
+			}
+		}
+	}
+	return "", false
+}
+
+// findBlockedCategory returns the first category which matches a blocked IAB category. Blocking a tier 1 category
+// (e.g. "IAB7") also blocks all of its subcategories (e.g. "IAB7-1").
+func findBlockedCategory(categories []string, blocked []string) (string, bool) {
+	for _, category := range categories {
+		for _, blockedCategory := range blocked {
+			if strings.EqualFold(category, blockedCategory) || strings.HasPrefix(strings.ToUpper(category), strings.ToUpper(blockedCategory)+"-") {
+				return category, true
+			}
+		}
+	}
+	return "", false
+}
+
+// findBlockedAttribute returns the first creative attribute which is blocked.
+func findBlockedAttribute(attributes []openrtb.CreativeAttribute, blocked []openrtb.CreativeAttribute) (openrtb.CreativeAttribute, bool) {
+	for _, attr := range attributes {
+		for _, blockedAttr := range blocked {
+			if attr == blockedAttr {
+				return attr, true
+			}
+		}
+	}
+	return 0, false
+}
+
 // validateBids will run some validation checks on the returned bids and excise any invalid bids
 func removeInvalidBids(request *openrtb.BidRequest, seatBid *pbsOrtbSeatBid) []error {
 	// Exit early if there is nothing to do.
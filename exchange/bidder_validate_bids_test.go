@@ -6,9 +6,13 @@ import (
 
 	"github.com/PubMatic-OpenWrap/openrtb"
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/currencies"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestAllValidBids(t *testing.T) {
@@ -41,7 +45,7 @@ func TestAllValidBids(t *testing.T) {
 				},
 			},
 		},
-	})
+	}, openrtb_ext.BidderAppnexus, &config.Configuration{}, &metricsConf.DummyMetricsEngine{})
 	seatBid, errs := bidder.requestBid(context.Background(), &openrtb.BidRequest{}, openrtb_ext.BidderAppnexus, 1.0, currencies.NewConstantRates(), &adapters.ExtraRequestInfo{}, false)
 	assert.Len(t, seatBid.bids, 3)
 	assert.Len(t, errs, 0)
@@ -82,7 +86,7 @@ func TestAllBadBids(t *testing.T) {
 				{},
 			},
 		},
-	})
+	}, openrtb_ext.BidderAppnexus, &config.Configuration{}, &metricsConf.DummyMetricsEngine{})
 	seatBid, errs := bidder.requestBid(context.Background(), &openrtb.BidRequest{}, openrtb_ext.BidderAppnexus, 1.0, currencies.NewConstantRates(), &adapters.ExtraRequestInfo{}, false)
 	assert.Len(t, seatBid.bids, 0)
 	assert.Len(t, errs, 5)
@@ -125,7 +129,7 @@ func TestMixedBids(t *testing.T) {
 				{},
 			},
 		},
-	})
+	}, openrtb_ext.BidderAppnexus, &config.Configuration{}, &metricsConf.DummyMetricsEngine{})
 	seatBid, errs := bidder.requestBid(context.Background(), &openrtb.BidRequest{}, openrtb_ext.BidderAppnexus, 1.0, currencies.NewConstantRates(), &adapters.ExtraRequestInfo{}, false)
 	assert.Len(t, seatBid.bids, 2)
 	assert.Len(t, errs, 3)
@@ -231,7 +235,7 @@ func TestCurrencyBids(t *testing.T) {
 				currency: tc.brpCur,
 				bids:     bids,
 			},
-		})
+		}, openrtb_ext.BidderAppnexus, &config.Configuration{}, &metricsConf.DummyMetricsEngine{})
 
 		expectedValidBids := len(bids)
 		expectedErrs := 0
@@ -252,6 +256,164 @@ func TestCurrencyBids(t *testing.T) {
 	}
 }
 
+func TestBlockedBids(t *testing.T) {
+	bids := []*pbsOrtbBid{
+		{bid: &openrtb.Bid{ID: "valid", ImpID: "imp", Price: 1, CrID: "c1", ADomain: []string{"good.com"}, Cat: []string{"IAB1"}}},
+		{bid: &openrtb.Bid{ID: "request-badv", ImpID: "imp", Price: 1, CrID: "c2", ADomain: []string{"www.bad.com"}}},
+		{bid: &openrtb.Bid{ID: "account-badv", ImpID: "imp", Price: 1, CrID: "c3", ADomain: []string{"BLOCKED.org"}}},
+		{bid: &openrtb.Bid{ID: "request-bcat", ImpID: "imp", Price: 1, CrID: "c4", ADomain: []string{"good.com"}, Cat: []string{"IAB7-39"}}},
+		{bid: &openrtb.Bid{ID: "account-bcat", ImpID: "imp", Price: 1, CrID: "c5", ADomain: []string{"good.com"}, Cat: []string{"IAB25"}}},
+		{bid: &openrtb.Bid{ID: "banner-battr", ImpID: "imp", Price: 1, CrID: "c6", ADomain: []string{"good.com"}, Attr: []openrtb.CreativeAttribute{3}}},
+		{bid: &openrtb.Bid{ID: "account-battr", ImpID: "imp", Price: 1, CrID: "c7", ADomain: []string{"good.com"}, Attr: []openrtb.CreativeAttribute{8}}},
+		{bid: &openrtb.Bid{ID: "other-imp-battr", ImpID: "other-imp", Price: 1, CrID: "c8", ADomain: []string{"good.com"}, Attr: []openrtb.CreativeAttribute{3}}},
+		{bid: &openrtb.Bid{ID: "no-adomain", ImpID: "imp", Price: 1, CrID: "c9"}},
+	}
+	request := &openrtb.BidRequest{
+		Imp: []openrtb.Imp{
+			{ID: "imp", Banner: &openrtb.Banner{BAttr: []openrtb.CreativeAttribute{3}}},
+			{ID: "other-imp", Video: &openrtb.Video{}},
+		},
+		Site: &openrtb.Site{Publisher: &openrtb.Publisher{ID: "1001"}},
+		BAdv: []string{"bad.com"},
+		BCat: []string{"IAB7"},
+	}
+	cfg := &config.Configuration{
+		BidBlocking: config.BidBlocking{
+			Enforce:        true,
+			RequireAdomain: true,
+			Accounts: map[string]config.BlockLists{
+				"1001": {BCat: []string{"IAB25"}, BAdv: []string{"blocked.org"}, BAttr: []int{8}},
+			},
+		},
+	}
+	metricsMock := &pbsmetrics.MetricsEngineMock{}
+	metricsMock.On("RecordAdapterBidRejected", openrtb_ext.BidderAppnexus, mock.Anything).Return()
+
+	bidder := ensureValidBids(&mockAdaptedBidder{
+		bidResponse: &pbsOrtbSeatBid{bids: bids},
+	}, openrtb_ext.BidderAppnexus, cfg, metricsMock)
+	seatBid, errs := bidder.requestBid(context.Background(), request, openrtb_ext.BidderAppnexus, 1.0, currencies.NewConstantRates(), &adapters.ExtraRequestInfo{}, false)
+
+	if assert.Len(t, seatBid.bids, 2) {
+		assert.Equal(t, "valid", seatBid.bids[0].bid.ID)
+		assert.Equal(t, "other-imp-battr", seatBid.bids[1].bid.ID)
+	}
+	assert.Len(t, errs, 7)
+	metricsMock.AssertNumberOfCalls(t, "RecordAdapterBidRejected", 7)
+	metricsMock.AssertCalled(t, "RecordAdapterBidRejected", openrtb_ext.BidderAppnexus, pbsmetrics.BidRejectBlockedAdvertiser)
+	metricsMock.AssertCalled(t, "RecordAdapterBidRejected", openrtb_ext.BidderAppnexus, pbsmetrics.BidRejectBlockedCategory)
+	metricsMock.AssertCalled(t, "RecordAdapterBidRejected", openrtb_ext.BidderAppnexus, pbsmetrics.BidRejectBlockedAttribute)
+	metricsMock.AssertCalled(t, "RecordAdapterBidRejected", openrtb_ext.BidderAppnexus, pbsmetrics.BidRejectMissingAdomain)
+}
+
+func TestBlockedBidsNotEnforced(t *testing.T) {
+	bidder := ensureValidBids(&mockAdaptedBidder{
+		bidResponse: &pbsOrtbSeatBid{
+			bids: []*pbsOrtbBid{
+				{bid: &openrtb.Bid{ID: "blocked", ImpID: "imp", Price: 1, CrID: "c1", ADomain: []string{"bad.com"}}},
+			},
+		},
+	}, openrtb_ext.BidderAppnexus, &config.Configuration{}, &metricsConf.DummyMetricsEngine{})
+	request := &openrtb.BidRequest{BAdv: []string{"bad.com"}}
+	seatBid, errs := bidder.requestBid(context.Background(), request, openrtb_ext.BidderAppnexus, 1.0, currencies.NewConstantRates(), &adapters.ExtraRequestInfo{}, false)
+	assert.Len(t, seatBid.bids, 1)
+	assert.Len(t, errs, 0)
+}
+
+func TestCreativeValidation(t *testing.T) {
+	secure := int8(1)
+	bids := []*pbsOrtbBid{
+		{bid: &openrtb.Bid{ID: "valid-banner", ImpID: "secure-imp", Price: 1, CrID: "c1", W: 300, H: 250, AdM: "<img src='https://ads.com/a.png'>"}, bidType: openrtb_ext.BidTypeBanner},
+		{bid: &openrtb.Bid{ID: "insecure", ImpID: "secure-imp", Price: 1, CrID: "c2", W: 300, H: 250, AdM: "<img src='HTTP://ads.com/a.png'>"}, bidType: openrtb_ext.BidTypeBanner},
+		{bid: &openrtb.Bid{ID: "bad-size", ImpID: "secure-imp", Price: 1, CrID: "c3", W: 728, H: 90}, bidType: openrtb_ext.BidTypeBanner},
+		{bid: &openrtb.Bid{ID: "insecure-other-imp", ImpID: "video-imp", Price: 1, CrID: "c4", AdM: "<VAST version=\"3.0\"><Ad><Wrapper><VASTAdTagURI>http://ads.com/vast</VASTAdTagURI></Wrapper></Ad></VAST>"}, bidType: openrtb_ext.BidTypeVideo},
+		{bid: &openrtb.Bid{ID: "bad-vast", ImpID: "video-imp", Price: 1, CrID: "c5", AdM: "<VAST version=\"3.0\"><Ad>"}, bidType: openrtb_ext.BidTypeVideo},
+		{bid: &openrtb.Bid{ID: "nurl-vast", ImpID: "video-imp", Price: 1, CrID: "c6", NURL: "https://ads.com/vast"}, bidType: openrtb_ext.BidTypeVideo},
+		{bid: &openrtb.Bid{ID: "xmlns-banner", ImpID: "secure-imp", Price: 1, CrID: "c7", W: 300, H: 250, AdM: "<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"}, bidType: openrtb_ext.BidTypeBanner},
+	}
+	request := &openrtb.BidRequest{
+		Imp: []openrtb.Imp{
+			{ID: "secure-imp", Secure: &secure, Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 300, H: 250}, {W: 300, H: 600}}}},
+			{ID: "video-imp", Video: &openrtb.Video{}},
+		},
+		Site: &openrtb.Site{Publisher: &openrtb.Publisher{ID: "1001"}},
+	}
+	cfg := &config.Configuration{
+		CreativeValidation: config.CreativeValidation{
+			CreativeValidationModes: config.CreativeValidationModes{
+				SecureMarkup: config.ValidationEnforce,
+				BannerSize:   config.ValidationWarn,
+				VastXML:      config.ValidationWarn,
+			},
+			Accounts: map[string]config.CreativeValidationModes{
+				"1001": {VastXML: config.ValidationEnforce},
+			},
+		},
+	}
+	metricsMock := &pbsmetrics.MetricsEngineMock{}
+	metricsMock.On("RecordAdapterBidRejected", openrtb_ext.BidderAppnexus, mock.Anything).Return()
+	metricsMock.On("RecordAdapterBidWarned", openrtb_ext.BidderAppnexus, mock.Anything).Return()
+
+	bidder := ensureValidBids(&mockAdaptedBidder{
+		bidResponse: &pbsOrtbSeatBid{bids: bids},
+	}, openrtb_ext.BidderAppnexus, cfg, metricsMock)
+	seatBid, errs := bidder.requestBid(context.Background(), request, openrtb_ext.BidderAppnexus, 1.0, currencies.NewConstantRates(), &adapters.ExtraRequestInfo{}, false)
+
+	var bidIDs []string
+	for _, bid := range seatBid.bids {
+		bidIDs = append(bidIDs, bid.bid.ID)
+	}
+	assert.Equal(t, []string{"valid-banner", "bad-size", "insecure-other-imp", "nurl-vast", "xmlns-banner"}, bidIDs)
+	assert.Len(t, errs, 2)
+	metricsMock.AssertNumberOfCalls(t, "RecordAdapterBidRejected", 2)
+	metricsMock.AssertCalled(t, "RecordAdapterBidRejected", openrtb_ext.BidderAppnexus, pbsmetrics.BidRejectInsecureCreative)
+	metricsMock.AssertCalled(t, "RecordAdapterBidRejected", openrtb_ext.BidderAppnexus, pbsmetrics.BidRejectInvalidVast)
+	metricsMock.AssertNumberOfCalls(t, "RecordAdapterBidWarned", 1)
+	metricsMock.AssertCalled(t, "RecordAdapterBidWarned", openrtb_ext.BidderAppnexus, pbsmetrics.BidRejectInvalidSize)
+}
+
+func TestLoadsInsecureResource(t *testing.T) {
+	testCases := []struct {
+		description string
+		markup      string
+		expected    bool
+	}{
+		{"Secure image", `<img src="https://ads.com/a.png">`, false},
+		{"Insecure image", `<img src="http://ads.com/a.png">`, true},
+		{"Insecure script without quotes", `<script src=http://ads.com/a.js></script>`, true},
+		{"Insecure escaped attribute", `<img src=\"http://ads.com/a.png\">`, true},
+		{"Insecure stylesheet", `<link rel="stylesheet" href='http://ads.com/a.css'>`, true},
+		{"Insecure CSS url", `<div style="background-image: url('http://ads.com/a.png')"></div>`, true},
+		{"xmlns", `<svg xmlns="http://www.w3.org/2000/svg"><image href="https://ads.com/a.png"/></svg>`, false},
+		{"Plain text URL", `<p>Visit http://ads.com for more</p>`, false},
+		{"http link", `<a href="http://ads.com/landing"><img src="https://ads.com/a.png"></a>`, false},
+		{"Insecure SVG image", `<svg><image xlink:href="http://ads.com/a.png"/></svg>`, true},
+		{"Secure VAST", `<VAST version="3.0" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><Ad><InLine><Impression><![CDATA[https://ads.com/imp]]></Impression><Creatives><Creative><Linear><TrackingEvents><Tracking event="start">https://ads.com/start</Tracking></TrackingEvents><MediaFiles><MediaFile delivery="progressive" type="video/mp4">https://ads.com/a.mp4</MediaFile></MediaFiles></Linear></Creative></Creatives></InLine></Ad></VAST>`, false},
+		{"Insecure VAST media file", `<VAST version="3.0"><Ad><InLine><Creatives><Creative><Linear><MediaFiles><MediaFile delivery="progressive" type="video/mp4"><![CDATA[ http://ads.com/a.mp4 ]]></MediaFile></MediaFiles></Linear></Creative></Creatives></InLine></Ad></VAST>`, true},
+		{"Insecure VAST tracking", `<VAST version="3.0"><Ad><InLine><Creatives><Creative><Linear><TrackingEvents><Tracking event="start">http://ads.com/start</Tracking></TrackingEvents></Linear></Creative></Creatives></InLine></Ad></VAST>`, true},
+	}
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, loadsInsecureResource(test.markup), test.description)
+	}
+}
+
+func TestIsWellFormedXML(t *testing.T) {
+	testCases := []struct {
+		description string
+		markup      string
+		expected    bool
+	}{
+		{"VAST", `<?xml version="1.0"?><VAST version="3.0"><Ad id="1"></Ad></VAST>`, true},
+		{"Unclosed element", `<VAST version="3.0"><Ad>`, false},
+		{"Mismatched element", `<VAST></Ad>`, false},
+		{"Plain text", `https://ads.com/vast.xml`, false},
+		{"Empty", ``, false},
+	}
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, isWellFormedXML(test.markup), test.description)
+	}
+}
+
 type mockAdaptedBidder struct {
 	bidResponse   *pbsOrtbSeatBid
 	errorResponse []error
@@ -0,0 +1,180 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+)
+
+// userEids are the request's user.ext.eids, along with the request.ext.prebid.data.eidpermissions
+// which decide the bidders that get each of them.
+type userEids struct {
+	eids []openrtb_ext.ExtUserEid
+	// changed is true if normalizing the eids changed them, so that every bidder needs a new user.ext.
+	changed     bool
+	permissions map[string][]string
+}
+
+// extractUserEids reads the user.ext.eids and normalizes them: the sources are trimmed and lower cased, the eids
+// which share a source are merged, and the repeated uids are dropped.
+//
+// It returns nil if the request has neither eids nor eid permissions.
+func extractUserEids(user *openrtb.User, permissions []openrtb_ext.ExtRequestPrebidDataEidPermission) (*userEids, error) {
+	var userExt struct {
+		Eids []openrtb_ext.ExtUserEid `json:"eids"`
+	}
+	if user != nil && len(user.Ext) > 0 {
+		if err := json.Unmarshal(user.Ext, &userExt); err != nil {
+			return nil, fmt.Errorf("request.user.ext is invalid: %v", err)
+		}
+	}
+	if len(userExt.Eids) == 0 && len(permissions) == 0 {
+		return nil, nil
+	}
+
+	eids := &userEids{
+		eids:        make([]openrtb_ext.ExtUserEid, 0, len(userExt.Eids)),
+		permissions: make(map[string][]string, len(permissions)),
+	}
+	for _, permission := range permissions {
+		source := normalizeEidSource(permission.Source)
+		eids.permissions[source] = append(eids.permissions[source], permission.Bidders...)
+	}
+
+	bySource := make(map[string]int, len(userExt.Eids))
+	for _, eid := range userExt.Eids {
+		source := normalizeEidSource(eid.Source)
+		if source != eid.Source {
+			eid.Source = source
+			eids.changed = true
+		}
+		index, seen := bySource[source]
+		if !seen {
+			bySource[source] = len(eids.eids)
+			eid.Uids = uniqueUids(eid.Uids, nil, &eids.changed)
+			eids.eids = append(eids.eids, eid)
+			continue
+		}
+
+		eids.changed = true
+		merged := &eids.eids[index]
+		if merged.ID == "" {
+			merged.ID = eid.ID
+		}
+		merged.Uids = uniqueUids(eid.Uids, merged.Uids, &eids.changed)
+	}
+	return eids, nil
+}
+
+// apply replaces the user.ext.eids in the bidder's request with the eids which the bidder is allowed to have.
+// The user is copied first, since it's shared with the other bidders. If the bidder's user isn't userBeforeFPD,
+// its first party data replaced it, so the eids of the bidder's own user.ext are filtered instead.
+func (e *userEids) apply(req *openrtb.BidRequest, userBeforeFPD *openrtb.User, bidder string, coreBidder string) error {
+	if e == nil || req.User == nil {
+		return nil
+	}
+	if req.User != userBeforeFPD {
+		bidderEids, err := extractUserEids(req.User, nil)
+		if err != nil || bidderEids == nil {
+			return err
+		}
+		bidderEids.permissions = e.permissions
+		e = bidderEids
+	}
+
+	allowed := make([]openrtb_ext.ExtUserEid, 0, len(e.eids))
+	for _, eid := range e.eids {
+		if e.allows(eid.Source, bidder, coreBidder) {
+			allowed = append(allowed, eid)
+		}
+	}
+	if !e.changed && len(allowed) == len(e.eids) {
+		return nil
+	}
+
+	var userExt map[string]json.RawMessage
+	if err := json.Unmarshal(req.User.Ext, &userExt); err != nil {
+		return fmt.Errorf("request.user.ext is invalid: %v", err)
+	}
+	if len(allowed) > 0 {
+		rawEids, err := json.Marshal(allowed)
+		if err != nil {
+			return err
+		}
+		userExt["eids"] = rawEids
+	} else {
+		delete(userExt, "eids")
+	}
+
+	user := *req.User
+	user.Ext = nil
+	if len(userExt) > 0 {
+		rawUserExt, err := json.Marshal(userExt)
+		if err != nil {
+			return err
+		}
+		user.Ext = rawUserExt
+	}
+	req.User = &user
+	return nil
+}
+
+// allows matches the bidder names case insensitively. An alias is allowed if its core bidder is.
+func (e *userEids) allows(source string, bidder string, coreBidder string) bool {
+	bidders, restricted := e.permissions[source]
+	if !restricted {
+		return true
+	}
+	for _, allowed := range bidders {
+		if allowed == "*" || strings.EqualFold(allowed, bidder) || strings.EqualFold(allowed, coreBidder) {
+			return true
+		}
+	}
+	return false
+}
+
+// eidSources returns the sources of the eids in the user.ext, for the debug output.
+func eidSources(user *openrtb.User) []string {
+	if user == nil || len(user.Ext) == 0 {
+		return nil
+	}
+	var userExt struct {
+		Eids []openrtb_ext.ExtUserEid `json:"eids"`
+	}
+	if err := json.Unmarshal(user.Ext, &userExt); err != nil {
+		return nil
+	}
+	var sources []string
+	for _, eid := range userExt.Eids {
+		sources = append(sources, eid.Source)
+	}
+	return sources
+}
+
+func normalizeEidSource(source string) string {
+	return strings.ToLower(strings.TrimSpace(source))
+}
+
+// uniqueUids appends the uids to existing, leaving out the ones with an ID which is already there.
+// It sets changed if any were left out.
+func uniqueUids(uids []openrtb_ext.ExtUserEidUid, existing []openrtb_ext.ExtUserEidUid, changed *bool) []openrtb_ext.ExtUserEidUid {
+	if len(uids) == 0 {
+		return existing
+	}
+	seen := make(map[string]struct{}, len(existing)+len(uids))
+	for _, uid := range existing {
+		seen[uid.ID] = struct{}{}
+	}
+	for _, uid := range uids {
+		if _, ok := seen[uid.ID]; ok {
+			*changed = true
+			continue
+		}
+		seen[uid.ID] = struct{}{}
+		existing = append(existing, uid)
+	}
+	return existing
+}
@@ -0,0 +1,151 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/currencies"
+	"github.com/PubMatic-OpenWrap/prebid-server/gdpr"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanOpenRTBRequestsEidPermissions(t *testing.T) {
+	req := &openrtb.BidRequest{
+		ID:   "request",
+		Site: &openrtb.Site{Page: "http://www.example.com"},
+		User: &openrtb.User{
+			Ext: json.RawMessage(`{"consent":"abc","eids":[{"source":"adserver.org","uids":[{"id":"1"}]},{"source":"Restricted.com","id":"2"},{"source":"anyone.com","id":"3"}]}`),
+		},
+		Imp: []openrtb.Imp{{
+			ID:     "imp",
+			Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 300, H: 250}}},
+			Ext:    json.RawMessage(`{"appnexus":{"placementId":1},"rubicon":{},"openx":{}}`),
+		}},
+		Ext: json.RawMessage(`{"prebid":{"data":{"eidpermissions":[{"source":"adserver.org","bidders":["appnexus"]},{"source":"restricted.com","bidders":["appnexus","openx"]},{"source":"anyone.com","bidders":["*"]}]}}}`),
+	}
+
+	requests, _, errs := cleanOpenRTBRequests(context.Background(), req, &emptyUsersync{}, map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels{}, pbsmetrics.Labels{}, &permissionsMock{}, true, false, nil, nil, nil)
+
+	assert.Empty(t, errs)
+	if assert.Len(t, requests, 3) {
+		assert.Equal(t, []string{"adserver.org", "restricted.com", "anyone.com"}, eidSources(requests["appnexus"].User))
+		assert.Equal(t, []string{"restricted.com", "anyone.com"}, eidSources(requests["openx"].User))
+		assert.Equal(t, []string{"anyone.com"}, eidSources(requests["rubicon"].User))
+		assert.Contains(t, string(requests["rubicon"].User.Ext), `"consent":"abc"`, "The rest of the user.ext should be kept")
+		assert.Equal(t, `{"prebid":{}}`, string(requests["rubicon"].Ext), "The bidders shouldn't see the eid permissions")
+	}
+	assert.Len(t, eidSources(req.User), 3, "The original request shouldn't change")
+}
+
+func TestCleanOpenRTBRequestsEidPermissionsAfterFirstPartyData(t *testing.T) {
+	req := &openrtb.BidRequest{
+		ID:   "request",
+		Site: &openrtb.Site{Page: "http://www.example.com"},
+		User: &openrtb.User{
+			Ext: json.RawMessage(`{"eids":[{"source":"adserver.org","uids":[{"id":"1"}]}]}`),
+		},
+		Imp: []openrtb.Imp{{
+			ID:     "imp",
+			Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 300, H: 250}}},
+			Ext:    json.RawMessage(`{"appnexus":{"placementId":1},"rubicon":{}}`),
+		}},
+		Ext: json.RawMessage(`{"prebid":{"data":{"eidpermissions":[{"source":"adserver.org","bidders":["appnexus"]}]},"bidderconfig":[{"bidders":["rubicon"],"config":{"ortb2":{"user":{"keywords":"k","ext":{"eids":[{"source":"Adserver.org","uids":[{"id":"1"}]},{"source":"anyone.com","uids":[{"id":"2"}]}]}}}}}]}}`),
+	}
+
+	requests, _, errs := cleanOpenRTBRequests(context.Background(), req, &emptyUsersync{}, map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels{}, pbsmetrics.Labels{}, &permissionsMock{}, true, false, nil, nil, nil)
+
+	assert.Empty(t, errs)
+	if assert.Len(t, requests, 2) {
+		assert.Equal(t, []string{"adserver.org"}, eidSources(requests["appnexus"].User))
+		assert.Equal(t, []string{"anyone.com"}, eidSources(requests["rubicon"].User), "A bidder config shouldn't add back the eids which the bidder isn't allowed")
+		assert.Equal(t, "k", requests["rubicon"].User.Keywords)
+	}
+}
+
+func TestCleanOpenRTBRequestsEidPermissionsBidderNames(t *testing.T) {
+	req := &openrtb.BidRequest{
+		ID:   "request",
+		Site: &openrtb.Site{Page: "http://www.example.com"},
+		User: &openrtb.User{
+			Ext: json.RawMessage(`{"eids":[{"source":"adserver.org","uids":[{"id":"1"}]}]}`),
+		},
+		Imp: []openrtb.Imp{{
+			ID:     "imp",
+			Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 300, H: 250}}},
+			Ext:    json.RawMessage(`{"myappnexus":{"placementId":1},"rubicon":{}}`),
+		}},
+		Ext: json.RawMessage(`{"prebid":{"aliases":{"myappnexus":"appnexus"},"data":{"eidpermissions":[{"source":"adserver.org","bidders":["AppNexus"]}]}}}`),
+	}
+
+	requests, _, errs := cleanOpenRTBRequests(context.Background(), req, &emptyUsersync{}, map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels{}, pbsmetrics.Labels{}, &permissionsMock{}, true, false, nil, nil, nil)
+
+	assert.Empty(t, errs)
+	if assert.Len(t, requests, 2) {
+		assert.Equal(t, []string{"adserver.org"}, eidSources(requests["myappnexus"].User), "An alias should get the eids of its core bidder, whatever the case")
+		assert.Empty(t, eidSources(requests["rubicon"].User))
+	}
+}
+
+func TestExtractUserEidsNormalizes(t *testing.T) {
+	user := &openrtb.User{
+		Ext: json.RawMessage(`{"eids":[{"source":" Adserver.org","uids":[{"id":"1"},{"id":"1"}]},{"source":"adserver.org","id":"x","uids":[{"id":"1"},{"id":"2"}]}]}`),
+	}
+
+	eids, err := extractUserEids(user, nil)
+
+	assert.NoError(t, err)
+	assert.True(t, eids.changed)
+	if assert.Len(t, eids.eids, 1) {
+		assert.Equal(t, "adserver.org", eids.eids[0].Source)
+		assert.Equal(t, "x", eids.eids[0].ID)
+		assert.Equal(t, []openrtb_ext.ExtUserEidUid{{ID: "1"}, {ID: "2"}}, eids.eids[0].Uids)
+	}
+
+	req := &openrtb.BidRequest{User: user}
+	assert.NoError(t, eids.apply(req, user, "appnexus", "appnexus"))
+	assert.JSONEq(t, `{"eids":[{"source":"adserver.org","id":"x","uids":[{"id":"1"},{"id":"2"}]}]}`, string(req.User.Ext))
+}
+
+func TestExtractUserEidsWithoutEids(t *testing.T) {
+	eids, err := extractUserEids(&openrtb.User{Ext: json.RawMessage(`{"consent":"abc"}`)}, nil)
+
+	assert.NoError(t, err)
+	assert.Nil(t, eids)
+	assert.NoError(t, eids.apply(&openrtb.BidRequest{User: &openrtb.User{}}, nil, "appnexus", "appnexus"))
+}
+
+func TestDebugEids(t *testing.T) {
+	e := &exchange{
+		adapterMap: map[openrtb_ext.BidderName]adaptedBidder{
+			openrtb_ext.BidderAppnexus: &recordingBidder{},
+			openrtb_ext.BidderRubicon:  &recordingBidder{},
+		},
+		me:                &metricsConf.DummyMetricsEngine{},
+		cache:             &mockCache{},
+		gDPR:              gdpr.AlwaysAllow{},
+		currencyConverter: currencies.NewRateConverterDefault(),
+	}
+	req := &openrtb.BidRequest{
+		ID:   "request",
+		Site: &openrtb.Site{Page: "http://www.example.com"},
+		User: &openrtb.User{Ext: json.RawMessage(`{"eids":[{"source":"adserver.org","id":"1"}]}`)},
+		Imp: []openrtb.Imp{{
+			ID:     "imp",
+			Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 300, H: 250}}},
+			Ext:    json.RawMessage(`{"appnexus":{"placementId":1},"rubicon":{}}`),
+		}},
+		Ext: json.RawMessage(`{"prebid":{"debug":1,"data":{"eidpermissions":[{"source":"adserver.org","bidders":["appnexus"]}]}}}`),
+	}
+
+	response, err := e.HoldAuction(context.Background(), req, &emptyUsersync{}, pbsmetrics.Labels{}, nil, nil)
+
+	assert.NoError(t, err)
+	var responseExt openrtb_ext.ExtBidResponse
+	assert.NoError(t, json.Unmarshal(response.Ext, &responseExt))
+	assert.Equal(t, map[openrtb_ext.BidderName][]string{"appnexus": {"adserver.org"}}, responseExt.Debug.Eids)
+}
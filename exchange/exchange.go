@@ -19,16 +19,20 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/currencies"
 	"github.com/PubMatic-OpenWrap/prebid-server/errortypes"
 	"github.com/PubMatic-OpenWrap/prebid-server/gdpr"
+	"github.com/PubMatic-OpenWrap/prebid-server/geolocation"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
 	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
 	"github.com/PubMatic-OpenWrap/prebid-server/prebid_cache_client"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/activity"
 	"github.com/golang/glog"
 )
 
 // Exchange runs Auctions. Implementations must be threadsafe, and will be shared across many goroutines.
 type Exchange interface {
 	// HoldAuction executes an OpenRTB v2.5 Auction.
-	HoldAuction(ctx context.Context, bidRequest *openrtb.BidRequest, usersyncs IdFetcher, labels pbsmetrics.Labels, categoriesFetcher *stored_requests.CategoryFetcher) (*openrtb.BidResponse, error)
+	//
+	// storedResponses may be nil. Otherwise, its responses replace the bidders' on the imps which have them.
+	HoldAuction(ctx context.Context, bidRequest *openrtb.BidRequest, usersyncs IdFetcher, labels pbsmetrics.Labels, categoriesFetcher *stored_requests.CategoryFetcher, storedResponses *StoredResponses) (*openrtb.BidResponse, error)
 }
 
 // IdFetcher can find the user's ID for a specific Bidder.
@@ -47,12 +51,20 @@ type exchange struct {
 	UsersyncIfAmbiguous bool
 	defaultTTLs         config.DefaultTTLs
 	enforceCCPA         bool
+	hostAliases         map[string]config.BidderAlias
+	activityControls    config.ActivityControls
+	eeaCountries        map[string]struct{}
+	geoLocation         geolocation.GeoLocation
+	// bidderClients are the clients of the bidders which don't share the host's client.
+	bidderClients []*http.Client
 }
 
 // Container to pass out response ext data from the GetAllBids goroutines back into the main thread
 type seatResponseExtra struct {
 	ResponseTimeMillis int
 	Errors             []openrtb_ext.ExtBidderError
+	// EidSources are the user.ext.eids sources which the bidder got. They're only set in debug mode.
+	EidSources []string
 }
 
 type bidResponseWrapper struct {
@@ -61,10 +73,10 @@ type bidResponseWrapper struct {
 	bidder       openrtb_ext.BidderName
 }
 
-func NewExchange(client *http.Client, cache prebid_cache_client.Client, cfg *config.Configuration, metricsEngine pbsmetrics.MetricsEngine, infos adapters.BidderInfos, gDPR gdpr.Permissions, currencyConverter *currencies.RateConverter) Exchange {
+func NewExchange(client *http.Client, cache prebid_cache_client.Client, cfg *config.Configuration, metricsEngine pbsmetrics.MetricsEngine, infos adapters.BidderInfos, gDPR gdpr.Permissions, currencyConverter *currencies.RateConverter, geoLocation geolocation.GeoLocation) Exchange {
 	e := new(exchange)
 
-	e.adapterMap = newAdapterMap(client, cfg, infos)
+	e.adapterMap, e.bidderClients = newAdapterMap(client, cfg, infos, metricsEngine)
 	e.cache = cache
 	e.cacheTime = time.Duration(cfg.CacheURL.ExpectedTimeMillis) * time.Millisecond
 	e.me = metricsEngine
@@ -73,10 +85,23 @@ func NewExchange(client *http.Client, cache prebid_cache_client.Client, cfg *con
 	e.UsersyncIfAmbiguous = cfg.GDPR.UsersyncIfAmbiguous
 	e.defaultTTLs = cfg.CacheURL.DefaultTTLs
 	e.enforceCCPA = cfg.CCPA.Enforce
+	e.hostAliases = cfg.BidderAliases
+	e.activityControls = cfg.ActivityControls
+	e.eeaCountries = cfg.GDPR.EEACountriesMap
+	e.geoLocation = geoLocation
 	return e
 }
 
-func (e *exchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidRequest, usersyncs IdFetcher, labels pbsmetrics.Labels, categoriesFetcher *stored_requests.CategoryFetcher) (*openrtb.BidResponse, error) {
+// CloseIdleConnections closes the idle connections of the clients which the exchange built for its bidders.
+// The host's client is shared, so it's left alone. It's meant for an exchange which a config reload replaced,
+// and doesn't stop the exchange from making new calls.
+func (e *exchange) CloseIdleConnections() {
+	for _, client := range e.bidderClients {
+		client.CloseIdleConnections()
+	}
+}
+
+func (e *exchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidRequest, usersyncs IdFetcher, labels pbsmetrics.Labels, categoriesFetcher *stored_requests.CategoryFetcher, storedResponses *StoredResponses) (*openrtb.BidResponse, error) {
 	debug := false
 	if bidRequest.Ext != nil {
 		var requestExt openrtb_ext.ExtRequest
@@ -90,12 +115,19 @@ func (e *exchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidReque
 		}
 	}
 
-	// Snapshot of resolved bid request for debug if test request
+	// Snapshot of resolved bid request for debug if test request. It's taken before the geo lookup, so that it
+	// shows the device as the publisher sent it.
 	resolvedRequest, err := buildResolvedRequest(bidRequest, debug)
 	if err != nil {
 		glog.Errorf("Error marshalling bid request for debug: %v", err)
 	}
 
+	// The device's location is needed by the activity controls and GDPR, as well as the bidders.
+	fillDeviceGeo(ctx, e.geoLocation, bidRequest)
+
+	activityControls := activity.NewControls(&e.activityControls, labels.PubID)
+	activityControls.Debug = debug
+
 	for _, impInRequest := range bidRequest.Imp {
 		var impLabels pbsmetrics.ImpLabels = pbsmetrics.ImpLabels{
 			BannerImps: impInRequest.Banner != nil,
@@ -108,7 +140,8 @@ func (e *exchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidReque
 
 	// Slice of BidRequests, each a copy of the original cleaned to only contain bidder data for the named bidder
 	blabels := make(map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels)
-	cleanRequests, aliases, errs := cleanOpenRTBRequests(ctx, bidRequest, usersyncs, blabels, labels, e.gDPR, e.UsersyncIfAmbiguous, e.enforceCCPA)
+	// The imps with a Stored Auction Response don't go to any bidder.
+	cleanRequests, aliases, errs := cleanOpenRTBRequests(ctx, storedResponses.withoutAuctionResponses(bidRequest), usersyncs, blabels, labels, e.gDPR, e.UsersyncIfAmbiguous, e.enforceCCPA, e.eeaCountries, e.hostAliases, activityControls)
 
 	// List of bidders we have requests for.
 	liveAdapters := listBiddersWithRequests(cleanRequests)
@@ -132,11 +165,13 @@ func (e *exchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidReque
 
 		if requestExt.Prebid.Targeting != nil {
 			targData = &targetData{
-				priceGranularity:  requestExt.Prebid.Targeting.PriceGranularity,
-				includeWinners:    requestExt.Prebid.Targeting.IncludeWinners,
-				includeBidderKeys: requestExt.Prebid.Targeting.IncludeBidderKeys,
-				includeCacheBids:  shouldCacheBids,
-				includeCacheVast:  shouldCacheVAST,
+				priceGranularity:     requestExt.Prebid.Targeting.PriceGranularity,
+				dealPriceGranularity: requestExt.Prebid.Targeting.DealPriceGranularity,
+				preferDeals:          requestExt.Prebid.Targeting.PreferDeals,
+				includeWinners:       requestExt.Prebid.Targeting.IncludeWinners,
+				includeBidderKeys:    requestExt.Prebid.Targeting.IncludeBidderKeys,
+				includeCacheBids:     shouldCacheBids,
+				includeCacheVast:     shouldCacheVAST,
 			}
 			targData.cacheHost, targData.cachePath = e.cache.GetExtCacheData()
 		}
@@ -150,7 +185,11 @@ func (e *exchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidReque
 	// Get currency rates conversions for the auction
 	conversions := e.currencyConverter.Rates()
 
-	adapterBids, adapterExtra, anyBidsReturned := e.getAllBids(auctionCtx, cleanRequests, aliases, bidAdjustmentFactors, blabels, conversions, debug)
+	adapterBids, adapterExtra, anyBidsReturned := e.getAllBids(auctionCtx, cleanRequests, aliases, bidAdjustmentFactors, blabels, conversions, debug, storedResponses)
+
+	liveAdapters, storedBidsAdded, storedErrs := storedResponses.addAuctionResponses(bidRequest, liveAdapters, adapterBids, adapterExtra, e.adapterMap, aliases, conversions)
+	anyBidsReturned = anyBidsReturned || storedBidsAdded
+	errs = append(errs, storedErrs...)
 
 	var auc *auction = nil
 	if anyBidsReturned {
@@ -165,10 +204,10 @@ func (e *exchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidReque
 			}
 		}
 
-		auc = newAuction(adapterBids, len(bidRequest.Imp))
+		auc = newAuction(adapterBids, len(bidRequest.Imp), targData != nil && targData.preferDeals)
 
 		if targData != nil {
-			auc.setRoundedPrices(targData.priceGranularity)
+			auc.setRoundedPrices(targData.priceGranularity, targData.dealPriceGranularity)
 			cacheErrs := auc.doCache(ctx, e.cache, targData, bidRequest, 60, &e.defaultTTLs, bidCategory)
 			if len(cacheErrs) > 0 {
 				errs = append(errs, cacheErrs...)
@@ -178,7 +217,7 @@ func (e *exchange) HoldAuction(ctx context.Context, bidRequest *openrtb.BidReque
 	}
 
 	// Build the response
-	return e.buildBidResponse(ctx, liveAdapters, adapterBids, bidRequest, resolvedRequest, adapterExtra, auc, debug, errs)
+	return e.buildBidResponse(ctx, liveAdapters, adapterBids, bidRequest, resolvedRequest, adapterExtra, auc, debug, activityControls.Traces(), errs)
 }
 
 func (e *exchange) makeAuctionContext(ctx context.Context, needsCache bool) (auctionCtx context.Context, cancel context.CancelFunc) {
@@ -193,7 +232,7 @@ func (e *exchange) makeAuctionContext(ctx context.Context, needsCache bool) (auc
 }
 
 // This piece sends all the requests to the bidder adapters and gathers the results.
-func (e *exchange) getAllBids(ctx context.Context, cleanRequests map[openrtb_ext.BidderName]*openrtb.BidRequest, aliases map[string]string, bidAdjustments map[string]float64, blabels map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels, conversions currencies.Conversions, debug bool) (map[openrtb_ext.BidderName]*pbsOrtbSeatBid, map[openrtb_ext.BidderName]*seatResponseExtra, bool) {
+func (e *exchange) getAllBids(ctx context.Context, cleanRequests map[openrtb_ext.BidderName]*openrtb.BidRequest, aliases map[string]string, bidAdjustments map[string]float64, blabels map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels, conversions currencies.Conversions, debug bool, storedResponses *StoredResponses) (map[openrtb_ext.BidderName]*pbsOrtbSeatBid, map[openrtb_ext.BidderName]*seatResponseExtra, bool) {
 	// Set up pointers to the bid results
 	adapterBids := make(map[openrtb_ext.BidderName]*pbsOrtbSeatBid, len(cleanRequests))
 	adapterExtra := make(map[openrtb_ext.BidderName]*seatResponseExtra, len(cleanRequests))
@@ -223,6 +262,7 @@ func (e *exchange) getAllBids(ctx context.Context, cleanRequests map[openrtb_ext
 			}
 			var reqInfo adapters.ExtraRequestInfo
 			reqInfo.PbsEntryPoint = bidlabels.RType
+			reqInfo.StoredBidResponses = storedResponses.bidResponsesFor(aName)
 			bids, err := e.adapterMap[coreBidder].requestBid(ctx, request, aName, adjustmentFactor, conversions, &reqInfo, debug)
 
 			// Add in time reporting
@@ -238,6 +278,9 @@ func (e *exchange) getAllBids(ctx context.Context, cleanRequests map[openrtb_ext
 			bidlabels.AdapterErrors = errorsToMetric(err)
 			// Append any bid validation errors to the error list
 			ae.Errors = serr
+			if debug {
+				ae.EidSources = eidSources(request.User)
+			}
 			brw.adapterExtra = ae
 			if bids != nil {
 				for _, bid := range bids.bids {
@@ -248,7 +291,7 @@ func (e *exchange) getAllBids(ctx context.Context, cleanRequests map[openrtb_ext
 			}
 			chBids <- brw
 		}, chBids)
-		go bidderRunner(bidderName, coreBidder, req, blabels[coreBidder], conversions)
+		go bidderRunner(bidderName, coreBidder, req, blabels[metricsBidder(string(bidderName), aliases, e.hostAliases)], conversions)
 	}
 	// Wait for the bidders to do their thing
 	for i := 0; i < len(cleanRequests); i++ {
@@ -320,7 +363,7 @@ func errsToBidderErrors(errs []error) []openrtb_ext.ExtBidderError {
 }
 
 // This piece takes all the bids supplied by the adapters and crafts an openRTB response to send back to the requester
-func (e *exchange) buildBidResponse(ctx context.Context, liveAdapters []openrtb_ext.BidderName, adapterBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, bidRequest *openrtb.BidRequest, resolvedRequest json.RawMessage, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, auc *auction, debug bool, errList []error) (*openrtb.BidResponse, error) {
+func (e *exchange) buildBidResponse(ctx context.Context, liveAdapters []openrtb_ext.BidderName, adapterBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, bidRequest *openrtb.BidRequest, resolvedRequest json.RawMessage, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, auc *auction, debug bool, activityTraces []openrtb_ext.ExtActivityTrace, errList []error) (*openrtb.BidResponse, error) {
 	bidResponse := new(openrtb.BidResponse)
 
 	bidResponse.ID = bidRequest.ID
@@ -343,7 +386,7 @@ func (e *exchange) buildBidResponse(ctx context.Context, liveAdapters []openrtb_
 
 	bidResponse.SeatBid = seatBids
 
-	bidResponseExt := e.makeExtBidResponse(adapterBids, adapterExtra, bidRequest, resolvedRequest, debug, errList)
+	bidResponseExt := e.makeExtBidResponse(adapterBids, adapterExtra, bidRequest, resolvedRequest, debug, activityTraces, errList)
 	buffer := &bytes.Buffer{}
 	enc := json.NewEncoder(buffer)
 	enc.SetEscapeHTML(false)
@@ -523,7 +566,7 @@ func getPrimaryAdServer(adServerId int) (string, error) {
 }
 
 // Extract all the data from the SeatBids and build the ExtBidResponse
-func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, req *openrtb.BidRequest, resolvedRequest json.RawMessage, debug bool, errList []error) *openrtb_ext.ExtBidResponse {
+func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, req *openrtb.BidRequest, resolvedRequest json.RawMessage, debug bool, activityTraces []openrtb_ext.ExtActivityTrace, errList []error) *openrtb_ext.ExtBidResponse {
 	bidResponseExt := &openrtb_ext.ExtBidResponse{
 		Errors:               make(map[openrtb_ext.BidderName][]openrtb_ext.ExtBidderError, len(adapterBids)),
 		ResponseTimeMillis:   make(map[openrtb_ext.BidderName]int, len(adapterBids)),
@@ -531,7 +574,8 @@ func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*pb
 	}
 	if debug {
 		bidResponseExt.Debug = &openrtb_ext.ExtResponseDebug{
-			HttpCalls: make(map[openrtb_ext.BidderName][]*openrtb_ext.ExtHttpCall),
+			HttpCalls:  make(map[openrtb_ext.BidderName][]*openrtb_ext.ExtHttpCall),
+			Activities: activityTraces,
 		}
 		if err := json.Unmarshal(resolvedRequest, &bidResponseExt.Debug.ResolvedRequest); err != nil {
 			glog.Errorf("Error unmarshalling bid request snapshot: %v", err)
@@ -545,6 +589,12 @@ func (e *exchange) makeExtBidResponse(adapterBids map[openrtb_ext.BidderName]*pb
 				bidResponseExt.Debug.HttpCalls[a] = b.httpCalls
 			}
 		}
+		if debug && len(adapterExtra[a].EidSources) > 0 {
+			if bidResponseExt.Debug.Eids == nil {
+				bidResponseExt.Debug.Eids = make(map[openrtb_ext.BidderName][]string)
+			}
+			bidResponseExt.Debug.Eids[a] = adapterExtra[a].EidSources
+		}
 		// Only make an entry for bidder errors if the bidder reported any.
 		if len(adapterExtra[a].Errors) > 0 {
 			bidResponseExt.Errors[a] = adapterExtra[a].Errors
@@ -0,0 +1,212 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	jsonpatch "github.com/evanphx/json-patch"
+)
+
+// firstPartyData decides which first party data each bidder gets. It comes from request.ext.prebid.data
+// and request.ext.prebid.bidderconfig.
+type firstPartyData struct {
+	// allowedBidders are the only bidders which get site.ext.data, app.ext.data and user.ext.data.
+	// If it's nil, every bidder gets them.
+	allowedBidders map[string]struct{}
+	bidderConfigs  []openrtb_ext.ExtBidderConfig
+	eidPermissions []openrtb_ext.ExtRequestPrebidDataEidPermission
+}
+
+// extractFirstPartyData reads the first party data rules from the request.ext, and returns the request.ext without them.
+// The rules are taken out so that the bidders can't learn which other bidders take part in the auction, or what data they get.
+//
+// It returns a nil *firstPartyData if the request has no rules.
+func extractFirstPartyData(requestExt json.RawMessage) (*firstPartyData, json.RawMessage, error) {
+	if len(requestExt) == 0 {
+		return nil, requestExt, nil
+	}
+
+	var ext map[string]json.RawMessage
+	if err := json.Unmarshal(requestExt, &ext); err != nil {
+		return nil, nil, err
+	}
+	var prebidExt map[string]json.RawMessage
+	if rawPrebidExt, ok := ext[openrtb_ext.PrebidExtKey]; ok {
+		if err := json.Unmarshal(rawPrebidExt, &prebidExt); err != nil {
+			return nil, nil, err
+		}
+	}
+	rawData, hasData := prebidExt["data"]
+	rawConfigs, hasConfigs := prebidExt["bidderconfig"]
+	if !hasData && !hasConfigs {
+		return nil, requestExt, nil
+	}
+
+	fpd := &firstPartyData{}
+	if hasData {
+		var data openrtb_ext.ExtRequestPrebidData
+		if err := json.Unmarshal(rawData, &data); err != nil {
+			return nil, nil, fmt.Errorf("request.ext.prebid.data is invalid: %v", err)
+		}
+		fpd.eidPermissions = data.EidPermissions
+		if data.Bidders != nil {
+			fpd.allowedBidders = make(map[string]struct{}, len(data.Bidders))
+			for _, bidder := range data.Bidders {
+				fpd.allowedBidders[bidder] = struct{}{}
+			}
+		}
+	}
+	if hasConfigs {
+		if err := json.Unmarshal(rawConfigs, &fpd.bidderConfigs); err != nil {
+			return nil, nil, fmt.Errorf("request.ext.prebid.bidderconfig is invalid: %v", err)
+		}
+	}
+
+	delete(prebidExt, "data")
+	delete(prebidExt, "bidderconfig")
+	rawPrebidExt, err := json.Marshal(prebidExt)
+	if err != nil {
+		return nil, nil, err
+	}
+	ext[openrtb_ext.PrebidExtKey] = rawPrebidExt
+	cleanExt, err := json.Marshal(ext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return fpd, cleanExt, nil
+}
+
+// apply prepares the site, app and user of the bidder's request. The ext.data is taken out if the bidder isn't allowed
+// to have it, and then the bidder's own config is merged in.
+//
+// This replaces req.Site, req.App and req.User instead of changing them, since they're shared with the other bidders.
+func (fpd *firstPartyData) apply(req *openrtb.BidRequest, bidder string) error {
+	if fpd == nil {
+		return nil
+	}
+
+	if fpd.allowedBidders != nil {
+		if _, ok := fpd.allowedBidders[bidder]; !ok {
+			if err := removeExtData(req); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, bidderConfig := range fpd.bidderConfigs {
+		if bidderConfig.Config == nil || bidderConfig.Config.ORTB2 == nil || !containsBidder(bidderConfig.Bidders, bidder) {
+			continue
+		}
+		ortb2 := bidderConfig.Config.ORTB2
+		if len(ortb2.Site) > 0 && req.Site != nil {
+			var site openrtb.Site
+			if err := mergeFirstPartyData(req.Site, ortb2.Site, &site); err != nil {
+				return fmt.Errorf("request.ext.prebid.bidderconfig for %s has an invalid site: %v", bidder, err)
+			}
+			req.Site = &site
+		}
+		if len(ortb2.App) > 0 && req.App != nil {
+			var app openrtb.App
+			if err := mergeFirstPartyData(req.App, ortb2.App, &app); err != nil {
+				return fmt.Errorf("request.ext.prebid.bidderconfig for %s has an invalid app: %v", bidder, err)
+			}
+			req.App = &app
+		}
+		if len(ortb2.User) > 0 {
+			user := req.User
+			if user == nil {
+				user = &openrtb.User{}
+			}
+			var mergedUser openrtb.User
+			if err := mergeFirstPartyData(user, ortb2.User, &mergedUser); err != nil {
+				return fmt.Errorf("request.ext.prebid.bidderconfig for %s has an invalid user: %v", bidder, err)
+			}
+			req.User = &mergedUser
+		}
+	}
+	return nil
+}
+
+// removeExtData replaces the request's site, app and user with copies which have no ext.data.
+func removeExtData(req *openrtb.BidRequest) error {
+	if req.Site != nil {
+		if ext, changed, err := withoutData(req.Site.Ext); err != nil {
+			return fmt.Errorf("request.site.ext is invalid: %v", err)
+		} else if changed {
+			site := *req.Site
+			site.Ext = ext
+			req.Site = &site
+		}
+	}
+	if req.App != nil {
+		if ext, changed, err := withoutData(req.App.Ext); err != nil {
+			return fmt.Errorf("request.app.ext is invalid: %v", err)
+		} else if changed {
+			app := *req.App
+			app.Ext = ext
+			req.App = &app
+		}
+	}
+	if req.User != nil {
+		if ext, changed, err := withoutData(req.User.Ext); err != nil {
+			return fmt.Errorf("request.user.ext is invalid: %v", err)
+		} else if changed {
+			user := *req.User
+			user.Ext = ext
+			req.User = &user
+		}
+	}
+	return nil
+}
+
+// withoutData returns the ext without its "data" field, and whether there was one.
+func withoutData(ext json.RawMessage) (json.RawMessage, bool, error) {
+	if len(ext) == 0 {
+		return ext, false, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(ext, &fields); err != nil {
+		return nil, false, err
+	}
+	if _, ok := fields["data"]; !ok {
+		return ext, false, nil
+	}
+	delete(fields, "data")
+	if len(fields) == 0 {
+		return nil, true, nil
+	}
+	newExt, err := json.Marshal(fields)
+	return newExt, true, err
+}
+
+// mergeFirstPartyData merges the patch into orig as a JSON Merge Patch, and unmarshals the result into out.
+func mergeFirstPartyData(orig interface{}, patch json.RawMessage, out interface{}) error {
+	origJSON, err := json.Marshal(orig)
+	if err != nil {
+		return err
+	}
+	merged, err := jsonpatch.MergePatch(origJSON, patch)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(merged, out)
+}
+
+func containsBidder(bidders []string, bidder string) bool {
+	for _, b := range bidders {
+		if b == bidder {
+			return true
+		}
+	}
+	return false
+}
+
+// getEidPermissions returns the request.ext.prebid.data.eidpermissions.
+func (fpd *firstPartyData) getEidPermissions() []openrtb_ext.ExtRequestPrebidDataEidPermission {
+	if fpd == nil {
+		return nil
+	}
+	return fpd.eidPermissions
+}
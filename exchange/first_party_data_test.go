@@ -0,0 +1,64 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanOpenRTBRequestsFirstPartyData(t *testing.T) {
+	req := &openrtb.BidRequest{
+		ID: "request",
+		Site: &openrtb.Site{
+			Page: "http://www.example.com",
+			Ext:  json.RawMessage(`{"amp":0,"data":{"section":"sports"}}`),
+		},
+		User: &openrtb.User{
+			Ext: json.RawMessage(`{"consent":"BONciguONcjGKADACHENAOLS1rAHDAFAAEAASABQAMwAeACEAFw","data":{"segments":["a"]}}`),
+		},
+		Imp: []openrtb.Imp{{
+			ID:     "imp",
+			Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 300, H: 250}}},
+			Ext:    json.RawMessage(`{"appnexus":{"placementId":1},"rubicon":{},"openx":{}}`),
+		}},
+		Ext: json.RawMessage(`{"prebid":{"debug":1,"data":{"bidders":["appnexus","openx"]},"bidderconfig":[{"bidders":["openx"],"config":{"ortb2":{"site":{"ext":{"data":{"section":"news"}}},"user":{"keywords":"openx"}}}}]}}`),
+	}
+
+	requests, _, errs := cleanOpenRTBRequests(context.Background(), req, &emptyUsersync{}, map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels{}, pbsmetrics.Labels{}, &permissionsMock{}, true, false, nil, nil, nil)
+
+	assert.Empty(t, errs)
+	if assert.Len(t, requests, 3) {
+		appnexus := requests["appnexus"]
+		assert.JSONEq(t, `{"amp":0,"data":{"section":"sports"}}`, string(appnexus.Site.Ext))
+		assert.Contains(t, string(appnexus.User.Ext), `"segments"`)
+		assert.JSONEq(t, `{"prebid":{"debug":1}}`, string(appnexus.Ext), "The bidders shouldn't see the first party data rules")
+
+		rubicon := requests["rubicon"]
+		assert.JSONEq(t, `{"amp":0}`, string(rubicon.Site.Ext), "A bidder outside ext.prebid.data.bidders shouldn't get site.ext.data")
+		assert.JSONEq(t, `{"consent":"BONciguONcjGKADACHENAOLS1rAHDAFAAEAASABQAMwAeACEAFw"}`, string(rubicon.User.Ext))
+
+		openx := requests["openx"]
+		assert.JSONEq(t, `{"amp":0,"data":{"section":"news"}}`, string(openx.Site.Ext))
+		assert.Equal(t, "http://www.example.com", openx.Site.Page)
+		assert.Equal(t, "openx", openx.User.Keywords)
+	}
+
+	assert.JSONEq(t, `{"amp":0,"data":{"section":"sports"}}`, string(req.Site.Ext), "The original request shouldn't change")
+	assert.Empty(t, req.User.Keywords)
+}
+
+func TestExtractFirstPartyDataWithoutRules(t *testing.T) {
+	ext := json.RawMessage(`{"prebid":{"debug":1}}`)
+
+	fpd, cleanExt, err := extractFirstPartyData(ext)
+
+	assert.NoError(t, err)
+	assert.Nil(t, fpd)
+	assert.Equal(t, ext, cleanExt, "A request without first party data rules should be left alone")
+	assert.NoError(t, fpd.apply(&openrtb.BidRequest{}, "appnexus"))
+}
@@ -2,19 +2,31 @@ package exchange
 
 import (
 	"encoding/json"
+	"strings"
 
 	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/gpp"
 )
 
-// ExtractGDPR will pull the gdpr flag from an openrtb request
-func extractGDPR(bidRequest *openrtb.BidRequest, usersyncIfAmbiguous bool) (gdpr int) {
+// ExtractGDPR will pull the gdpr flag from an openrtb request. If the request doesn't have one,
+// the GPP section IDs decide whether GDPR applies. If they don't either, GDPR applies when device.geo.country
+// is one of the eeaCountries, and the request is ambiguous when the country is unknown.
+func extractGDPR(bidRequest *openrtb.BidRequest, usersyncIfAmbiguous bool, eeaCountries map[string]struct{}) (gdpr int) {
 	var re regsExt
 	var err error
 	if bidRequest.Regs != nil {
 		err = json.Unmarshal(bidRequest.Regs.Ext, &re)
 	}
 	if re.GDPR == nil || err != nil {
-		if usersyncIfAmbiguous {
+		if signal := extractGPPSignals(bidRequest).GDPR; signal != "" {
+			if signal == "1" {
+				gdpr = 1
+			}
+		} else if country := deviceCountry(bidRequest); country != "" {
+			if _, ok := eeaCountries[strings.ToUpper(country)]; ok {
+				gdpr = 1
+			}
+		} else if usersyncIfAmbiguous {
 			gdpr = 0
 		} else {
 			gdpr = 1
@@ -25,7 +37,15 @@ func extractGDPR(bidRequest *openrtb.BidRequest, usersyncIfAmbiguous bool) (gdpr
 	return
 }
 
-// ExtractConsent will pull the consent string from an openrtb request
+func deviceCountry(bidRequest *openrtb.BidRequest) string {
+	if bidRequest.Device == nil || bidRequest.Device.Geo == nil {
+		return ""
+	}
+	return bidRequest.Device.Geo.Country
+}
+
+// ExtractConsent will pull the consent string from an openrtb request. If the request doesn't have one,
+// the TCF EU v2 section of its GPP string is used.
 func extractConsent(bidRequest *openrtb.BidRequest) (consent string) {
 	var ue userExt
 	var err error
@@ -36,9 +56,26 @@ func extractConsent(bidRequest *openrtb.BidRequest) (consent string) {
 		return
 	}
 	consent = ue.Consent
+	if consent == "" {
+		consent = extractGPPSignals(bidRequest).TCFConsent
+	}
 	return
 }
 
+// extractGPPSignals reads the privacy signals of an openrtb request's GPP string. A GPP string which can't be
+// parsed has none, since the request was already warned about it.
+func extractGPPSignals(bidRequest *openrtb.BidRequest) gpp.Signals {
+	policy, err := gpp.ReadPolicy(bidRequest)
+	if err != nil {
+		return gpp.Signals{}
+	}
+	signals, err := policy.Signals()
+	if err != nil {
+		return gpp.Signals{GDPR: signals.GDPR}
+	}
+	return signals
+}
+
 type userExt struct {
 	Consent string `json:"consent,omitempty"`
 }
@@ -17,13 +17,13 @@ func TestExtractGDPRFound(t *testing.T) {
 			Ext: json.RawMessage(`{"gdpr": 1}`),
 		},
 	}
-	gdpr := extractGDPR(&gdprTest, false)
+	gdpr := extractGDPR(&gdprTest, false, nil)
 	consent := extractConsent(&gdprTest)
 	assert.Equal(t, 1, gdpr)
 	assert.Equal(t, "BOS2bx5OS2bx5ABABBAAABoAAAAAFA", consent)
 
 	gdprTest.Regs.Ext = json.RawMessage(`{"gdpr": 0}`)
-	gdpr = extractGDPR(&gdprTest, true)
+	gdpr = extractGDPR(&gdprTest, true, nil)
 	consent = extractConsent(&gdprTest)
 	assert.Equal(t, 0, gdpr)
 	assert.Equal(t, "BOS2bx5OS2bx5ABABBAAABoAAAAAFA", consent)
@@ -32,13 +32,53 @@ func TestExtractGDPRFound(t *testing.T) {
 func TestGDPRUnknown(t *testing.T) {
 	gdprTest := openrtb.BidRequest{}
 
-	gdpr := extractGDPR(&gdprTest, false)
+	gdpr := extractGDPR(&gdprTest, false, nil)
 	consent := extractConsent(&gdprTest)
 	assert.Equal(t, 1, gdpr)
 	assert.Equal(t, "", consent)
 
-	gdpr = extractGDPR(&gdprTest, true)
+	gdpr = extractGDPR(&gdprTest, true, nil)
 	consent = extractConsent(&gdprTest)
 	assert.Equal(t, 0, gdpr)
 
 }
+
+func TestExtractGDPRFromGPP(t *testing.T) {
+	gdprTest := openrtb.BidRequest{
+		Regs: &openrtb.Regs{
+			Ext: json.RawMessage(`{"gpp":"DBABMA~CAAAAAAAAAAAAAAAAAAAAoAAAMAAAAAAAAAAABmA","gpp_sid":[2]}`),
+		},
+	}
+	assert.Equal(t, 1, extractGDPR(&gdprTest, true, nil))
+	assert.Equal(t, "CAAAAAAAAAAAAAAAAAAAAoAAAMAAAAAAAAAAABmA", extractConsent(&gdprTest))
+
+	gdprTest.Regs.Ext = json.RawMessage(`{"gpp":"DBABMA~CAAAAAAAAAAAAAAAAAAAAoAAAMAAAAAAAAAAABmA","gpp_sid":[6]}`)
+	assert.Equal(t, 0, extractGDPR(&gdprTest, false, nil))
+	assert.Equal(t, "", extractConsent(&gdprTest), "A section which doesn't apply shouldn't be read")
+
+	gdprTest.Regs.Ext = json.RawMessage(`{"gdpr":0,"gpp":"DBABMA~CAAAAAAAAAAAAAAAAAAAAoAAAMAAAAAAAAAAABmA","gpp_sid":[2]}`)
+	gdprTest.User = &openrtb.User{Ext: json.RawMessage(`{"consent":"BOS2bx5OS2bx5ABABBAAABoAAAAAFA"}`)}
+	assert.Equal(t, 0, extractGDPR(&gdprTest, false, nil), "regs.ext.gdpr should win over the GPP section IDs")
+	assert.Equal(t, "BOS2bx5OS2bx5ABABBAAABoAAAAAFA", extractConsent(&gdprTest), "user.ext.consent should win over the GPP string")
+}
+
+func TestExtractGDPRFromGeo(t *testing.T) {
+	eeaCountries := map[string]struct{}{"FRA": {}, "DEU": {}}
+	gdprTest := openrtb.BidRequest{
+		Device: &openrtb.Device{Geo: &openrtb.Geo{Country: "FRA"}},
+	}
+	assert.Equal(t, 1, extractGDPR(&gdprTest, true, eeaCountries), "A country in the EEA should win over usersync_if_ambiguous")
+
+	gdprTest.Device.Geo.Country = "usa"
+	assert.Equal(t, 0, extractGDPR(&gdprTest, false, eeaCountries), "A country outside the EEA should win over usersync_if_ambiguous")
+
+	gdprTest.Device.Geo.Country = ""
+	assert.Equal(t, 1, extractGDPR(&gdprTest, false, eeaCountries), "An unknown country should be ambiguous")
+
+	gdprTest.Device.Geo.Country = "FRA"
+	gdprTest.Regs = &openrtb.Regs{Ext: json.RawMessage(`{"gdpr":0}`)}
+	assert.Equal(t, 0, extractGDPR(&gdprTest, true, eeaCountries), "regs.ext.gdpr should win over the country")
+
+	gdprTest.Regs.Ext = json.RawMessage(`{"gpp":"DBABMA~CAAAAAAAAAAAAAAAAAAAAoAAAMAAAAAAAAAAABmA","gpp_sid":[6]}`)
+	assert.Equal(t, 0, extractGDPR(&gdprTest, true, eeaCountries), "The GPP section IDs should win over the country")
+}
@@ -0,0 +1,51 @@
+package exchange
+
+import (
+	"context"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/geolocation"
+)
+
+// fillDeviceGeo looks up the location of the device's IP address if the request doesn't say which country the
+// device is in. The fields of device.geo which the request already has are kept. The lookup is best effort, so
+// the request is left alone if it fails.
+func fillDeviceGeo(ctx context.Context, geoLocation geolocation.GeoLocation, bidRequest *openrtb.BidRequest) {
+	if geoLocation == nil || bidRequest.Device == nil {
+		return
+	}
+	if bidRequest.Device.Geo != nil && bidRequest.Device.Geo.Country != "" {
+		return
+	}
+	ip := bidRequest.Device.IP
+	if ip == "" {
+		ip = bidRequest.Device.IPv6
+	}
+	if ip == "" {
+		return
+	}
+
+	info, err := geoLocation.Lookup(ctx, ip)
+	if err != nil {
+		return
+	}
+
+	// The device and its geo are copied, since other parts of the request may share them.
+	device := *bidRequest.Device
+	var geo openrtb.Geo
+	if device.Geo != nil {
+		geo = *device.Geo
+	}
+	geo.Country = info.Country
+	if geo.Region == "" {
+		geo.Region = info.Region
+	}
+	if geo.Metro == "" {
+		geo.Metro = info.Metro
+	}
+	if geo.Type == 0 && geo.Lat == 0 && geo.Lon == 0 {
+		geo.Type = openrtb.LocationTypeIPAddress
+	}
+	device.Geo = &geo
+	bidRequest.Device = &device
+}
@@ -0,0 +1,126 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/currencies"
+	"github.com/PubMatic-OpenWrap/prebid-server/gdpr"
+	"github.com/PubMatic-OpenWrap/prebid-server/geolocation"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockGeoLocation map[string]geolocation.GeoInfo
+
+func (m mockGeoLocation) Lookup(ctx context.Context, ip string) (*geolocation.GeoInfo, error) {
+	if info, ok := m[ip]; ok {
+		return &info, nil
+	}
+	return nil, geolocation.ErrNotFound
+}
+
+func TestFillDeviceGeo(t *testing.T) {
+	geoLocation := mockGeoLocation{
+		"1.2.3.4":     {Country: "USA", Region: "CA", Metro: "807"},
+		"2001:db8::1": {Country: "DEU", Region: "BE"},
+	}
+
+	testCases := []struct {
+		description string
+		device      *openrtb.Device
+		expected    *openrtb.Device
+	}{
+		{
+			description: "No Device",
+		},
+		{
+			description: "No Geo",
+			device:      &openrtb.Device{IP: "1.2.3.4"},
+			expected:    &openrtb.Device{IP: "1.2.3.4", Geo: &openrtb.Geo{Country: "USA", Region: "CA", Metro: "807", Type: openrtb.LocationTypeIPAddress}},
+		},
+		{
+			description: "IPv6",
+			device:      &openrtb.Device{IPv6: "2001:db8::1"},
+			expected:    &openrtb.Device{IPv6: "2001:db8::1", Geo: &openrtb.Geo{Country: "DEU", Region: "BE", Type: openrtb.LocationTypeIPAddress}},
+		},
+		{
+			description: "Geo Without Country",
+			device:      &openrtb.Device{IP: "1.2.3.4", Geo: &openrtb.Geo{Region: "NY", Lat: 40.7, Lon: -74}},
+			expected:    &openrtb.Device{IP: "1.2.3.4", Geo: &openrtb.Geo{Country: "USA", Region: "NY", Metro: "807", Lat: 40.7, Lon: -74}},
+		},
+		{
+			description: "Geo With Country",
+			device:      &openrtb.Device{IP: "1.2.3.4", Geo: &openrtb.Geo{Country: "CAN"}},
+			expected:    &openrtb.Device{IP: "1.2.3.4", Geo: &openrtb.Geo{Country: "CAN"}},
+		},
+		{
+			description: "Unknown IP",
+			device:      &openrtb.Device{IP: "5.6.7.8"},
+			expected:    &openrtb.Device{IP: "5.6.7.8"},
+		},
+		{
+			description: "No IP",
+			device:      &openrtb.Device{UA: "Mozilla"},
+			expected:    &openrtb.Device{UA: "Mozilla"},
+		},
+	}
+
+	for _, test := range testCases {
+		req := &openrtb.BidRequest{Device: test.device}
+		fillDeviceGeo(context.Background(), geoLocation, req)
+		assert.Equal(t, test.expected, req.Device, test.description)
+	}
+}
+
+func TestFillDeviceGeoCopies(t *testing.T) {
+	geo := &openrtb.Geo{Region: "NY"}
+	device := &openrtb.Device{IP: "1.2.3.4", Geo: geo}
+	req := &openrtb.BidRequest{Device: device}
+
+	fillDeviceGeo(context.Background(), mockGeoLocation{"1.2.3.4": {Country: "USA"}}, req)
+
+	assert.Equal(t, "USA", req.Device.Geo.Country)
+	assert.Equal(t, "", geo.Country, "The original geo shouldn't change")
+	assert.True(t, device.Geo == geo, "The original device shouldn't change")
+}
+
+func TestResolvedRequestBeforeGeoLookup(t *testing.T) {
+	appnexus := &recordingBidder{}
+	e := &exchange{
+		adapterMap:        map[openrtb_ext.BidderName]adaptedBidder{openrtb_ext.BidderAppnexus: appnexus},
+		me:                &metricsConf.DummyMetricsEngine{},
+		cache:             &mockCache{},
+		gDPR:              gdpr.AlwaysAllow{},
+		currencyConverter: currencies.NewRateConverterDefault(),
+		geoLocation:       mockGeoLocation{"1.2.3.4": {Country: "USA", Region: "CA"}},
+	}
+	request := &openrtb.BidRequest{
+		ID:     "request",
+		Site:   &openrtb.Site{Page: "http://www.example.com"},
+		Device: &openrtb.Device{IP: "1.2.3.4"},
+		Ext:    json.RawMessage(`{"prebid":{"debug":1}}`),
+		Imp: []openrtb.Imp{{
+			ID:     "imp",
+			Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 300, H: 250}}},
+			Ext:    json.RawMessage(`{"appnexus":{"placementId":1}}`),
+		}},
+	}
+
+	response, err := e.HoldAuction(context.Background(), request, &emptyUsersync{}, pbsmetrics.Labels{}, nil, nil)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, appnexus.requests, 1) && assert.NotNil(t, appnexus.requests[0].Device.Geo) {
+		assert.Equal(t, "USA", appnexus.requests[0].Device.Geo.Country, "The bidders should get the device's location")
+	}
+	var ext openrtb_ext.ExtBidResponse
+	if assert.NoError(t, json.Unmarshal(response.Ext, &ext)) && assert.NotNil(t, ext.Debug) && assert.NotNil(t, ext.Debug.ResolvedRequest) {
+		assert.Nil(t, ext.Debug.ResolvedRequest.Device.Geo, "The resolved request should show the device as it was sent")
+	}
+}
@@ -0,0 +1,174 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/currencies"
+	"github.com/PubMatic-OpenWrap/prebid-server/errortypes"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+)
+
+// StoredResponses are the canned responses which stand in for the bidders on some imps, so that an auction
+// can be tested without live demand. The endpoints fetch them for imp.ext.prebid.storedauctionresponse and
+// imp.ext.prebid.storedbidresponse.
+type StoredResponses struct {
+	// AuctionResponses holds the responses for the imps which skip the bidders entirely, by imp ID.
+	AuctionResponses map[string]StoredAuctionResponse
+	// BidResponses holds the bodies which the bidders parse instead of their servers' responses, by imp ID and bidder.
+	BidResponses map[string]map[openrtb_ext.BidderName]json.RawMessage
+}
+
+// StoredAuctionResponse holds the SeatBids which stand in for the bidders on an imp.
+type StoredAuctionResponse struct {
+	SeatBids []openrtb.SeatBid
+	// Currency is the currency of the bids' prices. It's USD if empty.
+	Currency string
+}
+
+// withoutAuctionResponses returns a copy of the request which leaves out the imps with a Stored Auction Response.
+func (r *StoredResponses) withoutAuctionResponses(bidRequest *openrtb.BidRequest) *openrtb.BidRequest {
+	if r == nil || len(r.AuctionResponses) == 0 {
+		return bidRequest
+	}
+
+	liveRequest := *bidRequest
+	liveRequest.Imp = make([]openrtb.Imp, 0, len(bidRequest.Imp))
+	for _, imp := range bidRequest.Imp {
+		if _, ok := r.AuctionResponses[imp.ID]; !ok {
+			liveRequest.Imp = append(liveRequest.Imp, imp)
+		}
+	}
+	return &liveRequest
+}
+
+// bidResponsesFor returns the Stored Bid Responses for the bidder, by imp ID.
+func (r *StoredResponses) bidResponsesFor(bidder openrtb_ext.BidderName) map[string]json.RawMessage {
+	if r == nil {
+		return nil
+	}
+
+	var responses map[string]json.RawMessage
+	for impID, bidderResponses := range r.BidResponses {
+		if response, ok := bidderResponses[bidder]; ok {
+			if responses == nil {
+				responses = make(map[string]json.RawMessage)
+			}
+			responses[impID] = response
+		}
+	}
+	return responses
+}
+
+// addAuctionResponses adds the bids from the Stored Auction Responses to the ones the bidders made, so that they
+// go through the auction, the targeting and the cache like any other bid. Each SeatBid's seat is used as the bidder,
+// so it must be one of the host's bidders or an alias of one. The prices are converted to the seat's currency.
+//
+// It returns the bidders which now have a seat in the response, and whether any bids were added.
+func (r *StoredResponses) addAuctionResponses(bidRequest *openrtb.BidRequest, liveAdapters []openrtb_ext.BidderName, adapterBids map[openrtb_ext.BidderName]*pbsOrtbSeatBid, adapterExtra map[openrtb_ext.BidderName]*seatResponseExtra, adapterMap map[openrtb_ext.BidderName]adaptedBidder, aliases map[string]string, conversions currencies.Conversions) ([]openrtb_ext.BidderName, bool, []error) {
+	if r == nil {
+		return liveAdapters, false, nil
+	}
+
+	var errs []error
+	bidsAdded := false
+	for _, imp := range bidRequest.Imp {
+		storedResponse := r.AuctionResponses[imp.ID]
+		for _, seatBid := range storedResponse.SeatBids {
+			bidder := openrtb_ext.BidderName(seatBid.Seat)
+			if _, ok := adapterMap[resolveBidder(seatBid.Seat, aliases)]; !ok {
+				errs = append(errs, &errortypes.BadServerResponse{
+					Message: fmt.Sprintf("Stored Auction Response for imp %s has a seat %q which isn't a known bidder or alias", imp.ID, seatBid.Seat),
+				})
+				continue
+			}
+			if _, ok := adapterExtra[bidder]; !ok {
+				adapterExtra[bidder] = &seatResponseExtra{}
+				liveAdapters = append(liveAdapters, bidder)
+			}
+			if adapterBids[bidder] == nil {
+				adapterBids[bidder] = &pbsOrtbSeatBid{currency: "USD"}
+			}
+			conversionRate, err := storedBidsRate(bidRequest, adapterBids[bidder], storedResponse.Currency, conversions)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			for i := range seatBid.Bid {
+				bid := seatBid.Bid[i]
+				bid.ImpID = imp.ID
+				bid.Price = bid.Price * conversionRate
+				bidType, bidderExt, err := storedBidType(&bid, &imp)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				bid.Ext = bidderExt
+				adapterBids[bidder].bids = append(adapterBids[bidder].bids, &pbsOrtbBid{
+					bid:     &bid,
+					bidType: bidType,
+				})
+				bidsAdded = true
+			}
+		}
+	}
+	return liveAdapters, bidsAdded, errs
+}
+
+// storedBidsRate returns the rate which converts the Stored Auction Response's prices to the seat's currency.
+// A seat which has no bids yet takes the first of the request's currencies which the stored prices convert to,
+// the way requestBid picks one for a Bidder's response.
+func storedBidsRate(bidRequest *openrtb.BidRequest, seatBid *pbsOrtbSeatBid, storedCurrency string, conversions currencies.Conversions) (float64, error) {
+	if storedCurrency == "" {
+		storedCurrency = "USD"
+	}
+	if len(seatBid.bids) > 0 {
+		return conversions.GetRate(storedCurrency, seatBid.currency)
+	}
+
+	requestCurrencies := bidRequest.Cur
+	if len(requestCurrencies) == 0 {
+		requestCurrencies = []string{"USD"}
+	}
+	var rate float64
+	var err error
+	for _, currency := range requestCurrencies {
+		if rate, err = conversions.GetRate(storedCurrency, currency); err == nil {
+			seatBid.currency = currency
+			return rate, nil
+		}
+	}
+	return 0, err
+}
+
+// storedBidType reads the bid type from bid.ext.prebid.type or, failing that, from the imp's media types.
+// It also returns bid.ext.bidder, which becomes the bidder's ext in the response.
+func storedBidType(bid *openrtb.Bid, imp *openrtb.Imp) (openrtb_ext.BidType, json.RawMessage, error) {
+	var bidExt openrtb_ext.ExtBid
+	if len(bid.Ext) > 0 {
+		if err := json.Unmarshal(bid.Ext, &bidExt); err != nil {
+			return "", nil, &errortypes.BadServerResponse{
+				Message: fmt.Sprintf("Stored Auction Response for imp %s has a bid with a malformed ext: %v", imp.ID, err),
+			}
+		}
+	}
+	if bidExt.Prebid != nil && bidExt.Prebid.Type != "" {
+		return bidExt.Prebid.Type, bidExt.Bidder, nil
+	}
+
+	switch {
+	case imp.Banner != nil:
+		return openrtb_ext.BidTypeBanner, bidExt.Bidder, nil
+	case imp.Video != nil:
+		return openrtb_ext.BidTypeVideo, bidExt.Bidder, nil
+	case imp.Native != nil:
+		return openrtb_ext.BidTypeNative, bidExt.Bidder, nil
+	case imp.Audio != nil:
+		return openrtb_ext.BidTypeAudio, bidExt.Bidder, nil
+	}
+	return "", nil, &errortypes.BadServerResponse{
+		Message: fmt.Sprintf("Stored Auction Response for imp %s has a bid with no ext.prebid.type", imp.ID),
+	}
+}
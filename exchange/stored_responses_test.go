@@ -0,0 +1,225 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/adapters"
+	"github.com/PubMatic-OpenWrap/prebid-server/currencies"
+	"github.com/PubMatic-OpenWrap/prebid-server/errortypes"
+	"github.com/PubMatic-OpenWrap/prebid-server/gdpr"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoredAuctionResponses(t *testing.T) {
+	appnexus := &recordingBidder{}
+	e := &exchange{
+		adapterMap:        map[openrtb_ext.BidderName]adaptedBidder{openrtb_ext.BidderAppnexus: appnexus},
+		me:                &metricsConf.DummyMetricsEngine{},
+		cache:             &mockCache{},
+		gDPR:              gdpr.AlwaysAllow{},
+		currencyConverter: currencies.NewRateConverterDefault(),
+	}
+	request := &openrtb.BidRequest{
+		ID:   "request",
+		Site: &openrtb.Site{Page: "http://www.example.com"},
+		Ext:  json.RawMessage(`{"prebid":{"aliases":{"testbidder":"appnexus"}}}`),
+		Imp: []openrtb.Imp{{
+			ID:     "live",
+			Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 300, H: 250}}},
+			Ext:    json.RawMessage(`{"appnexus":{"placementId":1}}`),
+		}, {
+			ID:    "stored",
+			Video: &openrtb.Video{MIMEs: []string{"video/mp4"}},
+			Ext:   json.RawMessage(`{"appnexus":{"placementId":1},"prebid":{"storedauctionresponse":{"id":"1"}}}`),
+		}},
+	}
+	storedResponses := &StoredResponses{
+		AuctionResponses: map[string]StoredAuctionResponse{
+			"stored": {SeatBids: []openrtb.SeatBid{{
+				Seat: "testbidder",
+				Bid: []openrtb.Bid{
+					{ID: "video-bid", ImpID: "some-other-imp", Price: 2, Ext: json.RawMessage(`{"bidder":{"key":"value"}}`)},
+					{ID: "banner-bid", Price: 1, Ext: json.RawMessage(`{"prebid":{"type":"banner"}}`)},
+				},
+			}}},
+		},
+	}
+
+	response, err := e.HoldAuction(context.Background(), request, &emptyUsersync{}, pbsmetrics.Labels{}, nil, storedResponses)
+
+	assert.NoError(t, err)
+	if assert.Len(t, appnexus.requests, 1) {
+		assert.Len(t, appnexus.requests[0].Imp, 1, "The imp with a Stored Auction Response shouldn't reach the bidders")
+		assert.Equal(t, "live", appnexus.requests[0].Imp[0].ID)
+	}
+	if assert.Len(t, response.SeatBid, 1) {
+		seatBid := response.SeatBid[0]
+		assert.Equal(t, "testbidder", seatBid.Seat)
+		if assert.Len(t, seatBid.Bid, 2) {
+			assert.Equal(t, "stored", seatBid.Bid[0].ImpID, "Stored bids should be moved to the imp which asked for them")
+			assertBidExt(t, seatBid.Bid[0].Ext, openrtb_ext.BidTypeVideo, `{"key":"value"}`)
+			assertBidExt(t, seatBid.Bid[1].Ext, openrtb_ext.BidTypeBanner, "")
+		}
+	}
+}
+
+func TestStoredAuctionResponsesWithoutBidType(t *testing.T) {
+	storedResponses := &StoredResponses{
+		AuctionResponses: map[string]StoredAuctionResponse{
+			"imp": {SeatBids: []openrtb.SeatBid{{Seat: "appnexus", Bid: []openrtb.Bid{{ID: "bid", Price: 1}}}}},
+		},
+	}
+	request := &openrtb.BidRequest{Imp: []openrtb.Imp{{ID: "imp"}}}
+	adapterBids := make(map[openrtb_ext.BidderName]*pbsOrtbSeatBid)
+	adapterExtra := make(map[openrtb_ext.BidderName]*seatResponseExtra)
+
+	liveAdapters, bidsAdded, errs := storedResponses.addAuctionResponses(request, nil, adapterBids, adapterExtra, storedResponsesAdapterMap, nil, currencies.NewConstantRates())
+
+	assert.False(t, bidsAdded)
+	assert.Len(t, errs, 1, "A bid type can't be guessed for an imp without media types")
+	assert.Equal(t, []openrtb_ext.BidderName{"appnexus"}, liveAdapters)
+	assert.NotNil(t, adapterExtra["appnexus"])
+}
+
+func TestStoredAuctionResponsesUnknownSeat(t *testing.T) {
+	storedResponses := &StoredResponses{
+		AuctionResponses: map[string]StoredAuctionResponse{
+			"imp": {SeatBids: []openrtb.SeatBid{
+				{Seat: "unknown", Bid: []openrtb.Bid{{ID: "unknown-bid", Price: 1}}},
+				{Seat: "alias", Bid: []openrtb.Bid{{ID: "alias-bid", Price: 1}}},
+			}},
+		},
+	}
+	request := &openrtb.BidRequest{Imp: []openrtb.Imp{{ID: "imp", Banner: &openrtb.Banner{}}}}
+	adapterBids := make(map[openrtb_ext.BidderName]*pbsOrtbSeatBid)
+	adapterExtra := make(map[openrtb_ext.BidderName]*seatResponseExtra)
+
+	liveAdapters, bidsAdded, errs := storedResponses.addAuctionResponses(request, nil, adapterBids, adapterExtra, storedResponsesAdapterMap, map[string]string{"alias": "appnexus"}, currencies.NewConstantRates())
+
+	assert.True(t, bidsAdded)
+	if assert.Len(t, errs, 1, "A seat which isn't a bidder or an alias should be rejected") {
+		assert.IsType(t, &errortypes.BadServerResponse{}, errs[0])
+	}
+	assert.Equal(t, []openrtb_ext.BidderName{"alias"}, liveAdapters)
+	assert.Nil(t, adapterBids["unknown"])
+	assert.Nil(t, adapterExtra["unknown"])
+}
+
+func TestStoredAuctionResponsesCurrency(t *testing.T) {
+	conversions := currencies.NewRates(time.Now(), map[string]map[string]float64{
+		"USD": {"EUR": 0.5, "GBP": 0.25},
+	})
+	testCases := []struct {
+		description      string
+		storedCurrency   string
+		requestCur       []string
+		liveBids         *pbsOrtbSeatBid
+		expectedCurrency string
+		expectedPrice    float64
+		expectedErrors   int
+	}{
+		{
+			description:      "Stored prices in USD by default",
+			expectedCurrency: "USD",
+			expectedPrice:    2,
+		},
+		{
+			description:      "Converted to the request's currency",
+			storedCurrency:   "USD",
+			requestCur:       []string{"EUR"},
+			expectedCurrency: "EUR",
+			expectedPrice:    1,
+		},
+		{
+			description:      "Converted to the first request currency which has a rate",
+			storedCurrency:   "USD",
+			requestCur:       []string{"JPY", "GBP"},
+			expectedCurrency: "GBP",
+			expectedPrice:    0.5,
+		},
+		{
+			description:      "Converted to the currency of the bidder's live bids",
+			storedCurrency:   "USD",
+			requestCur:       []string{"GBP", "EUR"},
+			liveBids:         &pbsOrtbSeatBid{currency: "EUR", bids: []*pbsOrtbBid{{bid: &openrtb.Bid{ID: "live"}}}},
+			expectedCurrency: "EUR",
+			expectedPrice:    1,
+		},
+		{
+			description:      "No rate",
+			storedCurrency:   "JPY",
+			requestCur:       []string{"EUR"},
+			expectedCurrency: "USD",
+			expectedErrors:   1,
+		},
+	}
+
+	for _, test := range testCases {
+		storedResponses := &StoredResponses{
+			AuctionResponses: map[string]StoredAuctionResponse{
+				"imp": {
+					SeatBids: []openrtb.SeatBid{{Seat: "appnexus", Bid: []openrtb.Bid{{ID: "stored", Price: 2}}}},
+					Currency: test.storedCurrency,
+				},
+			},
+		}
+		request := &openrtb.BidRequest{Cur: test.requestCur, Imp: []openrtb.Imp{{ID: "imp", Banner: &openrtb.Banner{}}}}
+		adapterBids := make(map[openrtb_ext.BidderName]*pbsOrtbSeatBid)
+		if test.liveBids != nil {
+			adapterBids["appnexus"] = test.liveBids
+		}
+
+		_, _, errs := storedResponses.addAuctionResponses(request, nil, adapterBids, make(map[openrtb_ext.BidderName]*seatResponseExtra), storedResponsesAdapterMap, nil, conversions)
+
+		assert.Len(t, errs, test.expectedErrors, test.description)
+		assert.Equal(t, test.expectedCurrency, adapterBids["appnexus"].currency, test.description)
+		if test.expectedErrors == 0 {
+			storedBid := adapterBids["appnexus"].bids[len(adapterBids["appnexus"].bids)-1].bid
+			assert.Equal(t, "stored", storedBid.ID, test.description)
+			assert.Equal(t, test.expectedPrice, storedBid.Price, test.description)
+		}
+	}
+}
+
+var storedResponsesAdapterMap = map[openrtb_ext.BidderName]adaptedBidder{openrtb_ext.BidderAppnexus: &recordingBidder{}}
+
+func TestBidResponsesFor(t *testing.T) {
+	storedResponses := &StoredResponses{
+		BidResponses: map[string]map[openrtb_ext.BidderName]json.RawMessage{
+			"imp-1": {"appnexus": json.RawMessage(`{"id":"1"}`), "rubicon": json.RawMessage(`{"id":"2"}`)},
+			"imp-2": {"appnexus": json.RawMessage(`{"id":"3"}`)},
+		},
+	}
+
+	assert.Equal(t, map[string]json.RawMessage{"imp-1": json.RawMessage(`{"id":"1"}`), "imp-2": json.RawMessage(`{"id":"3"}`)}, storedResponses.bidResponsesFor("appnexus"))
+	assert.Nil(t, storedResponses.bidResponsesFor("openx"))
+
+	var noResponses *StoredResponses
+	assert.Nil(t, noResponses.bidResponsesFor("appnexus"))
+}
+
+func assertBidExt(t *testing.T, ext json.RawMessage, bidType openrtb_ext.BidType, bidderExt string) {
+	t.Helper()
+	var bidExt openrtb_ext.ExtBid
+	if assert.NoError(t, json.Unmarshal(ext, &bidExt)) {
+		assert.Equal(t, bidType, bidExt.Prebid.Type)
+		assert.Equal(t, bidderExt, string(bidExt.Bidder))
+	}
+}
+
+// recordingBidder remembers the requests it gets, and never bids.
+type recordingBidder struct {
+	requests []*openrtb.BidRequest
+}
+
+func (b *recordingBidder) requestBid(ctx context.Context, request *openrtb.BidRequest, name openrtb_ext.BidderName, bidAdjustment float64, conversions currencies.Conversions, reqInfo *adapters.ExtraRequestInfo, debug bool) (*pbsOrtbSeatBid, []error) {
+	b.requests = append(b.requests, request)
+	return &pbsOrtbSeatBid{}, nil
+}
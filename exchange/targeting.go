@@ -17,11 +17,14 @@ const maxKeyLength = 20
 // All functions on this struct are all nil-safe.
 // If the value is nil, then no targeting data will be tracked.
 type targetData struct {
-	priceGranularity  openrtb_ext.PriceGranularity
-	includeWinners    bool
-	includeBidderKeys bool
-	includeCacheBids  bool
-	includeCacheVast  bool
+	priceGranularity openrtb_ext.PriceGranularity
+	// dealPriceGranularity overrides priceGranularity for the hb_pb_deal key. It may be nil.
+	dealPriceGranularity *openrtb_ext.PriceGranularity
+	preferDeals          bool
+	includeWinners       bool
+	includeBidderKeys    bool
+	includeCacheBids     bool
+	includeCacheVast     bool
 	// cacheHost and cachePath exist to supply cache host and path as targeting parameters
 	cacheHost string
 	cachePath string
@@ -63,6 +66,9 @@ func (targData *targetData) setTargeting(auc *auction, isApp bool, categoryMappi
 
 			if deal := topBidPerBidder.bid.DealID; len(deal) > 0 {
 				targData.addKeys(targets, openrtb_ext.HbDealIDConstantKey, deal, bidderName, isOverallWinner)
+				if dealCpm, ok := auc.roundedDealPrices[topBidPerBidder]; ok {
+					targData.addKeys(targets, openrtb_ext.HbDealPriceKey, dealCpm, bidderName, isOverallWinner)
+				}
 			}
 
 			if isApp {
@@ -66,6 +66,34 @@ func TestTargetingCache(t *testing.T) {
 
 }
 
+func TestTargetingDeals(t *testing.T) {
+	dealBids := map[openrtb_ext.BidderName][]*openrtb.Bid{
+		openrtb_ext.BidderAppnexus: {{
+			ID:     "deal-bid",
+			ImpID:  "some-imp",
+			Price:  0.5,
+			CrID:   "1",
+			DealID: "some-deal",
+		}},
+		openrtb_ext.BidderRubicon: {{
+			ID:    "open-market-bid",
+			ImpID: "some-imp",
+			Price: 0.9,
+			CrID:  "2",
+		}},
+	}
+	targetingExt := `{"prebid":{"targeting":{"preferdeals":true,"dealpricegranularity":"low"}}}`
+	bids := runTargetingAuctionWithExt(t, dealBids, json.RawMessage(targetingExt), false)
+
+	dealTargets := parseTargets(t, bids["deal-bid"])
+	assert.Equal(t, "some-deal", dealTargets[string(openrtb_ext.HbDealIDConstantKey)])
+	assert.Equal(t, "0.50", dealTargets[string(openrtb_ext.HbDealPriceKey)])
+	assert.Equal(t, "0.50", dealTargets[openrtb_ext.HbDealPriceKey.BidderKey(openrtb_ext.BidderAppnexus, maxKeyLength)])
+
+	assertKeyExists(t, bids["open-market-bid"], string(openrtb_ext.HbpbConstantKey), false)
+	assertKeyExists(t, bids["open-market-bid"], openrtb_ext.HbDealPriceKey.BidderKey(openrtb_ext.BidderRubicon, maxKeyLength), false)
+}
+
 func assertKeyExists(t *testing.T, bid *openrtb.Bid, key string, expected bool) {
 	t.Helper()
 	targets := parseTargets(t, bid)
@@ -77,6 +105,11 @@ func assertKeyExists(t *testing.T, bid *openrtb.Bid, key string, expected bool)
 // runAuction takes a bunch of mock bids by Bidder and runs an auction. It returns a map of Bids indexed by their ImpID.
 // If includeCache is true, the auction will be run with cacheing as well, so the cache targeting keys should exist.
 func runTargetingAuction(t *testing.T, mockBids map[openrtb_ext.BidderName][]*openrtb.Bid, includeCache bool, includeWinners bool, includeBidderKeys bool, isApp bool) map[string]*openrtb.Bid {
+	return runTargetingAuctionWithExt(t, mockBids, buildTargetingExt(includeCache, includeWinners, includeBidderKeys), isApp)
+}
+
+// runTargetingAuctionWithExt works like runTargetingAuction, but takes the request.ext verbatim.
+func runTargetingAuctionWithExt(t *testing.T, mockBids map[openrtb_ext.BidderName][]*openrtb.Bid, requestExt json.RawMessage, isApp bool) map[string]*openrtb.Bid {
 	server := httptest.NewServer(http.HandlerFunc(mockServer))
 	defer server.Close()
 
@@ -94,7 +127,7 @@ func runTargetingAuction(t *testing.T, mockBids map[openrtb_ext.BidderName][]*op
 
 	req := &openrtb.BidRequest{
 		Imp: imps,
-		Ext: buildTargetingExt(includeCache, includeWinners, includeBidderKeys),
+		Ext: requestExt,
 	}
 	if isApp {
 		req.App = &openrtb.App{}
@@ -106,7 +139,7 @@ func runTargetingAuction(t *testing.T, mockBids map[openrtb_ext.BidderName][]*op
 	if error != nil {
 		t.Errorf("Failed to create a category Fetcher: %v", error)
 	}
-	bidResp, err := ex.HoldAuction(context.Background(), req, &mockFetcher{}, pbsmetrics.Labels{}, &categoriesFetcher)
+	bidResp, err := ex.HoldAuction(context.Background(), req, &mockFetcher{}, pbsmetrics.Labels{}, &categoriesFetcher, nil)
 
 	if err != nil {
 		t.Fatalf("Unexpected errors running auction: %v", err)
@@ -132,7 +165,7 @@ func buildAdapterMap(bids map[openrtb_ext.BidderName][]*openrtb.Bid, mockServerU
 		adapterMap[bidder] = adaptBidder(&mockTargetingBidder{
 			mockServerURL: mockServerURL,
 			bids:          bids,
-		}, client)
+		}, client, bidderAdapterConfig{}, &metricsConf.DummyMetricsEngine{}, bidder)
 	}
 	return adapterMap
 }
@@ -7,10 +7,12 @@ import (
 	"math/rand"
 
 	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/gdpr"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
 	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/activity"
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy/ccpa"
 	"github.com/buger/jsonparser"
 )
@@ -20,6 +22,9 @@ import (
 //   1. BidRequest.Imp[].Ext will only contain the "prebid" field and a "bidder" field which has the params for the intended Bidder.
 //   2. Every BidRequest.Imp[] requested Bids from the Bidder who keys it.
 //   3. BidRequest.User.BuyerUID will be set to that Bidder's ID.
+//   4. BidRequest.Site, App and User only carry the first party data which the Bidder is allowed to have.
+//   5. BidRequest.User.Ext.Eids only holds the IDs which the Bidder is allowed to have.
+//   6. Bidders which the activity controls deny fetchBids get no request, and the ones denied transmitUfpd or transmitPreciseGeo get a scrubbed request.
 func cleanOpenRTBRequests(ctx context.Context,
 	orig *openrtb.BidRequest,
 	usersyncs IdFetcher,
@@ -27,7 +32,10 @@ func cleanOpenRTBRequests(ctx context.Context,
 	labels pbsmetrics.Labels,
 	gDPR gdpr.Permissions,
 	usersyncIfAmbiguous,
-	enforceCCPA bool) (requestsByBidder map[openrtb_ext.BidderName]*openrtb.BidRequest, aliases map[string]string, errs []error) {
+	enforceCCPA bool,
+	eeaCountries map[string]struct{},
+	hostAliases map[string]config.BidderAlias,
+	activityControls *activity.Controls) (requestsByBidder map[openrtb_ext.BidderName]*openrtb.BidRequest, aliases map[string]string, errs []error) {
 
 	impsByBidder, errs := splitImps(orig.Imp)
 	if len(errs) > 0 {
@@ -38,10 +46,16 @@ func cleanOpenRTBRequests(ctx context.Context,
 	if len(errs) > 0 {
 		return
 	}
+	aliases = mergeHostAliases(aliases, hostAliases)
 
-	requestsByBidder, errs = splitBidRequest(orig, impsByBidder, aliases, usersyncs, blables, labels)
+	aliasGVLIDs, errs := parseAliasGVLIDs(orig)
+	if len(errs) > 0 {
+		return
+	}
 
-	gdpr := extractGDPR(orig, usersyncIfAmbiguous)
+	requestsByBidder, errs = splitBidRequest(orig, impsByBidder, aliases, hostAliases, usersyncs, blables, labels)
+
+	gdpr := extractGDPR(orig, usersyncIfAmbiguous, eeaCountries)
 	consent := extractConsent(orig)
 	isAMP := labels.RType == pbsmetrics.ReqTypeAMP
 
@@ -51,16 +65,27 @@ func cleanOpenRTBRequests(ctx context.Context,
 
 	if enforceCCPA {
 		ccpaPolicy, _ := ccpa.ReadPolicy(orig)
-		privacyEnforcement.CCPA = ccpaPolicy.ShouldEnforce()
+		gppSignals := extractGPPSignals(orig)
+		if ccpaPolicy.Value == "" {
+			ccpaPolicy.Value = gppSignals.USPrivacy
+		}
+		privacyEnforcement.CCPA = ccpaPolicy.ShouldEnforce() || gppSignals.USOptOut
 	}
 
+	activitySignals := activity.ReadSignals(orig)
+
 	for bidder, bidReq := range requestsByBidder {
+		component := activity.Component{Type: activity.ComponentBidder, Name: bidder.String()}
+		if !activityControls.Allow(activity.FetchBids, component, activitySignals) {
+			delete(requestsByBidder, bidder)
+			continue
+		}
+		privacyEnforcement.UFPD = !activityControls.Allow(activity.TransmitUFPD, component, activitySignals)
+		privacyEnforcement.PreciseGeo = !activityControls.Allow(activity.TransmitPreciseGeo, component, activitySignals)
 
 		if gdpr == 1 {
-			coreBidder := resolveBidder(bidder.String(), aliases)
-
 			var publisherID = labels.PubID
-			ok, err := gDPR.PersonalInfoAllowed(ctx, coreBidder, publisherID, consent)
+			ok, err := personalInfoAllowed(ctx, gDPR, bidder.String(), aliases, aliasGVLIDs, hostAliases, publisherID, consent)
 			privacyEnforcement.GDPR = !ok && err == nil
 		} else {
 			privacyEnforcement.GDPR = false
@@ -72,34 +97,55 @@ func cleanOpenRTBRequests(ctx context.Context,
 	return
 }
 
-func splitBidRequest(req *openrtb.BidRequest, impsByBidder map[string][]openrtb.Imp, aliases map[string]string, usersyncs IdFetcher, blabels map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels, labels pbsmetrics.Labels) (map[openrtb_ext.BidderName]*openrtb.BidRequest, []error) {
+func splitBidRequest(req *openrtb.BidRequest, impsByBidder map[string][]openrtb.Imp, aliases map[string]string, hostAliases map[string]config.BidderAlias, usersyncs IdFetcher, blabels map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels, labels pbsmetrics.Labels) (map[openrtb_ext.BidderName]*openrtb.BidRequest, []error) {
 	requestsByBidder := make(map[openrtb_ext.BidderName]*openrtb.BidRequest, len(impsByBidder))
 	explicitBuyerUIDs, err := extractBuyerUIDs(req.User)
 	if err != nil {
 		return nil, []error{err}
 	}
+	fpd, requestExt, err := extractFirstPartyData(req.Ext)
+	if err != nil {
+		return nil, []error{err}
+	}
+	eids, err := extractUserEids(req.User, fpd.getEidPermissions())
+	if err != nil {
+		return nil, []error{err}
+	}
+	var errs []error
 	for bidder, imps := range impsByBidder {
 		reqCopy := *req
 		coreBidder := resolveBidder(bidder, aliases)
+		metricsName := metricsBidder(bidder, aliases, hostAliases)
 		newLabel := pbsmetrics.AdapterLabels{
 			Source:      labels.Source,
 			RType:       labels.RType,
-			Adapter:     coreBidder,
+			Adapter:     metricsName,
 			PubID:       labels.PubID,
 			Browser:     labels.Browser,
 			CookieFlag:  labels.CookieFlag,
 			AdapterBids: pbsmetrics.AdapterBidPresent,
 		}
-		blabels[coreBidder] = &newLabel
-		if hadSync := prepareUser(&reqCopy, bidder, coreBidder, explicitBuyerUIDs, usersyncs); !hadSync && req.App == nil {
-			blabels[coreBidder].CookieFlag = pbsmetrics.CookieFlagNo
+		blabels[metricsName] = &newLabel
+		if hadSync := prepareUser(&reqCopy, bidder, syncerBidder(bidder, coreBidder, aliases, hostAliases), explicitBuyerUIDs, usersyncs); !hadSync && req.App == nil {
+			blabels[metricsName].CookieFlag = pbsmetrics.CookieFlagNo
 		} else {
-			blabels[coreBidder].CookieFlag = pbsmetrics.CookieFlagYes
+			blabels[metricsName].CookieFlag = pbsmetrics.CookieFlagYes
+		}
+		reqCopy.Ext = requestExt
+		// The eids are filtered after the first party data, since a bidder config may add eids to the user.
+		userBeforeFPD := reqCopy.User
+		if err := fpd.apply(&reqCopy, bidder); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := eids.apply(&reqCopy, userBeforeFPD, bidder, coreBidder.String()); err != nil {
+			errs = append(errs, err)
+			continue
 		}
 		reqCopy.Imp = imps
 		requestsByBidder[openrtb_ext.BidderName(bidder)] = &reqCopy
 	}
-	return requestsByBidder, nil
+	return requestsByBidder, errs
 }
 
 // extractBuyerUIDs parses the values from user.ext.prebid.buyeruids, and then deletes those values from the ext.
@@ -194,6 +240,8 @@ func sanitizedImpCopy(imp *openrtb.Imp,
 	// in the sanitized imp
 	if err := json.Unmarshal(rawPrebidExt, &prebidExt); err == nil {
 		delete(prebidExt, "bidder")
+		delete(prebidExt, "storedauctionresponse")
+		delete(prebidExt, "storedbidresponse")
 
 		var err error
 		if rawPrebidExt, err = json.Marshal(prebidExt); err != nil {
@@ -237,10 +285,11 @@ func sanitizedImpCopy(imp *openrtb.Imp,
 // prepareUser changes req.User so that it's ready for the given bidder.
 // This *will* mutate the request, but will *not* mutate any objects nested inside it.
 //
-// In this function, "givenBidder" may or may not be an alias. "coreBidder" must *not* be an alias.
+// In this function, "givenBidder" may or may not be an alias. "syncBidder" is the bidder whose cookie family
+// holds the ID: the core bidder, or a host alias which has a syncer of its own.
 // It returns true if a Cookie User Sync existed, and false otherwise.
-func prepareUser(req *openrtb.BidRequest, givenBidder string, coreBidder openrtb_ext.BidderName, explicitBuyerUIDs map[string]string, usersyncs IdFetcher) bool {
-	cookieId, hadCookie := usersyncs.GetId(coreBidder)
+func prepareUser(req *openrtb.BidRequest, givenBidder string, syncBidder openrtb_ext.BidderName, explicitBuyerUIDs map[string]string, usersyncs IdFetcher) bool {
+	cookieId, hadCookie := usersyncs.GetId(syncBidder)
 
 	if id, ok := explicitBuyerUIDs[givenBidder]; ok {
 		req.User = copyWithBuyerUID(req.User, id)
@@ -6,8 +6,13 @@ import (
 	"testing"
 
 	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/currencies"
+	"github.com/PubMatic-OpenWrap/prebid-server/gdpr"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
 	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/activity"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,7 +71,7 @@ func TestCleanOpenRTBRequests(t *testing.T) {
 	}
 
 	for _, test := range testCases {
-		reqByBidders, _, err := cleanOpenRTBRequests(context.Background(), test.req, &emptyUsersync{}, map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels{}, pbsmetrics.Labels{}, &permissionsMock{}, true, true)
+		reqByBidders, _, err := cleanOpenRTBRequests(context.Background(), test.req, &emptyUsersync{}, map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels{}, pbsmetrics.Labels{}, &permissionsMock{}, true, true, nil, nil, nil)
 		if test.hasError {
 			assert.NotNil(t, err, "Error shouldn't be nil")
 		} else {
@@ -97,7 +102,7 @@ func TestCleanOpenRTBRequestsCCPA(t *testing.T) {
 	for _, test := range testCases {
 		req := newCCPABidRequest(t)
 
-		results, _, errs := cleanOpenRTBRequests(context.Background(), req, &emptyUsersync{}, map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels{}, pbsmetrics.Labels{}, &permissionsMock{}, true, test.enforceCCPA)
+		results, _, errs := cleanOpenRTBRequests(context.Background(), req, &emptyUsersync{}, map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels{}, pbsmetrics.Labels{}, &permissionsMock{}, true, test.enforceCCPA, nil, nil, nil)
 		result := results["appnexus"]
 
 		assert.Nil(t, errs)
@@ -112,6 +117,156 @@ func TestCleanOpenRTBRequestsCCPA(t *testing.T) {
 	}
 }
 
+func TestCleanOpenRTBRequestsGPP(t *testing.T) {
+	testCases := []struct {
+		description     string
+		regsExt         string
+		expectDataScrub bool
+	}{
+		{
+			description:     "US National Opt Out",
+			regsExt:         `{"gpp":"DBABLA~BAAa","gpp_sid":[7]}`,
+			expectDataScrub: true,
+		},
+		{
+			description:     "US Privacy Section Opt Out",
+			regsExt:         `{"gpp":"DBACNYA~CAAAAAAAAAAAAAAAAAAAAoAAAMAAAAAAAAAAABmA~1YYN","gpp_sid":[6]}`,
+			expectDataScrub: true,
+		},
+		{
+			description:     "US National Section Doesn't Apply",
+			regsExt:         `{"gpp":"DBABLA~BAAa","gpp_sid":[6]}`,
+			expectDataScrub: false,
+		},
+		{
+			description:     "Invalid GPP String",
+			regsExt:         `{"gpp":"malformed","gpp_sid":[7]}`,
+			expectDataScrub: false,
+		},
+	}
+
+	for _, test := range testCases {
+		req := newCCPABidRequest(t)
+		req.Regs.Ext = json.RawMessage(test.regsExt)
+
+		results, _, errs := cleanOpenRTBRequests(context.Background(), req, &emptyUsersync{}, map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels{}, pbsmetrics.Labels{}, &permissionsMock{}, true, true, nil, nil, nil)
+		result := results["appnexus"]
+
+		assert.Nil(t, errs)
+
+		if test.expectDataScrub {
+			assert.Equal(t, result.User.BuyerUID, "", test.description+":User.BuyerUID")
+			assert.Equal(t, result.Device.DIDMD5, "", test.description+":Device.DIDMD5")
+		} else {
+			assert.NotEqual(t, result.User.BuyerUID, "", test.description+":User.BuyerUID")
+			assert.NotEqual(t, result.Device.DIDMD5, "", test.description+":Device.DIDMD5")
+		}
+	}
+}
+
+func TestCleanOpenRTBRequestsActivities(t *testing.T) {
+	deny := false
+	testCases := []struct {
+		description     string
+		activities      config.Activities
+		expectBidders   []openrtb_ext.BidderName
+		expectUFPDScrub bool
+		expectGeoScrub  bool
+	}{
+		{
+			description:   "No Activity Controls",
+			expectBidders: []openrtb_ext.BidderName{"appnexus", "rubicon"},
+		},
+		{
+			description: "Fetch Bids Denied",
+			activities: config.Activities{FetchBids: config.Activity{Rules: []config.ActivityRule{
+				{Allow: false, Condition: config.ActivityCondition{ComponentName: []string{"rubicon"}}},
+			}}},
+			expectBidders: []openrtb_ext.BidderName{"appnexus"},
+		},
+		{
+			description:     "Transmit UFPD Denied",
+			activities:      config.Activities{TransmitUFPD: config.Activity{Default: &deny}},
+			expectBidders:   []openrtb_ext.BidderName{"appnexus", "rubicon"},
+			expectUFPDScrub: true,
+		},
+		{
+			description:    "Transmit Precise Geo Denied",
+			activities:     config.Activities{TransmitPreciseGeo: config.Activity{Default: &deny}},
+			expectBidders:  []openrtb_ext.BidderName{"appnexus", "rubicon"},
+			expectGeoScrub: true,
+		},
+	}
+
+	for _, test := range testCases {
+		req := newCCPABidRequest(t)
+		req.Regs = nil
+		req.Device.Geo = &openrtb.Geo{Lat: 123.456, Lon: 678.89}
+		req.Imp[0].Ext = json.RawMessage(`{"appnexus": {"placementId": 1}, "rubicon": {}}`)
+		controls := activity.NewControls(&config.ActivityControls{Activities: test.activities}, "")
+
+		results, _, errs := cleanOpenRTBRequests(context.Background(), req, &emptyUsersync{}, map[openrtb_ext.BidderName]*pbsmetrics.AdapterLabels{}, pbsmetrics.Labels{}, &permissionsMock{}, true, true, nil, nil, controls)
+
+		assert.Nil(t, errs, test.description)
+		assert.ElementsMatch(t, test.expectBidders, listBiddersWithRequests(results), test.description)
+		for _, result := range results {
+			if test.expectUFPDScrub {
+				assert.Empty(t, result.User.ID, test.description+":User.ID")
+				assert.Empty(t, result.Device.IFA, test.description+":Device.IFA")
+			} else {
+				assert.Equal(t, "our-id", result.User.ID, test.description+":User.ID")
+				assert.Equal(t, "ifa", result.Device.IFA, test.description+":Device.IFA")
+			}
+			if test.expectGeoScrub {
+				assert.Equal(t, 123.46, result.Device.Geo.Lat, test.description+":Device.Geo.Lat")
+				assert.Equal(t, "132.173.230.0", result.Device.IP, test.description+":Device.IP")
+			} else {
+				assert.Equal(t, 123.456, result.Device.Geo.Lat, test.description+":Device.Geo.Lat")
+			}
+		}
+	}
+}
+
+func TestDebugActivities(t *testing.T) {
+	e := &exchange{
+		adapterMap: map[openrtb_ext.BidderName]adaptedBidder{
+			openrtb_ext.BidderAppnexus: &recordingBidder{},
+			openrtb_ext.BidderRubicon:  &recordingBidder{},
+		},
+		me:                &metricsConf.DummyMetricsEngine{},
+		cache:             &mockCache{},
+		gDPR:              gdpr.AlwaysAllow{},
+		currencyConverter: currencies.NewRateConverterDefault(),
+		activityControls: config.ActivityControls{
+			Accounts: map[string]config.Activities{
+				"1001": {FetchBids: config.Activity{Rules: []config.ActivityRule{
+					{Allow: false, Condition: config.ActivityCondition{ComponentName: []string{"rubicon"}}},
+				}}},
+			},
+		},
+	}
+	req := &openrtb.BidRequest{
+		ID:   "request",
+		Site: &openrtb.Site{Page: "http://www.example.com"},
+		Imp: []openrtb.Imp{{
+			ID:     "imp",
+			Banner: &openrtb.Banner{Format: []openrtb.Format{{W: 300, H: 250}}},
+			Ext:    json.RawMessage(`{"appnexus":{"placementId":1},"rubicon":{}}`),
+		}},
+		Ext: json.RawMessage(`{"prebid":{"debug":1}}`),
+	}
+
+	response, err := e.HoldAuction(context.Background(), req, &emptyUsersync{}, pbsmetrics.Labels{PubID: "1001"}, nil, nil)
+
+	assert.NoError(t, err)
+	var responseExt openrtb_ext.ExtBidResponse
+	assert.NoError(t, json.Unmarshal(response.Ext, &responseExt))
+	rule := 0
+	assert.Contains(t, responseExt.Debug.Activities, openrtb_ext.ExtActivityTrace{Activity: "fetchBids", Component: "bidder.rubicon", Allowed: false, Source: "account", Rule: &rule})
+	assert.Contains(t, responseExt.Debug.Activities, openrtb_ext.ExtActivityTrace{Activity: "fetchBids", Component: "bidder.appnexus", Allowed: true, Source: "account"})
+	assert.NotContains(t, responseExt.Debug.HttpCalls, openrtb_ext.BidderRubicon)
+}
+
 // newAdapterAliasBidRequest builds a BidRequest with aliases
 func newAdapterAliasBidRequest(t *testing.T) *openrtb.BidRequest {
 	dnt := int8(1)
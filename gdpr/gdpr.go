@@ -6,6 +6,7 @@ import (
 
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/prebid/go-gdpr/vendorlist"
 )
 
 type Permissions interface {
@@ -25,6 +26,15 @@ type Permissions interface {
 	PersonalInfoAllowed(ctx context.Context, bidder openrtb_ext.BidderName, PublisherID string, consent string) (bool, error)
 }
 
+// VendorPermissions can be implemented by Permissions which check a Global Vendor List ID directly.
+// It's used for the bidder aliases which have a vendor ID of their own.
+type VendorPermissions interface {
+	// Determines whether or not to send PI information to the vendor, or mask it out.
+	//
+	// If the consent string was nonsenical, the returned error will be an ErrorMalformedConsent.
+	VendorPersonalInfoAllowed(ctx context.Context, vendorID uint16, PublisherID string, consent string) (bool, error)
+}
+
 // NewPermissions gets an instance of the Permissions for use elsewhere in the project.
 func NewPermissions(ctx context.Context, cfg config.GDPR, vendorIDs map[openrtb_ext.BidderName]uint16, client *http.Client) Permissions {
 	// If the host doesn't buy into the IAB GDPR consent framework, then save some cycles and let all syncs happen.
@@ -33,9 +43,10 @@ func NewPermissions(ctx context.Context, cfg config.GDPR, vendorIDs map[openrtb_
 	}
 
 	return &permissionsImpl{
-		cfg:             cfg,
-		vendorIDs:       vendorIDs,
-		fetchVendorList: newVendorListFetcher(ctx, cfg, client, vendorListURLMaker),
+		cfg:               cfg,
+		vendorIDs:         vendorIDs,
+		fetchVendorList:   newVendorListFetcher(ctx, cfg, client, vendorListURLMaker, vendorlist.ParseEagerly),
+		fetchVendorListV2: newVendorListFetcher(ctx, cfg, client, vendorListV2URLMaker, parseVendorListV2),
 	}
 }
 
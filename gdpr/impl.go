@@ -8,6 +8,7 @@ import (
 	"github.com/prebid/go-gdpr/vendorlist"
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/gpp"
 )
 
 // This file implements GDPR permissions for the app.
@@ -19,6 +20,8 @@ type permissionsImpl struct {
 	cfg             config.GDPR
 	vendorIDs       map[openrtb_ext.BidderName]uint16
 	fetchVendorList func(ctx context.Context, id uint16) (vendorlist.VendorList, error)
+	// fetchVendorListV2 fetches the v2 Global Vendor List, which TCF v2 consent strings refer to.
+	fetchVendorListV2 func(ctx context.Context, id uint16) (vendorlist.VendorList, error)
 }
 
 func (p *permissionsImpl) HostCookiesAllowed(ctx context.Context, consent string) (bool, error) {
@@ -56,12 +59,23 @@ func (p *permissionsImpl) PersonalInfoAllowed(ctx context.Context, bidder openrt
 	return false, nil
 }
 
+func (p *permissionsImpl) VendorPersonalInfoAllowed(ctx context.Context, vendorID uint16, PublisherID string, consent string) (bool, error) {
+	if _, ok := p.cfg.NonStandardPublisherMap[PublisherID]; ok {
+		return true, nil
+	}
+	return p.allowPI(ctx, vendorID, consent)
+}
+
 func (p *permissionsImpl) allowSync(ctx context.Context, vendorID uint16, consent string) (bool, error) {
 	// If we're not given a consent string, respect the preferences in the app config.
 	if consent == "" {
 		return p.cfg.UsersyncIfAmbiguous, nil
 	}
 
+	if gpp.IsTCFv2(consent) {
+		return p.allowTCFv2(ctx, vendorID, consent, tcf2StorageAccess)
+	}
+
 	parsedConsent, vendor, err := p.parseVendor(ctx, vendorID, consent)
 	if err != nil {
 		return false, err
@@ -84,6 +98,10 @@ func (p *permissionsImpl) allowPI(ctx context.Context, vendorID uint16, consent
 		return p.cfg.UsersyncIfAmbiguous, nil
 	}
 
+	if gpp.IsTCFv2(consent) {
+		return p.allowTCFv2(ctx, vendorID, consent, tcf2StorageAccess, tcf2BasicAds)
+	}
+
 	parsedConsent, vendor, err := p.parseVendor(ctx, vendorID, consent)
 	if err != nil {
 		return false, err
@@ -119,6 +137,44 @@ func (p *permissionsImpl) parseVendor(ctx context.Context, vendorID uint16, cons
 	return
 }
 
+// The TCF v2 purposes which syncs and personal info need. They're numbered differently to the TCF v1 ones in consentconstants.
+const (
+	tcf2StorageAccess uint8 = 1
+	tcf2BasicAds      uint8 = 2
+)
+
+// allowTCFv2 checks a TCF v2 consent string, such as the TCF EU v2 section of a GPP string, against the v2
+// Global Vendor List. Each purpose needs the user's consent to it and to the vendor, if the vendor declares
+// it as a consent purpose, or the user not objecting to the vendor's legitimate interest in it, if the vendor
+// declares that instead. Storage access can only be based on consent.
+func (p *permissionsImpl) allowTCFv2(ctx context.Context, vendorID uint16, consent string, purposes ...uint8) (bool, error) {
+	parsedConsent, err := gpp.ParseTCFv2(consent)
+	if err != nil {
+		return false, &ErrorMalformedConsent{
+			consent: consent,
+			cause:   err,
+		}
+	}
+
+	vendorList, err := p.fetchVendorListV2(ctx, parsedConsent.VendorListVersion)
+	if err != nil {
+		return false, err
+	}
+	vendor := vendorList.Vendor(vendorID)
+	if vendor == nil {
+		return false, nil
+	}
+
+	for _, purpose := range purposes {
+		consented := vendor.Purpose(consentconstants.Purpose(purpose)) && parsedConsent.PurposeConsent(purpose) && parsedConsent.VendorConsent(vendorID)
+		legitimateInterest := purpose != tcf2StorageAccess && vendor.LegitimateInterest(consentconstants.Purpose(purpose)) && parsedConsent.PurposeLITransparency(purpose) && parsedConsent.VendorLegitimateInterest(vendorID)
+		if !consented && !legitimateInterest {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // Exporting to allow for easy test setups
 type AlwaysAllow struct{}
 
@@ -133,3 +189,7 @@ func (a AlwaysAllow) BidderSyncAllowed(ctx context.Context, bidder openrtb_ext.B
 func (a AlwaysAllow) PersonalInfoAllowed(ctx context.Context, bidder openrtb_ext.BidderName, PublisherID string, consent string) (bool, error) {
 	return true, nil
 }
+
+func (a AlwaysAllow) VendorPersonalInfoAllowed(ctx context.Context, vendorID uint16, PublisherID string, consent string) (bool, error) {
+	return true, nil
+}
@@ -190,6 +190,99 @@ func TestAllowPersonalInfo(t *testing.T) {
 	assertBoolsEqual(t, true, allowPI)
 }
 
+func TestTCFv2Consent(t *testing.T) {
+	perms := permissionsImpl{
+		cfg: config.GDPR{
+			HostVendorID: 2,
+		},
+		vendorIDs: map[openrtb_ext.BidderName]uint16{
+			openrtb_ext.BidderAppnexus: 1,
+			openrtb_ext.BidderPubmatic: 3,
+			openrtb_ext.BidderRubicon:  4,
+			openrtb_ext.BidderOpenx:    5,
+		},
+		fetchVendorList: failedListFetcher,
+		fetchVendorListV2: listFetcher(map[uint16]vendorlist.VendorList{
+			2: parseVendorListV2Data(t, testVendorListV2),
+		}),
+	}
+
+	// Purposes 1 and 2 and vendors 2, 3 and 4 have consent. Vendor 3's legitimate interest in purpose 2 is allowed.
+	consent := "CAAAAAAAAAAAAAAAAAAAACAAAMAAAEAAAAAAACHAAMQ"
+	allowSync, err := perms.HostCookiesAllowed(context.Background(), consent)
+	assertNilErr(t, err)
+	assertBoolsEqual(t, true, allowSync)
+
+	allowSync, err = perms.BidderSyncAllowed(context.Background(), openrtb_ext.BidderAppnexus, consent)
+	assertNilErr(t, err)
+	assertBoolsEqual(t, false, allowSync)
+
+	allowPI, err := perms.PersonalInfoAllowed(context.Background(), openrtb_ext.BidderPubmatic, "", consent)
+	assertNilErr(t, err)
+	assertBoolsEqual(t, true, allowPI)
+
+	// Vendor 4 isn't in the vendor list, so its consent doesn't count.
+	allowSync, err = perms.BidderSyncAllowed(context.Background(), openrtb_ext.BidderRubicon, consent)
+	assertNilErr(t, err)
+	assertBoolsEqual(t, false, allowSync)
+
+	// Purposes 1 and 2 and vendor 3 have consent, but the user objected to the legitimate interest in purpose 2.
+	consent = "CAAAAAAAAAAAAAAAAAAAACAAAMAAAAAAAAAAABiAAYg"
+	allowSync, err = perms.BidderSyncAllowed(context.Background(), openrtb_ext.BidderPubmatic, consent)
+	assertNilErr(t, err)
+	assertBoolsEqual(t, true, allowSync)
+
+	allowPI, err = perms.PersonalInfoAllowed(context.Background(), openrtb_ext.BidderPubmatic, "", consent)
+	assertNilErr(t, err)
+	assertBoolsEqual(t, false, allowPI)
+
+	// Vendor 5's legitimate interest in purposes 1 and 2 is allowed, but storage access needs consent.
+	consent = "CAAAAAAAAAAAAAAAAAAAACAAAAAAAMAAAAAAAAAAFBA"
+	allowSync, err = perms.BidderSyncAllowed(context.Background(), openrtb_ext.BidderOpenx, consent)
+	assertNilErr(t, err)
+	assertBoolsEqual(t, false, allowSync)
+
+	allowPI, err = perms.PersonalInfoAllowed(context.Background(), openrtb_ext.BidderOpenx, "", consent)
+	assertNilErr(t, err)
+	assertBoolsEqual(t, false, allowPI)
+
+	// Vendor list version 9 can't be fetched.
+	allowSync, err = perms.HostCookiesAllowed(context.Background(), "CAAAAAAAAAAAAAAAAAAAAJAAAMAAAAAAAAAAABuAAAA")
+	assertErr(t, err, false)
+	assertBoolsEqual(t, false, allowSync)
+
+	allowSync, err = perms.HostCookiesAllowed(context.Background(), "CAAAAAAA")
+	assertErr(t, err, true)
+	assertBoolsEqual(t, false, allowSync)
+}
+
+func TestVendorPersonalInfoAllowed(t *testing.T) {
+	vendorListData := mockVendorListData(t, 1, map[uint16]*purposes{
+		2: {
+			purposes: []uint8{1}, // cookie reads/writes
+		},
+		3: {
+			purposes: []uint8{1, 3}, // ad personalization
+		},
+	})
+	perms := permissionsImpl{
+		cfg: config.GDPR{
+			HostVendorID: 2,
+		},
+		fetchVendorList: listFetcher(map[uint16]vendorlist.VendorList{
+			1: parseVendorListData(t, vendorListData),
+		}),
+	}
+
+	allowPI, err := perms.VendorPersonalInfoAllowed(context.Background(), 2, "", "BOS2bx5OS2bx5ABABBAAABoAAAABBwAA")
+	assertNilErr(t, err)
+	assertBoolsEqual(t, false, allowPI)
+
+	allowPI, err = perms.VendorPersonalInfoAllowed(context.Background(), 3, "", "BOS2bx5OS2bx5ABABBAAABoAAAABBwAA")
+	assertNilErr(t, err)
+	assertBoolsEqual(t, true, allowPI)
+}
+
 func parseVendorListData(t *testing.T, data string) vendorlist.VendorList {
 	t.Helper()
 	parsed, err := vendorlist.ParseEagerly([]byte(data))
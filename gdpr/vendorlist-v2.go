@@ -0,0 +1,98 @@
+package gdpr
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/prebid/go-gdpr/consentconstants"
+	"github.com/prebid/go-gdpr/vendorlist"
+)
+
+// This file parses version 2 of the Global Vendor List, which TCF v2 consent strings refer to.
+// Its purposes are numbered differently to the v1 ones, so callers must use the TCF v2 numbers.
+//
+// See https://github.com/InteractiveAdvertisingBureau/GDPR-Transparency-and-Consent-Framework/blob/master/TCFv2/IAB%20Tech%20Lab%20-%20Consent%20string%20and%20vendor%20list%20formats%20v2.md#the-global-vendor-list
+
+// Make a URL which can be used to fetch a given version of the v2 Global Vendor List. If the version is 0,
+// this will fetch the latest version.
+func vendorListV2URLMaker(version uint16) string {
+	if version == 0 {
+		return "https://vendor-list.consensu.org/v2/vendor-list.json"
+	}
+	return "https://vendor-list.consensu.org/v2/archives/vendor-list-v" + strconv.Itoa(int(version)) + ".json"
+}
+
+type vendorListV2Contract struct {
+	Version uint16                                `json:"vendorListVersion"`
+	Vendors map[string]vendorListV2VendorContract `json:"vendors"`
+}
+
+type vendorListV2VendorContract struct {
+	ID                    uint16  `json:"id"`
+	PurposeIDs            []uint8 `json:"purposes"`
+	LegitimateInterestIDs []uint8 `json:"legIntPurposes"`
+}
+
+// parseVendorListV2 reads a v2 Global Vendor List into the vendorlist types, so that it can share the v1 cache.
+func parseVendorListV2(data []byte) (vendorlist.VendorList, error) {
+	var contract vendorListV2Contract
+	if err := json.Unmarshal(data, &contract); err != nil {
+		return nil, err
+	}
+	if contract.Version == 0 {
+		return nil, errors.New("the vendor list has no vendorListVersion")
+	}
+
+	list := vendorListV2{
+		version: contract.Version,
+		vendors: make(map[uint16]vendorV2, len(contract.Vendors)),
+	}
+	for _, vendor := range contract.Vendors {
+		list.vendors[vendor.ID] = vendorV2{
+			purposes:            purposeSet(vendor.PurposeIDs),
+			legitimateInterests: purposeSet(vendor.LegitimateInterestIDs),
+		}
+	}
+	return list, nil
+}
+
+func purposeSet(ids []uint8) map[consentconstants.Purpose]struct{} {
+	set := make(map[consentconstants.Purpose]struct{}, len(ids))
+	for _, id := range ids {
+		set[consentconstants.Purpose(id)] = struct{}{}
+	}
+	return set
+}
+
+type vendorListV2 struct {
+	version uint16
+	vendors map[uint16]vendorV2
+}
+
+func (l vendorListV2) Version() uint16 {
+	return l.version
+}
+
+func (l vendorListV2) Vendor(vendorID uint16) vendorlist.Vendor {
+	vendor, ok := l.vendors[vendorID]
+	if !ok {
+		return nil
+	}
+	return vendor
+}
+
+type vendorV2 struct {
+	purposes            map[consentconstants.Purpose]struct{}
+	legitimateInterests map[consentconstants.Purpose]struct{}
+}
+
+func (v vendorV2) Purpose(purposeID consentconstants.Purpose) bool {
+	_, ok := v.purposes[purposeID]
+	return ok
+}
+
+func (v vendorV2) LegitimateInterest(purposeID consentconstants.Purpose) bool {
+	_, ok := v.legitimateInterests[purposeID]
+	return ok
+}
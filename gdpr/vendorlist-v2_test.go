@@ -0,0 +1,86 @@
+package gdpr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prebid/go-gdpr/vendorlist"
+)
+
+// testVendorListV2 is a v2 Global Vendor List for the TCF v2 tests.
+//
+// Vendor 1 uses consent for purposes 1 and 2. Vendor 2 uses consent for purpose 1.
+// Vendor 3 uses consent for purpose 1 and legitimate interest for purpose 2.
+// Vendor 5 claims legitimate interest for purposes 1 and 2, which isn't allowed for purpose 1.
+const testVendorListV2 = `{
+  "gvlSpecificationVersion": 2,
+  "vendorListVersion": 2,
+  "tcfPolicyVersion": 2,
+  "vendors": {
+    "1": {"id": 1, "purposes": [1, 2], "legIntPurposes": [], "flexiblePurposes": []},
+    "2": {"id": 2, "purposes": [1], "legIntPurposes": []},
+    "3": {"id": 3, "purposes": [1], "legIntPurposes": [2], "flexiblePurposes": [2]},
+    "5": {"id": 5, "purposes": [], "legIntPurposes": [1, 2]}
+  }
+}`
+
+func TestParseVendorListV2(t *testing.T) {
+	list, err := parseVendorListV2([]byte(testVendorListV2))
+	assertNilErr(t, err)
+	if list.Version() != 2 {
+		t.Errorf("Expected version 2. Got %d", list.Version())
+	}
+
+	vendor := list.Vendor(3)
+	if vendor == nil {
+		t.Fatalf("Vendor 3 should be in the list")
+	}
+	assertBoolsEqual(t, true, vendor.Purpose(1))
+	assertBoolsEqual(t, false, vendor.Purpose(2))
+	assertBoolsEqual(t, false, vendor.LegitimateInterest(1))
+	assertBoolsEqual(t, true, vendor.LegitimateInterest(2))
+
+	if list.Vendor(4) != nil {
+		t.Errorf("Vendor 4 shouldn't be in the list")
+	}
+
+	_, err = parseVendorListV2([]byte(`{"vendors":{}}`))
+	assertErr(t, err, false)
+	_, err = parseVendorListV2([]byte(`{"vendorListVersion":2,"vendors":[]}`))
+	assertErr(t, err, false)
+}
+
+func TestVendorListV2Fetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(mockServer(2, map[int]string{2: testVendorListV2})))
+	defer server.Close()
+
+	fetcher := newVendorListFetcher(context.Background(), testConfig(), server.Client(), testURLMaker(server), parseVendorListV2)
+	list, err := fetcher(context.Background(), 2)
+	assertNilErr(t, err)
+	assertBoolsEqual(t, true, list.Vendor(1).Purpose(2))
+
+	// A v1 list doesn't parse as a v2 one.
+	v1Server := httptest.NewServer(http.HandlerFunc(mockServer(1, map[int]string{1: mockVendorListData(t, 1, map[uint16]*purposes{1: {purposes: []uint8{1}}})})))
+	defer v1Server.Close()
+
+	fetcher = newVendorListFetcher(context.Background(), testConfig(), v1Server.Client(), testURLMaker(v1Server), parseVendorListV2)
+	_, err = fetcher(context.Background(), 1)
+	assertErr(t, err, false)
+}
+
+func TestVendorListV2Maker(t *testing.T) {
+	assertStringsEqual(t, "https://vendor-list.consensu.org/v2/vendor-list.json", vendorListV2URLMaker(0))
+	assertStringsEqual(t, "https://vendor-list.consensu.org/v2/archives/vendor-list-v2.json", vendorListV2URLMaker(2))
+	assertStringsEqual(t, "https://vendor-list.consensu.org/v2/archives/vendor-list-v12.json", vendorListV2URLMaker(12))
+}
+
+func parseVendorListV2Data(t *testing.T, data string) vendorlist.VendorList {
+	t.Helper()
+	parsed, err := parseVendorListV2([]byte(data))
+	if err != nil {
+		t.Fatalf("Failed to parse vendor list data. %v", err)
+	}
+	return parsed
+}
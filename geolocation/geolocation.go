@@ -0,0 +1,32 @@
+package geolocation
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when the location of an IP address is unknown.
+var ErrNotFound = errors.New("the location of the IP address is unknown")
+
+// GeoInfo is the location of an IP address, in the form which an OpenRTB device.geo uses.
+type GeoInfo struct {
+	// Country is the ISO-3166-1 alpha-3 code of the country.
+	Country string
+	// Region is the ISO-3166-2 code of the country's subdivision, without the country prefix.
+	Region string
+	// Metro is the Nielsen DMA code of the metro area, if the IP address is in the US.
+	Metro string
+}
+
+// GeoLocation looks up the location of IPv4 and IPv6 addresses. Implementations must be threadsafe.
+type GeoLocation interface {
+	// Lookup returns ErrNotFound if it doesn't know where the IP address is.
+	Lookup(ctx context.Context, ip string) (*GeoInfo, error)
+}
+
+// NilGeoLocation doesn't know the location of any IP address. It's used when the host hasn't configured geo lookups.
+type NilGeoLocation struct{}
+
+func (NilGeoLocation) Lookup(ctx context.Context, ip string) (*GeoInfo, error) {
+	return nil, ErrNotFound
+}
@@ -0,0 +1,31 @@
+package geolocation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountryAlpha3(t *testing.T) {
+	testCases := []struct {
+		alpha2   string
+		expected string
+	}{
+		{alpha2: "US", expected: "USA"},
+		{alpha2: "de", expected: "DEU"},
+		{alpha2: "GB", expected: "GBR"},
+		{alpha2: "XX", expected: ""},
+		{alpha2: "", expected: ""},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, CountryAlpha3(test.alpha2), test.alpha2)
+	}
+}
+
+func TestNilGeoLocation(t *testing.T) {
+	info, err := NilGeoLocation{}.Lookup(context.Background(), "1.2.3.4")
+	assert.Nil(t, info)
+	assert.Equal(t, ErrNotFound, err)
+}
@@ -0,0 +1,83 @@
+package maxmind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strconv"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/geolocation"
+)
+
+// GeoLocation looks up IP addresses in a local database with the MaxMind DB format, such as GeoIP2 City
+// or GeoLite2 City. The whole database is held in memory.
+type GeoLocation struct {
+	reader *reader
+}
+
+// NewGeoLocation loads the database from a file.
+func NewGeoLocation(path string) (*GeoLocation, error) {
+	db, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewGeoLocationFromBytes(db)
+}
+
+// NewGeoLocationFromBytes loads the database from the contents of a file.
+func NewGeoLocationFromBytes(db []byte) (*GeoLocation, error) {
+	r, err := newReader(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the MaxMind DB: %v", err)
+	}
+	return &GeoLocation{reader: r}, nil
+}
+
+// Lookup implements geolocation.GeoLocation. It reads the country, the first subdivision and the metro code of
+// the GeoIP2 City record.
+func (g *GeoLocation) Lookup(ctx context.Context, ip string) (*geolocation.GeoInfo, error) {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return nil, errors.New("the IP address is invalid")
+	}
+
+	record, err := g.reader.lookup(parsedIP)
+	if err != nil {
+		return nil, err
+	}
+	fields, ok := record.(map[string]interface{})
+	if !ok {
+		return nil, geolocation.ErrNotFound
+	}
+
+	info := &geolocation.GeoInfo{}
+	if isoCode, ok := field(fields, "country", "iso_code").(string); ok {
+		info.Country = geolocation.CountryAlpha3(isoCode)
+	}
+	if info.Country == "" {
+		return nil, geolocation.ErrNotFound
+	}
+	if subdivisions, ok := fields["subdivisions"].([]interface{}); ok && len(subdivisions) > 0 {
+		if isoCode, ok := field(subdivisions[0], "iso_code").(string); ok {
+			info.Region = isoCode
+		}
+	}
+	if metroCode, ok := field(fields, "location", "metro_code").(uint64); ok && metroCode > 0 {
+		info.Metro = strconv.FormatUint(metroCode, 10)
+	}
+	return info, nil
+}
+
+// field follows the keys through nested maps, and returns nil if any of them is missing.
+func field(value interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = fields[key]
+	}
+	return value
+}
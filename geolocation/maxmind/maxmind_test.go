@@ -0,0 +1,251 @@
+package maxmind
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/geolocation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	for _, ipVersion := range []int{4, 6} {
+		for _, recordSize := range []int{24, 28, 32} {
+			networks := []testNetwork{
+				{cidr: "1.2.3.0/24", record: usRecord},
+				{cidr: "5.6.0.0/16", record: map[string]interface{}{"country": map[string]interface{}{"iso_code": "FR"}}},
+			}
+			if ipVersion == 6 {
+				networks = append(networks, testNetwork{cidr: "2001:db8::/32", record: deRecord})
+			}
+			g, err := NewGeoLocationFromBytes(buildDB(t, ipVersion, recordSize, networks))
+			if !assert.NoError(t, err) {
+				continue
+			}
+
+			info, err := g.Lookup(context.Background(), "1.2.3.4")
+			assert.NoError(t, err)
+			assert.Equal(t, &geolocation.GeoInfo{Country: "USA", Region: "CA", Metro: "807"}, info, "IPv%d with %d bit records", ipVersion, recordSize)
+
+			info, err = g.Lookup(context.Background(), "5.6.7.8")
+			assert.NoError(t, err)
+			assert.Equal(t, &geolocation.GeoInfo{Country: "FRA"}, info, "IPv%d with %d bit records", ipVersion, recordSize)
+
+			_, err = g.Lookup(context.Background(), "1.2.4.1")
+			assert.Equal(t, geolocation.ErrNotFound, err, "IPv%d with %d bit records", ipVersion, recordSize)
+
+			info, err = g.Lookup(context.Background(), "2001:db8::1")
+			if ipVersion == 6 {
+				assert.NoError(t, err)
+				assert.Equal(t, &geolocation.GeoInfo{Country: "DEU", Region: "BE"}, info, "IPv6 address with %d bit records", recordSize)
+			} else {
+				assert.Equal(t, geolocation.ErrNotFound, err, "An IPv4 database doesn't know IPv6 addresses")
+			}
+		}
+	}
+}
+
+func TestLookupInvalidIP(t *testing.T) {
+	g, err := NewGeoLocationFromBytes(buildDB(t, 4, 24, []testNetwork{{cidr: "1.2.3.0/24", record: usRecord}}))
+	assert.NoError(t, err)
+
+	_, err = g.Lookup(context.Background(), "not an ip")
+	assert.EqualError(t, err, "the IP address is invalid")
+}
+
+func TestLookupPointer(t *testing.T) {
+	// The first record is encoded at offset 0, so the second one's country points to it.
+	g, err := NewGeoLocationFromBytes(buildDB(t, 4, 24, []testNetwork{
+		{cidr: "1.2.3.0/24", record: map[string]interface{}{"iso_code": "US"}},
+		{cidr: "1.2.4.0/24", record: map[string]interface{}{"country": pointer(0)}},
+	}))
+	assert.NoError(t, err)
+
+	info, err := g.Lookup(context.Background(), "1.2.4.1")
+	assert.NoError(t, err)
+	assert.Equal(t, &geolocation.GeoInfo{Country: "USA"}, info)
+}
+
+func TestInvalidDB(t *testing.T) {
+	testCases := []struct {
+		description string
+		db          []byte
+		expected    string
+	}{
+		{
+			description: "No Metadata",
+			db:          []byte("not a database"),
+			expected:    "failed to read the MaxMind DB: the file isn't a MaxMind DB, since it has no metadata",
+		},
+		{
+			description: "Bad Record Size",
+			db:          buildMetadata(t, 1, 20, 4),
+			expected:    "failed to read the MaxMind DB: the record size must be 24, 28 or 32, not 20",
+		},
+		{
+			description: "Bad IP Version",
+			db:          buildMetadata(t, 1, 24, 5),
+			expected:    "failed to read the MaxMind DB: the IP version must be 4 or 6, not 5",
+		},
+		{
+			description: "Tree Larger Than File",
+			db:          buildMetadata(t, 100, 24, 4),
+			expected:    "failed to read the MaxMind DB: the search tree is larger than the file",
+		},
+	}
+
+	for _, test := range testCases {
+		_, err := NewGeoLocationFromBytes(test.db)
+		assert.EqualError(t, err, test.expected, test.description)
+	}
+}
+
+func TestDecodeLoop(t *testing.T) {
+	// A map whose only value points back to the map itself.
+	d := &decoder{data: []byte{0xe1, 0x41, 'a', 0x20, 0x00}}
+	_, _, err := d.decode(0)
+	assert.EqualError(t, err, "the data section is nested too deeply")
+}
+
+var usRecord = map[string]interface{}{
+	"country":      map[string]interface{}{"iso_code": "US"},
+	"subdivisions": []interface{}{map[string]interface{}{"iso_code": "CA"}},
+	"location":     map[string]interface{}{"metro_code": uint16(807)},
+}
+
+var deRecord = map[string]interface{}{
+	"country":      map[string]interface{}{"iso_code": "DE"},
+	"subdivisions": []interface{}{map[string]interface{}{"iso_code": "BE"}},
+}
+
+type testNetwork struct {
+	cidr   string
+	record map[string]interface{}
+}
+
+// pointer is a data section pointer to an offset, for the tests to encode.
+type pointer uint
+
+// buildDB writes a MaxMind DB with the networks. The networks mustn't overlap.
+func buildDB(t testing.TB, ipVersion int, recordSize int, networks []testNetwork) []byte {
+	t.Helper()
+
+	// Each record is a node index, -1 for no data, or -2-i for the data of the network i.
+	nodes := [][2]int{{-1, -1}}
+	var data []byte
+	dataOffsets := make([]int, len(networks))
+	for i, network := range networks {
+		dataOffsets[i] = len(data)
+		data = append(data, encode(t, network.record)...)
+
+		_, ipNet, err := net.ParseCIDR(network.cidr)
+		if err != nil {
+			t.Fatalf("Bad test network %s: %v", network.cidr, err)
+		}
+		ones, _ := ipNet.Mask.Size()
+		ip := ipNet.IP.To4()
+		if ip == nil {
+			ip = ipNet.IP.To16()
+		} else if ipVersion == 6 {
+			// An IPv6 tree has the IPv4 addresses in ::/96.
+			ip = append(make([]byte, 12), ip...)
+			ones += 96
+		}
+
+		node := 0
+		for bit := 0; bit < ones; bit++ {
+			side := int(ip[bit/8]>>(7-uint(bit%8))) & 1
+			if bit == ones-1 {
+				nodes[node][side] = -2 - i
+			} else {
+				if nodes[node][side] < 0 {
+					nodes = append(nodes, [2]int{-1, -1})
+					nodes[node][side] = len(nodes) - 1
+				}
+				node = nodes[node][side]
+			}
+		}
+	}
+
+	var db []byte
+	for _, node := range nodes {
+		var values [2]uint32
+		for side, record := range node {
+			switch {
+			case record >= 0:
+				values[side] = uint32(record)
+			case record == -1:
+				values[side] = uint32(len(nodes))
+			default:
+				values[side] = uint32(len(nodes) + dataSectionSeparator + dataOffsets[-2-record])
+			}
+		}
+		switch recordSize {
+		case 24:
+			db = append(db, byte(values[0]>>16), byte(values[0]>>8), byte(values[0]), byte(values[1]>>16), byte(values[1]>>8), byte(values[1]))
+		case 28:
+			db = append(db, byte(values[0]>>16), byte(values[0]>>8), byte(values[0]), byte(values[0]>>20)&0xF0|byte(values[1]>>24)&0x0F, byte(values[1]>>16), byte(values[1]>>8), byte(values[1]))
+		default:
+			db = append(db, 0, 0, 0, 0, 0, 0, 0, 0)
+			binary.BigEndian.PutUint32(db[len(db)-8:], values[0])
+			binary.BigEndian.PutUint32(db[len(db)-4:], values[1])
+		}
+	}
+
+	db = append(db, make([]byte, dataSectionSeparator)...)
+	db = append(db, data...)
+	return append(db, buildMetadata(t, len(nodes), recordSize, ipVersion)...)
+}
+
+func buildMetadata(t testing.TB, nodeCount int, recordSize int, ipVersion int) []byte {
+	return append(append([]byte{}, metadataMarker...), encode(t, map[string]interface{}{
+		"node_count":    uint32(nodeCount),
+		"record_size":   uint16(recordSize),
+		"ip_version":    uint16(ipVersion),
+		"database_type": "Test-City",
+	})...)
+}
+
+// encode writes a value in the data section format. Sizes must be below 29.
+func encode(t testing.TB, value interface{}) []byte {
+	t.Helper()
+	control := func(fieldType int, size int) []byte {
+		if fieldType > 7 {
+			return []byte{byte(size), byte(fieldType - 7)}
+		}
+		return []byte{byte(fieldType<<5 | size)}
+	}
+	unsigned := func(fieldType int, v uint64, size int) []byte {
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, v)
+		return append(control(fieldType, size), b[8-size:]...)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return append(control(typeString, len(v)), v...)
+	case uint16:
+		return unsigned(typeUint16, uint64(v), 2)
+	case uint32:
+		return unsigned(typeUint32, uint64(v), 4)
+	case pointer:
+		return []byte{byte(typePointer<<5) | byte(v>>8)&0x7, byte(v)}
+	case []interface{}:
+		b := control(typeArray, len(v))
+		for _, item := range v {
+			b = append(b, encode(t, item)...)
+		}
+		return b
+	case map[string]interface{}:
+		b := control(typeMap, len(v))
+		for key, item := range v {
+			b = append(b, encode(t, key)...)
+			b = append(b, encode(t, item)...)
+		}
+		return b
+	}
+	t.Fatalf("Can't encode %T", value)
+	return nil
+}
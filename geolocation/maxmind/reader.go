@@ -0,0 +1,349 @@
+package maxmind
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+)
+
+// metadataMarker starts the metadata section at the end of a MaxMind DB file.
+// See https://maxmind.github.io/MaxMind-DB/
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparator is the number of zero bytes between the search tree and the data section.
+const dataSectionSeparator = 16
+
+// The types of the data section fields.
+const (
+	typeExtended  = 0
+	typePointer   = 1
+	typeString    = 2
+	typeDouble    = 3
+	typeBytes     = 4
+	typeUint16    = 5
+	typeUint32    = 6
+	typeMap       = 7
+	typeInt32     = 8
+	typeUint64    = 9
+	typeUint128   = 10
+	typeArray     = 11
+	typeContainer = 12
+	typeEndMarker = 13
+	typeBool      = 14
+	typeFloat     = 15
+)
+
+// reader finds the records of IP addresses in a MaxMind DB file.
+type reader struct {
+	tree       []byte
+	data       []byte
+	nodeCount  uint
+	recordSize uint
+	ipVersion  uint
+	// ipv4Start is the node which the IPv4 addresses start from in an IPv6 tree.
+	ipv4Start uint
+}
+
+func newReader(db []byte) (*reader, error) {
+	metadataStart := bytes.LastIndex(db, metadataMarker)
+	if metadataStart < 0 {
+		return nil, errors.New("the file isn't a MaxMind DB, since it has no metadata")
+	}
+	metadata, _, err := (&decoder{data: db[metadataStart+len(metadataMarker):]}).decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("the metadata is invalid: %v", err)
+	}
+	metadataMap, ok := metadata.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("the metadata must be a map")
+	}
+
+	r := &reader{
+		nodeCount:  toUint(metadataMap["node_count"]),
+		recordSize: toUint(metadataMap["record_size"]),
+		ipVersion:  toUint(metadataMap["ip_version"]),
+	}
+	if r.recordSize != 24 && r.recordSize != 28 && r.recordSize != 32 {
+		return nil, fmt.Errorf("the record size must be 24, 28 or 32, not %d", r.recordSize)
+	}
+	if r.ipVersion != 4 && r.ipVersion != 6 {
+		return nil, fmt.Errorf("the IP version must be 4 or 6, not %d", r.ipVersion)
+	}
+
+	// The node count is checked before it's multiplied, so that a huge one can't overflow the tree size.
+	nodeSize := r.recordSize / 4
+	if r.nodeCount > uint(metadataStart)/nodeSize || r.nodeCount*nodeSize+dataSectionSeparator > uint(metadataStart) {
+		return nil, errors.New("the search tree is larger than the file")
+	}
+	treeSize := r.nodeCount * nodeSize
+	r.tree = db[:treeSize:treeSize]
+	r.data = db[treeSize+dataSectionSeparator : metadataStart]
+
+	if r.ipVersion == 6 {
+		for i := 0; i < 96 && r.ipv4Start < r.nodeCount; i++ {
+			r.ipv4Start = r.record(r.ipv4Start, 0)
+		}
+	}
+	return r, nil
+}
+
+// lookup decodes the record of the IP address. It returns nil if the database has no record for it.
+func (r *reader) lookup(ip net.IP) (interface{}, error) {
+	bits := ip.To4()
+	node := uint(0)
+	if bits != nil {
+		if r.ipVersion == 6 {
+			node = r.ipv4Start
+		}
+	} else {
+		if r.ipVersion == 4 {
+			return nil, nil
+		}
+		bits = ip.To16()
+	}
+	if bits == nil {
+		return nil, errors.New("the IP address is invalid")
+	}
+
+	for i := 0; i < len(bits)*8 && node < r.nodeCount; i++ {
+		bit := uint(bits[i/8]>>(7-uint(i%8))) & 1
+		node = r.record(node, bit)
+	}
+
+	switch {
+	case node == r.nodeCount:
+		return nil, nil
+	case node < r.nodeCount:
+		return nil, errors.New("the search tree is invalid")
+	}
+
+	offset := node - r.nodeCount - dataSectionSeparator
+	if offset >= uint(len(r.data)) {
+		return nil, errors.New("the search tree points past the data section")
+	}
+	value, _, err := (&decoder{data: r.data}).decode(offset)
+	return value, err
+}
+
+// record returns the left (0) or right (1) record of a node in the search tree.
+func (r *reader) record(node uint, side uint) uint {
+	nodeSize := r.recordSize / 4
+	b := r.tree[node*nodeSize : (node+1)*nodeSize]
+	switch r.recordSize {
+	case 24:
+		b = b[side*3:]
+		return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+	case 28:
+		if side == 0 {
+			return uint(b[3]&0xF0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])
+		}
+		return uint(b[3]&0x0F)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6])
+	default:
+		return uint(binary.BigEndian.Uint32(b[side*4:]))
+	}
+}
+
+// decoder reads the fields of a MaxMind DB data section.
+type decoder struct {
+	data []byte
+}
+
+var errTruncated = errors.New("the data section is truncated")
+
+// maxDepth bounds the nesting of maps and arrays, so that pointers which loop back can't recurse forever.
+const maxDepth = 32
+
+// decode returns the field at the offset, and the offset after it.
+func (d *decoder) decode(offset uint) (interface{}, uint, error) {
+	return d.decodeAt(offset, 0)
+}
+
+func (d *decoder) decodeAt(offset uint, depth int) (interface{}, uint, error) {
+	if depth > maxDepth {
+		return nil, 0, errors.New("the data section is nested too deeply")
+	}
+	fieldType, size, offset, err := d.decodeControl(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if fieldType != typePointer {
+		return d.decodeValue(fieldType, size, offset, depth)
+	}
+
+	pointer, next, err := d.decodePointer(size, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	// A pointer never points to another pointer.
+	fieldType, size, offset, err = d.decodeControl(pointer)
+	if err != nil {
+		return nil, 0, err
+	}
+	if fieldType == typePointer {
+		return nil, 0, errors.New("a pointer points to another pointer")
+	}
+	value, _, err := d.decodeValue(fieldType, size, offset, depth)
+	return value, next, err
+}
+
+// decodeControl reads the type and size of a field from its control byte, and whatever bytes extend them.
+// For pointers, the size is the control byte itself.
+func (d *decoder) decodeControl(offset uint) (uint, uint, uint, error) {
+	if offset >= uint(len(d.data)) {
+		return 0, 0, 0, errTruncated
+	}
+	control := uint(d.data[offset])
+	offset++
+
+	fieldType := control >> 5
+	if fieldType == typePointer {
+		return fieldType, control, offset, nil
+	}
+	if fieldType == typeExtended {
+		if offset >= uint(len(d.data)) {
+			return 0, 0, 0, errTruncated
+		}
+		fieldType = 7 + uint(d.data[offset])
+		offset++
+	}
+
+	size := control & 0x1F
+	if size >= 29 {
+		extraBytes := size - 28
+		if offset+extraBytes > uint(len(d.data)) {
+			return 0, 0, 0, errTruncated
+		}
+		extra := uintFromBytes(d.data[offset : offset+extraBytes])
+		offset += extraBytes
+		switch size {
+		case 29:
+			size = 29 + extra
+		case 30:
+			size = 285 + extra
+		default:
+			size = 65821 + extra
+		}
+	}
+	return fieldType, size, offset, nil
+}
+
+func (d *decoder) decodePointer(control uint, offset uint) (uint, uint, error) {
+	pointerSize := (control>>3)&0x3 + 1
+	if offset+pointerSize > uint(len(d.data)) {
+		return 0, 0, errTruncated
+	}
+	b := d.data[offset : offset+pointerSize]
+	var pointer uint
+	switch pointerSize {
+	case 1:
+		pointer = (control&0x7)<<8 | uintFromBytes(b)
+	case 2:
+		pointer = ((control&0x7)<<16 | uintFromBytes(b)) + 2048
+	case 3:
+		pointer = ((control&0x7)<<24 | uintFromBytes(b)) + 526336
+	default:
+		pointer = uintFromBytes(b)
+	}
+	return pointer, offset + pointerSize, nil
+}
+
+func (d *decoder) decodeValue(fieldType uint, size uint, offset uint, depth int) (interface{}, uint, error) {
+	switch fieldType {
+	case typeMap:
+		return d.decodeMap(size, offset, depth)
+	case typeArray:
+		return d.decodeArray(size, offset, depth)
+	case typeBool:
+		return size != 0, offset, nil
+	}
+
+	if offset+size > uint(len(d.data)) {
+		return nil, 0, errTruncated
+	}
+	b := d.data[offset : offset+size]
+	next := offset + size
+	switch fieldType {
+	case typeString:
+		return string(b), next, nil
+	case typeBytes, typeUint128:
+		return b, next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("a double must have 8 bytes, not %d", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("a float must have 4 bytes, not %d", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), next, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > 8 {
+			return nil, 0, fmt.Errorf("an unsigned integer can't have %d bytes", size)
+		}
+		return uint64(uintFromBytes(b)), next, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("an int32 can't have %d bytes", size)
+		}
+		return int64(int32(uint32(uintFromBytes(b)) << (32 - 8*size) >> (32 - 8*size))), next, nil
+	case typeContainer, typeEndMarker:
+		return nil, next, nil
+	}
+	return nil, 0, fmt.Errorf("the data section has an unknown type %d", fieldType)
+}
+
+func (d *decoder) decodeMap(size uint, offset uint, depth int) (interface{}, uint, error) {
+	// Each key and value takes at least one byte.
+	if offset+2*size > uint(len(d.data)) {
+		return nil, 0, errTruncated
+	}
+	values := make(map[string]interface{}, size)
+	for i := uint(0); i < size; i++ {
+		key, next, err := d.decodeAt(offset, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		keyString, ok := key.(string)
+		if !ok {
+			return nil, 0, errors.New("the keys of a map must be strings")
+		}
+		if values[keyString], offset, err = d.decodeAt(next, depth+1); err != nil {
+			return nil, 0, err
+		}
+	}
+	return values, offset, nil
+}
+
+func (d *decoder) decodeArray(size uint, offset uint, depth int) (interface{}, uint, error) {
+	// Each value takes at least one byte.
+	if offset+size > uint(len(d.data)) {
+		return nil, 0, errTruncated
+	}
+	values := make([]interface{}, size)
+	for i := uint(0); i < size; i++ {
+		var err error
+		if values[i], offset, err = d.decodeAt(offset, depth+1); err != nil {
+			return nil, 0, err
+		}
+	}
+	return values, offset, nil
+}
+
+func uintFromBytes(b []byte) uint {
+	var value uint
+	for _, octet := range b {
+		value = value<<8 | uint(octet)
+	}
+	return value
+}
+
+func toUint(value interface{}) uint {
+	if v, ok := value.(uint64); ok {
+		return uint(v)
+	}
+	return 0
+}
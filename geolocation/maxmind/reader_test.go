@@ -0,0 +1,239 @@
+package maxmind
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readerTestIPs are looked up in the corrupted databases, to cover both IPv4 and IPv6 trees.
+var readerTestIPs = []net.IP{
+	net.ParseIP("1.2.3.4"),
+	net.ParseIP("1.2.4.1"),
+	net.ParseIP("9.9.9.9"),
+	net.ParseIP("2001:db8::1"),
+}
+
+func TestReaderTruncated(t *testing.T) {
+	for _, db := range readerTestDBs(t) {
+		for size := 0; size < len(db); size++ {
+			assertReaderDoesNotPanic(t, db[:size])
+		}
+	}
+}
+
+func TestReaderCorrupted(t *testing.T) {
+	for _, db := range readerTestDBs(t) {
+		for i := range db {
+			for _, value := range []byte{0x00, 0xff, db[i] ^ 0x80, db[i] + 1} {
+				corrupted := append([]byte{}, db...)
+				corrupted[i] = value
+				assertReaderDoesNotPanic(t, corrupted)
+			}
+		}
+	}
+}
+
+func TestReaderInvalidTree(t *testing.T) {
+	testCases := []struct {
+		description string
+		record      []byte
+		expected    string
+	}{
+		{
+			description: "Loop To Root",
+			record:      []byte{0, 0, 0},
+			expected:    "the search tree is invalid",
+		},
+		{
+			description: "Past Data Section",
+			record:      []byte{0xff, 0xff, 0xff},
+			expected:    "the search tree points past the data section",
+		},
+	}
+
+	for _, test := range testCases {
+		// A single node whose records both hold the test's value.
+		db := append(append(append([]byte{}, test.record...), test.record...), make([]byte, dataSectionSeparator)...)
+		db = append(db, buildMetadata(t, 1, 24, 4)...)
+
+		r, err := newReader(db)
+		if !assert.NoError(t, err, test.description) {
+			continue
+		}
+		_, err = r.lookup(net.ParseIP("1.2.3.4"))
+		assert.EqualError(t, err, test.expected, test.description)
+	}
+}
+
+func TestReaderHugeNodeCount(t *testing.T) {
+	// A node_count of 2^59, whose tree size of node_count * 32 / 4 bytes overflows to 0.
+	metadata := append([]byte{}, metadataMarker...)
+	metadata = append(metadata, 0xe3)
+	metadata = append(metadata, encode(t, "node_count")...)
+	metadata = append(metadata, 0x08, 0x02, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00)
+	metadata = append(metadata, encode(t, "record_size")...)
+	metadata = append(metadata, encode(t, uint16(32))...)
+	metadata = append(metadata, encode(t, "ip_version")...)
+	metadata = append(metadata, encode(t, uint16(6))...)
+
+	_, err := newReader(append(make([]byte, 64), metadata...))
+	assert.EqualError(t, err, "the search tree is larger than the file")
+}
+
+func TestDecodeCorrupted(t *testing.T) {
+	testCases := []struct {
+		description string
+		data        []byte
+		expected    string
+	}{
+		{
+			description: "Empty",
+			data:        []byte{},
+			expected:    "the data section is truncated",
+		},
+		{
+			description: "Truncated String",
+			data:        []byte{0x45, 'a', 'b'},
+			expected:    "the data section is truncated",
+		},
+		{
+			description: "Truncated Extended Type",
+			data:        []byte{0x01},
+			expected:    "the data section is truncated",
+		},
+		{
+			description: "Truncated Size",
+			data:        []byte{0x5e, 0x01},
+			expected:    "the data section is truncated",
+		},
+		{
+			description: "Truncated Pointer",
+			data:        []byte{0x38, 0x00},
+			expected:    "the data section is truncated",
+		},
+		{
+			description: "Pointer Past Data",
+			data:        []byte{0x27, 0xff},
+			expected:    "the data section is truncated",
+		},
+		{
+			description: "Pointer To Pointer",
+			data:        []byte{0x20, 0x00},
+			expected:    "a pointer points to another pointer",
+		},
+		{
+			description: "Map Larger Than Data",
+			data:        []byte{0xff, 0xff, 0xff, 0xff},
+			expected:    "the data section is truncated",
+		},
+		{
+			description: "Array Larger Than Data",
+			data:        []byte{0x1f, 0x04, 0xff, 0xff, 0xff},
+			expected:    "the data section is truncated",
+		},
+		{
+			description: "Map With Integer Key",
+			data:        []byte{0xe1, 0xa1, 0x01, 0x40},
+			expected:    "the keys of a map must be strings",
+		},
+		{
+			description: "Bad Double",
+			data:        []byte{0x61, 0x00},
+			expected:    "a double must have 8 bytes, not 1",
+		},
+		{
+			description: "Bad Float",
+			data:        []byte{0x01, 0x08, 0x00},
+			expected:    "a float must have 4 bytes, not 1",
+		},
+		{
+			description: "Long Unsigned Integer",
+			data:        []byte{0xc9, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			expected:    "an unsigned integer can't have 9 bytes",
+		},
+		{
+			description: "Long Int32",
+			data:        []byte{0x05, 0x01, 0, 0, 0, 0, 0},
+			expected:    "an int32 can't have 5 bytes",
+		},
+		{
+			description: "Unknown Type",
+			data:        []byte{0x00, 0x20},
+			expected:    "the data section has an unknown type 39",
+		},
+		{
+			description: "Deep Nesting",
+			data:        nestedArrays(maxDepth + 2),
+			expected:    "the data section is nested too deeply",
+		},
+	}
+
+	for _, test := range testCases {
+		_, _, err := (&decoder{data: test.data}).decode(0)
+		assert.EqualError(t, err, test.expected, test.description)
+	}
+}
+
+func FuzzNewReader(f *testing.F) {
+	for _, db := range readerTestDBs(f) {
+		f.Add(db)
+	}
+	f.Fuzz(func(t *testing.T, db []byte) {
+		r, err := newReader(db)
+		if err != nil {
+			return
+		}
+		for _, ip := range readerTestIPs {
+			r.lookup(ip)
+		}
+	})
+}
+
+func FuzzDecode(f *testing.F) {
+	f.Add([]byte{0xe1, 0x41, 'a', 0x20, 0x00})
+	f.Add(encode(f, usRecord))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		(&decoder{data: data}).decode(0)
+	})
+}
+
+// readerTestDBs are valid databases of each IP version and record size, for the tests to corrupt.
+func readerTestDBs(t testing.TB) [][]byte {
+	networks := []testNetwork{
+		{cidr: "1.2.3.0/24", record: usRecord},
+		{cidr: "1.2.4.0/24", record: map[string]interface{}{"country": pointer(0)}},
+		{cidr: "2001:db8::/32", record: deRecord},
+	}
+	var dbs [][]byte
+	for _, recordSize := range []int{24, 28, 32} {
+		dbs = append(dbs, buildDB(t, 4, recordSize, networks[:2]))
+		dbs = append(dbs, buildDB(t, 6, recordSize, networks))
+	}
+	return dbs
+}
+
+// assertReaderDoesNotPanic reads the database and looks up the test IPs. Corrupted databases may fail either
+// step, but they mustn't panic.
+func assertReaderDoesNotPanic(t *testing.T, db []byte) {
+	t.Helper()
+	assert.NotPanics(t, func() {
+		r, err := newReader(db)
+		if err != nil {
+			return
+		}
+		for _, ip := range readerTestIPs {
+			r.lookup(ip)
+		}
+	})
+}
+
+// nestedArrays returns arrays which each hold the next one, nested depth times.
+func nestedArrays(depth int) []byte {
+	data := make([]byte, 0, 2*depth)
+	for i := 0; i < depth; i++ {
+		data = append(data, 0x01, 0x04)
+	}
+	return append(data, 0x40)
+}
@@ -1,8 +1,11 @@
 package prebidServer
 
 import (
+	"context"
+	"fmt"
 	"math/rand"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
@@ -10,6 +13,7 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
 	pbc "github.com/PubMatic-OpenWrap/prebid-server/prebid_cache_client"
 	"github.com/PubMatic-OpenWrap/prebid-server/router"
+	"github.com/PubMatic-OpenWrap/prebid-server/server"
 	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
 	"github.com/julienschmidt/httprouter"
 
@@ -18,82 +22,153 @@ import (
 )
 
 // Rev holds binary revision string
-// Set manually at build time using:
-//    go build -ldflags "-X main.Rev=`git rev-parse --short HEAD`"
-// Populated automatically at build / release time via .travis.yml
-//   `gox -os="linux" -arch="386" -output="{{.Dir}}_{{.OS}}_{{.Arch}}" -ldflags "-X main.Rev=`git rev-parse --short HEAD`" -verbose ./...;`
+// The prebid-server command sets it from its own Rev, which is set at build time using:
+//    go build -ldflags "-X main.Rev=`git rev-parse --short HEAD`" ./cmd/prebid-server
 // See issue #559
 var Rev string
 
-const schemaDirectory = "/home/http/GO_SERVER/dmhbserver/static/"
+// shutdownTimeout bounds how long ServeStandalone waits for the auctions in flight once it's asked to stop.
+const shutdownTimeout = 30 * time.Second
 
-/*
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
+var (
+	currencyConverter *currencies.RateConverter
+	stopConverter     sync.Once
+
+	// stopWatcher stops the config watcher started by start, if the config_reload section enabled it.
+	stopWatcher     chan struct{}
+	stopWatcherOnce sync.Once
 
-func main() {
-	flag.Parse() // required for glog flags and testing package flags
+	// listeners holds the channels of the servers started by ServeStandalone, so that Shutdown can stop them.
+	listeners struct {
+		sync.Mutex
+		stop     chan struct{}
+		stopOnce sync.Once
+		done     chan struct{}
+	}
+)
 
-	cfg, err := loadConfig()
+// InitPrebidServer builds Prebid Server for embedded use. The host serves the requests itself, through the
+// wrappers below, and should call Shutdown before it exits.
+func InitPrebidServer(configFile string) {
+	cfg, err := loadConfigFile(configFile)
 	if err != nil {
 		glog.Fatalf("Configuration could not be loaded or did not pass validation: %v", err)
 	}
 
-	err = serve(Rev, cfg)
-	if err != nil {
+	if _, err := start(Rev, configFile, cfg); err != nil {
 		glog.Errorf("prebid-server failed: %v", err)
 	}
 }
-*/
 
-func InitPrebidServer(configFile string) {
-	rand.Seed(time.Now().UnixNano())
-	v := viper.New()
-	config.SetupViper(v, configFile)
-	v.SetConfigFile(configFile)
-	v.ReadInConfig()
-
-	cfg, err := config.New(v)
+// ServeStandalone runs Prebid Server as its own server. It listens on the main, admin and Prometheus ports
+// until the process gets SIGTERM or SIGINT, or until Shutdown gets called, and then shuts down gracefully.
+func ServeStandalone(configFile string) error {
+	cfg, err := loadConfigFile(configFile)
+	if err != nil {
+		return fmt.Errorf("Configuration could not be loaded or did not pass validation: %v", err)
+	}
 
+	r, err := start(Rev, configFile, cfg)
 	if err != nil {
-		glog.Fatalf("Configuration could not be loaded or did not pass validation: %v", err)
+		return err
 	}
 
-	if err := serve(Rev, cfg); err != nil {
-		glog.Errorf("prebid-server failed: %v", err)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	listeners.Lock()
+	listeners.stop, listeners.done = stop, done
+	listeners.Unlock()
+
+	server.ListenUntil(cfg, router.Handler(), router.Admin(Rev, currencyConverter), r.MetricsEngine, stop)
+	close(done)
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return Shutdown(ctx)
+}
+
+// Shutdown stops Prebid Server gracefully, whether it runs standalone or embedded. The listeners of
+// ServeStandalone get closed first, then the auctions in flight are given until the context is done to
+// finish. Last, the Stored Request and currency pollers are stopped, the analytics modules are flushed
+// and the database connections are closed.
+func Shutdown(ctx context.Context) error {
+	listeners.Lock()
+	stop, done := listeners.stop, listeners.done
+	listeners.Unlock()
+	if stop != nil {
+		listeners.stopOnce.Do(func() { close(stop) })
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+	}
+
+	if stopWatcher != nil {
+		stopWatcherOnce.Do(func() { close(stopWatcher) })
+	}
+	err := router.Shutdown(ctx)
+	if currencyConverter != nil {
+		stopConverter.Do(currencyConverter.StopPeriodicFetching)
 	}
+	return err
 }
 
-func loadConfig() (*config.Configuration, error) {
+func loadConfigFile(configFile string) (*config.Configuration, error) {
 	v := viper.New()
-	config.SetupViper(v, "pbs") // filke = filename
+	config.SetupViper(v, configFile)
+	v.SetConfigFile(configFile)
+	v.ReadInConfig()
+
 	return config.New(v)
 }
 
-func serve(revision string, cfg *config.Configuration) error {
+// start builds the default Prebid Server instance, which backs the package level entry points.
+func start(revision string, configFile string, cfg *config.Configuration) (*router.Router, error) {
+	rand.Seed(time.Now().UnixNano())
 	fetchingInterval := time.Duration(cfg.CurrencyConverter.FetchIntervalSeconds) * time.Second
-	currencyConverter := currencies.NewRateConverter(&http.Client{}, cfg.CurrencyConverter.FetchURL, fetchingInterval)
+	currencyConverter = currencies.NewRateConverter(&http.Client{}, cfg.CurrencyConverter.FetchURL, fetchingInterval)
 
-	_, err := router.New(cfg, currencyConverter)
+	r, err := router.New(cfg, currencyConverter)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	pbc.InitPrebidCache(cfg.CacheURL.GetBaseURL())
 	pbc.InitPrebidCacheURL(cfg.ExternalURL)
 
-	// Add cors support
-	//corsRouter := router.SupportCORS(r)
-	//server.Listen(cfg, router.NoCache{Handler: corsRouter}, router.Admin(revision, currencyConverter), r.MetricsEngine)
-	//r.Shutdown()
-	return nil
+	if cfg.ConfigReload.Enabled {
+		stopWatcher = make(chan struct{})
+		interval := time.Duration(cfg.ConfigReload.PollIntervalSeconds) * time.Second
+		go config.WatchFile(configFile, interval, func() { reloadConfig(configFile) }, stopWatcher)
+	}
+	return r, nil
+}
+
+// reloadConfig applies the config file to the running instance. A config which can't be loaded or
+// doesn't pass validation is logged and ignored.
+func reloadConfig(configFile string) {
+	cfg, err := loadConfigFile(configFile)
+	if err != nil {
+		glog.Errorf("The config was not reloaded because it could not be loaded or did not pass validation: %v", err)
+		return
+	}
+	if err := router.Reload(cfg); err != nil {
+		glog.Errorf("%v", err)
+	}
 }
 
 func OrtbAuction(w http.ResponseWriter, r *http.Request) error {
 	return router.OrtbAuctionEndpointWrapper(w, r)
 }
 
+func AmpAuction(w http.ResponseWriter, r *http.Request) error {
+	return router.AmpAuctionEndpointWrapper(w, r)
+}
+
+func VideoAuction(w http.ResponseWriter, r *http.Request) error {
+	return router.VideoAuctionEndpointWrapper(w, r)
+}
+
 func Auction(w http.ResponseWriter, r *http.Request) {
 	router.AuctionWrapper(w, r)
 
@@ -99,6 +99,10 @@ const (
 	HbSizeConstantKey   TargetingKey = "hb_size"
 	HbDealIDConstantKey TargetingKey = "hb_deal"
 
+	// HbDealPriceKey is the price bucket of a bid which carries a deal. It is rounded with the deal price granularity
+	// (if one was given) so that deal line items never collide with the open market hb_pb buckets.
+	HbDealPriceKey TargetingKey = "hb_pb_deal"
+
 	// HbCacheKey and HbVastCacheKey store UUIDs which can be used to fetch things from prebid cache.
 	// Callers should *never* assume that either of these exist, since the call to the cache may always fail.
 	//
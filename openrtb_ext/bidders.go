@@ -5,10 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
-	"path/filepath"
 	"strings"
 
+	"github.com/PubMatic-OpenWrap/prebid-server/static"
 	"github.com/xeipuuv/gojsonschema"
 )
 
@@ -161,40 +160,42 @@ type BidderParamValidator interface {
 // NewBidderParamsValidator makes a BidderParamValidator, assuming all the necessary files exist in the filesystem.
 // This will error if, for example, a Bidder gets added but no JSON schema is written for them.
 func NewBidderParamsValidator(schemaDirectory string) (BidderParamValidator, error) {
-	fileInfos, err := ioutil.ReadDir(schemaDirectory)
+	return NewBidderParamsValidatorFS(static.Dir(schemaDirectory))
+}
+
+// NewBidderParamsValidatorFS makes a BidderParamValidator from the JSON schemas at the root of schemas.
+// Use static.Embedded(static.BidderParamsDir) for the schemas which were compiled into the binary.
+func NewBidderParamsValidatorFS(schemas static.FS) (BidderParamValidator, error) {
+	fileInfos, err := schemas.ReadDir(".")
 	if err != nil {
-		return nil, fmt.Errorf("Failed to read JSON schemas from directory %s. %v", schemaDirectory, err)
+		return nil, fmt.Errorf("Failed to read JSON schemas from directory %v. %v", schemas, err)
 	}
 
 	schemaContents := make(map[BidderName]string, 50)
-	schemas := make(map[BidderName]*gojsonschema.Schema, 50)
+	parsedSchemas := make(map[BidderName]*gojsonschema.Schema, 50)
 	for _, fileInfo := range fileInfos {
 		bidderName := strings.TrimSuffix(fileInfo.Name(), ".json")
 		if _, isValid := BidderMap[bidderName]; !isValid {
-			return nil, fmt.Errorf("File %s/%s does not match a valid BidderName.", schemaDirectory, fileInfo.Name())
+			return nil, fmt.Errorf("File %v/%s does not match a valid BidderName.", schemas, fileInfo.Name())
 		}
-		toOpen, err := filepath.Abs(filepath.Join(schemaDirectory, fileInfo.Name()))
-		if err != nil {
-			return nil, fmt.Errorf("Failed to get an absolute representation of the path: %s, %v", toOpen, err)
-		}
-		schemaLoader := gojsonschema.NewReferenceLoader("file:///" + filepath.ToSlash(toOpen))
-		loadedSchema, err := gojsonschema.NewSchema(schemaLoader)
+
+		fileBytes, err := schemas.ReadFile(fileInfo.Name())
 		if err != nil {
-			return nil, fmt.Errorf("Failed to load json schema at %s: %v", toOpen, err)
+			return nil, fmt.Errorf("Failed to read file %v/%s: %v", schemas, fileInfo.Name(), err)
 		}
 
-		fileBytes, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", schemaDirectory, fileInfo.Name()))
+		loadedSchema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(fileBytes))
 		if err != nil {
-			return nil, fmt.Errorf("Failed to read file %s/%s: %v", schemaDirectory, fileInfo.Name(), err)
+			return nil, fmt.Errorf("Failed to load json schema at %v/%s: %v", schemas, fileInfo.Name(), err)
 		}
 
-		schemas[BidderName(bidderName)] = loadedSchema
+		parsedSchemas[BidderName(bidderName)] = loadedSchema
 		schemaContents[BidderName(bidderName)] = string(fileBytes)
 	}
 
 	return &bidderParamValidator{
 		schemaContents: schemaContents,
-		parsedSchemas:  schemas,
+		parsedSchemas:  parsedSchemas,
 	}, nil
 }
 
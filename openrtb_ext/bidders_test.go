@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/PubMatic-OpenWrap/prebid-server/static"
 	"github.com/xeipuuv/gojsonschema"
 )
 
@@ -49,6 +50,21 @@ func TestInvalidParams(t *testing.T) {
 	}
 }
 
+func TestEmbeddedBidderParamSchemas(t *testing.T) {
+	embedded, err := NewBidderParamsValidatorFS(static.Embedded(static.BidderParamsDir))
+	if err != nil {
+		t.Fatalf("Failed to load the embedded bidder params schemas: %v", err)
+	}
+	for _, bidderName := range BidderMap {
+		if embedded.Schema(bidderName) != validator.Schema(bidderName) {
+			t.Errorf("The embedded schema for bidder %s differs from static/bidder-params/%s.json", bidderName, bidderName)
+		}
+	}
+	if err := embedded.Validate(BidderAppnexus, json.RawMessage(`{}`)); err == nil {
+		t.Error("These params should be invalid.")
+	}
+}
+
 func TestBidderList(t *testing.T) {
 	list := BidderList()
 	for _, bidderName := range BidderMap {
@@ -20,6 +20,10 @@ type ExtImp struct {
 type ExtImpPrebid struct {
 	StoredRequest *ExtStoredRequest `json:"storedrequest"`
 
+	// StoredAuctionResponse and StoredBidResponse replace the bidders with canned responses, for testing.
+	StoredAuctionResponse *ExtStoredAuctionResponse `json:"storedauctionresponse,omitempty"`
+	StoredBidResponse     []ExtStoredBidResponse    `json:"storedbidresponse,omitempty"`
+
 	// NOTE: This is not part of the official API, we are not expecting clients
 	// migrate from imp[...].ext.${BIDDER} to imp[...].ext.prebid.bidder.${BIDDER}
 	// at this time
@@ -31,3 +35,36 @@ type ExtImpPrebid struct {
 type ExtStoredRequest struct {
 	ID string `json:"id"`
 }
+
+// ExtStoredAuctionResponse defines the contract for bidrequest.imp[i].ext.prebid.storedauctionresponse.
+// The Stored Response is an array of OpenRTB SeatBids, which are returned for the imp instead of calling any bidder.
+type ExtStoredAuctionResponse struct {
+	ID string `json:"id"`
+}
+
+// ExtStoredBidResponse defines the contract for bidrequest.imp[i].ext.prebid.storedbidresponse[j].
+// The Stored Response is the raw body of the bidder's HTTP response, which is parsed by the bidder's MakeBids
+// instead of calling the bidder's server.
+type ExtStoredBidResponse struct {
+	Bidder string `json:"bidder"`
+	ID     string `json:"id"`
+}
+
+// ExtVideo defines the contract for bidrequest.imp[i].video.ext. It holds the ad pod fields of OpenRTB 2.6,
+// which ConvertDownTo25 moves here.
+type ExtVideo struct {
+	// PodID groups the imps of the same ad pod.
+	PodID string `json:"podid,omitempty"`
+	// PodSeq is the position of the pod in the content stream: 1 for the first, -1 for the last, 0 for any.
+	PodSeq int `json:"podseq,omitempty"`
+	// SlotInPod is the position of the ad in the pod: 1 for the first, -1 for the last, 2 for the first or last, 0 for any.
+	SlotInPod int `json:"slotinpod,omitempty"`
+	// MaxSeq is the most ads which can be played in the pod.
+	MaxSeq int `json:"maxseq,omitempty"`
+	// PodDur is the total duration of the pod, in seconds.
+	PodDur int `json:"poddur,omitempty"`
+	// RqdDurs are the exact durations, in seconds, which the ads may have.
+	RqdDurs []int `json:"rqddurs,omitempty"`
+	// MinCPMPerSec is the minimum CPM per second of the ads.
+	MinCPMPerSec float64 `json:"mincpmpersec,omitempty"`
+}
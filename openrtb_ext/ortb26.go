@@ -0,0 +1,311 @@
+package openrtb_ext
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ortb26Field is an OpenRTB 2.6 field which the openrtb 2.5 types can't hold, and the place in its parent's ext
+// where Prebid Server kept it under OpenRTB 2.5.
+type ortb26Field struct {
+	name string
+	ext  []string
+}
+
+// ortb26Object lists the OpenRTB 2.6 fields of an object, by the object's path in its request or response.
+// A path through an array applies to each object in it.
+type ortb26Object struct {
+	path   []string
+	fields []ortb26Field
+}
+
+// ortb26RequestFields are the OpenRTB 2.6 fields of a bid request.
+var ortb26RequestFields = []ortb26Object{
+	{path: []string{"regs"}, fields: []ortb26Field{
+		{name: "gdpr", ext: []string{"gdpr"}},
+		{name: "us_privacy", ext: []string{"us_privacy"}},
+		{name: "gpp", ext: []string{"gpp"}},
+		{name: "gpp_sid", ext: []string{"gpp_sid"}},
+	}},
+	{path: []string{"user"}, fields: []ortb26Field{
+		{name: "consent", ext: []string{"consent"}},
+		{name: "eids", ext: []string{"eids"}},
+	}},
+	{path: []string{"source"}, fields: []ortb26Field{
+		{name: "schain", ext: []string{"schain"}},
+	}},
+	{path: []string{"device"}, fields: []ortb26Field{
+		{name: "sua", ext: []string{"sua"}},
+	}},
+	{path: []string{"imp"}, fields: []ortb26Field{
+		{name: "rwdd", ext: []string{"prebid", "is_rewarded_inventory"}},
+	}},
+	{path: []string{"imp", "video"}, fields: []ortb26Field{
+		{name: "maxseq", ext: []string{"maxseq"}},
+		{name: "poddur", ext: []string{"poddur"}},
+		{name: "podid", ext: []string{"podid"}},
+		{name: "podseq", ext: []string{"podseq"}},
+		{name: "rqddurs", ext: []string{"rqddurs"}},
+		{name: "slotinpod", ext: []string{"slotinpod"}},
+		{name: "mincpmpersec", ext: []string{"mincpmpersec"}},
+	}},
+}
+
+// ortb26ImpFields are the OpenRTB 2.6 fields of an imp, such as a Stored Imp.
+var ortb26ImpFields = objectsUnder("imp", ortb26RequestFields)
+
+// ortb26BidFields are the OpenRTB 2.6 fields of a bid, and where a Bidder's bid keeps them under OpenRTB 2.5.
+var ortb26BidFields = []ortb26Field{
+	{name: "mtype", ext: []string{"mtype"}},
+	{name: "dur", ext: []string{"dur"}},
+	{name: "slotinpod", ext: []string{"slotinpod"}},
+	{name: "cattax", ext: []string{"cattax"}},
+	{name: "apis", ext: []string{"apis"}},
+	{name: "langb", ext: []string{"langb"}},
+}
+
+// ortb26BidderResponseFields are the OpenRTB 2.6 fields of the responses from a Bidder's endpoint.
+var ortb26BidderResponseFields = []ortb26Object{
+	{path: []string{"seatbid", "bid"}, fields: ortb26BidFields},
+}
+
+// ortb26ResponseFields are the OpenRTB 2.6 fields of Prebid Server's own responses. The exchange puts each
+// Bidder's bid.ext in bid.ext.bidder, so that's where they are until they're moved out.
+var ortb26ResponseFields = []ortb26Object{
+	{path: []string{"seatbid", "bid"}, fields: fieldsUnder("bidder", ortb26BidFields)},
+}
+
+// ConvertDownTo25 moves the OpenRTB 2.6 fields of a bid request to their OpenRTB 2.5 ext locations, so that they
+// survive being read into the openrtb types. A field which is also set in its ext location replaces it.
+func ConvertDownTo25(request []byte) ([]byte, error) {
+	return convertOrtb26Fields("request", request, ortb26RequestFields, moveToExt)
+}
+
+// ConvertUpTo26 moves the fields which ConvertDownTo25 put in ext back to their OpenRTB 2.6 locations,
+// for the bidders which read OpenRTB 2.6. A field which is already set in its OpenRTB 2.6 location is kept.
+func ConvertUpTo26(request []byte) ([]byte, error) {
+	return convertOrtb26Fields("request", request, ortb26RequestFields, moveFromExt)
+}
+
+// ConvertImpDownTo25 does what ConvertDownTo25 does for a single imp.
+func ConvertImpDownTo25(imp []byte) ([]byte, error) {
+	return convertOrtb26Fields("imp", imp, ortb26ImpFields, moveToExt)
+}
+
+// ConvertBidderResponseDownTo25 moves the OpenRTB 2.6 fields of the bids in a response from a Bidder's endpoint
+// to bid.ext, so that the Bidder can read them into the openrtb types.
+func ConvertBidderResponseDownTo25(response []byte) ([]byte, error) {
+	return convertOrtb26Fields("response", response, ortb26BidderResponseFields, moveToExt)
+}
+
+// ConvertResponseUpTo26 moves the OpenRTB 2.6 fields of the bids in Prebid Server's response from
+// bid.ext.bidder to their OpenRTB 2.6 locations.
+func ConvertResponseUpTo26(response []byte) ([]byte, error) {
+	return convertOrtb26Fields("response", response, ortb26ResponseFields, moveFromExt)
+}
+
+// objectsUnder returns the objects whose path starts with the key, with the key taken off their paths.
+func objectsUnder(key string, objects []ortb26Object) []ortb26Object {
+	var under []ortb26Object
+	for _, object := range objects {
+		if object.path[0] == key {
+			under = append(under, ortb26Object{path: object.path[1:], fields: object.fields})
+		}
+	}
+	return under
+}
+
+// fieldsUnder returns the fields with their ext locations moved under the key.
+func fieldsUnder(key string, fields []ortb26Field) []ortb26Field {
+	under := make([]ortb26Field, len(fields))
+	for i, field := range fields {
+		under[i] = ortb26Field{name: field.name, ext: append([]string{key}, field.ext...)}
+	}
+	return under
+}
+
+// jsonObject holds a JSON object whose values haven't been parsed yet.
+type jsonObject map[string]json.RawMessage
+
+func convertOrtb26Fields(name string, document []byte, objects []ortb26Object, move func(object jsonObject, field ortb26Field) (bool, error)) ([]byte, error) {
+	var root jsonObject
+	if err := json.Unmarshal(document, &root); err != nil {
+		if _, isTypeErr := err.(*json.UnmarshalTypeError); !isTypeErr {
+			return nil, err
+		}
+		return nil, fmt.Errorf("the %s must be a JSON object", name)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("the %s must be a JSON object", name)
+	}
+
+	changed := false
+	for _, object := range objects {
+		fields := object.fields
+		updated, err := updateObjects(root, object.path, func(object jsonObject) (bool, error) {
+			objectChanged := false
+			for _, field := range fields {
+				fieldChanged, err := move(object, field)
+				if err != nil {
+					return false, err
+				}
+				objectChanged = objectChanged || fieldChanged
+			}
+			return objectChanged, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s is invalid: %v", strings.Join(append([]string{name}, object.path...), "."), err)
+		}
+		changed = changed || updated
+	}
+
+	if !changed {
+		return document, nil
+	}
+	return marshalJSON(root)
+}
+
+// marshalJSON encodes the value without escaping HTML, since the ads in a response are HTML.
+func marshalJSON(value interface{}) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	enc := json.NewEncoder(buffer)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// updateObjects calls update on the objects at the path. If a value on the path is an array, each of its
+// objects is updated. It returns true if any object changed.
+func updateObjects(object jsonObject, path []string, update func(object jsonObject) (bool, error)) (bool, error) {
+	if len(path) == 0 {
+		return update(object)
+	}
+
+	raw := bytes.TrimSpace(object[path[0]])
+	if len(raw) == 0 {
+		return false, nil
+	}
+	switch raw[0] {
+	case '{':
+		var child jsonObject
+		if err := json.Unmarshal(raw, &child); err != nil {
+			return false, err
+		}
+		changed, err := updateObjects(child, path[1:], update)
+		if !changed || err != nil {
+			return false, err
+		}
+		return true, object.set(path[0], child)
+	case '[':
+		var children []json.RawMessage
+		if err := json.Unmarshal(raw, &children); err != nil {
+			return false, err
+		}
+		changed := false
+		for i := range children {
+			var child jsonObject
+			if trimmed := bytes.TrimSpace(children[i]); len(trimmed) == 0 || trimmed[0] != '{' {
+				continue
+			}
+			if err := json.Unmarshal(children[i], &child); err != nil {
+				return false, err
+			}
+			childChanged, err := updateObjects(child, path[1:], update)
+			if err != nil {
+				return false, err
+			}
+			if childChanged {
+				if children[i], err = marshalJSON(child); err != nil {
+					return false, err
+				}
+				changed = true
+			}
+		}
+		if !changed {
+			return false, nil
+		}
+		return true, object.set(path[0], children)
+	}
+	return false, nil
+}
+
+func moveToExt(object jsonObject, field ortb26Field) (bool, error) {
+	value, ok := object[field.name]
+	if !ok {
+		return false, nil
+	}
+	delete(object, field.name)
+	return true, object.setPath(append([]string{"ext"}, field.ext...), value)
+}
+
+func moveFromExt(object jsonObject, field ortb26Field) (bool, error) {
+	value, ok, err := object.deletePath(append([]string{"ext"}, field.ext...))
+	if !ok || err != nil {
+		return false, err
+	}
+	if _, exists := object[field.name]; !exists {
+		object[field.name] = value
+	}
+	return true, nil
+}
+
+func (object jsonObject) set(key string, value interface{}) error {
+	raw, err := marshalJSON(value)
+	if err != nil {
+		return err
+	}
+	object[key] = raw
+	return nil
+}
+
+// setPath sets the value at the path, creating the objects on the way if they don't exist.
+func (object jsonObject) setPath(path []string, value json.RawMessage) error {
+	if len(path) == 1 {
+		object[path[0]] = value
+		return nil
+	}
+	var child jsonObject
+	if raw, ok := object[path[0]]; ok {
+		if err := json.Unmarshal(raw, &child); err != nil {
+			return fmt.Errorf("%s must be an object", path[0])
+		}
+	}
+	if child == nil {
+		child = make(jsonObject, 1)
+	}
+	if err := child.setPath(path[1:], value); err != nil {
+		return err
+	}
+	return object.set(path[0], child)
+}
+
+// deletePath removes the value at the path, and returns it. The objects on the way which are empty afterwards
+// are removed too.
+func (object jsonObject) deletePath(path []string) (json.RawMessage, bool, error) {
+	raw, ok := object[path[0]]
+	if !ok {
+		return nil, false, nil
+	}
+	if len(path) == 1 {
+		delete(object, path[0])
+		return raw, true, nil
+	}
+
+	var child jsonObject
+	if err := json.Unmarshal(raw, &child); err != nil || child == nil {
+		return nil, false, nil
+	}
+	value, ok, err := child.deletePath(path[1:])
+	if !ok || err != nil {
+		return nil, false, err
+	}
+	if len(child) == 0 {
+		delete(object, path[0])
+		return value, true, nil
+	}
+	return value, true, object.set(path[0], child)
+}
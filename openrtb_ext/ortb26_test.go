@@ -0,0 +1,99 @@
+package openrtb_ext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const ortb26Request = `{
+  "id": "req",
+  "imp": [
+    {"id": "1", "rwdd": 1, "video": {"mimes": ["video/mp4"], "podid": "pod-1", "podseq": 1, "slotinpod": -1, "rqddurs": [15, 30]}},
+    {"id": "2", "banner": {"w": 300, "h": 250}}
+  ],
+  "regs": {"gdpr": 1, "gpp": "DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1NYN", "gpp_sid": [2, 6]},
+  "user": {"consent": "BONV8oqONXwgmADACHENAO7pqzAAppY", "eids": [{"source": "example.com", "uids": [{"id": "1"}]}]},
+  "source": {"tid": "t", "schain": {"complete": 1, "nodes": [{"asi": "example.com", "sid": "1", "hp": 1}], "ver": "1.0"}},
+  "device": {"ua": "Mozilla", "sua": {"mobile": 0}}
+}`
+
+const ortb25Request = `{
+  "id": "req",
+  "imp": [
+    {"id": "1", "ext": {"prebid": {"is_rewarded_inventory": 1}}, "video": {"mimes": ["video/mp4"], "ext": {"podid": "pod-1", "podseq": 1, "slotinpod": -1, "rqddurs": [15, 30]}}},
+    {"id": "2", "banner": {"w": 300, "h": 250}}
+  ],
+  "regs": {"ext": {"gdpr": 1, "gpp": "DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1NYN", "gpp_sid": [2, 6]}},
+  "user": {"ext": {"consent": "BONV8oqONXwgmADACHENAO7pqzAAppY", "eids": [{"source": "example.com", "uids": [{"id": "1"}]}]}},
+  "source": {"tid": "t", "ext": {"schain": {"complete": 1, "nodes": [{"asi": "example.com", "sid": "1", "hp": 1}], "ver": "1.0"}}},
+  "device": {"ua": "Mozilla", "ext": {"sua": {"mobile": 0}}}
+}`
+
+func TestConvertDownTo25(t *testing.T) {
+	converted, err := ConvertDownTo25([]byte(ortb26Request))
+	assert.NoError(t, err)
+	assert.JSONEq(t, ortb25Request, string(converted))
+
+	converted, err = ConvertDownTo25([]byte(ortb25Request))
+	assert.NoError(t, err)
+	assert.JSONEq(t, ortb25Request, string(converted), "A 2.5 request should be left alone")
+}
+
+func TestConvertDownTo25Overrides(t *testing.T) {
+	converted, err := ConvertDownTo25([]byte(`{"regs":{"us_privacy":"1YNN","ext":{"us_privacy":"1NNN","gdpr":0}}}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"regs":{"ext":{"us_privacy":"1YNN","gdpr":0}}}`, string(converted))
+}
+
+func TestConvertUpTo26(t *testing.T) {
+	converted, err := ConvertUpTo26([]byte(ortb25Request))
+	assert.NoError(t, err)
+	assert.JSONEq(t, ortb26Request, string(converted))
+
+	converted, err = ConvertUpTo26([]byte(`{"user":{"eids":[{"source":"a.com"}],"ext":{"eids":[{"source":"b.com"}],"data":1}}}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"user":{"eids":[{"source":"a.com"}],"ext":{"data":1}}}`, string(converted), "A 2.6 field should win over its ext location")
+}
+
+func TestConvertNotAnObject(t *testing.T) {
+	_, err := ConvertDownTo25([]byte(`["not", "a", "request"]`))
+	assert.EqualError(t, err, "the request must be a JSON object")
+	_, err = ConvertUpTo26([]byte(`null`))
+	assert.EqualError(t, err, "the request must be a JSON object")
+	_, err = ConvertUpTo26([]byte(`<xml/>`))
+	assert.Error(t, err)
+}
+
+func TestConvertImpDownTo25(t *testing.T) {
+	converted, err := ConvertImpDownTo25([]byte(`{"id":"1","rwdd":1,"video":{"mimes":["video/mp4"],"podid":"pod-1"}}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"1","ext":{"prebid":{"is_rewarded_inventory":1}},"video":{"mimes":["video/mp4"],"ext":{"podid":"pod-1"}}}`, string(converted))
+
+	_, err = ConvertImpDownTo25([]byte(`[]`))
+	assert.EqualError(t, err, "the imp must be a JSON object")
+}
+
+func TestConvertBidderResponseDownTo25(t *testing.T) {
+	converted, err := ConvertBidderResponseDownTo25([]byte(`{"id":"resp","seatbid":[{"bid":[{"id":"b1","price":1,"mtype":2,"dur":30,"ext":{"any":"value"}},{"id":"b2","price":1}]}]}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"resp","seatbid":[{"bid":[{"id":"b1","price":1,"ext":{"any":"value","mtype":2,"dur":30}},{"id":"b2","price":1}]}]}`, string(converted))
+
+	unchanged := []byte(`{"id":"resp","seatbid":[{"bid":[{"id":"b1","adm":"<div>"}]}]}`)
+	converted, err = ConvertBidderResponseDownTo25(unchanged)
+	assert.NoError(t, err)
+	assert.Equal(t, unchanged, converted, "A 2.5 response should be left alone")
+}
+
+func TestConvertResponseUpTo26(t *testing.T) {
+	converted, err := ConvertResponseUpTo26([]byte(`{"id":"resp","seatbid":[{"seat":"appnexus","bid":[{"id":"b1","adm":"<div>&</div>","ext":{"bidder":{"mtype":2,"slotinpod":1},"prebid":{"type":"video"}}}]}]}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"resp","seatbid":[{"seat":"appnexus","bid":[{"id":"b1","adm":"<div>&</div>","mtype":2,"slotinpod":1,"ext":{"prebid":{"type":"video"}}}]}]}`, string(converted))
+	assert.Contains(t, string(converted), `<div>&</div>`, "The markup shouldn't be HTML escaped")
+
+	_, err = ConvertResponseUpTo26([]byte(`{"seatbid":{"bid":1}}`))
+	assert.NoError(t, err, "Values which aren't objects or arrays are skipped")
+
+	_, err = ConvertResponseUpTo26([]byte(`null`))
+	assert.EqualError(t, err, "the response must be a JSON object")
+}
@@ -1,5 +1,7 @@
 package openrtb_ext
 
+import "github.com/PubMatic-OpenWrap/openrtb"
+
 // ExtPublisher defines the contract for ...publisher.ext (found in both bidrequest.site and bidrequest.app)
 type ExtPublisher struct {
 	Prebid *ExtPublisherPrebid `json:"prebid"`
@@ -11,3 +13,17 @@ type ExtPublisherPrebid struct {
 	// host. As such, the definition depends on the PBS hosting entity.
 	ParentAccount *string `json:"parentAccount,omitempty"`
 }
+
+// AccountID returns the publisher ID of the request's site or app, or an empty string if there isn't one.
+func AccountID(request *openrtb.BidRequest) string {
+	if request == nil {
+		return ""
+	}
+	if request.Site != nil && request.Site.Publisher != nil {
+		return request.Site.Publisher.ID
+	}
+	if request.App != nil && request.App.Publisher != nil {
+		return request.App.Publisher.ID
+	}
+	return ""
+}
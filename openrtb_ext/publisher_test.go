@@ -0,0 +1,41 @@
+package openrtb_ext
+
+import (
+	"testing"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountID(t *testing.T) {
+	testCases := []struct {
+		description string
+		request     *openrtb.BidRequest
+		expected    string
+	}{
+		{
+			description: "Nil Request",
+			request:     nil,
+			expected:    "",
+		},
+		{
+			description: "Site Publisher",
+			request:     &openrtb.BidRequest{Site: &openrtb.Site{Publisher: &openrtb.Publisher{ID: "site-pub"}}},
+			expected:    "site-pub",
+		},
+		{
+			description: "App Publisher",
+			request:     &openrtb.BidRequest{App: &openrtb.App{Publisher: &openrtb.Publisher{ID: "app-pub"}}},
+			expected:    "app-pub",
+		},
+		{
+			description: "No Publisher",
+			request:     &openrtb.BidRequest{Site: &openrtb.Site{}},
+			expected:    "",
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, AccountID(test.request), test.description)
+	}
+}
@@ -9,4 +9,10 @@ type ExtRegs struct {
 
 	// USPrivacy should be a four character string, see: https://iabtechlab.com/wp-content/uploads/2019/11/OpenRTB-Extension-U.S.-Privacy-IAB-Tech-Lab.pdf
 	USPrivacy string `json:"us_privacy,omitempty"`
+
+	// GPP is a Global Privacy Platform string, see: https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform
+	GPP string `json:"gpp,omitempty"`
+
+	// GPPSID lists the sections of the GPP string which apply to the request.
+	GPPSID []int8 `json:"gpp_sid,omitempty"`
 }
@@ -13,12 +13,53 @@ type ExtRequest struct {
 // ExtRequestPrebid defines the contract for bidrequest.ext.prebid
 type ExtRequestPrebid struct {
 	Aliases              map[string]string      `json:"aliases,omitempty"`
+	AliasGVLIDs          map[string]uint16      `json:"aliasgvlids,omitempty"`
 	BidAdjustmentFactors map[string]float64     `json:"bidadjustmentfactors,omitempty"`
 	Cache                *ExtRequestPrebidCache `json:"cache,omitempty"`
 	StoredRequest        *ExtStoredRequest      `json:"storedrequest,omitempty"`
 	Targeting            *ExtRequestTargeting   `json:"targeting,omitempty"`
 	Debug                int                    `json:"debug,omitempty"`
 	BidderParams         interface{}            `json:"bidderparams,omitempty"`
+	Data                 *ExtRequestPrebidData  `json:"data,omitempty"`
+	BidderConfigs        []ExtBidderConfig      `json:"bidderconfig,omitempty"`
+}
+
+// ExtRequestPrebidData defines the contract for bidrequest.ext.prebid.data
+type ExtRequestPrebidData struct {
+	// Bidders are the only bidders which get the first party data in site.ext.data, app.ext.data and user.ext.data.
+	// Every bidder gets it if this is nil.
+	Bidders []string `json:"bidders,omitempty"`
+
+	EidPermissions []ExtRequestPrebidDataEidPermission `json:"eidpermissions,omitempty"`
+}
+
+// ExtRequestPrebidDataEidPermission defines the contract for bidrequest.ext.prebid.data.eidpermissions[i]
+//
+// Only the Bidders get the user.ext.eids from the Source. "*" stands for every bidder.
+// The eids from sources without a permission go to every bidder.
+type ExtRequestPrebidDataEidPermission struct {
+	Source  string   `json:"source"`
+	Bidders []string `json:"bidders"`
+}
+
+// ExtBidderConfig defines the contract for bidrequest.ext.prebid.bidderconfig[i]
+type ExtBidderConfig struct {
+	Bidders []string            `json:"bidders"`
+	Config  *ExtBidderConfigFPD `json:"config"`
+}
+
+// ExtBidderConfigFPD defines the contract for bidrequest.ext.prebid.bidderconfig[i].config
+type ExtBidderConfigFPD struct {
+	ORTB2 *ExtBidderConfigORTB2 `json:"ortb2"`
+}
+
+// ExtBidderConfigORTB2 defines the contract for bidrequest.ext.prebid.bidderconfig[i].config.ortb2
+//
+// Each object is merged into the bidders' copy of the request's site, app or user as a JSON Merge Patch (RFC 7386).
+type ExtBidderConfigORTB2 struct {
+	Site json.RawMessage `json:"site,omitempty"`
+	App  json.RawMessage `json:"app,omitempty"`
+	User json.RawMessage `json:"user,omitempty"`
 }
 
 // ExtRequestPrebidCache defines the contract for bidrequest.ext.prebid.cache
@@ -56,6 +97,8 @@ type ExtRequestTargeting struct {
 	IncludeBidderKeys    bool                     `json:"includebidderkeys"`
 	IncludeBrandCategory *ExtIncludeBrandCategory `json:"includebrandcategory"`
 	DurationRangeSec     []int                    `json:"durationrangesec"`
+	PreferDeals          bool                     `json:"preferdeals"`
+	DealPriceGranularity *PriceGranularity        `json:"dealpricegranularity,omitempty"`
 }
 
 type ExtIncludeBrandCategory struct {
@@ -25,6 +25,22 @@ type ExtResponseDebug struct {
 	HttpCalls map[BidderName][]*ExtHttpCall `json:"httpcalls,omitempty"`
 	// Request after resolution of stored requests and debug overrides
 	ResolvedRequest *openrtb.BidRequest `json:"resolvedrequest,omitempty"`
+	// Eids defines the contract for bidresponse.ext.debug.eids. These are the user.ext.eids sources which each bidder got.
+	Eids map[BidderName][]string `json:"eids,omitempty"`
+	// Activities defines the contract for bidresponse.ext.debug.activities. It records how each activity control was decided.
+	Activities []ExtActivityTrace `json:"activities,omitempty"`
+}
+
+// ExtActivityTrace defines the contract for an entry of bidresponse.ext.debug.activities
+type ExtActivityTrace struct {
+	Activity string `json:"activity"`
+	// Component is "{type}.{name}", such as "bidder.appnexus".
+	Component string `json:"component"`
+	Allowed   bool   `json:"allowed"`
+	// Source is "account" if the request's account configures the activity, and "host" otherwise.
+	Source string `json:"source"`
+	// Rule is the index of the rule which decided. It's undefined if the activity's default did.
+	Rule *int `json:"rule,omitempty"`
 }
 
 // ExtResponseSyncData defines the contract for bidresponse.ext.usersync.{bidder}
@@ -0,0 +1,233 @@
+package pbs
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/ssl"
+)
+
+// The params which carry the proof that the user changed their preference.
+const (
+	recaptchaResponseParam = "g-recaptcha-response"
+	optOutTokenParam       = "optout_token"
+	optOutTokenCookieName  = "optout_token"
+)
+
+// ErrNoOptOutProof means the request didn't try to prove that the user made it. /optout then sends the user
+// to the opt out form, rather than changing their preference.
+var ErrNoOptOutProof = errors.New("the request has no proof that the user made it")
+
+// OptOutVerifier checks that a request to /optout came from the user, and not from a script or another site.
+type OptOutVerifier interface {
+	// Name identifies the verifier in the analytics.
+	Name() string
+	// Challenge returns the token which the next request must send back in the optout_token param, or "" if the
+	// verifier doesn't use one. It may set cookies on the response.
+	Challenge(w http.ResponseWriter) (string, error)
+	// Verify returns ErrNoOptOutProof if the request has no proof, and another error if the proof is invalid.
+	Verify(r *http.Request) error
+}
+
+// NewOptOutVerifier returns the verifier which cfg.OptOut picks.
+func NewOptOutVerifier(cfg *config.Configuration) OptOutVerifier {
+	switch cfg.OptOut.Verifier {
+	case config.OptOutVerifierNone:
+		return newNoOptOutVerifier(cfg.ExternalURL)
+	case config.OptOutVerifierToken:
+		return NewTokenVerifier(cfg.OptOut.TokenSecret, time.Duration(cfg.OptOut.TokenTTLSeconds)*time.Second, cfg.HostCookie.Domain)
+	default:
+		return NewRecaptchaVerifier(cfg.RecaptchaSecret)
+	}
+}
+
+// RecaptchaVerifier verifies a Google reCAPTCHA response with Google.
+type RecaptchaVerifier struct {
+	secret string
+	url    string
+	client *http.Client
+}
+
+// NewRecaptchaVerifier returns a verifier which checks reCAPTCHA responses with the host's secret.
+func NewRecaptchaVerifier(secret string) *RecaptchaVerifier {
+	return &RecaptchaVerifier{
+		secret: secret,
+		url:    RECAPTCHA_URL,
+		client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: ssl.GetRootCAPool()},
+			},
+		},
+	}
+}
+
+// Struct for parsing json in google's response
+type googleResponse struct {
+	Success    bool
+	ErrorCodes []string `json:"error-codes"`
+}
+
+func (v *RecaptchaVerifier) Name() string {
+	return config.OptOutVerifierRecaptcha
+}
+
+func (v *RecaptchaVerifier) Challenge(w http.ResponseWriter) (string, error) {
+	return "", nil
+}
+
+func (v *RecaptchaVerifier) Verify(r *http.Request) error {
+	response := r.FormValue(recaptchaResponseParam)
+	if response == "" {
+		return ErrNoOptOutProof
+	}
+
+	resp, err := v.client.PostForm(v.url,
+		url.Values{"secret": {v.secret}, "response": {response}})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var gr = googleResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
+		return err
+	}
+	if !gr.Success {
+		return fmt.Errorf("Captcha verify failed: %s", strings.Join(gr.ErrorCodes, ", "))
+	}
+	return nil
+}
+
+// TokenVerifier checks a signed token which it handed out earlier. The token is also stored in a cookie, and the
+// request must POST back the same one, so that another site can't make a valid request without reading the
+// cookie. The cookie is SameSite=Strict, and /optout isn't readable cross-origin, so other sites can't read
+// either. It needs no outbound calls.
+type TokenVerifier struct {
+	secret []byte
+	ttl    time.Duration
+	domain string
+	now    func() time.Time
+}
+
+// NewTokenVerifier returns a verifier whose tokens are signed with the secret, and expire after the ttl.
+// The cookie which holds the token is set on the domain, or on the request's host if it's empty.
+func NewTokenVerifier(secret string, ttl time.Duration, domain string) *TokenVerifier {
+	return &TokenVerifier{
+		secret: []byte(secret),
+		ttl:    ttl,
+		domain: domain,
+		now:    time.Now,
+	}
+}
+
+func (v *TokenVerifier) Name() string {
+	return config.OptOutVerifierToken
+}
+
+func (v *TokenVerifier) Challenge(w http.ResponseWriter) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	expires := v.now().Add(v.ttl)
+	unsigned := strconv.FormatInt(expires.Unix(), 10) + "." + hex.EncodeToString(nonce)
+	token := unsigned + "." + v.sign(unsigned)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     optOutTokenCookieName,
+		Value:    token,
+		Domain:   v.domain,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	return token, nil
+}
+
+func (v *TokenVerifier) Verify(r *http.Request) error {
+	token := r.FormValue(optOutTokenParam)
+	if token == "" || r.Method != http.MethodPost {
+		return ErrNoOptOutProof
+	}
+	if cookie, err := r.Cookie(optOutTokenCookieName); err != nil || !hmac.Equal([]byte(cookie.Value), []byte(token)) {
+		return errors.New("the optout_token doesn't match the cookie")
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("the optout_token is malformed")
+	}
+	if !hmac.Equal([]byte(v.sign(parts[0]+"."+parts[1])), []byte(parts[2])) {
+		return errors.New("the optout_token has an invalid signature")
+	}
+	expires, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return errors.New("the optout_token is malformed")
+	}
+	if v.now().Unix() > expires {
+		return errors.New("the optout_token has expired")
+	}
+	return nil
+}
+
+func (v *TokenVerifier) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, v.secret)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// noOptOutVerifier trusts every POST from the host's own pages. It rejects GETs, so that an image on another
+// page can't change the user's preference, and POSTs whose Origin, or Referer if there's no Origin, isn't the
+// external_url's, so that a form on another site can't either.
+type noOptOutVerifier struct {
+	origin string
+}
+
+func newNoOptOutVerifier(externalURL string) noOptOutVerifier {
+	return noOptOutVerifier{origin: urlOrigin(externalURL)}
+}
+
+func (noOptOutVerifier) Name() string {
+	return config.OptOutVerifierNone
+}
+
+func (noOptOutVerifier) Challenge(w http.ResponseWriter) (string, error) {
+	return "", nil
+}
+
+func (v noOptOutVerifier) Verify(r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return ErrNoOptOutProof
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		origin = urlOrigin(r.Referer())
+	}
+	if origin == "" || !strings.EqualFold(origin, v.origin) {
+		return errors.New("the request didn't come from the external_url's origin")
+	}
+	return nil
+}
+
+// urlOrigin returns the scheme and host of the URL, or "" if it isn't an absolute URL.
+func urlOrigin(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return ""
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
@@ -0,0 +1,178 @@
+package pbs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/analytics"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTokenSecret = "0123456789abcdef"
+
+func TestRecaptchaVerifier(t *testing.T) {
+	google := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("secret") == "secret" && r.FormValue("response") == "human" {
+			w.Write([]byte(`{"success":true}`))
+		} else {
+			w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+		}
+	}))
+	defer google.Close()
+	verifier := NewRecaptchaVerifier("secret")
+	verifier.url = google.URL
+
+	assert.Equal(t, ErrNoOptOutProof, verifier.Verify(httptest.NewRequest("POST", "/optout", nil)))
+	assert.NoError(t, verifier.Verify(newOptOutRequest(url.Values{recaptchaResponseParam: {"human"}}, nil)))
+	assert.EqualError(t, verifier.Verify(newOptOutRequest(url.Values{recaptchaResponseParam: {"robot"}}, nil)), "Captcha verify failed: invalid-input-response")
+}
+
+func TestTokenVerifier(t *testing.T) {
+	verifier := NewTokenVerifier(testTokenSecret, time.Hour, "")
+	w := httptest.NewRecorder()
+	token, err := verifier.Challenge(w)
+	assert.NoError(t, err)
+	cookie := tokenCookie(w)
+	assert.True(t, cookie.Secure)
+	assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+
+	assert.Equal(t, ErrNoOptOutProof, verifier.Verify(newOptOutRequest(nil, cookie)))
+	assert.NoError(t, verifier.Verify(newOptOutRequest(url.Values{optOutTokenParam: {token}}, cookie)))
+	assert.EqualError(t, verifier.Verify(newOptOutRequest(url.Values{optOutTokenParam: {token}}, nil)),
+		"the optout_token doesn't match the cookie", "Another site shouldn't be able to send the token without the cookie")
+
+	parts := strings.Split(token, ".")
+	forged := "9999999999." + parts[1] + "." + parts[2]
+	assert.EqualError(t, verifier.Verify(newOptOutRequest(url.Values{optOutTokenParam: {forged}}, &http.Cookie{Name: optOutTokenCookieName, Value: forged})),
+		"the optout_token has an invalid signature")
+
+	get := httptest.NewRequest("GET", "/optout?"+url.Values{optOutTokenParam: {token}}.Encode(), nil)
+	get.AddCookie(cookie)
+	assert.Equal(t, ErrNoOptOutProof, verifier.Verify(get), "A GET shouldn't be able to change the preference")
+
+	verifier.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	assert.EqualError(t, verifier.Verify(newOptOutRequest(url.Values{optOutTokenParam: {token}}, cookie)), "the optout_token has expired")
+}
+
+func TestNoOptOutVerifier(t *testing.T) {
+	verifier := NewOptOutVerifier(&config.Configuration{ExternalURL: "http://prebid.example.com/", OptOut: config.OptOut{Verifier: config.OptOutVerifierNone}})
+
+	assert.Equal(t, ErrNoOptOutProof, verifier.Verify(httptest.NewRequest("GET", "/optout?optout=1", nil)))
+	assert.NoError(t, verifier.Verify(newOptOutRequest(url.Values{"optout": {"1"}}, nil)))
+
+	referred := newOptOutRequest(url.Values{"optout": {"1"}}, nil)
+	referred.Header.Del("Origin")
+	referred.Header.Set("Referer", "http://prebid.example.com/static/optout.html")
+	assert.NoError(t, verifier.Verify(referred), "The Referer should be used if there's no Origin")
+
+	crossSite := newOptOutRequest(url.Values{"optout": {"1"}}, nil)
+	crossSite.Header.Set("Origin", "https://evil.example.com")
+	assert.EqualError(t, verifier.Verify(crossSite), "the request didn't come from the external_url's origin", "Another site's form shouldn't be trusted")
+
+	noOrigin := newOptOutRequest(url.Values{"optout": {"1"}}, nil)
+	noOrigin.Header.Del("Origin")
+	assert.Error(t, verifier.Verify(noOrigin), "A POST without an Origin or Referer shouldn't be trusted")
+}
+
+func TestOptOutJSON(t *testing.T) {
+	logged := &optOutAnalytics{}
+	deps := &UserSyncDeps{
+		OptOutVerifier:   NewTokenVerifier(testTokenSecret, time.Hour, ""),
+		HostCookieConfig: &config.HostCookie{OptOutURL: "http://example.com/optout", OptInURL: "http://example.com/optin"},
+		MetricsEngine:    &metricsConf.DummyMetricsEngine{},
+		PBSAnalytics:     logged,
+	}
+
+	w := httptest.NewRecorder()
+	deps.OptOut(w, httptest.NewRequest("GET", "/optout?format=json", nil), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"optout":false,"token":"`)
+	cookie := tokenCookie(w)
+	assert.False(t, logged.last.Changed)
+
+	w = httptest.NewRecorder()
+	deps.OptOut(w, newOptOutRequest(url.Values{"format": {"json"}, "optout": {"1"}, optOutTokenParam: {cookie.Value}}, cookie), nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"optout":true}`, w.Body.String())
+	assert.Equal(t, analytics.OptOutObject{Status: http.StatusOK, Errors: []error{}, Verifier: "token", OptOut: true, Changed: true}, *logged.last)
+	assert.False(t, uidsCookie(t, w).AllowSyncs(), "The user should be opted out")
+
+	w = httptest.NewRecorder()
+	deps.OptOut(w, newOptOutRequest(url.Values{"format": {"json"}, "optout": {"1"}, optOutTokenParam: {"bad"}}, cookie), nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"optout":false,"error":"the optout_token doesn't match the cookie"}`, w.Body.String())
+	assert.Equal(t, http.StatusUnauthorized, logged.last.Status)
+}
+
+func TestOptOutRedirects(t *testing.T) {
+	deps := &UserSyncDeps{
+		ExternalUrl:      "http://prebid.example.com",
+		OptOutVerifier:   newNoOptOutVerifier("http://prebid.example.com"),
+		HostCookieConfig: &config.HostCookie{OptOutURL: "http://example.com/optout", OptInURL: "http://example.com/optin"},
+		MetricsEngine:    &metricsConf.DummyMetricsEngine{},
+		PBSAnalytics:     &optOutAnalytics{},
+	}
+
+	w := httptest.NewRecorder()
+	deps.OptOut(w, httptest.NewRequest("GET", "/optout", nil), nil)
+	assert.Equal(t, "http://prebid.example.com/static/optout.html", w.Header().Get("Location"), "A request without proof should be sent to the form")
+
+	w = httptest.NewRecorder()
+	deps.OptOut(w, newOptOutRequest(url.Values{"optout": {"1"}}, nil), nil)
+	assert.Equal(t, "http://example.com/optout", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	deps.OptOut(w, newOptOutRequest(url.Values{}, nil), nil)
+	assert.Equal(t, "http://example.com/optin", w.Header().Get("Location"))
+	assert.True(t, uidsCookie(t, w).AllowSyncs())
+}
+
+func newOptOutRequest(form url.Values, cookie *http.Cookie) *http.Request {
+	r := httptest.NewRequest("POST", "/optout", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Origin", "http://prebid.example.com")
+	if cookie != nil {
+		r.AddCookie(cookie)
+	}
+	return r
+}
+
+func tokenCookie(w *httptest.ResponseRecorder) *http.Cookie {
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == optOutTokenCookieName {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func uidsCookie(t *testing.T, w *httptest.ResponseRecorder) *usersync.PBSCookie {
+	t.Helper()
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == usersync.UID_COOKIE_NAME {
+			parsed, err := usersync.ParsePBSCookie(cookie, nil)
+			assert.NoError(t, err)
+			return parsed
+		}
+	}
+	t.Fatalf("The response has no %s cookie", usersync.UID_COOKIE_NAME)
+	return nil
+}
+
+type optOutAnalytics struct {
+	last *analytics.OptOutObject
+}
+
+func (a *optOutAnalytics) LogAuctionObject(ao *analytics.AuctionObject)        {}
+func (a *optOutAnalytics) LogVideoObject(vo *analytics.VideoObject)            {}
+func (a *optOutAnalytics) LogCookieSyncObject(cso *analytics.CookieSyncObject) {}
+func (a *optOutAnalytics) LogSetUIDObject(so *analytics.SetUIDObject)          {}
+func (a *optOutAnalytics) LogAmpObject(ao *analytics.AmpObject)                {}
+func (a *optOutAnalytics) LogOptOutObject(oo *analytics.OptOutObject)          { a.last = oo }
@@ -9,7 +9,7 @@ import (
 	"testing"
 
 	"github.com/magiconair/properties/assert"
-	"github.com/PubMatic-OpenWrap/prebid-server/cache/dummycache"
+	"github.com/PubMatic-OpenWrap/prebid-server/cache/datacache"
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 )
 
@@ -72,7 +72,7 @@ func TestParseSimpleRequest(t *testing.T) {
     `)
 	r := httptest.NewRequest("POST", "/auction", bytes.NewBuffer(body))
 	r.Header.Add("Referer", "http://nytimes.com/cool.html")
-	d, _ := dummycache.New()
+	d := datacache.NewDummy()
 	hcc := config.HostCookie{}
 
 	pbs_req, err := ParsePBSRequest(r, &config.AuctionTimeouts{
@@ -146,7 +146,7 @@ func TestHeaderParsing(t *testing.T) {
 	r := httptest.NewRequest("POST", "/auction", bytes.NewBuffer(body))
 	r.Header.Add("Referer", "http://nytimes.com/cool.html")
 	r.Header.Add("User-Agent", "Mozilla/")
-	d, _ := dummycache.New()
+	d := datacache.NewDummy()
 	hcc := config.HostCookie{}
 
 	d.Config().Set("dummy", dummyConfig)
@@ -231,7 +231,7 @@ func TestParseConfig(t *testing.T) {
     `)
 	r := httptest.NewRequest("POST", "/auction", bytes.NewBuffer(body))
 	r.Header.Add("Referer", "http://nytimes.com/cool.html")
-	d, _ := dummycache.New()
+	d := datacache.NewDummy()
 	hcc := config.HostCookie{}
 
 	d.Config().Set("dummy", dummyConfig)
@@ -311,7 +311,7 @@ func TestParseMobileRequestFirstVersion(t *testing.T) {
 	}
     `)
 	r := httptest.NewRequest("POST", "/auction", bytes.NewBuffer(body))
-	d, _ := dummycache.New()
+	d := datacache.NewDummy()
 	hcc := config.HostCookie{}
 
 	pbs_req, err := ParsePBSRequest(r, &config.AuctionTimeouts{
@@ -410,7 +410,7 @@ func TestParseMobileRequest(t *testing.T) {
 	}
     `)
 	r := httptest.NewRequest("POST", "/auction", bytes.NewBuffer(body))
-	d, _ := dummycache.New()
+	d := datacache.NewDummy()
 	hcc := config.HostCookie{}
 
 	pbs_req, err := ParsePBSRequest(r, &config.AuctionTimeouts{
@@ -513,7 +513,7 @@ func TestParseMalformedMobileRequest(t *testing.T) {
 	}
     `)
 	r := httptest.NewRequest("POST", "/auction", bytes.NewBuffer(body))
-	d, _ := dummycache.New()
+	d := datacache.NewDummy()
 	hcc := config.HostCookie{}
 
 	pbs_req, err := ParsePBSRequest(r, &config.AuctionTimeouts{
@@ -620,7 +620,7 @@ func TestParseRequestWithInstl(t *testing.T) {
 	}
     `)
 	r := httptest.NewRequest("POST", "/auction", bytes.NewBuffer(body))
-	d, _ := dummycache.New()
+	d := datacache.NewDummy()
 	hcc := config.HostCookie{}
 
 	pbs_req, err := ParsePBSRequest(r, &config.AuctionTimeouts{
@@ -676,7 +676,7 @@ func doTimeoutTest(t *testing.T, expected int, requested int, max uint64, def ui
 		]
 }`, requested)
 	r := httptest.NewRequest("POST", "/auction", strings.NewReader(body))
-	d, _ := dummycache.New()
+	d := datacache.NewDummy()
 	parsed, err := ParsePBSRequest(r, cfg, d, &config.HostCookie{})
 	if err != nil {
 		t.Fatalf("Unexpected err: %v", err)
@@ -712,7 +712,7 @@ func TestParsePBSRequestUsesHostCookie(t *testing.T) {
 		t.Fatalf("new request failed")
 	}
 	r.AddCookie(&http.Cookie{Name: "key", Value: "testcookie"})
-	d, _ := dummycache.New()
+	d := datacache.NewDummy()
 	hcc := config.HostCookie{
 		CookieName: "key",
 		Family:     "family",
@@ -1,12 +1,9 @@
 package pbs
 
 import (
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/url"
-	"strings"
 	"time"
 
 	"github.com/golang/glog"
@@ -14,7 +11,6 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/analytics"
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
-	"github.com/PubMatic-OpenWrap/prebid-server/ssl"
 	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
 )
 
@@ -38,68 +34,86 @@ type uidWithExpiry struct {
 
 type UserSyncDeps struct {
 	ExternalUrl      string
-	RecaptchaSecret  string
+	OptOutVerifier   OptOutVerifier
 	HostCookieConfig *config.HostCookie
 	MetricsEngine    pbsmetrics.MetricsEngine
 	PBSAnalytics     analytics.PBSAnalyticsModule
 }
 
-// Struct for parsing json in google's response
-type googleResponse struct {
-	Success    bool
-	ErrorCodes []string `json:"error-codes"`
+// optOutResponse is the body of the /optout response if the request has format=json.
+type optOutResponse struct {
+	OptOut bool   `json:"optout"`
+	Token  string `json:"token,omitempty"`
+	Error  string `json:"error,omitempty"`
 }
 
-func (deps *UserSyncDeps) VerifyRecaptcha(response string) error {
-	ts := &http.Transport{
-		TLSClientConfig: &tls.Config{RootCAs: ssl.GetRootCAPool()},
+// OptOut changes the user's preference. The optout param opts them out if it's set, and back in if it isn't.
+// The request must prove that the user made it, or else the user is sent to the opt out form. If the request
+// has format=json, the endpoint answers with an optOutResponse rather than redirects.
+func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	oo := analytics.OptOutObject{
+		Status:   http.StatusOK,
+		Errors:   make([]error, 0),
+		Verifier: deps.OptOutVerifier.Name(),
 	}
 
-	client := &http.Client{
-		Transport: ts,
-	}
-	resp, err := client.PostForm(RECAPTCHA_URL,
-		url.Values{"secret": {deps.RecaptchaSecret}, "response": {response}})
+	defer deps.PBSAnalytics.LogOptOutObject(&oo)
+
+	wantsJSON := r.FormValue("format") == "json"
+	pc, err := usersync.ReadPBSCookieFromRequest(r, deps.HostCookieConfig)
 	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	var gr = googleResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&gr); err != nil {
-		return err
-	}
-	if !gr.Success {
-		return fmt.Errorf("Captcha verify failed: %s", strings.Join(gr.ErrorCodes, ", "))
+		deps.MetricsEngine.RecordUIDCookieError(usersync.CookieErrorReason(err))
 	}
-	return nil
-}
-
-func (deps *UserSyncDeps) OptOut(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
-	optout := r.FormValue("optout")
-	rr := r.FormValue("g-recaptcha-response")
+	oo.OptOut = !pc.AllowSyncs()
 
-	if rr == "" {
-		http.Redirect(w, r, fmt.Sprintf("%s/static/optout.html", deps.ExternalUrl), 301)
+	err = deps.OptOutVerifier.Verify(r)
+	if err == ErrNoOptOutProof {
+		if !wantsJSON {
+			http.Redirect(w, r, fmt.Sprintf("%s/static/optout.html", deps.ExternalUrl), 301)
+			return
+		}
+		token, err := deps.OptOutVerifier.Challenge(w)
+		if err != nil {
+			oo.Status = http.StatusInternalServerError
+			oo.Errors = append(oo.Errors, err)
+			writeOptOutResponse(w, oo.Status, optOutResponse{OptOut: oo.OptOut, Error: "Failed to create a token"})
+			return
+		}
+		writeOptOutResponse(w, http.StatusOK, optOutResponse{OptOut: oo.OptOut, Token: token})
 		return
 	}
-
-	err := deps.VerifyRecaptcha(rr)
 	if err != nil {
 		if glog.V(2) {
-			glog.Infof("Opt Out failed recaptcha: %v", err)
+			glog.Infof("Opt Out failed %s verification: %v", deps.OptOutVerifier.Name(), err)
+		}
+		oo.Status = http.StatusUnauthorized
+		oo.Errors = append(oo.Errors, err)
+		if wantsJSON {
+			writeOptOutResponse(w, oo.Status, optOutResponse{OptOut: oo.OptOut, Error: err.Error()})
+		} else {
+			w.WriteHeader(oo.Status)
 		}
-		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
 
-	pc := usersync.ParsePBSCookieFromRequest(r, deps.HostCookieConfig)
-	pc.SetPreference(optout == "")
+	optout := r.FormValue("optout") != ""
+	oo.Changed = oo.OptOut != optout
+	oo.OptOut = optout
+	pc.SetPreference(!optout)
 
 	pc.SetCookieOnResponse(w, false, "", deps.HostCookieConfig, deps.HostCookieConfig.TTLDuration())
 
-	if optout == "" {
-		http.Redirect(w, r, deps.HostCookieConfig.OptInURL, 301)
-	} else {
+	if wantsJSON {
+		writeOptOutResponse(w, http.StatusOK, optOutResponse{OptOut: optout})
+	} else if optout {
 		http.Redirect(w, r, deps.HostCookieConfig.OptOutURL, 301)
+	} else {
+		http.Redirect(w, r, deps.HostCookieConfig.OptInURL, 301)
 	}
 }
+
+func writeOptOutResponse(w http.ResponseWriter, status int, response optOutResponse) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
@@ -160,6 +160,27 @@ func (me *MultiMetricsEngine) RecordStoredImpCacheResult(cacheResult pbsmetrics.
 	}
 }
 
+// RecordAdapterBidRejected across all engines
+func (me *MultiMetricsEngine) RecordAdapterBidRejected(adapter openrtb_ext.BidderName, reason pbsmetrics.BidRejectReason) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterBidRejected(adapter, reason)
+	}
+}
+
+// RecordAdapterBidWarned across all engines
+func (me *MultiMetricsEngine) RecordAdapterBidWarned(adapter openrtb_ext.BidderName, reason pbsmetrics.BidRejectReason) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterBidWarned(adapter, reason)
+	}
+}
+
+// RecordAdapterConnections across all engines
+func (me *MultiMetricsEngine) RecordAdapterConnections(adapter openrtb_ext.BidderName, connWasReused bool, connWaitTime time.Duration) {
+	for _, thisME := range *me {
+		thisME.RecordAdapterConnections(adapter, connWasReused, connWaitTime)
+	}
+}
+
 // RecordAdapterCookieSync across all engines
 func (me *MultiMetricsEngine) RecordAdapterCookieSync(adapter openrtb_ext.BidderName, gdprBlocked bool) {
 	for _, thisME := range *me {
@@ -174,6 +195,13 @@ func (me *MultiMetricsEngine) RecordUserIDSet(userLabels pbsmetrics.UserLabels)
 	}
 }
 
+// RecordUIDCookieError across all engines
+func (me *MultiMetricsEngine) RecordUIDCookieError(reason pbsmetrics.CookieError) {
+	for _, thisME := range *me {
+		thisME.RecordUIDCookieError(reason)
+	}
+}
+
 // RecordPrebidCacheRequestTime across all engines
 func (me *MultiMetricsEngine) RecordPrebidCacheRequestTime(success bool, length time.Duration) {
 	for _, thisME := range *me {
@@ -232,6 +260,18 @@ func (me *DummyMetricsEngine) RecordAdapterTime(labels pbsmetrics.AdapterLabels,
 func (me *DummyMetricsEngine) RecordCookieSync() {
 }
 
+// RecordAdapterBidRejected as a noop
+func (me *DummyMetricsEngine) RecordAdapterBidRejected(adapter openrtb_ext.BidderName, reason pbsmetrics.BidRejectReason) {
+}
+
+// RecordAdapterBidWarned as a noop
+func (me *DummyMetricsEngine) RecordAdapterBidWarned(adapter openrtb_ext.BidderName, reason pbsmetrics.BidRejectReason) {
+}
+
+// RecordAdapterConnections as a noop
+func (me *DummyMetricsEngine) RecordAdapterConnections(adapter openrtb_ext.BidderName, connWasReused bool, connWaitTime time.Duration) {
+}
+
 // RecordAdapterCookieSync as a noop
 func (me *DummyMetricsEngine) RecordAdapterCookieSync(adapter openrtb_ext.BidderName, gdprBlocked bool) {
 }
@@ -240,6 +280,10 @@ func (me *DummyMetricsEngine) RecordAdapterCookieSync(adapter openrtb_ext.Bidder
 func (me *DummyMetricsEngine) RecordUserIDSet(userLabels pbsmetrics.UserLabels) {
 }
 
+// RecordUIDCookieError as a noop
+func (me *DummyMetricsEngine) RecordUIDCookieError(reason pbsmetrics.CookieError) {
+}
+
 // RecordStoredReqCacheResult as a noop
 func (me *DummyMetricsEngine) RecordStoredReqCacheResult(cacheResult pbsmetrics.CacheResult, inc int) {
 }
@@ -28,18 +28,22 @@ type Metrics struct {
 	PrebidCacheRequestTimerError   metrics.Timer
 	StoredReqCacheMeter            map[CacheResult]metrics.Meter
 	StoredImpCacheMeter            map[CacheResult]metrics.Meter
+	UIDCookieErrorMeter            map[CookieError]metrics.Meter
 
 	// Metrics for OpenRTB requests specifically. So we can track what % of RequestsMeter are OpenRTB
 	// and know when legacy requests have been abandoned.
-	RequestStatuses       map[RequestType]map[RequestStatus]metrics.Meter
-	AmpNoCookieMeter      metrics.Meter
-	CookieSyncMeter       metrics.Meter
-	CookieSyncGen         map[openrtb_ext.BidderName]metrics.Meter
-	CookieSyncGDPRPrevent map[openrtb_ext.BidderName]metrics.Meter
-	userSyncOptout        metrics.Meter
-	userSyncBadRequest    metrics.Meter
-	userSyncSet           map[openrtb_ext.BidderName]metrics.Meter
-	userSyncGDPRPrevent   map[openrtb_ext.BidderName]metrics.Meter
+	RequestStatuses         map[RequestType]map[RequestStatus]metrics.Meter
+	AmpNoCookieMeter        metrics.Meter
+	CookieSyncMeter         metrics.Meter
+	CookieSyncGen           map[openrtb_ext.BidderName]metrics.Meter
+	CookieSyncGDPRPrevent   map[openrtb_ext.BidderName]metrics.Meter
+	userSyncOptout          metrics.Meter
+	userSyncBadRequest      metrics.Meter
+	userSyncSet             map[openrtb_ext.BidderName]metrics.Meter
+	userSyncGDPRPrevent     map[openrtb_ext.BidderName]metrics.Meter
+	userSyncCCPAPrevent     map[openrtb_ext.BidderName]metrics.Meter
+	userSyncRateLimited     map[openrtb_ext.BidderName]metrics.Meter
+	userSyncActivityPrevent map[openrtb_ext.BidderName]metrics.Meter
 
 	// Media types found in the "imp" JSON object
 	ImpsTypeBanner metrics.Meter
@@ -69,6 +73,11 @@ type AdapterMetrics struct {
 	BidsReceivedMeter metrics.Meter
 	PanicMeter        metrics.Meter
 	MarkupMetrics     map[openrtb_ext.BidType]*MarkupDeliveryMetrics
+	RejectedBidMeters map[BidRejectReason]metrics.Meter
+	WarnedBidMeters   map[BidRejectReason]metrics.Meter
+	ConnCreated       metrics.Meter
+	ConnReused        metrics.Meter
+	ConnWaitTime      metrics.Timer
 }
 
 type MarkupDeliveryMetrics struct {
@@ -115,6 +124,7 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderNa
 		PrebidCacheRequestTimerError:   blankTimer,
 		StoredReqCacheMeter:            make(map[CacheResult]metrics.Meter),
 		StoredImpCacheMeter:            make(map[CacheResult]metrics.Meter),
+		UIDCookieErrorMeter:            make(map[CookieError]metrics.Meter),
 		AmpNoCookieMeter:               blankMeter,
 		CookieSyncMeter:                blankMeter,
 		CookieSyncGen:                  make(map[openrtb_ext.BidderName]metrics.Meter),
@@ -123,6 +133,9 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderNa
 		userSyncBadRequest:             blankMeter,
 		userSyncSet:                    make(map[openrtb_ext.BidderName]metrics.Meter),
 		userSyncGDPRPrevent:            make(map[openrtb_ext.BidderName]metrics.Meter),
+		userSyncCCPAPrevent:            make(map[openrtb_ext.BidderName]metrics.Meter),
+		userSyncRateLimited:            make(map[openrtb_ext.BidderName]metrics.Meter),
+		userSyncActivityPrevent:        make(map[openrtb_ext.BidderName]metrics.Meter),
 
 		ImpsTypeBanner: blankMeter,
 		ImpsTypeVideo:  blankMeter,
@@ -139,6 +152,10 @@ func NewBlankMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderNa
 		newMetrics.AdapterMetrics[a] = makeBlankAdapterMetrics()
 	}
 
+	for _, reason := range CookieErrors() {
+		newMetrics.UIDCookieErrorMeter[reason] = blankMeter
+	}
+
 	for _, t := range RequestTypes() {
 		newMetrics.RequestStatuses[t] = make(map[RequestStatus]metrics.Meter)
 		for _, s := range RequestStatuses() {
@@ -184,6 +201,9 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 		newMetrics.CookieSyncGDPRPrevent[a] = metrics.GetOrRegisterMeter(fmt.Sprintf("cookie_sync.%s.gdpr_prevent", string(a)), registry)
 		newMetrics.userSyncSet[a] = metrics.GetOrRegisterMeter(fmt.Sprintf("usersync.%s.sets", string(a)), registry)
 		newMetrics.userSyncGDPRPrevent[a] = metrics.GetOrRegisterMeter(fmt.Sprintf("usersync.%s.gdpr_prevent", string(a)), registry)
+		newMetrics.userSyncCCPAPrevent[a] = metrics.GetOrRegisterMeter(fmt.Sprintf("usersync.%s.ccpa_prevent", string(a)), registry)
+		newMetrics.userSyncRateLimited[a] = metrics.GetOrRegisterMeter(fmt.Sprintf("usersync.%s.rate_limited", string(a)), registry)
+		newMetrics.userSyncActivityPrevent[a] = metrics.GetOrRegisterMeter(fmt.Sprintf("usersync.%s.activity_prevent", string(a)), registry)
 		registerAdapterMetrics(registry, "adapter", string(a), newMetrics.AdapterMetrics[a])
 	}
 	for typ, statusMap := range newMetrics.RequestStatuses {
@@ -195,9 +215,15 @@ func NewMetrics(registry metrics.Registry, exchanges []openrtb_ext.BidderName, d
 		newMetrics.StoredReqCacheMeter[cacheRes] = metrics.GetOrRegisterMeter(fmt.Sprintf("stored_request_cache_%s", string(cacheRes)), registry)
 		newMetrics.StoredImpCacheMeter[cacheRes] = metrics.GetOrRegisterMeter(fmt.Sprintf("stored_imp_cache_%s", string(cacheRes)), registry)
 	}
+	for _, reason := range CookieErrors() {
+		newMetrics.UIDCookieErrorMeter[reason] = metrics.GetOrRegisterMeter(fmt.Sprintf("uid_cookie_%s", string(reason)), registry)
+	}
 
 	newMetrics.userSyncSet[unknownBidder] = metrics.GetOrRegisterMeter("usersync.unknown.sets", registry)
 	newMetrics.userSyncGDPRPrevent[unknownBidder] = metrics.GetOrRegisterMeter("usersync.unknown.gdpr_prevent", registry)
+	newMetrics.userSyncCCPAPrevent[unknownBidder] = metrics.GetOrRegisterMeter("usersync.unknown.ccpa_prevent", registry)
+	newMetrics.userSyncRateLimited[unknownBidder] = metrics.GetOrRegisterMeter("usersync.unknown.rate_limited", registry)
+	newMetrics.userSyncActivityPrevent[unknownBidder] = metrics.GetOrRegisterMeter("usersync.unknown.activity_prevent", registry)
 	return newMetrics
 }
 
@@ -214,10 +240,19 @@ func makeBlankAdapterMetrics() *AdapterMetrics {
 		BidsReceivedMeter: blankMeter,
 		PanicMeter:        blankMeter,
 		MarkupMetrics:     makeBlankBidMarkupMetrics(),
+		RejectedBidMeters: make(map[BidRejectReason]metrics.Meter),
+		WarnedBidMeters:   make(map[BidRejectReason]metrics.Meter),
+		ConnCreated:       blankMeter,
+		ConnReused:        blankMeter,
+		ConnWaitTime:      &metrics.NilTimer{},
 	}
 	for _, err := range AdapterErrors() {
 		newAdapter.ErrorMeters[err] = blankMeter
 	}
+	for _, reason := range BidRejectReasons() {
+		newAdapter.RejectedBidMeters[reason] = blankMeter
+		newAdapter.WarnedBidMeters[reason] = blankMeter
+	}
 	return newAdapter
 }
 
@@ -254,6 +289,16 @@ func registerAdapterMetrics(registry metrics.Registry, adapterOrAccount string,
 	}
 	if adapterOrAccount != "adapter" {
 		am.BidsReceivedMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.bids_received", adapterOrAccount, exchange), registry)
+	} else {
+		for reason := range am.RejectedBidMeters {
+			am.RejectedBidMeters[reason] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.bids_rejected.%s", adapterOrAccount, exchange, reason), registry)
+		}
+		for reason := range am.WarnedBidMeters {
+			am.WarnedBidMeters[reason] = metrics.GetOrRegisterMeter(fmt.Sprintf("%s.%s.bids_warned.%s", adapterOrAccount, exchange, reason), registry)
+		}
+		am.ConnCreated = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.connections_created", adapterOrAccount, exchange), registry)
+		am.ConnReused = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.connections_reused", adapterOrAccount, exchange), registry)
+		am.ConnWaitTime = metrics.GetOrRegisterTimer(fmt.Sprintf("%[1]s.%[2]s.connection_wait_time", adapterOrAccount, exchange), registry)
 	}
 	am.PanicMeter = metrics.GetOrRegisterMeter(fmt.Sprintf("%[1]s.%[2]s.requests.panic", adapterOrAccount, exchange), registry)
 }
@@ -482,6 +527,46 @@ func (me *Metrics) RecordCookieSync() {
 	me.CookieSyncMeter.Mark(1)
 }
 
+// RecordAdapterBidRejected implements a part of the MetricsEngine interface. Records a bid removed by the exchange after the adapter returned it
+func (me *Metrics) RecordAdapterBidRejected(adapter openrtb_ext.BidderName, reason BidRejectReason) {
+	am, ok := me.AdapterMetrics[adapter]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", string(adapter))
+		return
+	}
+	if meter, ok := am.RejectedBidMeters[reason]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordAdapterBidWarned implements a part of the MetricsEngine interface. Records a bid which failed a check the exchange only warns about
+func (me *Metrics) RecordAdapterBidWarned(adapter openrtb_ext.BidderName, reason BidRejectReason) {
+	am, ok := me.AdapterMetrics[adapter]
+	if !ok {
+		glog.Errorf("Trying to run adapter metrics on %s: adapter metrics not found", string(adapter))
+		return
+	}
+	if meter, ok := am.WarnedBidMeters[reason]; ok {
+		meter.Mark(1)
+	}
+}
+
+// RecordAdapterConnections implements a part of the MetricsEngine interface. Records whether the HTTP call to the
+// adapter reused an idle connection, and how long it waited to get one
+func (me *Metrics) RecordAdapterConnections(adapter openrtb_ext.BidderName, connWasReused bool, connWaitTime time.Duration) {
+	am, ok := me.AdapterMetrics[adapter]
+	if !ok {
+		glog.Errorf("Trying to run adapter connection metrics on %s: adapter metrics not found", string(adapter))
+		return
+	}
+	if connWasReused {
+		am.ConnReused.Mark(1)
+	} else {
+		am.ConnCreated.Mark(1)
+	}
+	am.ConnWaitTime.Update(connWaitTime)
+}
+
 // RecordAdapterCookieSync implements a part of the MetricsEngine interface. Records a cookie sync adpter sync request and gdpr status
 func (me *Metrics) RecordAdapterCookieSync(adapter openrtb_ext.BidderName, gdprBlocked bool) {
 	me.CookieSyncGen[adapter].Mark(1)
@@ -501,6 +586,20 @@ func (me *Metrics) RecordUserIDSet(userLabels UserLabels) {
 		doMark(userLabels.Bidder, me.userSyncSet)
 	case RequestActionGDPR:
 		doMark(userLabels.Bidder, me.userSyncGDPRPrevent)
+	case RequestActionCCPA:
+		doMark(userLabels.Bidder, me.userSyncCCPAPrevent)
+	case RequestActionRateLimited:
+		doMark(userLabels.Bidder, me.userSyncRateLimited)
+	case RequestActionActivity:
+		doMark(userLabels.Bidder, me.userSyncActivityPrevent)
+	}
+}
+
+// RecordUIDCookieError implements a part of the MetricsEngine interface. Records the uids cookies
+// which couldn't be used
+func (me *Metrics) RecordUIDCookieError(reason CookieError) {
+	if meter, ok := me.UIDCookieErrorMeter[reason]; ok {
+		meter.Mark(1)
 	}
 }
 
@@ -2,6 +2,7 @@ package pbsmetrics
 
 import (
 	"testing"
+	"time"
 
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
@@ -27,6 +28,14 @@ func TestNewMetrics(t *testing.T) {
 	ensureContains(t, registry, "usersync.appnexus.gdpr_prevent", m.userSyncGDPRPrevent["appnexus"])
 	ensureContains(t, registry, "usersync.rubicon.gdpr_prevent", m.userSyncGDPRPrevent["rubicon"])
 	ensureContains(t, registry, "usersync.unknown.gdpr_prevent", m.userSyncGDPRPrevent["unknown"])
+	ensureContains(t, registry, "uid_cookie_tampered", m.UIDCookieErrorMeter[CookieErrorTampered])
+	ensureContains(t, registry, "uid_cookie_invalid", m.UIDCookieErrorMeter[CookieErrorInvalid])
+	ensureContains(t, registry, "usersync.appnexus.ccpa_prevent", m.userSyncCCPAPrevent["appnexus"])
+	ensureContains(t, registry, "usersync.unknown.ccpa_prevent", m.userSyncCCPAPrevent["unknown"])
+	ensureContains(t, registry, "usersync.appnexus.rate_limited", m.userSyncRateLimited["appnexus"])
+	ensureContains(t, registry, "usersync.unknown.rate_limited", m.userSyncRateLimited["unknown"])
+	ensureContains(t, registry, "usersync.appnexus.activity_prevent", m.userSyncActivityPrevent["appnexus"])
+	ensureContains(t, registry, "usersync.unknown.activity_prevent", m.userSyncActivityPrevent["unknown"])
 	ensureContains(t, registry, "prebid_cache_request_time.ok", m.PrebidCacheRequestTimerSuccess)
 	ensureContains(t, registry, "prebid_cache_request_time.err", m.PrebidCacheRequestTimerError)
 
@@ -69,6 +78,44 @@ func TestRecordBidType(t *testing.T) {
 	VerifyMetrics(t, "Appnexus Video Nurl Bids", m.AdapterMetrics[openrtb_ext.BidderAppnexus].MarkupMetrics[openrtb_ext.BidTypeVideo].NurlMeter.Count(), 1)
 }
 
+func TestRecordAdapterBidRejected(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{})
+
+	m.RecordAdapterBidRejected(openrtb_ext.BidderAppnexus, BidRejectBlockedCategory)
+	ensureContains(t, registry, "adapter.appnexus.bids_rejected.blocked_category", m.AdapterMetrics[openrtb_ext.BidderAppnexus].RejectedBidMeters[BidRejectBlockedCategory])
+	VerifyMetrics(t, "Appnexus Blocked Category Bids", m.AdapterMetrics[openrtb_ext.BidderAppnexus].RejectedBidMeters[BidRejectBlockedCategory].Count(), 1)
+	VerifyMetrics(t, "Appnexus Blocked Advertiser Bids", m.AdapterMetrics[openrtb_ext.BidderAppnexus].RejectedBidMeters[BidRejectBlockedAdvertiser].Count(), 0)
+}
+
+func TestRecordAdapterBidWarned(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{})
+
+	m.RecordAdapterBidWarned(openrtb_ext.BidderAppnexus, BidRejectInvalidSize)
+	ensureContains(t, registry, "adapter.appnexus.bids_warned.invalid_size", m.AdapterMetrics[openrtb_ext.BidderAppnexus].WarnedBidMeters[BidRejectInvalidSize])
+	VerifyMetrics(t, "Appnexus Invalid Size Warnings", m.AdapterMetrics[openrtb_ext.BidderAppnexus].WarnedBidMeters[BidRejectInvalidSize].Count(), 1)
+	VerifyMetrics(t, "Appnexus Invalid Size Rejections", m.AdapterMetrics[openrtb_ext.BidderAppnexus].RejectedBidMeters[BidRejectInvalidSize].Count(), 0)
+}
+
+func TestRecordAdapterConnections(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{})
+
+	m.RecordAdapterConnections(openrtb_ext.BidderAppnexus, true, 2*time.Millisecond)
+	m.RecordAdapterConnections(openrtb_ext.BidderAppnexus, false, 10*time.Millisecond)
+	m.RecordAdapterConnections(openrtb_ext.BidderAppnexus, true, 0)
+
+	am := m.AdapterMetrics[openrtb_ext.BidderAppnexus]
+	ensureContains(t, registry, "adapter.appnexus.connections_created", am.ConnCreated)
+	ensureContains(t, registry, "adapter.appnexus.connections_reused", am.ConnReused)
+	ensureContains(t, registry, "adapter.appnexus.connection_wait_time", am.ConnWaitTime)
+	VerifyMetrics(t, "Appnexus Connections Created", am.ConnCreated.Count(), 1)
+	VerifyMetrics(t, "Appnexus Connections Reused", am.ConnReused.Count(), 2)
+	VerifyMetrics(t, "Appnexus Connection Wait Time Count", am.ConnWaitTime.Count(), 3)
+	VerifyMetrics(t, "Appnexus Connection Wait Time Max", am.ConnWaitTime.Max(), int64(10*time.Millisecond))
+}
+
 func TestRecordGDPRRejection(t *testing.T) {
 	registry := metrics.NewRegistry()
 	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{})
@@ -79,6 +126,31 @@ func TestRecordGDPRRejection(t *testing.T) {
 	VerifyMetrics(t, "GDPR sync rejects", m.userSyncGDPRPrevent[openrtb_ext.BidderAppnexus].Count(), 1)
 }
 
+func TestRecordCCPARejectionAndRateLimit(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{})
+	m.RecordUserIDSet(UserLabels{
+		Action: RequestActionCCPA,
+		Bidder: openrtb_ext.BidderAppnexus,
+	})
+	m.RecordUserIDSet(UserLabels{
+		Action: RequestActionRateLimited,
+		Bidder: openrtb_ext.BidderAppnexus,
+	})
+	VerifyMetrics(t, "CCPA sync rejects", m.userSyncCCPAPrevent[openrtb_ext.BidderAppnexus].Count(), 1)
+	VerifyMetrics(t, "Rate limited syncs", m.userSyncRateLimited[openrtb_ext.BidderAppnexus].Count(), 1)
+}
+
+func TestRecordActivityRejection(t *testing.T) {
+	registry := metrics.NewRegistry()
+	m := NewMetrics(registry, []openrtb_ext.BidderName{openrtb_ext.BidderAppnexus}, config.DisabledMetrics{})
+	m.RecordUserIDSet(UserLabels{
+		Action: RequestActionActivity,
+		Bidder: openrtb_ext.BidderAppnexus,
+	})
+	VerifyMetrics(t, "Activity control sync rejects", m.userSyncActivityPrevent[openrtb_ext.BidderAppnexus].Count(), 1)
+}
+
 func ensureContains(t *testing.T, registry metrics.Registry, name string, metric interface{}) {
 	t.Helper()
 	if inRegistry := registry.Get(name); inRegistry == nil {
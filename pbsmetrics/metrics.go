@@ -70,6 +70,9 @@ type AdapterError string
 // CacheResult : Cache hit/miss
 type CacheResult string
 
+// BidRejectReason : The reason the exchange removed a bid returned by the adapter
+type BidRejectReason string
+
 // PublisherUnknown : Default value for Labels.PubID
 const PublisherUnknown = "unknown"
 
@@ -203,6 +206,29 @@ func AdapterErrors() []AdapterError {
 	}
 }
 
+// Bid rejection reasons
+const (
+	BidRejectBlockedAdvertiser BidRejectReason = "blocked_advertiser"
+	BidRejectBlockedCategory   BidRejectReason = "blocked_category"
+	BidRejectBlockedAttribute  BidRejectReason = "blocked_attribute"
+	BidRejectMissingAdomain    BidRejectReason = "missing_adomain"
+	BidRejectInsecureCreative  BidRejectReason = "insecure_creative"
+	BidRejectInvalidSize       BidRejectReason = "invalid_size"
+	BidRejectInvalidVast       BidRejectReason = "invalid_vast"
+)
+
+func BidRejectReasons() []BidRejectReason {
+	return []BidRejectReason{
+		BidRejectBlockedAdvertiser,
+		BidRejectBlockedCategory,
+		BidRejectBlockedAttribute,
+		BidRejectMissingAdomain,
+		BidRejectInsecureCreative,
+		BidRejectInvalidSize,
+		BidRejectInvalidVast,
+	}
+}
+
 const (
 	// CacheHit represents a cache hit i.e the key was found in cache
 	CacheHit CacheResult = "hit"
@@ -219,6 +245,25 @@ func CacheResults() []CacheResult {
 	}
 }
 
+// CookieError : The reason the uids cookie in a request couldn't be used
+type CookieError string
+
+// uids cookie errors
+const (
+	// CookieErrorInvalid means the cookie couldn't be decoded
+	CookieErrorInvalid CookieError = "invalid"
+	// CookieErrorTampered means the cookie's signature didn't match, or it wasn't signed when it had to be
+	CookieErrorTampered CookieError = "tampered"
+)
+
+// CookieErrors returns the possible uids cookie errors
+func CookieErrors() []CookieError {
+	return []CookieError{
+		CookieErrorInvalid,
+		CookieErrorTampered,
+	}
+}
+
 // UserLabels : Labels for /setuid endpoint
 type UserLabels struct {
 	Action RequestAction
@@ -230,10 +275,13 @@ type RequestAction string
 
 // /setuid action labels
 const (
-	RequestActionSet    RequestAction = "set"
-	RequestActionOptOut RequestAction = "opt_out"
-	RequestActionGDPR   RequestAction = "gdpr"
-	RequestActionErr    RequestAction = "err"
+	RequestActionSet         RequestAction = "set"
+	RequestActionOptOut      RequestAction = "opt_out"
+	RequestActionGDPR        RequestAction = "gdpr"
+	RequestActionCCPA        RequestAction = "ccpa"
+	RequestActionRateLimited RequestAction = "rate_limited"
+	RequestActionActivity    RequestAction = "activity"
+	RequestActionErr         RequestAction = "err"
 )
 
 // RequestActions returns possible setuid action labels
@@ -242,6 +290,9 @@ func RequestActions() []RequestAction {
 		RequestActionSet,
 		RequestActionOptOut,
 		RequestActionGDPR,
+		RequestActionCCPA,
+		RequestActionRateLimited,
+		RequestActionActivity,
 		RequestActionErr,
 	}
 }
@@ -266,9 +317,13 @@ type MetricsEngine interface {
 	RecordAdapterBidReceived(labels AdapterLabels, bidType openrtb_ext.BidType, hasAdm bool)
 	RecordAdapterPrice(labels AdapterLabels, cpm float64)
 	RecordAdapterTime(labels AdapterLabels, length time.Duration)
+	RecordAdapterBidRejected(adapter openrtb_ext.BidderName, reason BidRejectReason)
+	RecordAdapterBidWarned(adapter openrtb_ext.BidderName, reason BidRejectReason)
+	RecordAdapterConnections(adapter openrtb_ext.BidderName, connWasReused bool, connWaitTime time.Duration)
 	RecordCookieSync()
 	RecordAdapterCookieSync(adapter openrtb_ext.BidderName, gdprBlocked bool)
 	RecordUserIDSet(userLabels UserLabels) // Function should verify bidder values
+	RecordUIDCookieError(reason CookieError)
 	RecordStoredReqCacheResult(cacheResult CacheResult, inc int)
 	RecordStoredImpCacheResult(cacheResult CacheResult, inc int)
 	RecordPrebidCacheRequestTime(success bool, length time.Duration)
@@ -72,6 +72,21 @@ func (me *MetricsEngineMock) RecordCookieSync() {
 	me.Called()
 }
 
+// RecordAdapterBidRejected mock
+func (me *MetricsEngineMock) RecordAdapterBidRejected(adapter openrtb_ext.BidderName, reason BidRejectReason) {
+	me.Called(adapter, reason)
+}
+
+// RecordAdapterBidWarned mock
+func (me *MetricsEngineMock) RecordAdapterBidWarned(adapter openrtb_ext.BidderName, reason BidRejectReason) {
+	me.Called(adapter, reason)
+}
+
+// RecordAdapterConnections mock
+func (me *MetricsEngineMock) RecordAdapterConnections(adapter openrtb_ext.BidderName, connWasReused bool, connWaitTime time.Duration) {
+	me.Called(adapter, connWasReused, connWaitTime)
+}
+
 // RecordAdapterCookieSync mock
 func (me *MetricsEngineMock) RecordAdapterCookieSync(adapter openrtb_ext.BidderName, gdprBlocked bool) {
 	me.Called(adapter, gdprBlocked)
@@ -82,6 +97,11 @@ func (me *MetricsEngineMock) RecordUserIDSet(userLabels UserLabels) {
 	me.Called(userLabels)
 }
 
+// RecordUIDCookieError mock
+func (me *MetricsEngineMock) RecordUIDCookieError(reason CookieError) {
+	me.Called(reason)
+}
+
 // RecordStoredReqCacheResult mock
 func (me *MetricsEngineMock) RecordStoredReqCacheResult(cacheResult CacheResult, inc int) {
 	me.Called(cacheResult, inc)
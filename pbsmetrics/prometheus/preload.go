@@ -13,6 +13,7 @@ func preloadLabelValues(m *Metrics) {
 		boolValues            = boolValuesAsString()
 		cacheResultValues     = cacheResultsAsString()
 		cookieValues          = cookieTypesAsString()
+		cookieErrorValues     = cookieErrorsAsString()
 		connectionErrorValues = []string{connectionAcceptError, connectionCloseError}
 		requestStatusValues   = requestStatusesAsString()
 		requestTypeValues     = requestTypesAsString()
@@ -54,11 +55,19 @@ func preloadLabelValues(m *Metrics) {
 		cacheResultLabel: cacheResultValues,
 	})
 
+	preloadLabelValuesForCounter(m.uidCookieErrors, map[string][]string{
+		cookieErrorLabel: cookieErrorValues,
+	})
+
 	preloadLabelValuesForCounter(m.adapterBids, map[string][]string{
 		adapterLabel:        adapterValues,
 		markupDeliveryLabel: bidTypeValues,
 	})
 
+	// adapterBidsRejected and adapterBidsWarned are not preloaded. Rejections should be rare, and preloading every adapter and reason
+	// combination would be costly for a metric which stays empty on most hosts.
+	// The adapter connection metrics are not preloaded either, since hosts need to opt into them.
+
 	preloadLabelValuesForCounter(m.adapterCookieSync, map[string][]string{
 		adapterLabel:        adapterValues,
 		privacyBlockedLabel: boolValues,
@@ -27,16 +27,23 @@ type Metrics struct {
 	requestsWithoutCookie        *prometheus.CounterVec
 	storedImpressionsCacheResult *prometheus.CounterVec
 	storedRequestCacheResult     *prometheus.CounterVec
+	uidCookieErrors              *prometheus.CounterVec
+	userSyncActivityPrevented    prometheus.Counter
 
 	// Adapter Metrics
-	adapterBids          *prometheus.CounterVec
-	adapterCookieSync    *prometheus.CounterVec
-	adapterErrors        *prometheus.CounterVec
-	adapterPanics        *prometheus.CounterVec
-	adapterPrices        *prometheus.HistogramVec
-	adapterRequests      *prometheus.CounterVec
-	adapterRequestsTimer *prometheus.HistogramVec
-	adapterUserSync      *prometheus.CounterVec
+	adapterBids               *prometheus.CounterVec
+	adapterBidsRejected       *prometheus.CounterVec
+	adapterBidsWarned         *prometheus.CounterVec
+	adapterConnectionsCreated *prometheus.CounterVec
+	adapterConnectionsReused  *prometheus.CounterVec
+	adapterConnectionWaitTime *prometheus.HistogramVec
+	adapterCookieSync         *prometheus.CounterVec
+	adapterErrors             *prometheus.CounterVec
+	adapterPanics             *prometheus.CounterVec
+	adapterPrices             *prometheus.HistogramVec
+	adapterRequests           *prometheus.CounterVec
+	adapterRequestsTimer      *prometheus.HistogramVec
+	adapterUserSync           *prometheus.CounterVec
 
 	// Account Metrics
 	accountRequests *prometheus.CounterVec
@@ -51,6 +58,7 @@ const (
 	cacheResultLabel     = "cache_result"
 	connectionErrorLabel = "connection_error"
 	cookieLabel          = "cookie"
+	cookieErrorLabel     = "cookie_error"
 	hasBidsLabel         = "has_bids"
 	isAudioLabel         = "audio"
 	isBannerLabel        = "banner"
@@ -58,6 +66,7 @@ const (
 	isVideoLabel         = "video"
 	markupDeliveryLabel  = "delivery"
 	privacyBlockedLabel  = "privacy_blocked"
+	rejectReasonLabel    = "reject_reason"
 	requestStatusLabel   = "request_status"
 	requestTypeLabel     = "request_type"
 	successLabel         = "success"
@@ -99,6 +108,10 @@ func NewMetrics(cfg config.PrometheusMetrics) *Metrics {
 		"cookie_sync_requests",
 		"Count of cookie sync requests to Prebid Server.")
 
+	metrics.userSyncActivityPrevented = newCounterWithoutLabels(cfg, metrics.Registry,
+		"usersync_activity_prevented",
+		"Count of setuid requests which the activity controls prevented from saving a cookie.")
+
 	metrics.impressions = newCounter(cfg, metrics.Registry,
 		"impressions_requests",
 		"Count of requested impressions to Prebid Server labeled by type.",
@@ -140,11 +153,42 @@ func NewMetrics(cfg config.PrometheusMetrics) *Metrics {
 		"Count of stored request cache requests attempts by hits or miss.",
 		[]string{cacheResultLabel})
 
+	metrics.uidCookieErrors = newCounter(cfg, metrics.Registry,
+		"uid_cookie_errors",
+		"Count of uids cookies which couldn't be used, labeled by whether they were invalid or tampered with.",
+		[]string{cookieErrorLabel})
+
 	metrics.adapterBids = newCounter(cfg, metrics.Registry,
 		"adapter_bids",
 		"Count of bids labeled by adapter and markup delivery type (adm or nurl).",
 		[]string{adapterLabel, markupDeliveryLabel})
 
+	metrics.adapterBidsRejected = newCounter(cfg, metrics.Registry,
+		"adapter_bids_rejected",
+		"Count of bids removed by Prebid Server after the adapter returned them, labeled by adapter and reason.",
+		[]string{adapterLabel, rejectReasonLabel})
+
+	metrics.adapterBidsWarned = newCounter(cfg, metrics.Registry,
+		"adapter_bids_warned",
+		"Count of bids which failed a check Prebid Server only warns about, labeled by adapter and reason.",
+		[]string{adapterLabel, rejectReasonLabel})
+
+	metrics.adapterConnectionsCreated = newCounter(cfg, metrics.Registry,
+		"adapter_connections_created",
+		"Count of new connections opened to the adapter's servers, labeled by adapter.",
+		[]string{adapterLabel})
+
+	metrics.adapterConnectionsReused = newCounter(cfg, metrics.Registry,
+		"adapter_connections_reused",
+		"Count of idle connections reused for calls to the adapter's servers, labeled by adapter.",
+		[]string{adapterLabel})
+
+	metrics.adapterConnectionWaitTime = newHistogram(cfg, metrics.Registry,
+		"adapter_connection_wait_seconds",
+		"Seconds waited to get a connection to the adapter's servers, labeled by adapter.",
+		[]string{adapterLabel},
+		cacheWriteTimeBuckts)
+
 	metrics.adapterCookieSync = newCounter(cfg, metrics.Registry,
 		"adapter_cookie_sync",
 		"Count of cookie sync requests received labeled by adapter and if the sync was blocked due to privacy regulation (GDPR, CCPA, etc...).",
@@ -336,6 +380,32 @@ func (m *Metrics) RecordAdapterTime(labels pbsmetrics.AdapterLabels, length time
 	}
 }
 
+func (m *Metrics) RecordAdapterBidRejected(adapter openrtb_ext.BidderName, reason pbsmetrics.BidRejectReason) {
+	m.adapterBidsRejected.With(prometheus.Labels{
+		adapterLabel:      string(adapter),
+		rejectReasonLabel: string(reason),
+	}).Inc()
+}
+
+func (m *Metrics) RecordAdapterBidWarned(adapter openrtb_ext.BidderName, reason pbsmetrics.BidRejectReason) {
+	m.adapterBidsWarned.With(prometheus.Labels{
+		adapterLabel:      string(adapter),
+		rejectReasonLabel: string(reason),
+	}).Inc()
+}
+
+func (m *Metrics) RecordAdapterConnections(adapter openrtb_ext.BidderName, connWasReused bool, connWaitTime time.Duration) {
+	labels := prometheus.Labels{
+		adapterLabel: string(adapter),
+	}
+	if connWasReused {
+		m.adapterConnectionsReused.With(labels).Inc()
+	} else {
+		m.adapterConnectionsCreated.With(labels).Inc()
+	}
+	m.adapterConnectionWaitTime.With(labels).Observe(connWaitTime.Seconds())
+}
+
 func (m *Metrics) RecordCookieSync() {
 	m.cookieSync.Inc()
 }
@@ -348,6 +418,12 @@ func (m *Metrics) RecordAdapterCookieSync(adapter openrtb_ext.BidderName, privac
 }
 
 func (m *Metrics) RecordUserIDSet(labels pbsmetrics.UserLabels) {
+	// The activity control rejections aren't labeled by adapter, to keep the per-adapter cardinality down.
+	if labels.Action == pbsmetrics.RequestActionActivity {
+		m.userSyncActivityPrevented.Inc()
+		return
+	}
+
 	adapter := string(labels.Bidder)
 	if adapter != "" {
 		m.adapterUserSync.With(prometheus.Labels{
@@ -357,6 +433,12 @@ func (m *Metrics) RecordUserIDSet(labels pbsmetrics.UserLabels) {
 	}
 }
 
+func (m *Metrics) RecordUIDCookieError(reason pbsmetrics.CookieError) {
+	m.uidCookieErrors.With(prometheus.Labels{
+		cookieErrorLabel: string(reason),
+	}).Inc()
+}
+
 func (m *Metrics) RecordStoredReqCacheResult(cacheResult pbsmetrics.CacheResult, inc int) {
 	m.storedRequestCacheResult.With(prometheus.Labels{
 		cacheResultLabel: string(cacheResult),
@@ -61,7 +61,7 @@ func TestMetricCountGatekeeping(t *testing.T) {
 	// Verify Per-Adapter Cardinality
 	// - This assertion provides a warning for newly added adapter metrics. Threre are 40+ adapters which makes the
 	//   cost of new per-adapter metrics rather expensive. Thought should be given when adding new per-adapter metrics.
-	assert.True(t, perAdapterCardinalityCount <= 22, "Per-Adapter Cardinality")
+	assert.True(t, perAdapterCardinalityCount <= 24, "Per-Adapter Cardinality")
 }
 
 func TestConnectionMetrics(t *testing.T) {
@@ -769,6 +769,23 @@ func TestUserIDSetMetric(t *testing.T) {
 		})
 }
 
+func TestUserIDSetMetricActivityPrevented(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordUserIDSet(pbsmetrics.UserLabels{
+		Bidder: openrtb_ext.BidderName("anyName"),
+		Action: pbsmetrics.RequestActionActivity,
+	})
+
+	assertCounterValue(t, "", "userSyncActivityPrevented", m.userSyncActivityPrevented, 1)
+
+	actualTotalCount := float64(0)
+	processMetrics(m.adapterUserSync, func(m dto.Metric) {
+		actualTotalCount += m.GetCounter().GetValue()
+	})
+	assert.Equal(t, float64(0), actualTotalCount, "adapter user sync count")
+}
+
 func TestUserIDSetMetricWhenBidderEmpty(t *testing.T) {
 	m := createMetricsForTesting()
 	action := pbsmetrics.RequestActionErr
@@ -802,6 +819,64 @@ func TestAdapterPanicMetric(t *testing.T) {
 		})
 }
 
+func TestAdapterBidRejectedMetric(t *testing.T) {
+	m := createMetricsForTesting()
+	adapterName := "anyName"
+
+	m.RecordAdapterBidRejected(openrtb_ext.BidderName(adapterName), pbsmetrics.BidRejectBlockedAdvertiser)
+	m.RecordAdapterBidRejected(openrtb_ext.BidderName(adapterName), pbsmetrics.BidRejectBlockedAdvertiser)
+	m.RecordAdapterBidRejected(openrtb_ext.BidderName(adapterName), pbsmetrics.BidRejectMissingAdomain)
+
+	assertCounterVecValue(t, "", "adapterBidsRejected:blocked_advertiser", m.adapterBidsRejected,
+		2,
+		prometheus.Labels{
+			adapterLabel:      adapterName,
+			rejectReasonLabel: string(pbsmetrics.BidRejectBlockedAdvertiser),
+		})
+	assertCounterVecValue(t, "", "adapterBidsRejected:missing_adomain", m.adapterBidsRejected,
+		1,
+		prometheus.Labels{
+			adapterLabel:      adapterName,
+			rejectReasonLabel: string(pbsmetrics.BidRejectMissingAdomain),
+		})
+}
+
+func TestAdapterBidWarnedMetric(t *testing.T) {
+	m := createMetricsForTesting()
+	adapterName := "anyName"
+
+	m.RecordAdapterBidWarned(openrtb_ext.BidderName(adapterName), pbsmetrics.BidRejectInvalidVast)
+
+	assertCounterVecValue(t, "", "adapterBidsWarned:invalid_vast", m.adapterBidsWarned,
+		1,
+		prometheus.Labels{
+			adapterLabel:      adapterName,
+			rejectReasonLabel: string(pbsmetrics.BidRejectInvalidVast),
+		})
+}
+
+func TestAdapterConnectionMetrics(t *testing.T) {
+	m := createMetricsForTesting()
+	adapterName := "anyName"
+
+	m.RecordAdapterConnections(openrtb_ext.BidderName(adapterName), true, 2*time.Second)
+	m.RecordAdapterConnections(openrtb_ext.BidderName(adapterName), true, 0)
+	m.RecordAdapterConnections(openrtb_ext.BidderName(adapterName), false, 3*time.Second)
+
+	assertCounterVecValue(t, "", "adapterConnectionsReused", m.adapterConnectionsReused,
+		2,
+		prometheus.Labels{
+			adapterLabel: adapterName,
+		})
+	assertCounterVecValue(t, "", "adapterConnectionsCreated", m.adapterConnectionsCreated,
+		1,
+		prometheus.Labels{
+			adapterLabel: adapterName,
+		})
+	result := getHistogramFromHistogramVec(m.adapterConnectionWaitTime, adapterLabel, adapterName)
+	assertHistogram(t, "adapterConnectionWaitTime", result, 3, 5)
+}
+
 func TestStoredReqCacheResultMetric(t *testing.T) {
 	m := createMetricsForTesting()
 
@@ -842,6 +917,23 @@ func TestStoredImpCacheResultMetric(t *testing.T) {
 		})
 }
 
+func TestUIDCookieErrorMetric(t *testing.T) {
+	m := createMetricsForTesting()
+
+	m.RecordUIDCookieError(pbsmetrics.CookieErrorTampered)
+
+	assertCounterVecValue(t, "", "uidCookieErrors:tampered", m.uidCookieErrors,
+		float64(1),
+		prometheus.Labels{
+			cookieErrorLabel: string(pbsmetrics.CookieErrorTampered),
+		})
+	assertCounterVecValue(t, "", "uidCookieErrors:invalid", m.uidCookieErrors,
+		float64(0),
+		prometheus.Labels{
+			cookieErrorLabel: string(pbsmetrics.CookieErrorInvalid),
+		})
+}
+
 func TestCookieMetric(t *testing.T) {
 	m := createMetricsForTesting()
 
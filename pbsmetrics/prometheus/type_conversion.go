@@ -7,11 +7,15 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
 )
 
+// actionsAsString returns the setuid actions which are labeled by adapter. The activity control
+// rejections are counted without an adapter label, so they're left out.
 func actionsAsString() []string {
 	values := pbsmetrics.RequestActions()
-	valuesAsString := make([]string, len(values))
-	for i, v := range values {
-		valuesAsString[i] = string(v)
+	valuesAsString := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != pbsmetrics.RequestActionActivity {
+			valuesAsString = append(valuesAsString, string(v))
+		}
 	}
 	return valuesAsString
 }
@@ -50,6 +54,15 @@ func cookieTypesAsString() []string {
 	return valuesAsString
 }
 
+func cookieErrorsAsString() []string {
+	values := pbsmetrics.CookieErrors()
+	valuesAsString := make([]string, len(values))
+	for i, v := range values {
+		valuesAsString[i] = string(v)
+	}
+	return valuesAsString
+}
+
 func cacheResultsAsString() []string {
 	values := pbsmetrics.CacheResults()
 	valuesAsString := make([]string, len(values))
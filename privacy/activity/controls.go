@@ -0,0 +1,215 @@
+package activity
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/gpp"
+)
+
+// Name identifies a privacy sensitive activity.
+type Name string
+
+const (
+	SyncUser           Name = "syncUser"
+	TransmitUFPD       Name = "transmitUfpd"
+	TransmitPreciseGeo Name = "transmitPreciseGeo"
+	FetchBids          Name = "fetchBids"
+	ReportAnalytics    Name = "reportAnalytics"
+)
+
+// ComponentType is the kind of component which does an activity.
+type ComponentType string
+
+const (
+	ComponentBidder    ComponentType = "bidder"
+	ComponentAnalytics ComponentType = "analytics"
+)
+
+// Component is the bidder or analytics module which does an activity.
+type Component struct {
+	Type ComponentType
+	Name string
+}
+
+func (c Component) String() string {
+	return string(c.Type) + "." + c.Name
+}
+
+// Signals are the parts of a request which the conditions of the activity rules match.
+type Signals struct {
+	// Country and Region come from device.geo, using the ISO-3166-1-alpha-3 and ISO-3166-2 codes.
+	Country string
+	Region  string
+	// GPPSIDs are the section IDs of regs.ext.gpp_sid.
+	GPPSIDs []int8
+	// GDPR is true if regs.ext.gdpr is 1, or if it's undefined and gpp_sid has the TCF EU v2 section.
+	GDPR bool
+}
+
+// ReadSignals extracts the signals of an OpenRTB request. The geo falls back to user.geo if device.geo is missing.
+func ReadSignals(req *openrtb.BidRequest) Signals {
+	signals := Signals{}
+	if req == nil {
+		return signals
+	}
+
+	if req.Device != nil && req.Device.Geo != nil {
+		signals.Country, signals.Region = req.Device.Geo.Country, req.Device.Geo.Region
+	} else if req.User != nil && req.User.Geo != nil {
+		signals.Country, signals.Region = req.User.Geo.Country, req.User.Geo.Region
+	}
+
+	if req.Regs != nil && len(req.Regs.Ext) > 0 {
+		var ext openrtb_ext.ExtRegs
+		if err := json.Unmarshal(req.Regs.Ext, &ext); err == nil {
+			signals.GPPSIDs = ext.GPPSID
+			if ext.GDPR != nil {
+				signals.GDPR = *ext.GDPR == 1
+			} else {
+				signals.GDPR = hasSectionID(ext.GPPSID, int8(gpp.SectionTCFEUv2))
+			}
+		}
+	}
+	return signals
+}
+
+// Controls decides the activities of the requests of one account. A nil Controls allows everything.
+type Controls struct {
+	activities        config.Activities
+	accountActivities config.Activities
+	// Debug records a trace of every decision, for Traces to return.
+	Debug  bool
+	traces []openrtb_ext.ExtActivityTrace
+}
+
+// NewControls returns the controls which apply to the requests of the given account.
+func NewControls(cfg *config.ActivityControls, account string) *Controls {
+	return &Controls{
+		activities:        cfg.ForAccount(account),
+		accountActivities: cfg.Accounts[account],
+	}
+}
+
+// Allow returns true if the component may do the activity for a request with the given signals.
+func (c *Controls) Allow(name Name, component Component, signals Signals) bool {
+	if c == nil {
+		return true
+	}
+
+	activity, source := c.activity(name)
+	allowed := activity.Default == nil || *activity.Default
+	rule := -1
+	for i := range activity.Rules {
+		if matches(activity.Rules[i].Condition, component, signals) {
+			allowed = activity.Rules[i].Allow
+			rule = i
+			break
+		}
+	}
+
+	if c.Debug {
+		trace := openrtb_ext.ExtActivityTrace{
+			Activity:  string(name),
+			Component: component.String(),
+			Allowed:   allowed,
+			Source:    source,
+		}
+		if rule >= 0 {
+			trace.Rule = &rule
+		}
+		c.traces = append(c.traces, trace)
+	}
+	return allowed
+}
+
+// Traces returns the decisions which Allow made while Debug was on.
+func (c *Controls) Traces() []openrtb_ext.ExtActivityTrace {
+	if c == nil {
+		return nil
+	}
+	return c.traces
+}
+
+// activity returns the controls of an activity, and whether they're the account's or the host's.
+func (c *Controls) activity(name Name) (config.Activity, string) {
+	var activity, accountActivity config.Activity
+	switch name {
+	case SyncUser:
+		activity, accountActivity = c.activities.SyncUser, c.accountActivities.SyncUser
+	case TransmitUFPD:
+		activity, accountActivity = c.activities.TransmitUFPD, c.accountActivities.TransmitUFPD
+	case TransmitPreciseGeo:
+		activity, accountActivity = c.activities.TransmitPreciseGeo, c.accountActivities.TransmitPreciseGeo
+	case FetchBids:
+		activity, accountActivity = c.activities.FetchBids, c.accountActivities.FetchBids
+	case ReportAnalytics:
+		activity, accountActivity = c.activities.ReportAnalytics, c.accountActivities.ReportAnalytics
+	}
+	if accountActivity.IsSet() {
+		return activity, "account"
+	}
+	return activity, "host"
+}
+
+func matches(condition config.ActivityCondition, component Component, signals Signals) bool {
+	if len(condition.ComponentType) > 0 && !containsFold(condition.ComponentType, string(component.Type)) {
+		return false
+	}
+	if len(condition.ComponentName) > 0 && !containsFold(condition.ComponentName, component.Name) {
+		return false
+	}
+	if len(condition.GPPSID) > 0 && !anySectionID(signals.GPPSIDs, condition.GPPSID) {
+		return false
+	}
+	if len(condition.Geo) > 0 && !matchesGeo(condition.Geo, signals) {
+		return false
+	}
+	if condition.GDPR != nil && *condition.GDPR != signals.GDPR {
+		return false
+	}
+	return true
+}
+
+func matchesGeo(geos []string, signals Signals) bool {
+	for _, geo := range geos {
+		parts := strings.SplitN(geo, ".", 2)
+		if !strings.EqualFold(parts[0], signals.Country) {
+			continue
+		}
+		if len(parts) == 1 || strings.EqualFold(parts[1], signals.Region) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func anySectionID(ids []int8, wanted []int8) bool {
+	for _, id := range wanted {
+		if hasSectionID(ids, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSectionID(ids []int8, id int8) bool {
+	for _, sectionID := range ids {
+		if sectionID == id {
+			return true
+		}
+	}
+	return false
+}
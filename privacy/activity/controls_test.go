@@ -0,0 +1,177 @@
+package activity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/stretchr/testify/assert"
+)
+
+var appnexus = Component{Type: ComponentBidder, Name: "appnexus"}
+
+func TestAllow(t *testing.T) {
+	deny := false
+	yes := true
+
+	testCases := []struct {
+		description string
+		activity    config.Activity
+		component   Component
+		signals     Signals
+		expected    bool
+	}{
+		{
+			description: "No Rules",
+			activity:    config.Activity{},
+			component:   appnexus,
+			expected:    true,
+		},
+		{
+			description: "Default Deny",
+			activity:    config.Activity{Default: &deny},
+			component:   appnexus,
+			expected:    false,
+		},
+		{
+			description: "Component Name Matches",
+			activity: config.Activity{Default: &deny, Rules: []config.ActivityRule{
+				{Allow: true, Condition: config.ActivityCondition{ComponentName: []string{"rubicon", "AppNexus"}}},
+			}},
+			component: appnexus,
+			expected:  true,
+		},
+		{
+			description: "Component Type Doesn't Match",
+			activity: config.Activity{Rules: []config.ActivityRule{
+				{Allow: false, Condition: config.ActivityCondition{ComponentType: []string{"analytics"}}},
+			}},
+			component: appnexus,
+			expected:  true,
+		},
+		{
+			description: "First Matching Rule Wins",
+			activity: config.Activity{Rules: []config.ActivityRule{
+				{Allow: false, Condition: config.ActivityCondition{Geo: []string{"USA.CA"}}},
+				{Allow: true, Condition: config.ActivityCondition{Geo: []string{"USA"}}},
+				{Allow: false},
+			}},
+			component: appnexus,
+			signals:   Signals{Country: "usa", Region: "ca"},
+			expected:  false,
+		},
+		{
+			description: "Geo Country Only",
+			activity: config.Activity{Rules: []config.ActivityRule{
+				{Allow: false, Condition: config.ActivityCondition{Geo: []string{"USA.CA"}}},
+				{Allow: true, Condition: config.ActivityCondition{Geo: []string{"USA"}}},
+				{Allow: false},
+			}},
+			component: appnexus,
+			signals:   Signals{Country: "USA", Region: "VA"},
+			expected:  true,
+		},
+		{
+			description: "GPP Section ID And GDPR Must Both Match",
+			activity: config.Activity{Rules: []config.ActivityRule{
+				{Allow: false, Condition: config.ActivityCondition{GPPSID: []int8{7, 8}, GDPR: &yes}},
+			}},
+			component: appnexus,
+			signals:   Signals{GPPSIDs: []int8{8}},
+			expected:  true,
+		},
+		{
+			description: "GPP Section ID And GDPR Match",
+			activity: config.Activity{Rules: []config.ActivityRule{
+				{Allow: false, Condition: config.ActivityCondition{GPPSID: []int8{7, 8}, GDPR: &yes}},
+			}},
+			component: appnexus,
+			signals:   Signals{GPPSIDs: []int8{2, 8}, GDPR: true},
+			expected:  false,
+		},
+	}
+
+	for _, test := range testCases {
+		controls := NewControls(&config.ActivityControls{Activities: config.Activities{FetchBids: test.activity}}, "")
+		assert.Equal(t, test.expected, controls.Allow(FetchBids, test.component, test.signals), test.description)
+		assert.True(t, controls.Allow(SyncUser, test.component, test.signals), test.description+": other activities shouldn't be affected")
+	}
+}
+
+func TestAllowAccount(t *testing.T) {
+	deny := false
+	cfg := &config.ActivityControls{
+		Activities: config.Activities{
+			SyncUser:     config.Activity{Default: &deny},
+			TransmitUFPD: config.Activity{Default: &deny},
+		},
+		Accounts: map[string]config.Activities{
+			"1001": {SyncUser: config.Activity{Rules: []config.ActivityRule{
+				{Allow: true, Condition: config.ActivityCondition{ComponentName: []string{"appnexus"}}},
+			}}},
+		},
+	}
+
+	controls := NewControls(cfg, "1001")
+	controls.Debug = true
+	assert.True(t, controls.Allow(SyncUser, appnexus, Signals{}))
+	assert.False(t, controls.Allow(TransmitUFPD, appnexus, Signals{}))
+
+	rule := 0
+	assert.Equal(t, []openrtb_ext.ExtActivityTrace{
+		{Activity: "syncUser", Component: "bidder.appnexus", Allowed: true, Source: "account", Rule: &rule},
+		{Activity: "transmitUfpd", Component: "bidder.appnexus", Allowed: false, Source: "host"},
+	}, controls.Traces())
+
+	assert.False(t, NewControls(cfg, "1002").Allow(SyncUser, appnexus, Signals{}))
+	assert.Empty(t, NewControls(cfg, "1002").Traces(), "Traces should only be recorded in debug mode")
+}
+
+func TestNilControls(t *testing.T) {
+	var controls *Controls
+	assert.True(t, controls.Allow(FetchBids, appnexus, Signals{}))
+	assert.Nil(t, controls.Traces())
+}
+
+func TestReadSignals(t *testing.T) {
+	testCases := []struct {
+		description string
+		request     *openrtb.BidRequest
+		expected    Signals
+	}{
+		{
+			description: "Nil Request",
+			expected:    Signals{},
+		},
+		{
+			description: "Device Geo",
+			request: &openrtb.BidRequest{
+				Device: &openrtb.Device{Geo: &openrtb.Geo{Country: "USA", Region: "CA"}},
+				User:   &openrtb.User{Geo: &openrtb.Geo{Country: "CAN"}},
+				Regs:   &openrtb.Regs{Ext: json.RawMessage(`{"gdpr":0,"gpp_sid":[2,7]}`)},
+			},
+			expected: Signals{Country: "USA", Region: "CA", GPPSIDs: []int8{2, 7}},
+		},
+		{
+			description: "User Geo And GDPR From GPP",
+			request: &openrtb.BidRequest{
+				User: &openrtb.User{Geo: &openrtb.Geo{Country: "DEU"}},
+				Regs: &openrtb.Regs{Ext: json.RawMessage(`{"gpp_sid":[2]}`)},
+			},
+			expected: Signals{Country: "DEU", GPPSIDs: []int8{2}, GDPR: true},
+		},
+		{
+			description: "GDPR",
+			request: &openrtb.BidRequest{
+				Regs: &openrtb.Regs{Ext: json.RawMessage(`{"gdpr":1}`)},
+			},
+			expected: Signals{GDPR: true},
+		},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.expected, ReadSignals(test.request), test.description)
+	}
+}
@@ -9,11 +9,15 @@ type Enforcement struct {
 	CCPA  bool
 	COPPA bool
 	GDPR  bool
+	// UFPD removes the user's first party data, which the transmitUfpd activity control can deny.
+	UFPD bool
+	// PreciseGeo reduces the precision of the geo and IP addresses, which the transmitPreciseGeo activity control can deny.
+	PreciseGeo bool
 }
 
 // Any returns true if at least one privacy policy requires enforcement.
 func (e Enforcement) Any() bool {
-	return e.CCPA || e.COPPA || e.GDPR
+	return e.CCPA || e.COPPA || e.GDPR || e.UFPD || e.PreciseGeo
 }
 
 // Apply cleans personally identifiable information from an OpenRTB bid request.
@@ -23,13 +27,39 @@ func (e Enforcement) Apply(bidRequest *openrtb.BidRequest, isAMP bool) {
 
 func (e Enforcement) apply(bidRequest *openrtb.BidRequest, isAMP bool, scrubber Scrubber) {
 	if bidRequest != nil && e.Any() {
-		bidRequest.Device = scrubber.ScrubDevice(bidRequest.Device, e.getDeviceMacAndIFA(), e.getIPv6ScrubStrategy(), e.getGeoScrubStrategy())
+		bidRequest.Device = scrubber.ScrubDevice(bidRequest.Device, e.getDeviceIDScrubStrategy(), e.getIPv6ScrubStrategy(), e.getGeoScrubStrategy())
 		bidRequest.User = scrubber.ScrubUser(bidRequest.User, e.getUserScrubStrategy(isAMP), e.getGeoScrubStrategy())
+		if e.UFPD {
+			scrubSiteAppExtData(bidRequest)
+		}
 	}
 }
 
-func (e Enforcement) getDeviceMacAndIFA() bool {
-	return e.COPPA
+// scrubSiteAppExtData removes the first party data which the publisher put in site.ext.data and app.ext.data.
+// The rest of the site and app describe the inventory rather than the user, so they're kept.
+func scrubSiteAppExtData(bidRequest *openrtb.BidRequest) {
+	if bidRequest.Site != nil && len(bidRequest.Site.Ext) > 0 {
+		siteCopy := *bidRequest.Site
+		siteCopy.Ext = scrubExtKeys(siteCopy.Ext, "data")
+		bidRequest.Site = &siteCopy
+	}
+	if bidRequest.App != nil && len(bidRequest.App.Ext) > 0 {
+		appCopy := *bidRequest.App
+		appCopy.Ext = scrubExtKeys(appCopy.Ext, "data")
+		bidRequest.App = &appCopy
+	}
+}
+
+func (e Enforcement) getDeviceIDScrubStrategy() ScrubStrategyDeviceID {
+	if e.COPPA || e.UFPD {
+		return ScrubStrategyDeviceIDAll
+	}
+
+	if e.GDPR || e.CCPA {
+		return ScrubStrategyDeviceIDHashes
+	}
+
+	return ScrubStrategyDeviceIDNone
 }
 
 func (e Enforcement) getIPv6ScrubStrategy() ScrubStrategyIPV6 {
@@ -37,7 +67,7 @@ func (e Enforcement) getIPv6ScrubStrategy() ScrubStrategyIPV6 {
 		return ScrubStrategyIPV6Lowest32
 	}
 
-	if e.GDPR || e.CCPA {
+	if e.GDPR || e.CCPA || e.PreciseGeo {
 		return ScrubStrategyIPV6Lowest16
 	}
 
@@ -49,7 +79,7 @@ func (e Enforcement) getGeoScrubStrategy() ScrubStrategyGeo {
 		return ScrubStrategyGeoFull
 	}
 
-	if e.GDPR || e.CCPA {
+	if e.GDPR || e.CCPA || e.PreciseGeo {
 		return ScrubStrategyGeoReducedPrecision
 	}
 
@@ -57,6 +87,10 @@ func (e Enforcement) getGeoScrubStrategy() ScrubStrategyGeo {
 }
 
 func (e Enforcement) getUserScrubStrategy(isAMP bool) ScrubStrategyUser {
+	if e.UFPD {
+		return ScrubStrategyUserFirstPartyData
+	}
+
 	if e.COPPA {
 		return ScrubStrategyUserFull
 	}
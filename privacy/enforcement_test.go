@@ -1,6 +1,7 @@
 package privacy
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/PubMatic-OpenWrap/openrtb"
@@ -51,14 +52,14 @@ func TestAny(t *testing.T) {
 
 func TestApply(t *testing.T) {
 	testCases := []struct {
-		enforcement             Enforcement
-		isAMP                   bool
-		expectedDeviceMacAndIFA bool
-		expectedDeviceIPv6      ScrubStrategyIPV6
-		expectedDeviceGeo       ScrubStrategyGeo
-		expectedUser            ScrubStrategyUser
-		expectedUserGeo         ScrubStrategyGeo
-		description             string
+		enforcement        Enforcement
+		isAMP              bool
+		expectedDeviceID   ScrubStrategyDeviceID
+		expectedDeviceIPv6 ScrubStrategyIPV6
+		expectedDeviceGeo  ScrubStrategyGeo
+		expectedUser       ScrubStrategyUser
+		expectedUserGeo    ScrubStrategyGeo
+		description        string
 	}{
 		{
 			enforcement: Enforcement{
@@ -66,13 +67,13 @@ func TestApply(t *testing.T) {
 				COPPA: true,
 				GDPR:  true,
 			},
-			isAMP:                   true,
-			expectedDeviceMacAndIFA: true,
-			expectedDeviceIPv6:      ScrubStrategyIPV6Lowest32,
-			expectedDeviceGeo:       ScrubStrategyGeoFull,
-			expectedUser:            ScrubStrategyUserFull,
-			expectedUserGeo:         ScrubStrategyGeoFull,
-			description:             "All Enforced - Most Strict",
+			isAMP:              true,
+			expectedDeviceID:   ScrubStrategyDeviceIDAll,
+			expectedDeviceIPv6: ScrubStrategyIPV6Lowest32,
+			expectedDeviceGeo:  ScrubStrategyGeoFull,
+			expectedUser:       ScrubStrategyUserFull,
+			expectedUserGeo:    ScrubStrategyGeoFull,
+			description:        "All Enforced - Most Strict",
 		},
 		{
 			enforcement: Enforcement{
@@ -80,13 +81,13 @@ func TestApply(t *testing.T) {
 				COPPA: true,
 				GDPR:  false,
 			},
-			isAMP:                   false,
-			expectedDeviceMacAndIFA: true,
-			expectedDeviceIPv6:      ScrubStrategyIPV6Lowest32,
-			expectedDeviceGeo:       ScrubStrategyGeoFull,
-			expectedUser:            ScrubStrategyUserFull,
-			expectedUserGeo:         ScrubStrategyGeoFull,
-			description:             "COPPA",
+			isAMP:              false,
+			expectedDeviceID:   ScrubStrategyDeviceIDAll,
+			expectedDeviceIPv6: ScrubStrategyIPV6Lowest32,
+			expectedDeviceGeo:  ScrubStrategyGeoFull,
+			expectedUser:       ScrubStrategyUserFull,
+			expectedUserGeo:    ScrubStrategyGeoFull,
+			description:        "COPPA",
 		},
 		{
 			enforcement: Enforcement{
@@ -94,13 +95,13 @@ func TestApply(t *testing.T) {
 				COPPA: false,
 				GDPR:  true,
 			},
-			isAMP:                   false,
-			expectedDeviceMacAndIFA: false,
-			expectedDeviceIPv6:      ScrubStrategyIPV6Lowest16,
-			expectedDeviceGeo:       ScrubStrategyGeoReducedPrecision,
-			expectedUser:            ScrubStrategyUserBuyerIDOnly,
-			expectedUserGeo:         ScrubStrategyGeoReducedPrecision,
-			description:             "GDPR",
+			isAMP:              false,
+			expectedDeviceID:   ScrubStrategyDeviceIDHashes,
+			expectedDeviceIPv6: ScrubStrategyIPV6Lowest16,
+			expectedDeviceGeo:  ScrubStrategyGeoReducedPrecision,
+			expectedUser:       ScrubStrategyUserBuyerIDOnly,
+			expectedUserGeo:    ScrubStrategyGeoReducedPrecision,
+			description:        "GDPR",
 		},
 		{
 			enforcement: Enforcement{
@@ -108,13 +109,13 @@ func TestApply(t *testing.T) {
 				COPPA: false,
 				GDPR:  true,
 			},
-			isAMP:                   true,
-			expectedDeviceMacAndIFA: false,
-			expectedDeviceIPv6:      ScrubStrategyIPV6Lowest16,
-			expectedDeviceGeo:       ScrubStrategyGeoReducedPrecision,
-			expectedUser:            ScrubStrategyUserNone,
-			expectedUserGeo:         ScrubStrategyGeoReducedPrecision,
-			description:             "GDPR For AMP",
+			isAMP:              true,
+			expectedDeviceID:   ScrubStrategyDeviceIDHashes,
+			expectedDeviceIPv6: ScrubStrategyIPV6Lowest16,
+			expectedDeviceGeo:  ScrubStrategyGeoReducedPrecision,
+			expectedUser:       ScrubStrategyUserNone,
+			expectedUserGeo:    ScrubStrategyGeoReducedPrecision,
+			description:        "GDPR For AMP",
 		},
 		{
 			enforcement: Enforcement{
@@ -122,13 +123,13 @@ func TestApply(t *testing.T) {
 				COPPA: false,
 				GDPR:  false,
 			},
-			isAMP:                   false,
-			expectedDeviceMacAndIFA: false,
-			expectedDeviceIPv6:      ScrubStrategyIPV6Lowest16,
-			expectedDeviceGeo:       ScrubStrategyGeoReducedPrecision,
-			expectedUser:            ScrubStrategyUserBuyerIDOnly,
-			expectedUserGeo:         ScrubStrategyGeoReducedPrecision,
-			description:             "CCPA",
+			isAMP:              false,
+			expectedDeviceID:   ScrubStrategyDeviceIDHashes,
+			expectedDeviceIPv6: ScrubStrategyIPV6Lowest16,
+			expectedDeviceGeo:  ScrubStrategyGeoReducedPrecision,
+			expectedUser:       ScrubStrategyUserBuyerIDOnly,
+			expectedUserGeo:    ScrubStrategyGeoReducedPrecision,
+			description:        "CCPA",
 		},
 		{
 			enforcement: Enforcement{
@@ -136,13 +137,13 @@ func TestApply(t *testing.T) {
 				COPPA: false,
 				GDPR:  false,
 			},
-			isAMP:                   true,
-			expectedDeviceMacAndIFA: false,
-			expectedDeviceIPv6:      ScrubStrategyIPV6Lowest16,
-			expectedDeviceGeo:       ScrubStrategyGeoReducedPrecision,
-			expectedUser:            ScrubStrategyUserBuyerIDOnly,
-			expectedUserGeo:         ScrubStrategyGeoReducedPrecision,
-			description:             "CCPA For AMP",
+			isAMP:              true,
+			expectedDeviceID:   ScrubStrategyDeviceIDHashes,
+			expectedDeviceIPv6: ScrubStrategyIPV6Lowest16,
+			expectedDeviceGeo:  ScrubStrategyGeoReducedPrecision,
+			expectedUser:       ScrubStrategyUserBuyerIDOnly,
+			expectedUserGeo:    ScrubStrategyGeoReducedPrecision,
+			description:        "CCPA For AMP",
 		},
 		{
 			enforcement: Enforcement{
@@ -150,13 +151,37 @@ func TestApply(t *testing.T) {
 				COPPA: false,
 				GDPR:  true,
 			},
-			isAMP:                   true,
-			expectedDeviceMacAndIFA: false,
-			expectedDeviceIPv6:      ScrubStrategyIPV6Lowest16,
-			expectedDeviceGeo:       ScrubStrategyGeoReducedPrecision,
-			expectedUser:            ScrubStrategyUserNone,
-			expectedUserGeo:         ScrubStrategyGeoReducedPrecision,
-			description:             "GDPR And CCPA For AMP",
+			isAMP:              true,
+			expectedDeviceID:   ScrubStrategyDeviceIDHashes,
+			expectedDeviceIPv6: ScrubStrategyIPV6Lowest16,
+			expectedDeviceGeo:  ScrubStrategyGeoReducedPrecision,
+			expectedUser:       ScrubStrategyUserNone,
+			expectedUserGeo:    ScrubStrategyGeoReducedPrecision,
+			description:        "GDPR And CCPA For AMP",
+		},
+		{
+			enforcement: Enforcement{
+				UFPD: true,
+			},
+			isAMP:              false,
+			expectedDeviceID:   ScrubStrategyDeviceIDAll,
+			expectedDeviceIPv6: ScrubStrategyIPV6None,
+			expectedDeviceGeo:  ScrubStrategyGeoNone,
+			expectedUser:       ScrubStrategyUserFirstPartyData,
+			expectedUserGeo:    ScrubStrategyGeoNone,
+			description:        "UFPD",
+		},
+		{
+			enforcement: Enforcement{
+				PreciseGeo: true,
+			},
+			isAMP:              false,
+			expectedDeviceID:   ScrubStrategyDeviceIDNone,
+			expectedDeviceIPv6: ScrubStrategyIPV6Lowest16,
+			expectedDeviceGeo:  ScrubStrategyGeoReducedPrecision,
+			expectedUser:       ScrubStrategyUserNone,
+			expectedUserGeo:    ScrubStrategyGeoReducedPrecision,
+			description:        "Precise Geo",
 		},
 	}
 
@@ -169,7 +194,7 @@ func TestApply(t *testing.T) {
 		user := &openrtb.User{ID: "after"}
 
 		m := &mockScrubber{}
-		m.On("ScrubDevice", req.Device, test.expectedDeviceMacAndIFA, test.expectedDeviceIPv6, test.expectedDeviceGeo).Return(device).Once()
+		m.On("ScrubDevice", req.Device, test.expectedDeviceID, test.expectedDeviceIPv6, test.expectedDeviceGeo).Return(device).Once()
 		m.On("ScrubUser", req.User, test.expectedUser, test.expectedUserGeo).Return(user).Once()
 
 		test.enforcement.apply(req, test.isAMP, m)
@@ -199,12 +224,60 @@ func TestApplyNoneApplicable(t *testing.T) {
 	assert.Equal(t, user, req.User, "User Set Correctly")
 }
 
+func TestApplyUFPDScrubsSiteAndAppExtData(t *testing.T) {
+	testCases := []struct {
+		description  string
+		enforcement  Enforcement
+		siteExt      json.RawMessage
+		appExt       json.RawMessage
+		expectedSite json.RawMessage
+		expectedApp  json.RawMessage
+	}{
+		{
+			description:  "UFPD - Data Removed",
+			enforcement:  Enforcement{UFPD: true},
+			siteExt:      json.RawMessage(`{"amp":0,"data":{"section":"sports"}}`),
+			appExt:       json.RawMessage(`{"data":{"section":"sports"}}`),
+			expectedSite: json.RawMessage(`{"amp":0}`),
+			expectedApp:  nil,
+		},
+		{
+			description:  "UFPD - No Data",
+			enforcement:  Enforcement{UFPD: true},
+			siteExt:      json.RawMessage(`{"amp":0}`),
+			appExt:       nil,
+			expectedSite: json.RawMessage(`{"amp":0}`),
+			expectedApp:  nil,
+		},
+		{
+			description:  "GDPR - Data Kept",
+			enforcement:  Enforcement{GDPR: true},
+			siteExt:      json.RawMessage(`{"data":{"section":"sports"}}`),
+			appExt:       json.RawMessage(`{"data":{"section":"sports"}}`),
+			expectedSite: json.RawMessage(`{"data":{"section":"sports"}}`),
+			expectedApp:  json.RawMessage(`{"data":{"section":"sports"}}`),
+		},
+	}
+
+	for _, test := range testCases {
+		site := &openrtb.Site{ID: "anySite", Ext: test.siteExt}
+		app := &openrtb.App{ID: "anyApp", Ext: test.appExt}
+		req := &openrtb.BidRequest{Site: site, App: app}
+
+		test.enforcement.Apply(req, false)
+
+		assert.Equal(t, &openrtb.Site{ID: "anySite", Ext: test.expectedSite}, req.Site, test.description+":site")
+		assert.Equal(t, &openrtb.App{ID: "anyApp", Ext: test.expectedApp}, req.App, test.description+":app")
+		assert.Equal(t, test.siteExt, site.Ext, test.description+":original site unchanged")
+	}
+}
+
 type mockScrubber struct {
 	mock.Mock
 }
 
-func (m *mockScrubber) ScrubDevice(device *openrtb.Device, macAndIFA bool, ipv6 ScrubStrategyIPV6, geo ScrubStrategyGeo) *openrtb.Device {
-	args := m.Called(device, macAndIFA, ipv6, geo)
+func (m *mockScrubber) ScrubDevice(device *openrtb.Device, id ScrubStrategyDeviceID, ipv6 ScrubStrategyIPV6, geo ScrubStrategyGeo) *openrtb.Device {
+	args := m.Called(device, id, ipv6, geo)
 	return args.Get(0).(*openrtb.Device)
 }
 
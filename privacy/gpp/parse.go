@@ -0,0 +1,318 @@
+package gpp
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// gppHeaderType is the type which every GPP header starts with.
+const gppHeaderType = 3
+
+// GPP is a parsed GPP string.
+type GPP struct {
+	Version  uint8
+	Sections []Section
+}
+
+// Section is one section of a GPP string, in its encoded form.
+type Section struct {
+	ID    SectionID
+	Value string
+}
+
+// Parse splits a GPP string into its sections. The sections themselves aren't decoded.
+func Parse(value string) (GPP, error) {
+	parts := strings.Split(value, "~")
+
+	header, err := newBitReader(parts[0])
+	if err != nil {
+		return GPP{}, fmt.Errorf("the GPP header is invalid: %v", err)
+	}
+	if headerType := header.readInt(6); headerType != gppHeaderType {
+		return GPP{}, fmt.Errorf("the GPP header must have type %d, not %d", gppHeaderType, headerType)
+	}
+	parsed := GPP{Version: uint8(header.readInt(6))}
+	ids := header.readFibonacciRange()
+	if header.err != nil {
+		return GPP{}, fmt.Errorf("the GPP header is invalid: %v", header.err)
+	}
+
+	if len(ids) != len(parts)-1 {
+		return GPP{}, errMissingSection
+	}
+	parsed.Sections = make([]Section, len(ids))
+	for i, id := range ids {
+		parsed.Sections[i] = Section{ID: SectionID(id), Value: parts[i+1]}
+	}
+	return parsed, nil
+}
+
+// usSectionFields are the 2 bit fields which the core segment of each US national and state section starts with,
+// after its 6 bit version, as far as the last opt-out field. The fields named in usOptOutFields hold 1 if the user
+// opted out.
+var usSectionFields = map[SectionID][]string{
+	SectionUSNat: {"SharingNotice", "SaleOptOutNotice", "SharingOptOutNotice", "TargetedAdvertisingOptOutNotice", "SensitiveDataProcessingOptOutNotice", "SensitiveDataLimitUseNotice", "SaleOptOut", "SharingOptOut", "TargetedAdvertisingOptOut"},
+	SectionUSCA:  {"SaleOptOutNotice", "SharingOptOutNotice", "SensitiveDataLimitUseNotice", "SaleOptOut", "SharingOptOut"},
+	SectionUSVA:  {"SharingNotice", "SaleOptOutNotice", "TargetedAdvertisingOptOutNotice", "SaleOptOut", "TargetedAdvertisingOptOut"},
+	SectionUSCO:  {"SharingNotice", "SaleOptOutNotice", "TargetedAdvertisingOptOutNotice", "SaleOptOut", "TargetedAdvertisingOptOut"},
+	SectionUSUT:  {"SharingNotice", "SaleOptOutNotice", "TargetedAdvertisingOptOutNotice", "SensitiveDataProcessingOptOutNotice", "SaleOptOut", "TargetedAdvertisingOptOut"},
+	SectionUSCT:  {"SharingNotice", "SaleOptOutNotice", "TargetedAdvertisingOptOutNotice", "SaleOptOut", "TargetedAdvertisingOptOut"},
+}
+
+var usOptOutFields = map[string]bool{
+	"SaleOptOut":                true,
+	"SharingOptOut":             true,
+	"TargetedAdvertisingOptOut": true,
+}
+
+const usOptedOut = 1
+
+// usOptOut returns true if a US national or state section says the user opted out. Other sections return false.
+func usOptOut(section Section) (bool, error) {
+	fields, ok := usSectionFields[section.ID]
+	if !ok {
+		return false, nil
+	}
+
+	core, err := newBitReader(strings.SplitN(section.Value, ".", 2)[0])
+	if err != nil {
+		return false, fmt.Errorf("GPP section %d is invalid: %v", section.ID, err)
+	}
+	core.readInt(6)
+	optOut := false
+	for _, field := range fields {
+		if value := core.readInt(2); usOptOutFields[field] && value == usOptedOut {
+			optOut = true
+		}
+	}
+	if core.err != nil {
+		return false, fmt.Errorf("GPP section %d is invalid: %v", section.ID, core.err)
+	}
+	return optOut, nil
+}
+
+// TCFv2Consent is the core segment of a TCF v2 consent string, which is also the TCF EU v2 section of a GPP string.
+type TCFv2Consent struct {
+	Version                   uint8
+	VendorListVersion         uint16
+	purposesConsent           uint32
+	purposesLITransparency    uint32
+	vendorConsents            vendorSet
+	vendorLegitimateInterests vendorSet
+}
+
+// PurposeConsent returns true if the user consented to the purpose, numbered from 1.
+func (c TCFv2Consent) PurposeConsent(purpose uint8) bool {
+	return purposeBit(c.purposesConsent, purpose)
+}
+
+// PurposeLITransparency returns true if the user was told about the legitimate interest in the purpose, and
+// didn't object to it.
+func (c TCFv2Consent) PurposeLITransparency(purpose uint8) bool {
+	return purposeBit(c.purposesLITransparency, purpose)
+}
+
+// VendorConsent returns true if the user consented to the vendor.
+func (c TCFv2Consent) VendorConsent(vendorID uint16) bool {
+	return c.vendorConsents.contains(vendorID)
+}
+
+// VendorLegitimateInterest returns true if the vendor's legitimate interest was established, and the user
+// didn't object to it.
+func (c TCFv2Consent) VendorLegitimateInterest(vendorID uint16) bool {
+	return c.vendorLegitimateInterests.contains(vendorID)
+}
+
+// purposeBit reads the purpose, numbered from 1, from the 24 purpose bits of a TCF v2 string.
+func purposeBit(purposes uint32, purpose uint8) bool {
+	if purpose < 1 || purpose > 24 {
+		return false
+	}
+	return purposes&(1<<(24-uint(purpose))) != 0
+}
+
+// vendorSet is a vendor section of a TCF v2 string, which is either a bit field or a list of ranges.
+type vendorSet struct {
+	bits   []bool
+	ranges [][2]uint16
+}
+
+func (s vendorSet) contains(vendorID uint16) bool {
+	if vendorID == 0 {
+		return false
+	}
+	if s.bits != nil {
+		return int(vendorID) <= len(s.bits) && s.bits[vendorID-1]
+	}
+	for _, vendorRange := range s.ranges {
+		if vendorID >= vendorRange[0] && vendorID <= vendorRange[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTCFv2 returns true if the consent string is a TCF v2 one. Their 6 bit version of 2 encodes as a leading "C".
+func IsTCFv2(consent string) bool {
+	return strings.HasPrefix(consent, "C")
+}
+
+// ParseTCFv2 decodes the core segment of a TCF v2 consent string. The publisher restrictions at its end
+// aren't read.
+func ParseTCFv2(consent string) (TCFv2Consent, error) {
+	core, err := newBitReader(strings.SplitN(consent, ".", 2)[0])
+	if err != nil {
+		return TCFv2Consent{}, err
+	}
+
+	parsed := TCFv2Consent{Version: uint8(core.readInt(6))}
+	if parsed.Version != 2 {
+		return TCFv2Consent{}, fmt.Errorf("the consent string has version %d, not 2", parsed.Version)
+	}
+	// Created, LastUpdated, CmpId, CmpVersion, ConsentScreen and ConsentLanguage
+	core.skip(36 + 36 + 12 + 12 + 6 + 12)
+	parsed.VendorListVersion = uint16(core.readInt(12))
+	// TcfPolicyVersion, IsServiceSpecific, UseNonStandardTexts and SpecialFeatureOptIns
+	core.skip(6 + 1 + 1 + 12)
+	parsed.purposesConsent = uint32(core.readInt(24))
+	parsed.purposesLITransparency = uint32(core.readInt(24))
+	// PurposeOneTreatment and PublisherCC
+	core.skip(1 + 12)
+
+	parsed.vendorConsents = core.readVendorSet()
+	parsed.vendorLegitimateInterests = core.readVendorSet()
+
+	if core.err != nil {
+		return TCFv2Consent{}, core.err
+	}
+	return parsed, nil
+}
+
+var errTooShort = errors.New("the value is too short")
+
+// errMissingSection is returned when the header lists a different number of sections than the GPP string has.
+var errMissingSection = errors.New("the GPP header doesn't match the number of sections")
+
+// maxSectionID bounds the section ID ranges of a GPP header.
+const maxSectionID = 127
+
+// bitReader reads the big-endian bit fields of a base64url encoded GPP or TCF segment.
+// Once a read runs past the end, err is set and every read returns zero.
+type bitReader struct {
+	data []byte
+	pos  uint
+	err  error
+}
+
+func newBitReader(segment string) (*bitReader, error) {
+	if segment == "" {
+		return nil, errors.New("the value is empty")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return nil, errors.New("the value must be base64url encoded")
+	}
+	return &bitReader{data: data}, nil
+}
+
+func (r *bitReader) readBool() bool {
+	return r.readInt(1) == 1
+}
+
+func (r *bitReader) readInt(bits uint) uint64 {
+	if r.err != nil {
+		return 0
+	}
+	if r.pos+bits > uint(len(r.data))*8 {
+		r.err = errTooShort
+		return 0
+	}
+	var value uint64
+	for i := uint(0); i < bits; i++ {
+		bit := (r.data[(r.pos+i)/8] >> (7 - (r.pos+i)%8)) & 1
+		value = value<<1 | uint64(bit)
+	}
+	r.pos += bits
+	return value
+}
+
+func (r *bitReader) skip(bits uint) {
+	if r.err == nil && r.pos+bits > uint(len(r.data))*8 {
+		r.err = errTooShort
+	}
+	r.pos += bits
+}
+
+// readFibonacci reads a Fibonacci coded integer, which ends with two consecutive 1 bits.
+func (r *bitReader) readFibonacci() uint64 {
+	var value uint64
+	previous, current := uint64(1), uint64(1)
+	lastBit := false
+	for r.err == nil {
+		bit := r.readBool()
+		if bit && lastBit {
+			return value
+		}
+		if bit {
+			value += current
+		}
+		previous, current = current, previous+current
+		lastBit = bit
+	}
+	return 0
+}
+
+// readFibonacciRange reads a list of integers which is encoded as a 12 bit count of entries, each of which is either
+// a single integer or a range. The integers are Fibonacci coded offsets from the previous one.
+func (r *bitReader) readFibonacciRange() []uint64 {
+	numEntries := r.readInt(12)
+	var values []uint64
+	var last uint64
+	for i := uint64(0); i < numEntries && r.err == nil; i++ {
+		isRange := r.readBool()
+		start := last + r.readFibonacci()
+		end := start
+		if isRange {
+			end = start + r.readFibonacci()
+		}
+		if end > maxSectionID {
+			r.err = fmt.Errorf("section IDs can't be larger than %d", maxSectionID)
+			return nil
+		}
+		for value := start; value <= end && r.err == nil; value++ {
+			values = append(values, value)
+		}
+		last = end
+	}
+	if r.err != nil {
+		return nil
+	}
+	return values
+}
+
+// readVendorSet reads a vendor section: its MaxVendorId, IsRangeEncoding, and then either a bit field or
+// a list of ranges.
+func (r *bitReader) readVendorSet() vendorSet {
+	var set vendorSet
+	maxVendorID := uint16(r.readInt(16))
+	if r.readBool() {
+		numEntries := r.readInt(12)
+		for i := uint64(0); i < numEntries && r.err == nil; i++ {
+			isRange := r.readBool()
+			start := uint16(r.readInt(16))
+			end := start
+			if isRange {
+				end = uint16(r.readInt(16))
+			}
+			set.ranges = append(set.ranges, [2]uint16{start, end})
+		}
+	} else {
+		set.bits = make([]bool, 0, maxVendorID)
+		for i := uint16(0); i < maxVendorID && r.err == nil; i++ {
+			set.bits = append(set.bits, r.readBool())
+		}
+	}
+	return set
+}
@@ -0,0 +1,182 @@
+package gpp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+)
+
+// SectionID identifies a section of a GPP string. See https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform
+type SectionID int8
+
+const (
+	SectionTCFEUv2 SectionID = 2
+	SectionUSPv1   SectionID = 6
+	SectionUSNat   SectionID = 7
+	SectionUSCA    SectionID = 8
+	SectionUSVA    SectionID = 9
+	SectionUSCO    SectionID = 10
+	SectionUSUT    SectionID = 11
+	SectionUSCT    SectionID = 12
+)
+
+// Policy represents the Global Privacy Platform signals for an OpenRTB bid request.
+type Policy struct {
+	// Value is the GPP string.
+	Value string
+	// SectionIDs are the sections of the GPP string which apply to the request.
+	SectionIDs []SectionID
+}
+
+// Signals are the privacy signals which a GPP policy carries for the regulations Prebid Server enforces.
+type Signals struct {
+	// GDPR is "1" if the TCF EU v2 section applies, "0" if it doesn't, and "" if the policy doesn't say.
+	GDPR string
+	// TCFConsent is the TCF v2 consent string of the TCF EU v2 section.
+	TCFConsent string
+	// USPrivacy is the US Privacy string of the USP v1 section.
+	USPrivacy string
+	// USOptOut is true if the user opted out of sale, sharing or targeted advertising in a US national or state section.
+	USOptOut bool
+}
+
+// ReadPolicy extracts the GPP policy from an OpenRTB regs ext.
+func ReadPolicy(req *openrtb.BidRequest) (Policy, error) {
+	policy := Policy{}
+
+	if req != nil && req.Regs != nil && len(req.Regs.Ext) > 0 {
+		var ext openrtb_ext.ExtRegs
+		if err := json.Unmarshal(req.Regs.Ext, &ext); err != nil {
+			return policy, err
+		}
+		policy.Value = ext.GPP
+		for _, id := range ext.GPPSID {
+			policy.SectionIDs = append(policy.SectionIDs, SectionID(id))
+		}
+	}
+
+	return policy, nil
+}
+
+// Write mutates an OpenRTB bid request with the context of the GPP policy.
+func (p Policy) Write(req *openrtb.BidRequest) error {
+	if p.Value == "" && len(p.SectionIDs) == 0 {
+		return nil
+	}
+
+	if req.Regs == nil {
+		req.Regs = &openrtb.Regs{}
+	}
+
+	ext := make(map[string]json.RawMessage, 2)
+	if len(req.Regs.Ext) > 0 {
+		if err := json.Unmarshal(req.Regs.Ext, &ext); err != nil {
+			return err
+		}
+	}
+	if p.Value != "" {
+		value, _ := json.Marshal(p.Value)
+		ext["gpp"] = value
+	}
+	if len(p.SectionIDs) > 0 {
+		sectionIDs, _ := json.Marshal(p.SectionIDs)
+		ext["gpp_sid"] = sectionIDs
+	}
+
+	var err error
+	req.Regs.Ext, err = json.Marshal(ext)
+	return err
+}
+
+// Validate returns an error if the GPP string can't be parsed.
+func (p Policy) Validate() error {
+	if p.Value == "" {
+		return nil
+	}
+
+	_, err := Parse(p.Value)
+	return err
+}
+
+// Signals derives the TCF EU v2 and US privacy signals of the policy. Only the sections which the
+// policy's section IDs list are read. If the policy has no section IDs, every section of the GPP string is read.
+func (p Policy) Signals() (Signals, error) {
+	signals := Signals{}
+	if len(p.SectionIDs) > 0 {
+		signals.GDPR = "0"
+		if p.applies(SectionTCFEUv2) {
+			signals.GDPR = "1"
+		}
+	}
+	if p.Value == "" {
+		return signals, nil
+	}
+
+	parsed, err := Parse(p.Value)
+	if err != nil {
+		return signals, err
+	}
+
+	for _, section := range parsed.Sections {
+		if !p.applies(section.ID) {
+			continue
+		}
+		switch section.ID {
+		case SectionTCFEUv2:
+			signals.TCFConsent = section.Value
+		case SectionUSPv1:
+			signals.USPrivacy = section.Value
+		default:
+			optOut, err := usOptOut(section)
+			if err != nil {
+				return signals, err
+			}
+			signals.USOptOut = signals.USOptOut || optOut
+		}
+	}
+
+	return signals, nil
+}
+
+func (p Policy) applies(id SectionID) bool {
+	if len(p.SectionIDs) == 0 {
+		return true
+	}
+	for _, sectionID := range p.SectionIDs {
+		if sectionID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSectionIDs parses a comma separated list of section IDs, such as the gpp_sid query param.
+func ParseSectionIDs(value string) ([]SectionID, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(value, ",")
+	ids := make([]SectionID, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("gpp_sid must be a comma separated list of section IDs, but it contains %q", part)
+		}
+		ids = append(ids, SectionID(id))
+	}
+	return ids, nil
+}
+
+// JoinSectionIDs formats section IDs as a comma separated list, the way ParseSectionIDs reads them.
+func JoinSectionIDs(ids []SectionID) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(int(id))
+	}
+	return strings.Join(parts, ",")
+}
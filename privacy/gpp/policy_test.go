@@ -0,0 +1,354 @@
+package gpp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/PubMatic-OpenWrap/openrtb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead(t *testing.T) {
+	testCases := []struct {
+		description    string
+		request        *openrtb.BidRequest
+		expectedPolicy Policy
+		expectedError  bool
+	}{
+		{
+			description: "Success",
+			request: &openrtb.BidRequest{
+				Regs: &openrtb.Regs{
+					Ext: json.RawMessage(`{"gpp":"DBABMA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA","gpp_sid":[2]}`),
+				},
+			},
+			expectedPolicy: Policy{
+				Value:      "DBABMA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA",
+				SectionIDs: []SectionID{SectionTCFEUv2},
+			},
+		},
+		{
+			description:    "Empty - No Request",
+			request:        nil,
+			expectedPolicy: Policy{},
+		},
+		{
+			description: "Empty - No Value",
+			request: &openrtb.BidRequest{
+				Regs: &openrtb.Regs{
+					Ext: json.RawMessage(`{"us_privacy":"1YNN"}`),
+				},
+			},
+			expectedPolicy: Policy{},
+		},
+		{
+			description: "Serialization Issue",
+			request: &openrtb.BidRequest{
+				Regs: &openrtb.Regs{
+					Ext: json.RawMessage(`malformed`),
+				},
+			},
+			expectedPolicy: Policy{},
+			expectedError:  true,
+		},
+	}
+
+	for _, test := range testCases {
+		result, err := ReadPolicy(test.request)
+		assertError(t, test.expectedError, err, test.description)
+		assert.Equal(t, test.expectedPolicy, result, test.description)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	testCases := []struct {
+		description string
+		policy      Policy
+		request     *openrtb.BidRequest
+		expected    *openrtb.BidRequest
+	}{
+		{
+			description: "Disabled",
+			policy:      Policy{},
+			request:     &openrtb.BidRequest{},
+			expected:    &openrtb.BidRequest{},
+		},
+		{
+			description: "Enabled - Nil Regs",
+			policy:      Policy{Value: "DBABMA~anything", SectionIDs: []SectionID{2}},
+			request:     &openrtb.BidRequest{},
+			expected: &openrtb.BidRequest{Regs: &openrtb.Regs{
+				Ext: json.RawMessage(`{"gpp":"DBABMA~anything","gpp_sid":[2]}`),
+			}},
+		},
+		{
+			description: "Enabled - Existing Ext",
+			policy:      Policy{Value: "DBABMA~anything"},
+			request: &openrtb.BidRequest{Regs: &openrtb.Regs{
+				Ext: json.RawMessage(`{"us_privacy":"1YNN","gpp":"old"}`),
+			}},
+			expected: &openrtb.BidRequest{Regs: &openrtb.Regs{
+				Ext: json.RawMessage(`{"gpp":"DBABMA~anything","us_privacy":"1YNN"}`),
+			}},
+		},
+	}
+
+	for _, test := range testCases {
+		err := test.policy.Write(test.request)
+		assert.NoError(t, err, test.description)
+		assert.Equal(t, test.expected, test.request, test.description)
+	}
+}
+
+func TestParse(t *testing.T) {
+	parsed, err := Parse("DBABMA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA")
+	assert.NoError(t, err)
+	assert.Equal(t, GPP{Version: 1, Sections: []Section{{ID: SectionTCFEUv2, Value: "CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA"}}}, parsed)
+
+	parsed, err = Parse("DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN")
+	assert.NoError(t, err)
+	assert.Equal(t, []Section{
+		{ID: SectionTCFEUv2, Value: "CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA"},
+		{ID: SectionUSPv1, Value: "1YNN"},
+	}, parsed.Sections)
+
+	header := newBitWriter().int(3, 6).int(1, 6).int(1, 12).int(1, 1).fibonacci(7).fibonacci(5).String()
+	parsed, err = Parse(header + "~a~b~c~d~e~f")
+	assert.NoError(t, err)
+	ids := make([]SectionID, len(parsed.Sections))
+	for i, section := range parsed.Sections {
+		ids[i] = section.ID
+	}
+	assert.Equal(t, []SectionID{7, 8, 9, 10, 11, 12}, ids, "A range of section IDs should be expanded")
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		value       string
+		expected    string
+	}{
+		{
+			description: "Not Base64",
+			value:       "D*ABMA~a",
+			expected:    "the GPP header is invalid: the value must be base64url encoded",
+		},
+		{
+			description: "Wrong Type",
+			value:       "BBABMA~a",
+			expected:    "the GPP header must have type 3, not 1",
+		},
+		{
+			description: "Truncated Header",
+			value:       "DBAB",
+			expected:    "the GPP header is invalid: the value is too short",
+		},
+		{
+			description: "Missing Section",
+			value:       "DBACNYA~a",
+			expected:    "the GPP header doesn't match the number of sections",
+		},
+	}
+
+	for _, test := range testCases {
+		_, err := Parse(test.value)
+		assert.EqualError(t, err, test.expected, test.description)
+		assert.Error(t, Policy{Value: test.value}.Validate(), test.description)
+	}
+	assert.NoError(t, Policy{}.Validate())
+}
+
+func TestParseTCFv2(t *testing.T) {
+	bitfield := tcfv2Core(0xC00000).int(5, 16).int(0, 1).int(0x09, 5).int(0, 16).int(0, 1).String()
+	parsed, err := ParseTCFv2(bitfield + ".YAAAAAAAAAAA")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(40), parsed.VendorListVersion)
+	assert.True(t, parsed.PurposeConsent(1))
+	assert.True(t, parsed.PurposeConsent(2))
+	assert.False(t, parsed.PurposeConsent(3))
+	assert.False(t, parsed.PurposeConsent(25))
+	assert.Equal(t, []bool{false, true, false, false, true}, []bool{
+		parsed.VendorConsent(1), parsed.VendorConsent(2), parsed.VendorConsent(3), parsed.VendorConsent(4), parsed.VendorConsent(5),
+	})
+	assert.False(t, parsed.VendorConsent(6))
+	assert.True(t, IsTCFv2(bitfield))
+
+	ranges := tcfv2Core(0x800000).int(80, 16).int(1, 1).int(2, 12).int(0, 1).int(3, 16).int(1, 1).int(40, 16).int(80, 16).int(0, 16).int(0, 1).String()
+	parsed, err = ParseTCFv2(ranges)
+	assert.NoError(t, err)
+	assert.True(t, parsed.PurposeConsent(1))
+	assert.False(t, parsed.PurposeConsent(2))
+	assert.Equal(t, []bool{false, true, false, true, true, true}, []bool{
+		parsed.VendorConsent(2), parsed.VendorConsent(3), parsed.VendorConsent(4), parsed.VendorConsent(40), parsed.VendorConsent(60), parsed.VendorConsent(80),
+	})
+
+	legitimateInterests := tcfv2CoreLI(0, 0x400000).int(1, 16).int(0, 1).int(1, 1).int(3, 16).int(1, 1).int(1, 12).int(0, 1).int(3, 16).String()
+	parsed, err = ParseTCFv2(legitimateInterests)
+	assert.NoError(t, err)
+	assert.False(t, parsed.PurposeLITransparency(1))
+	assert.True(t, parsed.PurposeLITransparency(2))
+	assert.False(t, parsed.PurposeLITransparency(25))
+	assert.True(t, parsed.VendorConsent(1))
+	assert.False(t, parsed.VendorLegitimateInterest(1))
+	assert.True(t, parsed.VendorLegitimateInterest(3))
+
+	_, err = ParseTCFv2(tcfv2Core(0).int(1, 16).int(0, 1).int(1, 1).String())
+	assert.EqualError(t, err, "the value is too short", "The vendor legitimate interest section is required")
+
+	_, err = ParseTCFv2("BONV8oqONXwgmADACHENAO7pqzAAppY")
+	assert.EqualError(t, err, "the consent string has version 1, not 2")
+	assert.False(t, IsTCFv2("BONV8oqONXwgmADACHENAO7pqzAAppY"))
+
+	_, err = ParseTCFv2(tcfv2Core(0).int(10, 16).String())
+	assert.EqualError(t, err, "the value is too short")
+}
+
+func TestSignals(t *testing.T) {
+	tcf := tcfv2Core(0).int(0, 16).int(0, 1).String()
+	usnatOptOut := newBitWriter().int(1, 6).int(0, 12).int(2, 2).int(1, 2).String()
+	usnatNoOptOut := newBitWriter().int(1, 6).int(0, 12).int(2, 2).int(2, 2).int(2, 2).String()
+	usvaOptOut := newBitWriter().int(1, 6).int(0, 6).int(1, 2).int(2, 2).String() + ".YA"
+	header := func(ids ...uint64) string {
+		w := newBitWriter().int(3, 6).int(1, 6).int(uint64(len(ids)), 12)
+		last := uint64(0)
+		for _, id := range ids {
+			w.int(0, 1).fibonacci(id - last)
+			last = id
+		}
+		return w.String()
+	}
+
+	testCases := []struct {
+		description string
+		policy      Policy
+		expected    Signals
+		expectError bool
+	}{
+		{
+			description: "Empty",
+			policy:      Policy{},
+			expected:    Signals{},
+		},
+		{
+			description: "Section IDs Without TCF",
+			policy:      Policy{SectionIDs: []SectionID{SectionUSNat}},
+			expected:    Signals{GDPR: "0"},
+		},
+		{
+			description: "TCF And USP Apply",
+			policy:      Policy{Value: header(2, 6) + "~" + tcf + "~1YYN", SectionIDs: []SectionID{2, 6}},
+			expected:    Signals{GDPR: "1", TCFConsent: tcf, USPrivacy: "1YYN"},
+		},
+		{
+			description: "No Section IDs Reads Every Section",
+			policy:      Policy{Value: header(2, 7) + "~" + tcf + "~" + usnatOptOut},
+			expected:    Signals{TCFConsent: tcf, USOptOut: true},
+		},
+		{
+			description: "Sections Which Don't Apply Are Ignored",
+			policy:      Policy{Value: header(2, 7) + "~" + tcf + "~" + usnatOptOut, SectionIDs: []SectionID{2}},
+			expected:    Signals{GDPR: "1", TCFConsent: tcf},
+		},
+		{
+			description: "US National Without Opt Out",
+			policy:      Policy{Value: header(7) + "~" + usnatNoOptOut, SectionIDs: []SectionID{7}},
+			expected:    Signals{GDPR: "0"},
+		},
+		{
+			description: "US State Opt Out",
+			policy:      Policy{Value: header(7, 9) + "~" + usnatNoOptOut + "~" + usvaOptOut, SectionIDs: []SectionID{7, 9}},
+			expected:    Signals{GDPR: "0", USOptOut: true},
+		},
+		{
+			description: "Truncated US Section",
+			policy:      Policy{Value: header(7) + "~BA", SectionIDs: []SectionID{7}},
+			expected:    Signals{GDPR: "0"},
+			expectError: true,
+		},
+	}
+
+	for _, test := range testCases {
+		signals, err := test.policy.Signals()
+		assertError(t, test.expectError, err, test.description)
+		assert.Equal(t, test.expected, signals, test.description)
+	}
+}
+
+func TestParseSectionIDs(t *testing.T) {
+	ids, err := ParseSectionIDs("2, 6,7")
+	assert.NoError(t, err)
+	assert.Equal(t, []SectionID{2, 6, 7}, ids)
+	assert.Equal(t, "2,6,7", JoinSectionIDs(ids))
+
+	ids, err = ParseSectionIDs("")
+	assert.NoError(t, err)
+	assert.Nil(t, ids)
+	assert.Equal(t, "", JoinSectionIDs(ids))
+
+	_, err = ParseSectionIDs("2,tcf")
+	assert.EqualError(t, err, `gpp_sid must be a comma separated list of section IDs, but it contains "tcf"`)
+}
+
+func assertError(t *testing.T, expectError bool, err error, description string) {
+	t.Helper()
+	if expectError {
+		assert.Error(t, err, description)
+	} else {
+		assert.NoError(t, err, description)
+	}
+}
+
+// tcfv2Core writes the fields of a TCF v2 core segment up to its vendor consents, with a vendor list version of 40.
+func tcfv2Core(purposesConsent uint64) *bitWriter {
+	return tcfv2CoreLI(purposesConsent, 0)
+}
+
+// tcfv2CoreLI starts a TCF v2 core segment, up to its vendor consent section.
+func tcfv2CoreLI(purposesConsent uint64, purposesLITransparency uint64) *bitWriter {
+	return newBitWriter().int(2, 6).int(0, 36+36+12+12+6+12).int(40, 12).int(0, 6+1+1+12).int(purposesConsent, 24).int(purposesLITransparency, 24).int(0, 1+12)
+}
+
+type bitWriter struct {
+	bits []bool
+}
+
+func newBitWriter() *bitWriter {
+	return &bitWriter{}
+}
+
+func (w *bitWriter) int(value uint64, bits uint) *bitWriter {
+	for i := bits; i > 0; i-- {
+		w.bits = append(w.bits, value&(1<<(i-1)) != 0)
+	}
+	return w
+}
+
+func (w *bitWriter) fibonacci(value uint64) *bitWriter {
+	fibs := []uint64{1, 2}
+	for fibs[len(fibs)-1] <= value {
+		fibs = append(fibs, fibs[len(fibs)-1]+fibs[len(fibs)-2])
+	}
+	code := make([]bool, len(fibs)-1)
+	for i := len(code) - 1; i >= 0; i-- {
+		if fibs[i] <= value {
+			code[i] = true
+			value -= fibs[i]
+		}
+	}
+	for len(code) > 0 && !code[len(code)-1] {
+		code = code[:len(code)-1]
+	}
+	w.bits = append(append(w.bits, code...), true)
+	return w
+}
+
+func (w *bitWriter) String() string {
+	data := make([]byte, (len(w.bits)+7)/8)
+	for i, bit := range w.bits {
+		if bit {
+			data[i/8] |= 1 << (7 - uint(i)%8)
+		}
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -5,12 +5,14 @@ import (
 
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy/ccpa"
 	"github.com/PubMatic-OpenWrap/prebid-server/privacy/gdpr"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy/gpp"
 )
 
 // Policies represents the privacy regulations for an OpenRTB bid request.
 type Policies struct {
 	GDPR gdpr.Policy
 	CCPA ccpa.Policy
+	GPP  gpp.Policy
 }
 
 type policyWriter interface {
@@ -20,7 +22,7 @@ type policyWriter interface {
 // Write mutates an OpenRTB bid request with the policies applied.
 func (p Policies) Write(req *openrtb.BidRequest) error {
 	return writePolicies(req, []policyWriter{
-		p.GDPR, p.CCPA,
+		p.GDPR, p.CCPA, p.GPP,
 	})
 }
 
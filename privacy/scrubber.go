@@ -1,11 +1,26 @@
 package privacy
 
 import (
+	"encoding/json"
 	"strings"
 
 	"github.com/PubMatic-OpenWrap/openrtb"
 )
 
+// ScrubStrategyDeviceID defines the approach to scrub PII from the device identifiers.
+type ScrubStrategyDeviceID int
+
+const (
+	// ScrubStrategyDeviceIDNone does not remove any device identifier.
+	ScrubStrategyDeviceIDNone ScrubStrategyDeviceID = iota
+
+	// ScrubStrategyDeviceIDHashes removes the hashed device and platform ids.
+	ScrubStrategyDeviceIDHashes
+
+	// ScrubStrategyDeviceIDAll removes the hashed ids, the MAC address hashes and the IFA.
+	ScrubStrategyDeviceIDAll
+)
+
 // ScrubStrategyIPV6 defines the approach to scrub PII from an IPV6 address.
 type ScrubStrategyIPV6 int
 
@@ -46,11 +61,15 @@ const (
 
 	// ScrubStrategyUserBuyerIDOnly removes the user's buyer id.
 	ScrubStrategyUserBuyerIDOnly
+
+	// ScrubStrategyUserFirstPartyData removes what ScrubStrategyUserFull does, along with the user's
+	// data segments, keywords, ext.eids and ext.data.
+	ScrubStrategyUserFirstPartyData
 )
 
 // Scrubber removes PII from parts of an OpenRTB request.
 type Scrubber interface {
-	ScrubDevice(device *openrtb.Device, macAndIFA bool, ipv6 ScrubStrategyIPV6, geo ScrubStrategyGeo) *openrtb.Device
+	ScrubDevice(device *openrtb.Device, id ScrubStrategyDeviceID, ipv6 ScrubStrategyIPV6, geo ScrubStrategyGeo) *openrtb.Device
 	ScrubUser(user *openrtb.User, strategy ScrubStrategyUser, geo ScrubStrategyGeo) *openrtb.User
 }
 
@@ -61,20 +80,22 @@ func NewScrubber() Scrubber {
 	return scrubber{}
 }
 
-func (scrubber) ScrubDevice(device *openrtb.Device, macAndIFA bool, ipv6 ScrubStrategyIPV6, geo ScrubStrategyGeo) *openrtb.Device {
+func (scrubber) ScrubDevice(device *openrtb.Device, id ScrubStrategyDeviceID, ipv6 ScrubStrategyIPV6, geo ScrubStrategyGeo) *openrtb.Device {
 	if device == nil {
 		return nil
 	}
 
 	deviceCopy := *device
-
-	deviceCopy.DIDMD5 = ""
-	deviceCopy.DIDSHA1 = ""
-	deviceCopy.DPIDMD5 = ""
-	deviceCopy.DPIDSHA1 = ""
 	deviceCopy.IP = scrubIPV4(device.IP)
 
-	if macAndIFA {
+	if id != ScrubStrategyDeviceIDNone {
+		deviceCopy.DIDMD5 = ""
+		deviceCopy.DIDSHA1 = ""
+		deviceCopy.DPIDMD5 = ""
+		deviceCopy.DPIDSHA1 = ""
+	}
+
+	if id == ScrubStrategyDeviceIDAll {
 		deviceCopy.MACSHA1 = ""
 		deviceCopy.MACMD5 = ""
 		deviceCopy.IFA = ""
@@ -105,11 +126,16 @@ func (scrubber) ScrubUser(user *openrtb.User, strategy ScrubStrategyUser, geo Sc
 	userCopy := *user
 
 	switch strategy {
-	case ScrubStrategyUserFull:
+	case ScrubStrategyUserFull, ScrubStrategyUserFirstPartyData:
 		userCopy.BuyerUID = ""
 		userCopy.ID = ""
 		userCopy.Yob = 0
 		userCopy.Gender = ""
+		if strategy == ScrubStrategyUserFirstPartyData {
+			userCopy.Data = nil
+			userCopy.Keywords = ""
+			userCopy.Ext = scrubExtKeys(user.Ext, "eids", "data")
+		}
 	case ScrubStrategyUserBuyerIDOnly:
 		userCopy.BuyerUID = ""
 	}
@@ -124,6 +150,37 @@ func (scrubber) ScrubUser(user *openrtb.User, strategy ScrubStrategyUser, geo Sc
 	return &userCopy
 }
 
+// scrubExtKeys removes the keys from an ext. An ext which can't be parsed is removed entirely.
+func scrubExtKeys(ext json.RawMessage, keys ...string) json.RawMessage {
+	if len(ext) == 0 {
+		return ext
+	}
+
+	var extMap map[string]json.RawMessage
+	if err := json.Unmarshal(ext, &extMap); err != nil {
+		return nil
+	}
+	found := false
+	for _, key := range keys {
+		if _, ok := extMap[key]; ok {
+			delete(extMap, key)
+			found = true
+		}
+	}
+	if !found {
+		return ext
+	}
+	if len(extMap) == 0 {
+		return nil
+	}
+
+	scrubbed, err := json.Marshal(extMap)
+	if err != nil {
+		return nil
+	}
+	return scrubbed
+}
+
 func scrubIPV4(ip string) string {
 	i := strings.LastIndex(ip, ".")
 	if i == -1 {
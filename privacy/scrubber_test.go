@@ -1,6 +1,7 @@
 package privacy
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/PubMatic-OpenWrap/openrtb"
@@ -29,7 +30,7 @@ func TestScrubDevice(t *testing.T) {
 
 	testCases := []struct {
 		expected    *openrtb.Device
-		isMacAndIFA bool
+		deviceID    ScrubStrategyDeviceID
 		ipv6        ScrubStrategyIPV6
 		geo         ScrubStrategyGeo
 		description string
@@ -47,7 +48,7 @@ func TestScrubDevice(t *testing.T) {
 				IPv6:     "2001:0db8:0000:0000:0000:ff00:0:0",
 				Geo:      &openrtb.Geo{},
 			},
-			isMacAndIFA: true,
+			deviceID:    ScrubStrategyDeviceIDAll,
 			ipv6:        ScrubStrategyIPV6Lowest32,
 			geo:         ScrubStrategyGeoFull,
 			description: "Full Scrubbing",
@@ -65,7 +66,7 @@ func TestScrubDevice(t *testing.T) {
 				IPv6:     "2001:0db8:0000:0000:0000:ff00:0042:0",
 				Geo:      &openrtb.Geo{},
 			},
-			isMacAndIFA: true,
+			deviceID:    ScrubStrategyDeviceIDAll,
 			ipv6:        ScrubStrategyIPV6Lowest16,
 			geo:         ScrubStrategyGeoFull,
 			description: "IPv6 Lowest 16",
@@ -83,7 +84,7 @@ func TestScrubDevice(t *testing.T) {
 				IPv6:     "2001:0db8:0000:0000:0000:ff00:0042:8329",
 				Geo:      &openrtb.Geo{},
 			},
-			isMacAndIFA: true,
+			deviceID:    ScrubStrategyDeviceIDAll,
 			ipv6:        ScrubStrategyIPV6None,
 			geo:         ScrubStrategyGeoFull,
 			description: "IPv6 None",
@@ -107,7 +108,7 @@ func TestScrubDevice(t *testing.T) {
 					ZIP:   "some zip",
 				},
 			},
-			isMacAndIFA: true,
+			deviceID:    ScrubStrategyDeviceIDAll,
 			ipv6:        ScrubStrategyIPV6Lowest32,
 			geo:         ScrubStrategyGeoReducedPrecision,
 			description: "Geo Reduced Precision",
@@ -131,7 +132,7 @@ func TestScrubDevice(t *testing.T) {
 					ZIP:   "some zip",
 				},
 			},
-			isMacAndIFA: true,
+			deviceID:    ScrubStrategyDeviceIDAll,
 			ipv6:        ScrubStrategyIPV6Lowest32,
 			geo:         ScrubStrategyGeoNone,
 			description: "Geo None",
@@ -149,15 +150,39 @@ func TestScrubDevice(t *testing.T) {
 				IPv6:     "2001:0db8:0000:0000:0000:ff00:0:0",
 				Geo:      &openrtb.Geo{},
 			},
-			isMacAndIFA: false,
+			deviceID:    ScrubStrategyDeviceIDHashes,
 			ipv6:        ScrubStrategyIPV6Lowest32,
 			geo:         ScrubStrategyGeoFull,
 			description: "Without MAC Address And IFA Scrubbing",
 		},
+		{
+			expected: &openrtb.Device{
+				DIDMD5:   "anyDIDMD5",
+				DIDSHA1:  "anyDIDSHA1",
+				DPIDMD5:  "anyDPIDMD5",
+				DPIDSHA1: "anyDPIDSHA1",
+				MACSHA1:  "anyMACSHA1",
+				MACMD5:   "anyMACMD5",
+				IFA:      "anyIFA",
+				IP:       "1.2.3.0",
+				IPv6:     "2001:0db8:0000:0000:0000:ff00:0042:0",
+				Geo: &openrtb.Geo{
+					Lat:   123.46,
+					Lon:   678.89,
+					Metro: "some metro",
+					City:  "some city",
+					ZIP:   "some zip",
+				},
+			},
+			deviceID:    ScrubStrategyDeviceIDNone,
+			ipv6:        ScrubStrategyIPV6Lowest16,
+			geo:         ScrubStrategyGeoReducedPrecision,
+			description: "Without Device ID Scrubbing",
+		},
 	}
 
 	for _, test := range testCases {
-		result := NewScrubber().ScrubDevice(device, test.isMacAndIFA, test.ipv6, test.geo)
+		result := NewScrubber().ScrubDevice(device, test.deviceID, test.ipv6, test.geo)
 		assert.Equal(t, test.expected, result, test.description)
 	}
 }
@@ -263,6 +288,58 @@ func TestScrubUser(t *testing.T) {
 	}
 }
 
+func TestScrubUserFirstPartyData(t *testing.T) {
+	testCases := []struct {
+		ext         json.RawMessage
+		expectedExt json.RawMessage
+		description string
+	}{
+		{
+			ext:         json.RawMessage(`{"consent":"anyConsent","eids":[{"source":"anySource"}]}`),
+			expectedExt: json.RawMessage(`{"consent":"anyConsent"}`),
+			description: "Eids Removed",
+		},
+		{
+			ext:         json.RawMessage(`{"eids":[{"source":"anySource"}]}`),
+			expectedExt: nil,
+			description: "Only Eids",
+		},
+		{
+			ext:         json.RawMessage(`{"consent":"anyConsent"}`),
+			expectedExt: json.RawMessage(`{"consent":"anyConsent"}`),
+			description: "No Eids",
+		},
+		{
+			ext:         json.RawMessage(`{"consent":"anyConsent","data":{"interests":["cars"]}}`),
+			expectedExt: json.RawMessage(`{"consent":"anyConsent"}`),
+			description: "Data Removed",
+		},
+		{
+			ext:         json.RawMessage(`{"data":{"interests":["cars"]},"eids":[{"source":"anySource"}]}`),
+			expectedExt: nil,
+			description: "Only Data And Eids",
+		},
+		{
+			ext:         nil,
+			expectedExt: nil,
+			description: "No Ext",
+		},
+	}
+
+	for _, test := range testCases {
+		user := &openrtb.User{
+			BuyerUID: "anyBuyerUID",
+			ID:       "anyID",
+			Keywords: "anyKeywords",
+			Data:     []openrtb.Data{{ID: "anyData"}},
+			Ext:      test.ext,
+		}
+
+		result := NewScrubber().ScrubUser(user, ScrubStrategyUserFirstPartyData, ScrubStrategyGeoNone)
+		assert.Equal(t, &openrtb.User{Ext: test.expectedExt}, result, test.description)
+	}
+}
+
 func TestScrubIPV4(t *testing.T) {
 	testCases := []struct {
 		IP          string
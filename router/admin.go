@@ -8,6 +8,8 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/endpoints"
 )
 
+// Admin builds the handler of the admin port. Once New has been called, it also serves the admin endpoints of the
+// default instance, such as the stored requests admin API.
 func Admin(revision string, rateConverter *currencies.RateConverter) *http.ServeMux {
 	// Add endpoints to the admin server
 	// Making sure to add pprof routes
@@ -21,5 +23,9 @@ func Admin(revision string, rateConverter *currencies.RateConverter) *http.Serve
 	// Register prebid-server defined admin handlers
 	mux.HandleFunc("/currency/rates", endpoints.NewCurrencyRatesEndpoint(rateConverter))
 	mux.HandleFunc("/version", endpoints.NewVersionEndpoint(revision))
+	// The more specific patterns above win over this one.
+	if defaultServer != nil {
+		mux.Handle("/", defaultServer.AdminHandler())
+	}
 	return mux
 }
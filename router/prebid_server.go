@@ -0,0 +1,609 @@
+package router
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/adapters"
+	"github.com/PubMatic-OpenWrap/prebid-server/analytics"
+	analyticsConf "github.com/PubMatic-OpenWrap/prebid-server/analytics/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/cache"
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/currencies"
+	"github.com/PubMatic-OpenWrap/prebid-server/endpoints"
+	infoEndpoints "github.com/PubMatic-OpenWrap/prebid-server/endpoints/info"
+	"github.com/PubMatic-OpenWrap/prebid-server/endpoints/openrtb2"
+	"github.com/PubMatic-OpenWrap/prebid-server/exchange"
+	"github.com/PubMatic-OpenWrap/prebid-server/gdpr"
+	"github.com/PubMatic-OpenWrap/prebid-server/geolocation"
+	"github.com/PubMatic-OpenWrap/prebid-server/geolocation/maxmind"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbs"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
+	pbc "github.com/PubMatic-OpenWrap/prebid-server/prebid_cache_client"
+	"github.com/PubMatic-OpenWrap/prebid-server/ssl"
+	"github.com/PubMatic-OpenWrap/prebid-server/static"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/admin"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/memory_store"
+	storedRequestsConf "github.com/PubMatic-OpenWrap/prebid-server/stored_requests/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
+	"github.com/PubMatic-OpenWrap/prebid-server/usersync/usersyncers"
+
+	"github.com/golang/glog"
+	"github.com/julienschmidt/httprouter"
+)
+
+// PrebidServer is a self-contained instance of Prebid Server.
+//
+// It owns everything its endpoints depend on, so several instances with different configs can run
+// side by side in the same process. Build one with NewPrebidServer.
+type PrebidServer struct {
+	metrics   pbsmetrics.MetricsEngine
+	analytics analytics.PBSAnalyticsModule
+	dataCache cache.Cache
+	router    *httprouter.Router
+	// adminRouter holds the endpoints which only the admin port serves.
+	adminRouter *httprouter.Router
+	shutdown    func()
+
+	// opts holds the dependencies which don't change when the config is reloaded.
+	opts          serverOptions
+	rateConverter *currencies.RateConverter
+	// current holds the *endpointSet built from the latest config. reloadLock serializes the reloads.
+	current    atomic.Value
+	reloadLock sync.Mutex
+
+	// lifecycle guards closing, so that no request gets added to inFlight once Shutdown has started to wait on it.
+	lifecycle    sync.Mutex
+	closing      bool
+	inFlight     sync.WaitGroup
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+// endpointSet holds everything which gets rebuilt when the config is reloaded.
+type endpointSet struct {
+	// active is read locked by the requests which use the set, so that a replaced set can wait for them.
+	active        sync.RWMutex
+	cfg           *config.Configuration
+	syncers       map[openrtb_ext.BidderName]usersync.Usersyncer
+	gdprVendorIDs map[openrtb_ext.BidderName]uint16
+	gdprPerms     gdpr.Permissions
+	legacyMap     map[string]adapters.Adapter
+	geoLocation   geolocation.GeoLocation
+	exchange      exchange.Exchange
+	auction       httprouter.Handle
+	amp           httprouter.Handle
+	video         httprouter.Handle
+	cookieSync    httprouter.Handle
+	syncPage      httprouter.Handle
+	setUID        httprouter.Handle
+	optOut        httprouter.Handle
+	getUIDs       httprouter.Handle
+	infoBidders   httprouter.Handle
+	bidderDetails httprouter.Handle
+	bidderParams  httprouter.Handle
+	status        httprouter.Handle
+	storedData    *openrtb2.StoredDataValidator
+}
+
+// Option customizes the dependencies of a PrebidServer. Anything which isn't overridden is built from the config.
+type Option func(*serverOptions)
+
+type serverOptions struct {
+	client            *http.Client
+	metricsEngine     pbsmetrics.MetricsEngine
+	analytics         analytics.PBSAnalyticsModule
+	storedReqFetcher  stored_requests.Fetcher
+	ampFetcher        stored_requests.Fetcher
+	videoFetcher      stored_requests.Fetcher
+	categoriesFetcher stored_requests.CategoryFetcher
+	paramsValidator   openrtb_ext.BidderParamValidator
+	bidderParams      static.FS
+	bidderInfos       static.FS
+	auctionStore      stored_requests.Store
+	ampStore          stored_requests.Store
+	videoStore        stored_requests.Store
+	geoLocation       geolocation.GeoLocation
+}
+
+// WithHTTPClient sets the client used to call the bidders, Prebid Cache and the other remote services.
+func WithHTTPClient(client *http.Client) Option {
+	return func(opts *serverOptions) {
+		opts.client = client
+	}
+}
+
+// WithMetricsEngine sets the engine which records the metrics of the instance.
+func WithMetricsEngine(metricsEngine pbsmetrics.MetricsEngine) Option {
+	return func(opts *serverOptions) {
+		opts.metricsEngine = metricsEngine
+	}
+}
+
+// WithAnalytics sets the analytics module which the endpoints log to.
+func WithAnalytics(module analytics.PBSAnalyticsModule) Option {
+	return func(opts *serverOptions) {
+		opts.analytics = module
+	}
+}
+
+// WithStoredRequestFetcher sets the fetcher for the Stored Requests and Imps of the /openrtb2/auction,
+// /openrtb2/amp and /openrtb2/video endpoints.
+func WithStoredRequestFetcher(fetcher stored_requests.Fetcher) Option {
+	return func(opts *serverOptions) {
+		opts.storedReqFetcher = fetcher
+		opts.ampFetcher = fetcher
+		opts.videoFetcher = fetcher
+	}
+}
+
+// WithStoredRequestStores sets the writable stores which the stored requests admin API manages, when the config enables it.
+// Any store left nil keeps its data in memory. The stores are only read by the fetchers built from the config,
+// not by the one set through WithStoredRequestFetcher.
+func WithStoredRequestStores(auction stored_requests.Store, amp stored_requests.Store, video stored_requests.Store) Option {
+	return func(opts *serverOptions) {
+		opts.auctionStore = auction
+		opts.ampStore = amp
+		opts.videoStore = video
+	}
+}
+
+// WithCategoriesFetcher sets the fetcher for the category mappings.
+func WithCategoriesFetcher(fetcher stored_requests.CategoryFetcher) Option {
+	return func(opts *serverOptions) {
+		opts.categoriesFetcher = fetcher
+	}
+}
+
+// WithParamsValidator sets the validator for the bidder params. It takes precedence over WithStaticDirectories.
+func WithParamsValidator(validator openrtb_ext.BidderParamValidator) Option {
+	return func(opts *serverOptions) {
+		opts.paramsValidator = validator
+	}
+}
+
+// WithStaticDirectories sets the directories which hold the bidder params JSON schemas and the bidder info files.
+// It takes precedence over the static_assets config.
+func WithStaticDirectories(schemaDirectory string, infoDirectory string) Option {
+	return WithStaticFiles(static.Dir(schemaDirectory), static.Dir(infoDirectory))
+}
+
+// WithStaticFiles sets the trees which hold the bidder params JSON schemas and the bidder info files.
+// It takes precedence over the static_assets config.
+func WithStaticFiles(bidderParams static.FS, bidderInfos static.FS) Option {
+	return func(opts *serverOptions) {
+		opts.bidderParams = bidderParams
+		opts.bidderInfos = bidderInfos
+	}
+}
+
+// WithGeoLocation sets the lookup which fills the requests' device.geo. By default, it's built from the
+// geolocation config whenever the config is loaded.
+func WithGeoLocation(geoLocation geolocation.GeoLocation) Option {
+	return func(opts *serverOptions) {
+		opts.geoLocation = geoLocation
+	}
+}
+
+// NewPrebidServer builds a Prebid Server instance from the config.
+func NewPrebidServer(cfg *config.Configuration, rateConverter *currencies.RateConverter, options ...Option) (*PrebidServer, error) {
+	opts := serverOptions{
+		bidderParams: staticFiles(cfg.StaticAssets.BidderParamsDirectory, static.BidderParamsDir),
+		bidderInfos:  staticFiles(cfg.StaticAssets.BidderInfoDirectory, static.BidderInfoDir),
+	}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if opts.client == nil {
+		opts.client = newHTTPClient(cfg)
+	}
+	if opts.metricsEngine == nil {
+		opts.metricsEngine = metricsConf.NewMetricsEngine(cfg, legacyBidderList(cfg))
+	}
+	if opts.analytics == nil {
+		opts.analytics = analyticsConf.NewPBSAnalyticsWithActivities(&cfg.Analytics, &cfg.ActivityControls)
+	}
+	if opts.paramsValidator == nil {
+		validator, err := openrtb_ext.NewBidderParamsValidatorFS(opts.bidderParams)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to create the bidder params validator. %v", err)
+		}
+		opts.paramsValidator = validator
+	}
+
+	s := &PrebidServer{
+		metrics:       opts.metricsEngine,
+		analytics:     opts.analytics,
+		router:        httprouter.New(),
+		adminRouter:   httprouter.New(),
+		rateConverter: rateConverter,
+	}
+
+	admins := s.newAdminEndpoints(cfg.StoredRequestsAdmin, &opts)
+	_, shutdown, fetcher, ampFetcher, categoriesFetcher, videoFetcher, dataCacheFetcher := storedRequestsConf.NewStoredRequests(cfg, s.metrics, opts.client, s.router, admins)
+	s.shutdown = shutdown
+	if opts.storedReqFetcher == nil {
+		opts.storedReqFetcher, opts.ampFetcher, opts.videoFetcher = fetcher, ampFetcher, videoFetcher
+	}
+	if opts.categoriesFetcher == nil {
+		opts.categoriesFetcher = categoriesFetcher
+	}
+	s.opts = opts
+
+	var err error
+	if s.dataCache, err = loadDataCache(cfg, dataCacheFetcher); err != nil {
+		return nil, fmt.Errorf("Prebid Server could not load data cache: %v", err)
+	}
+
+	initial, err := s.newEndpointSet(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.current.Store(initial)
+
+	s.router.POST("/auction", s.route(func(e *endpointSet) httprouter.Handle { return e.legacyAuction(s) }))
+	s.router.POST("/openrtb2/auction", s.route(func(e *endpointSet) httprouter.Handle { return e.auction }))
+	s.router.POST("/openrtb2/video", s.route(func(e *endpointSet) httprouter.Handle { return e.video }))
+	s.router.GET("/openrtb2/amp", s.route(func(e *endpointSet) httprouter.Handle { return e.amp }))
+	s.router.GET("/info/bidders", s.route(func(e *endpointSet) httprouter.Handle { return e.infoBidders }))
+	s.router.GET("/info/bidders/:bidderName", s.route(func(e *endpointSet) httprouter.Handle { return e.bidderDetails }))
+	s.router.GET("/bidders/params", s.route(func(e *endpointSet) httprouter.Handle { return e.bidderParams }))
+	s.router.POST("/cookie_sync", s.route(func(e *endpointSet) httprouter.Handle { return e.cookieSync }))
+	s.router.GET("/sync", s.route(func(e *endpointSet) httprouter.Handle { return e.syncPage }))
+	s.router.GET("/setuid", s.route(func(e *endpointSet) httprouter.Handle { return e.setUID }))
+	s.router.POST("/optout", s.route(func(e *endpointSet) httprouter.Handle { return e.optOut }))
+	s.router.GET("/optout", s.route(func(e *endpointSet) httprouter.Handle { return e.optOut }))
+	s.router.GET("/getuids", s.route(func(e *endpointSet) httprouter.Handle { return e.getUIDs }))
+	s.router.GET("/status", s.route(func(e *endpointSet) httprouter.Handle { return e.status }))
+
+	return s, nil
+}
+
+// newEndpointSet builds the exchange and the endpoints for cfg, on top of the dependencies which
+// the instance keeps across reloads.
+//
+// The GDPR permissions, the legacy adapters and the geolocation database are taken from prev if their
+// settings haven't changed, so that a reload doesn't fetch the vendor lists, open new connection pools or
+// read the database again.
+func (s *PrebidServer) newEndpointSet(cfg *config.Configuration, prev *endpointSet) (*endpointSet, error) {
+	opts := s.opts
+	e := &endpointSet{cfg: cfg}
+
+	disabledBidders := map[string]string{
+		"indexExchange": "Bidder \"indexExchange\" has been deprecated and is no longer available. Please use bidder \"ix\" and note that the bidder params have changed.",
+	}
+	bidderInfos := adapters.ParseBidderInfosFS(cfg.Adapters, opts.bidderInfos, openrtb_ext.BidderList())
+	bidderMap := exchange.DisableBidders(bidderInfos, disabledBidders)
+
+	aliases, defReqJSON, err := readDefaultRequest(cfg.DefReqConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read the default request. %v", err)
+	}
+	aliases = withHostAliases(aliases, cfg.BidderAliases)
+
+	e.syncers = usersyncers.NewSyncerMap(cfg)
+	e.gdprVendorIDs = adapters.GDPRAwareSyncerIDs(e.syncers)
+	if prev != nil && reflect.DeepEqual(prev.cfg.GDPR, cfg.GDPR) && reflect.DeepEqual(prev.gdprVendorIDs, e.gdprVendorIDs) {
+		e.gdprPerms = prev.gdprPerms
+	} else {
+		e.gdprPerms = gdpr.NewPermissions(context.Background(), cfg.GDPR, e.gdprVendorIDs, opts.client)
+	}
+	if prev != nil && reflect.DeepEqual(prev.cfg.Adapters, cfg.Adapters) {
+		e.legacyMap = prev.legacyMap
+	} else {
+		e.legacyMap = newExchangeMap(cfg)
+	}
+
+	e.geoLocation = opts.geoLocation
+	if e.geoLocation == nil && prev != nil && reflect.DeepEqual(prev.cfg.GeoLocation, cfg.GeoLocation) {
+		e.geoLocation = prev.geoLocation
+	}
+	if e.geoLocation == nil {
+		if e.geoLocation, err = newGeoLocation(cfg.GeoLocation); err != nil {
+			return nil, fmt.Errorf("Failed to load the geolocation database. %v", err)
+		}
+	}
+
+	theExchange := exchange.NewExchange(opts.client, pbc.NewClient(&cfg.CacheURL, &cfg.ExtCacheURL, s.metrics), cfg, s.metrics, bidderInfos, e.gdprPerms, s.rateConverter, e.geoLocation)
+	e.exchange = theExchange
+
+	if e.auction, err = openrtb2.NewEndpoint(theExchange, opts.paramsValidator, opts.storedReqFetcher, opts.categoriesFetcher, cfg, s.metrics, s.analytics, disabledBidders, defReqJSON, bidderMap); err != nil {
+		return nil, fmt.Errorf("Failed to create the openrtb endpoint handler. %v", err)
+	}
+	if e.amp, err = openrtb2.NewAmpEndpoint(theExchange, opts.paramsValidator, opts.ampFetcher, opts.categoriesFetcher, cfg, s.metrics, s.analytics, disabledBidders, defReqJSON, bidderMap); err != nil {
+		return nil, fmt.Errorf("Failed to create the amp endpoint handler. %v", err)
+	}
+	if e.video, err = openrtb2.NewVideoEndpoint(theExchange, opts.paramsValidator, opts.storedReqFetcher, opts.videoFetcher, opts.categoriesFetcher, cfg, s.metrics, s.analytics, disabledBidders, defReqJSON, bidderMap); err != nil {
+		return nil, fmt.Errorf("Failed to create the video endpoint handler. %v", err)
+	}
+	e.cookieSync = endpoints.NewCookieSyncEndpoint(e.syncers, cfg, e.gdprPerms, s.metrics, s.analytics)
+	e.syncPage = endpoints.NewSyncPageEndpoint(e.syncers, cfg, e.gdprPerms, s.metrics, s.analytics)
+	e.setUID = endpoints.NewSetUIDEndpoint(cfg, e.syncers, e.gdprPerms, s.analytics, s.metrics)
+	e.optOut = (&pbs.UserSyncDeps{
+		ExternalUrl:      cfg.ExternalURL,
+		OptOutVerifier:   pbs.NewOptOutVerifier(cfg),
+		HostCookieConfig: &cfg.HostCookie,
+		MetricsEngine:    s.metrics,
+		PBSAnalytics:     s.analytics,
+	}).OptOut
+	e.getUIDs = endpoints.NewGetUIDsEndpoint(cfg.HostCookie)
+	infoMetadata := infoEndpoints.Metadata{
+		Adapters:        cfg.Adapters,
+		HostAliases:     cfg.BidderAliases,
+		Syncers:         e.syncers,
+		ParamsValidator: opts.paramsValidator,
+	}
+	e.infoBidders = infoEndpoints.NewBiddersEndpoint(bidderInfos, aliases, infoMetadata)
+	e.bidderDetails = infoEndpoints.NewBidderDetailsEndpoint(bidderInfos, aliases, infoMetadata)
+	if e.bidderParams, err = NewJsonDirectoryServerFS(opts.bidderParams, opts.paramsValidator, aliases); err != nil {
+		return nil, fmt.Errorf("Failed to create the bidder params endpoint handler. %v", err)
+	}
+	e.status = endpoints.NewStatusEndpoint(cfg.StatusResponse)
+	e.storedData = openrtb2.NewStoredDataValidator(opts.paramsValidator, cfg, bidderMap)
+
+	return e, nil
+}
+
+// newAdminEndpoints builds the stored requests admin API and registers its routes on the admin router, if the
+// config enables it. The data is validated with the latest config.
+func (s *PrebidServer) newAdminEndpoints(cfg config.StoredRequestsAdmin, opts *serverOptions) storedRequestsConf.AdminEndpoints {
+	if !cfg.Enabled {
+		return storedRequestsConf.AdminEndpoints{}
+	}
+	glog.Infof("Serving the stored requests admin API under %s on the admin port", cfg.Endpoint)
+
+	admins := storedRequestsConf.AdminEndpoints{
+		Auction: admin.NewEndpoint(storeOrMemory(opts.auctionStore),
+			func(data json.RawMessage) []error { return s.endpoints().storedData.ValidateRequest(data) },
+			func(data json.RawMessage) []error { return s.endpoints().storedData.ValidateImp(data) }),
+		AMP: admin.NewEndpoint(storeOrMemory(opts.ampStore),
+			func(data json.RawMessage) []error { return s.endpoints().storedData.ValidateAmpRequest(data) }, nil),
+		Video: admin.NewEndpoint(storeOrMemory(opts.videoStore),
+			func(data json.RawMessage) []error { return s.endpoints().storedData.ValidateVideoRequest(data) }, nil),
+	}
+	admins.Auction.Register(s.adminRouter, cfg.Endpoint+"/openrtb2", cfg.Token)
+	admins.AMP.Register(s.adminRouter, cfg.Endpoint+"/amp", cfg.Token)
+	admins.Video.Register(s.adminRouter, cfg.Endpoint+"/video", cfg.Token)
+	return admins
+}
+
+// newGeoLocation loads the geolocation database, or returns a lookup which never finds anything if it's disabled.
+func newGeoLocation(cfg config.GeoLocation) (geolocation.GeoLocation, error) {
+	if !cfg.Enabled {
+		return geolocation.NilGeoLocation{}, nil
+	}
+	return maxmind.NewGeoLocation(cfg.Database)
+}
+
+func storeOrMemory(store stored_requests.Store) stored_requests.Store {
+	if store == nil {
+		glog.Warning("The stored requests admin API keeps its data in memory. It will be lost on restart, and other instances won't see it.")
+		return memory_store.NewStore()
+	}
+	return store
+}
+
+func (e *endpointSet) legacyAuction(s *PrebidServer) httprouter.Handle {
+	return endpoints.Auction(e.cfg, e.syncers, e.gdprPerms, s.metrics, s.dataCache, e.legacyMap)
+}
+
+// endpoints returns the endpoints built from the latest config.
+func (s *PrebidServer) endpoints() *endpointSet {
+	return s.current.Load().(*endpointSet)
+}
+
+// route looks the handle up on every request, so that the router always serves the latest config.
+func (s *PrebidServer) route(pick func(*endpointSet) httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		e := s.endpoints()
+		e.active.RLock()
+		defer e.active.RUnlock()
+		pick(e)(w, r, ps)
+	}
+}
+
+// retire waits for the requests which use the set to finish, then closes the idle connections of the
+// bidder clients which its exchange built. The next set has clients of its own. A request which still
+// picks the set up afterwards works, since closing the idle connections doesn't stop new calls.
+func (e *endpointSet) retire() {
+	e.active.Lock()
+	defer e.active.Unlock()
+	if closer, ok := e.exchange.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+// staticFiles reads from the configured directory, or from the copy of static/{embeddedDir} in the binary if there isn't one.
+func staticFiles(directory string, embeddedDir string) static.FS {
+	if directory == "" {
+		return static.Embedded(embeddedDir)
+	}
+	return static.Dir(directory)
+}
+
+// newHTTPClient builds the default client of an instance. It trusts both the hardcoded certificates and
+// the ones found in the container's local file system.
+func newHTTPClient(cfg *config.Configuration) *http.Client {
+	certPool := ssl.GetRootCAPool()
+	var readCertErr error
+	certPool, readCertErr = ssl.AppendPEMFileToRootCAPool(certPool, cfg.PemCertsFile)
+	if readCertErr != nil {
+		glog.Infof("Could not read certificates file: %s \n", readCertErr.Error())
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			MaxIdleConns:        cfg.Client.MaxIdleConns,
+			MaxIdleConnsPerHost: cfg.Client.MaxIdleConnsPerHost,
+			IdleConnTimeout:     time.Duration(cfg.Client.IdleConnTimeout) * time.Second,
+			TLSClientConfig:     &tls.Config{RootCAs: certPool},
+		},
+	}
+}
+
+// legacyBidderList is the list of bidders which get metrics. It's a hack because of how legacy handles districtm.
+// The host's bidder_aliases get metrics of their own too. Since the metrics engine is only built once, Reload
+// rejects the configs which add or remove aliases.
+func legacyBidderList(cfg *config.Configuration) []openrtb_ext.BidderName {
+	bidders := append(openrtb_ext.BidderList(), openrtb_ext.BidderName("districtm"))
+	for aliasName := range cfg.BidderAliases {
+		bidders = append(bidders, openrtb_ext.BidderName(aliasName))
+	}
+	return bidders
+}
+
+// withHostAliases adds the host's bidder_aliases to the default request's aliases, which are shown by the info endpoints.
+func withHostAliases(aliases map[string]string, hostAliases map[string]config.BidderAlias) map[string]string {
+	merged := make(map[string]string, len(aliases)+len(hostAliases))
+	for aliasName, alias := range hostAliases {
+		merged[aliasName] = alias.Bidder
+	}
+	for aliasName, coreBidder := range aliases {
+		merged[aliasName] = coreBidder
+	}
+	return merged
+}
+
+// Auction handles a request to /openrtb2/auction.
+func (s *PrebidServer) Auction(w http.ResponseWriter, r *http.Request) {
+	s.serveEndpoint(func(e *endpointSet) httprouter.Handle { return e.auction }, w, r)
+}
+
+// AMP handles a request to /openrtb2/amp.
+func (s *PrebidServer) AMP(w http.ResponseWriter, r *http.Request) {
+	s.serveEndpoint(func(e *endpointSet) httprouter.Handle { return e.amp }, w, r)
+}
+
+// Video handles a request to /openrtb2/video.
+func (s *PrebidServer) Video(w http.ResponseWriter, r *http.Request) {
+	s.serveEndpoint(func(e *endpointSet) httprouter.Handle { return e.video }, w, r)
+}
+
+// LegacyAuction handles a request to the legacy /auction endpoint.
+func (s *PrebidServer) LegacyAuction(w http.ResponseWriter, r *http.Request) {
+	s.serveEndpoint(func(e *endpointSet) httprouter.Handle { return e.legacyAuction(s) }, w, r)
+}
+
+// CookieSync handles a request to /cookie_sync.
+func (s *PrebidServer) CookieSync(w http.ResponseWriter, r *http.Request) {
+	s.serveEndpoint(func(e *endpointSet) httprouter.Handle { return e.cookieSync }, w, r)
+}
+
+// SyncPage handles a request to /sync.
+func (s *PrebidServer) SyncPage(w http.ResponseWriter, r *http.Request) {
+	s.serveEndpoint(func(e *endpointSet) httprouter.Handle { return e.syncPage }, w, r)
+}
+
+// SetUID handles a request to /setuid.
+func (s *PrebidServer) SetUID(w http.ResponseWriter, r *http.Request) {
+	s.serveEndpoint(func(e *endpointSet) httprouter.Handle { return e.setUID }, w, r)
+}
+
+// OptOut handles a request to /optout.
+func (s *PrebidServer) OptOut(w http.ResponseWriter, r *http.Request) {
+	s.serveEndpoint(func(e *endpointSet) httprouter.Handle { return e.optOut }, w, r)
+}
+
+// GetUIDs handles a request to /getuids.
+func (s *PrebidServer) GetUIDs(w http.ResponseWriter, r *http.Request) {
+	s.serveEndpoint(func(e *endpointSet) httprouter.Handle { return e.getUIDs }, w, r)
+}
+
+// serveEndpoint serves a request to one of the embedded endpoints. Like the routed ones, it holds the set
+// for the whole call, so that a reload doesn't retire the set while the request is using it.
+func (s *PrebidServer) serveEndpoint(pick func(*endpointSet) httprouter.Handle, w http.ResponseWriter, r *http.Request) {
+	s.serve(s.route(pick), w, r)
+}
+
+// Handler serves all the public endpoints of the instance, for hosts which run it as a standalone server.
+func (s *PrebidServer) Handler() http.Handler {
+	return NoCache{Handler: SupportCORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.serve(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+			s.router.ServeHTTP(w, r)
+		}, w, r)
+	}))}
+}
+
+// AdminHandler serves the endpoints of the instance which belong on the admin port, such as the stored
+// requests admin API. Hosts which run it as a standalone server should mount it on their admin server.
+func (s *PrebidServer) AdminHandler() http.Handler {
+	return NoCache{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.serve(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+			s.adminRouter.ServeHTTP(w, r)
+		}, w, r)
+	})}
+}
+
+// serve runs the handle unless the instance is shutting down, in which case it responds with a 503.
+func (s *PrebidServer) serve(handle httprouter.Handle, w http.ResponseWriter, r *http.Request) {
+	s.lifecycle.Lock()
+	if s.closing {
+		s.lifecycle.Unlock()
+		http.Error(w, "Prebid Server is shutting down.", http.StatusServiceUnavailable)
+		return
+	}
+	s.inFlight.Add(1)
+	s.lifecycle.Unlock()
+
+	defer s.inFlight.Done()
+	handle(w, r, nil)
+}
+
+// Shutdown stops the instance gracefully. New requests get a 503 right away, and the ones in flight
+// are given until the context is done to finish. Then the Stored Request pollers and listeners are
+// stopped, the analytics modules are flushed and the database connections are closed.
+//
+// Shutdown is safe to call more than once. Every call returns the result of the first one.
+func (s *PrebidServer) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() {
+		s.lifecycle.Lock()
+		s.closing = true
+		s.lifecycle.Unlock()
+
+		drained := make(chan struct{})
+		go func() {
+			s.inFlight.Wait()
+			close(drained)
+		}()
+		select {
+		case <-drained:
+		case <-ctx.Done():
+			s.shutdownErr = fmt.Errorf("Prebid Server shut down with requests still in flight: %v", ctx.Err())
+		}
+
+		if flusher, ok := s.analytics.(analytics.Flusher); ok {
+			flusher.Flush()
+		}
+		if s.dataCache != nil {
+			if err := s.dataCache.Close(); err != nil && s.shutdownErr == nil {
+				s.shutdownErr = fmt.Errorf("Failed to close the data cache: %v", err)
+			}
+		}
+		// This closes the database connection, so it has to come after the data cache.
+		if s.shutdown != nil {
+			s.shutdown()
+		}
+	})
+	return s.shutdownErr
+}
+
+// SyncerMap returns the usersyncers of the instance, keyed by bidder.
+func (s *PrebidServer) SyncerMap() map[openrtb_ext.BidderName]usersync.Usersyncer {
+	return s.endpoints().syncers
+}
+
+// MetricsEngine returns the engine which records the metrics of the instance.
+func (s *PrebidServer) MetricsEngine() pbsmetrics.MetricsEngine {
+	return s.metrics
+}
@@ -0,0 +1,236 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/currencies"
+	"github.com/PubMatic-OpenWrap/prebid-server/geolocation"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/empty_fetcher"
+	"github.com/julienschmidt/httprouter"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPrebidServer(t *testing.T, options ...Option) *PrebidServer {
+	t.Helper()
+	v := viper.New()
+	config.SetupViper(v, "")
+	cfg, err := config.New(v)
+	if !assert.NoError(t, err, "Failed to build the default config") {
+		t.FailNow()
+	}
+
+	options = append([]Option{
+		WithStoredRequestFetcher(empty_fetcher.EmptyFetcher{}),
+	}, options...)
+	s, err := NewPrebidServer(cfg, currencies.NewRateConverterDefault(), options...)
+	if !assert.NoError(t, err, "Failed to build the Prebid Server instance") {
+		t.FailNow()
+	}
+	return s
+}
+
+func TestPrebidServerInstancesAreIsolated(t *testing.T) {
+	firstMetrics := &metricsConf.DummyMetricsEngine{}
+	secondMetrics := &metricsConf.DummyMetricsEngine{}
+	first := newTestPrebidServer(t, WithMetricsEngine(firstMetrics))
+	second := newTestPrebidServer(t, WithMetricsEngine(secondMetrics))
+
+	assert.True(t, first.MetricsEngine() == firstMetrics, "The first instance should use its own metrics engine")
+	assert.True(t, second.MetricsEngine() == secondMetrics, "The second instance should use its own metrics engine")
+	assert.NotEmpty(t, first.SyncerMap())
+	assert.NotEmpty(t, second.SyncerMap())
+}
+
+func TestPrebidServerAuction(t *testing.T) {
+	s := newTestPrebidServer(t, WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+
+	req := httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader("{}"))
+	recorder := httptest.NewRecorder()
+	s.Auction(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code, "An auction without imps should be rejected")
+}
+
+func TestPrebidServerGetUIDs(t *testing.T) {
+	s := newTestPrebidServer(t, WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+
+	req := httptest.NewRequest("GET", "/getuids", nil)
+	recorder := httptest.NewRecorder()
+	s.GetUIDs(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{}`, recorder.Body.String())
+}
+
+func TestAuctionWrappers(t *testing.T) {
+	defaultServer = nil
+	wrappers := map[string]func(w http.ResponseWriter, r *http.Request) error{
+		"openrtb2": OrtbAuctionEndpointWrapper,
+		"amp":      AmpAuctionEndpointWrapper,
+		"video":    VideoAuctionEndpointWrapper,
+	}
+	for name, wrapper := range wrappers {
+		err := wrapper(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader("{}")))
+		assert.Equal(t, errNotInitialized, err, "The %s wrapper should fail before the server is initialized", name)
+	}
+	handlers := map[string]func(w http.ResponseWriter, r *http.Request){
+		"auction":    AuctionWrapper,
+		"getuids":    GetUIDSWrapper,
+		"setuid":     SetUIDSWrapper,
+		"cookiesync": CookieSync,
+		"handler":    Handler().ServeHTTP,
+	}
+	for name, handler := range handlers {
+		recorder := httptest.NewRecorder()
+		handler(recorder, httptest.NewRequest("POST", "/", strings.NewReader("{}")))
+		assert.Equal(t, http.StatusServiceUnavailable, recorder.Code, "The %s wrapper should fail before the server is initialized", name)
+	}
+	assert.Nil(t, SyncerMap())
+
+	defaultServer = newTestPrebidServer(t, WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+	defer func() { defaultServer = nil }()
+
+	recorder := httptest.NewRecorder()
+	assert.NoError(t, AmpAuctionEndpointWrapper(recorder, httptest.NewRequest("GET", "/openrtb2/amp", nil)))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code, "An AMP request without a tag_id should be rejected")
+
+	recorder = httptest.NewRecorder()
+	assert.NoError(t, VideoAuctionEndpointWrapper(recorder, httptest.NewRequest("POST", "/openrtb2/video", strings.NewReader("{}"))))
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code, "The video endpoint reports invalid requests as critical errors")
+}
+
+func TestPrebidServerStaticDirectories(t *testing.T) {
+	newTestPrebidServer(t, WithStaticDirectories("../static/bidder-params", "../static/bidder-info"))
+
+	v := viper.New()
+	config.SetupViper(v, "")
+	v.Set("static_assets.bidder_params_directory", "./does-not-exist")
+	cfg, err := config.New(v)
+	if !assert.NoError(t, err, "Failed to build the config") {
+		return
+	}
+	_, err = NewPrebidServer(cfg, currencies.NewRateConverterDefault(), WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+	assert.Error(t, err, "A missing bidder params directory should fail the startup")
+}
+
+func TestPrebidServerGeoLocation(t *testing.T) {
+	v := viper.New()
+	config.SetupViper(v, "")
+	v.Set("geolocation.enabled", true)
+	v.Set("geolocation.database", "./does-not-exist.mmdb")
+	cfg, err := config.New(v)
+	if !assert.NoError(t, err, "Failed to build the config") {
+		return
+	}
+	_, err = NewPrebidServer(cfg, currencies.NewRateConverterDefault(), WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+	assert.Error(t, err, "A missing geolocation database should fail the startup")
+
+	_, err = NewPrebidServer(cfg, currencies.NewRateConverterDefault(), WithMetricsEngine(&metricsConf.DummyMetricsEngine{}), WithGeoLocation(geolocation.NilGeoLocation{}))
+	assert.NoError(t, err, "A geolocation option should replace the database")
+}
+
+func TestPrebidServerHandler(t *testing.T) {
+	s := newTestPrebidServer(t, WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/status", nil))
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "no-cache, no-store, must-revalidate", recorder.Header().Get("Cache-Control"))
+
+	recorder = httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code, "An auction without imps should be rejected")
+}
+
+func TestPrebidServerShutdownDrainsRequests(t *testing.T) {
+	s := newTestPrebidServer(t, WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	go s.serve(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		close(started)
+		<-release
+	}, httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	<-started
+
+	shutdownDone := make(chan error)
+	go func() {
+		shutdownDone <- s.Shutdown(context.Background())
+	}()
+
+	select {
+	case <-shutdownDone:
+		t.Fatal("Shutdown should wait for the requests in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	recorder := httptest.NewRecorder()
+	s.Auction(recorder, httptest.NewRequest("POST", "/openrtb2/auction", strings.NewReader("{}")))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code, "New requests should be rejected during the shutdown")
+
+	close(release)
+	assert.NoError(t, <-shutdownDone)
+	assert.NoError(t, s.Shutdown(context.Background()), "A second Shutdown should return the result of the first")
+}
+
+func TestPrebidServerShutdownTimeout(t *testing.T) {
+	s := newTestPrebidServer(t, WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	go s.serve(func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+		close(started)
+		<-release
+	}, httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Error(t, s.Shutdown(ctx), "Shutdown should report the requests which didn't finish in time")
+}
+
+func TestPrebidServerStoredRequestsAdmin(t *testing.T) {
+	v := viper.New()
+	config.SetupViper(v, "")
+	v.Set("stored_requests_admin.enabled", true)
+	v.Set("stored_requests_admin.token", "secret")
+	cfg, err := config.New(v)
+	if !assert.NoError(t, err, "Failed to build the config") {
+		return
+	}
+	s, err := NewPrebidServer(cfg, currencies.NewRateConverterDefault(), WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+	if !assert.NoError(t, err, "Failed to build the Prebid Server instance") {
+		return
+	}
+
+	put := func(path string, body string) int {
+		req := httptest.NewRequest("PUT", path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		recorder := httptest.NewRecorder()
+		s.AdminHandler().ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	publicReq := httptest.NewRequest("PUT", "/admin/storedrequests/openrtb2/imps/imp", strings.NewReader(`{}`))
+	publicReq.Header.Set("Authorization", "Bearer secret")
+	publicRecorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(publicRecorder, publicReq)
+	assert.Equal(t, http.StatusNotFound, publicRecorder.Code, "The admin API shouldn't be served on the public port")
+
+	assert.Equal(t, http.StatusBadRequest, put("/admin/storedrequests/openrtb2/imps/imp", `{"banner":{"format":[{"w":300,"h":250}]},"ext":{"appnexus":{}}}`), "Bad bidder params should be rejected")
+	assert.Equal(t, http.StatusOK, put("/admin/storedrequests/openrtb2/imps/imp", `{"banner":{"format":[{"w":300,"h":250}]},"ext":{"appnexus":{"placementId":12883451}}}`))
+	assert.Equal(t, http.StatusBadRequest, put("/admin/storedrequests/amp/requests/tag", `{"id":"req"}`), "AMP configs need an imp")
+
+	_, imps, errs := s.opts.storedReqFetcher.FetchRequests(context.Background(), nil, []string{"imp"})
+	assert.Empty(t, errs)
+	assert.Contains(t, string(imps["imp"]), "12883451", "The auction should see the saved imp")
+}
@@ -0,0 +1,107 @@
+package router
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+
+	"github.com/golang/glog"
+)
+
+// restartOnlySettings are the config sections which are only read at startup. Reload applies
+// everything else, and warns about changes to these. The exchange and the endpoints which a reload
+// builds see the startup values of these sections too, so that the bidders' own HTTP clients keep
+// inheriting the host's startup http_client settings, for example.
+var restartOnlySettings = []string{
+	"host",
+	"port",
+	"admin_port",
+	"http_client",
+	"enable_gzip",
+	"metrics",
+	"datacache",
+	"stored_requests",
+	"category_mapping",
+	"stored_video_req",
+	"stored_requests_admin",
+	"analytics",
+	"certificates_file",
+	"static_assets",
+	"config_reload",
+}
+
+// Reload swaps in the parts of the instance which depend on cfg: the exchange and its adapters, the
+// disabled bidders, the blacklisted apps and accounts, the GDPR settings, the timeouts and the syncers.
+// Requests in flight finish with the config they started with. Once they have, the bidder clients of
+// the replaced exchange close their idle connections.
+//
+// An invalid config is rejected and the instance keeps the current one. So is a config which adds or removes
+// bidder_aliases, since each alias gets metrics of its own when the metrics engine is built at startup. The
+// sections listed in restartOnlySettings keep their startup values until the next restart.
+func (s *PrebidServer) Reload(cfg *config.Configuration) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("The config was not reloaded because it's invalid: %v", err)
+	}
+
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	if err := checkAliasNames(s.endpoints().cfg.BidderAliases, cfg.BidderAliases); err != nil {
+		return fmt.Errorf("The config was not reloaded: %v", err)
+	}
+
+	current := s.endpoints()
+	next, err := s.newEndpointSet(cfg.WithSectionsOf(current.cfg, restartOnlySettings), current)
+	if err != nil {
+		return fmt.Errorf("The config was not reloaded: %v", err)
+	}
+	changes := config.Diff(current.cfg, cfg)
+	s.current.Store(next)
+	go current.retire()
+
+	if len(changes) == 0 {
+		glog.Info("Reloaded the config. Nothing changed.")
+		return nil
+	}
+	glog.Info("Reloaded the config. The changes are:")
+	for _, change := range changes {
+		if section := restartOnlySection(change); section != "" {
+			glog.Warningf("  %s (%s only takes effect after a restart)", change, section)
+		} else {
+			glog.Infof("  %s", change)
+		}
+	}
+	return nil
+}
+
+// checkAliasNames returns an error if the next bidder_aliases don't have the same names as the current ones.
+// The other settings of an alias can be reloaded.
+func checkAliasNames(current map[string]config.BidderAlias, next map[string]config.BidderAlias) error {
+	var changed []string
+	for name := range next {
+		if _, ok := current[name]; !ok {
+			changed = append(changed, name+" was added")
+		}
+	}
+	for name := range current {
+		if _, ok := next[name]; !ok {
+			changed = append(changed, name+" was removed")
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	sort.Strings(changed)
+	return fmt.Errorf("bidder_aliases can only be added or removed with a restart, since their metrics are registered at startup (%s)", strings.Join(changed, ", "))
+}
+
+func restartOnlySection(change string) string {
+	for _, section := range restartOnlySettings {
+		if strings.HasPrefix(change, section+".") || strings.HasPrefix(change, section+":") {
+			return section
+		}
+	}
+	return ""
+}
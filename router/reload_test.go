@@ -0,0 +1,174 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func newReloadedConfig(t *testing.T, settings map[string]interface{}) *config.Configuration {
+	t.Helper()
+	v := viper.New()
+	config.SetupViper(v, "")
+	for key, value := range settings {
+		v.Set(key, value)
+	}
+	cfg, err := config.New(v)
+	if !assert.NoError(t, err, "Failed to build the config") {
+		t.FailNow()
+	}
+	return cfg
+}
+
+func getStatus(s *PrebidServer) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/status", nil))
+	return recorder
+}
+
+func TestReload(t *testing.T) {
+	s := newTestPrebidServer(t, WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+	assert.Equal(t, http.StatusNoContent, getStatus(s).Code)
+
+	cfg := newReloadedConfig(t, map[string]interface{}{
+		"status_response":  "ready",
+		"blacklisted_apps": []string{"spam-app"},
+	})
+	assert.NoError(t, s.Reload(cfg))
+
+	recorder := getStatus(s)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "ready", recorder.Body.String(), "The endpoints should serve the reloaded config")
+	assert.True(t, s.endpoints().cfg.BlacklistedAppMap["spam-app"], "The blacklisted apps should be reloaded")
+}
+
+func TestReloadRejectsInvalidConfig(t *testing.T) {
+	s := newTestPrebidServer(t, WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+	original := s.endpoints()
+
+	cfg := newReloadedConfig(t, map[string]interface{}{"status_response": "ready"})
+	cfg.MaxRequestSize = -1
+	assert.Error(t, s.Reload(cfg))
+
+	assert.True(t, s.endpoints() == original, "An invalid config shouldn't replace the current one")
+	assert.Equal(t, http.StatusNoContent, getStatus(s).Code)
+}
+
+func TestReloadRejectsMissingDefaultRequest(t *testing.T) {
+	s := newTestPrebidServer(t, WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+	original := s.endpoints()
+
+	cfg := newReloadedConfig(t, map[string]interface{}{
+		"status_response":           "ready",
+		"default_request.type":      "file",
+		"default_request.file.name": "does-not-exist.json",
+	})
+	assert.Error(t, s.Reload(cfg))
+
+	assert.True(t, s.endpoints() == original, "A missing default request shouldn't replace the current config")
+	assert.Equal(t, http.StatusNoContent, getStatus(s).Code)
+}
+
+func TestEmbeddedEndpointsHoldTheSet(t *testing.T) {
+	s := newTestPrebidServer(t, WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+	original := s.endpoints()
+
+	original.active.Lock()
+	served := make(chan struct{})
+	go func() {
+		s.GetUIDs(httptest.NewRecorder(), httptest.NewRequest("GET", "/getuids", nil))
+		close(served)
+	}()
+	select {
+	case <-served:
+		t.Fatal("An embedded request shouldn't use a set which is being retired")
+	case <-time.After(50 * time.Millisecond):
+	}
+	original.active.Unlock()
+	<-served
+}
+
+func TestReloadReusesUnchangedDependencies(t *testing.T) {
+	s := newTestPrebidServer(t, WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+	original := s.endpoints()
+
+	assert.NoError(t, s.Reload(newReloadedConfig(t, map[string]interface{}{"status_response": "ready"})))
+	reloaded := s.endpoints()
+	assert.True(t, reloaded.gdprPerms == original.gdprPerms, "The GDPR permissions shouldn't be rebuilt if their settings didn't change")
+	assert.Equal(t, reflect.ValueOf(original.legacyMap).Pointer(), reflect.ValueOf(reloaded.legacyMap).Pointer(), "The legacy adapters shouldn't be rebuilt if the adapters didn't change")
+	assert.True(t, reloaded.geoLocation == original.geoLocation)
+	assert.False(t, reloaded.exchange == original.exchange, "The exchange should be rebuilt")
+
+	assert.NoError(t, s.Reload(newReloadedConfig(t, map[string]interface{}{"adapters.appnexus.endpoint": "http://appnexus.test/bid"})))
+	assert.NotEqual(t, reflect.ValueOf(reloaded.legacyMap).Pointer(), reflect.ValueOf(s.endpoints().legacyMap).Pointer(), "The legacy adapters should pick up the new endpoints")
+}
+
+func TestReloadKeepsRestartOnlySettings(t *testing.T) {
+	s := newTestPrebidServer(t, WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+	original := s.endpoints().cfg
+
+	cfg := newReloadedConfig(t, map[string]interface{}{
+		"status_response":                  "ready",
+		"http_client.max_idle_connections": 1,
+	})
+	assert.NoError(t, s.Reload(cfg))
+
+	assert.Equal(t, original.Client, s.endpoints().cfg.Client, "The exchange should keep the startup http_client settings")
+	assert.Equal(t, "ready", s.endpoints().cfg.StatusResponse)
+}
+
+func TestEndpointSetRetireWaitsForRequests(t *testing.T) {
+	e := &endpointSet{}
+	e.active.RLock()
+
+	retired := make(chan struct{})
+	go func() {
+		e.retire()
+		close(retired)
+	}()
+
+	select {
+	case <-retired:
+		t.Fatal("retire should wait for the requests which use the set")
+	case <-time.After(50 * time.Millisecond):
+	}
+	e.active.RUnlock()
+	<-retired
+}
+
+func TestReloadRejectsAliasChanges(t *testing.T) {
+	s := newTestPrebidServer(t, WithMetricsEngine(&metricsConf.DummyMetricsEngine{}))
+	original := s.endpoints()
+
+	cfg := newReloadedConfig(t, map[string]interface{}{
+		"bidder_aliases": map[string]interface{}{"newalias": map[string]interface{}{"bidder": "appnexus"}},
+	})
+	err := s.Reload(cfg)
+
+	if assert.Error(t, err, "An alias can't get metrics without a restart") {
+		assert.Contains(t, err.Error(), "newalias was added")
+	}
+	assert.True(t, s.endpoints() == original, "A config which adds an alias shouldn't replace the current one")
+}
+
+func TestCheckAliasNames(t *testing.T) {
+	current := map[string]config.BidderAlias{"first": {Bidder: "appnexus"}}
+
+	assert.NoError(t, checkAliasNames(current, map[string]config.BidderAlias{"first": {Bidder: "appnexus", UserSyncURL: "http://sync.com"}}), "The settings of an alias can change")
+	assert.EqualError(t, checkAliasNames(current, map[string]config.BidderAlias{"second": {Bidder: "appnexus"}}), "bidder_aliases can only be added or removed with a restart, since their metrics are registered at startup (first was removed, second was added)")
+	assert.NoError(t, checkAliasNames(nil, map[string]config.BidderAlias{}))
+}
+
+func TestRestartOnlySection(t *testing.T) {
+	assert.Equal(t, "stored_requests", restartOnlySection("stored_requests.filesystem: false -> true"))
+	assert.Equal(t, "port", restartOnlySection("port: 8000 -> 8001"))
+	assert.Equal(t, "", restartOnlySection("portal: a -> b"))
+	assert.Equal(t, "", restartOnlySection("adapters[appnexus].endpoint: a -> b"))
+}
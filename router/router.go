@@ -2,15 +2,12 @@ package router
 
 import (
 	"context"
-	"crypto/tls"
-	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters"
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters/adform"
@@ -22,27 +19,15 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters/pulsepoint"
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters/rubicon"
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters/sovrn"
-	"github.com/PubMatic-OpenWrap/prebid-server/analytics"
-	analyticsConf "github.com/PubMatic-OpenWrap/prebid-server/analytics/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/cache"
-	"github.com/PubMatic-OpenWrap/prebid-server/cache/dummycache"
-	"github.com/PubMatic-OpenWrap/prebid-server/cache/filecache"
-	"github.com/PubMatic-OpenWrap/prebid-server/cache/postgrescache"
+	"github.com/PubMatic-OpenWrap/prebid-server/cache/datacache"
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/currencies"
-	"github.com/PubMatic-OpenWrap/prebid-server/endpoints"
-	"github.com/PubMatic-OpenWrap/prebid-server/endpoints/openrtb2"
-	"github.com/PubMatic-OpenWrap/prebid-server/exchange"
-	"github.com/PubMatic-OpenWrap/prebid-server/gdpr"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
-	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
 	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
-	pbc "github.com/PubMatic-OpenWrap/prebid-server/prebid_cache_client"
-	"github.com/PubMatic-OpenWrap/prebid-server/ssl"
+	"github.com/PubMatic-OpenWrap/prebid-server/static"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
-	storedRequestsConf "github.com/PubMatic-OpenWrap/prebid-server/stored_requests/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
-	"github.com/PubMatic-OpenWrap/prebid-server/usersync/usersyncers"
 
 	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
@@ -50,22 +35,8 @@ import (
 	"github.com/rs/cors"
 )
 
-var dataCache cache.Cache
-var exchanges map[string]adapters.Adapter
-var (
-	g_syncers           map[openrtb_ext.BidderName]usersync.Usersyncer
-	g_cfg               *config.Configuration
-	g_ex                exchange.Exchange
-	g_paramsValidator   openrtb_ext.BidderParamValidator
-	g_storedReqFetcher  stored_requests.Fetcher
-	g_gdprPerms         gdpr.Permissions
-	g_metrics           pbsmetrics.MetricsEngine
-	g_analytics         analytics.PBSAnalyticsModule
-	g_disabledBidders   map[string]string
-	g_categoriesFetcher stored_requests.CategoryFetcher
-	g_bidderMap         map[string]openrtb_ext.BidderName
-	g_defReqJSON        []byte
-)
+// defaultServer is the instance behind the package level wrappers. It's built by New.
+var defaultServer *PrebidServer
 
 // NewJsonDirectoryServer is used to serve .json files from a directory as a single blob. For example,
 // given a directory containing the files "a.json" and "b.json", this returns a Handle which serves JSON like:
@@ -76,12 +47,17 @@ var (
 // }
 //
 // This function stores the file contents in memory, and should not be used on large directories.
-// If the root directory, or any of the files in it, cannot be read, then the program will exit.
-func NewJsonDirectoryServer(schemaDirectory string, validator openrtb_ext.BidderParamValidator, aliases map[string]string) httprouter.Handle {
+// If the root directory, or any of the files in it, cannot be read, then it returns an error.
+func NewJsonDirectoryServer(schemaDirectory string, validator openrtb_ext.BidderParamValidator, aliases map[string]string) (httprouter.Handle, error) {
+	return NewJsonDirectoryServerFS(static.Dir(schemaDirectory), validator, aliases)
+}
+
+// NewJsonDirectoryServerFS works like NewJsonDirectoryServer, but lists the .json files at the root of schemas.
+func NewJsonDirectoryServerFS(schemas static.FS, validator openrtb_ext.BidderParamValidator, aliases map[string]string) (httprouter.Handle, error) {
 	// Slurp the files into memory first, since they're small and it minimizes request latency.
-	files, err := ioutil.ReadDir(schemaDirectory)
+	files, err := schemas.ReadDir(".")
 	if err != nil {
-		glog.Fatalf("Failed to read directory %s: %v", schemaDirectory, err)
+		return nil, fmt.Errorf("Failed to read directory %v: %v", schemas, err)
 	}
 
 	data := make(map[string]json.RawMessage, len(files))
@@ -89,7 +65,7 @@ func NewJsonDirectoryServer(schemaDirectory string, validator openrtb_ext.Bidder
 		bidder := strings.TrimSuffix(file.Name(), ".json")
 		bidderName, isValid := openrtb_ext.BidderMap[bidder]
 		if !isValid {
-			glog.Fatalf("Schema exists for an unknown bidder: %s", bidder)
+			return nil, fmt.Errorf("Schema exists for an unknown bidder: %s", bidder)
 		}
 		data[bidder] = json.RawMessage(validator.Schema(bidderName))
 	}
@@ -98,20 +74,20 @@ func NewJsonDirectoryServer(schemaDirectory string, validator openrtb_ext.Bidder
 	for aliasName, bidderName := range aliases {
 		bidderData, ok := data[bidderName]
 		if !ok {
-			glog.Fatalf("Default alias (%s) exists referencing unknown bidder: %s", aliasName, bidderName)
+			return nil, fmt.Errorf("Default alias (%s) exists referencing unknown bidder: %s", aliasName, bidderName)
 		}
 		data[aliasName] = bidderData
 	}
 
 	response, err := json.Marshal(data)
 	if err != nil {
-		glog.Fatalf("Failed to marshal bidder param JSON-schema: %v", err)
+		return nil, fmt.Errorf("Failed to marshal bidder param JSON-schema: %v", err)
 	}
 
 	return func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) {
 		w.Header().Add("Content-Type", "application/json")
 		w.Write(response)
-	}
+	}, nil
 }
 
 func serveIndex(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -129,33 +105,29 @@ func (m NoCache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	m.Handler.ServeHTTP(w, r)
 }
 
-func loadDataCache(cfg *config.Configuration, db *sql.DB) (err error) {
+// loadDataCache returns the accounts and ad unit configs of the legacy /auction endpoint. The fetcher comes from
+// the Stored Request backends, if the datacache type uses them.
+func loadDataCache(cfg *config.Configuration, fetcher stored_requests.Fetcher) (dataCache cache.Cache, err error) {
 	switch cfg.DataCache.Type {
 	case "dummy":
-		dataCache, err = dummycache.New()
-		if err != nil {
-			glog.Fatalf("Dummy cache not configured: %s", err.Error())
-		}
-
+		return datacache.NewDummy(), nil
+	case "stored":
+		return datacache.New(fetcher), nil
 	case "postgres":
-		if db == nil {
-			return fmt.Errorf("Nil db cannot connect to postgres. Did you forget to set the config.stored_requests.postgres values?")
+		if fetcher == nil {
+			return nil, fmt.Errorf("Nil db cannot connect to postgres. Did you forget to set the config.stored_requests.postgres values?")
 		}
-		dataCache = postgrescache.New(db, postgrescache.CacheConfig{
-			Size: cfg.DataCache.CacheSize,
-			TTL:  cfg.DataCache.TTLSeconds,
-		})
-		return nil
+		return datacache.New(fetcher), nil
 	case "filecache":
-		dataCache, err = filecache.New(cfg.DataCache.Filename)
+		glog.Warning("datacache.type=filecache is deprecated. Move the accounts and configs to the backends of datacache.type=stored.")
+		dataCache, err = datacache.NewFromYAML(cfg.DataCache.Filename)
 		if err != nil {
-			return fmt.Errorf("FileCache Error: %s", err.Error())
+			return nil, fmt.Errorf("FileCache Error: %s", err.Error())
 		}
-
+		return dataCache, nil
 	default:
-		return fmt.Errorf("Unknown datacache.type: %s", cfg.DataCache.Type)
+		return nil, fmt.Errorf("Unknown datacache.type: %s", cfg.DataCache.Type)
 	}
-	return nil
 }
 
 func newExchangeMap(cfg *config.Configuration) map[string]adapters.Adapter {
@@ -182,154 +154,127 @@ type Router struct {
 	Shutdown        func()
 }
 
+// New builds the default Prebid Server instance, which backs the package level wrappers below.
+//
+// Embedders which need more than one instance, or want to inject their own dependencies, should use
+// NewPrebidServer instead.
 func New(cfg *config.Configuration, rateConvertor *currencies.RateConverter) (r *Router, err error) {
-
-	const schemaDirectory = "/home/http/GO_SERVER/dmhbserver/static/bidder-params"
-	const infoDirectory = "/home/http/GO_SERVER/dmhbserver/static/bidder-info"
-
 	r = &Router{
-		Router: httprouter.New(),
+		MetricsEngine: metricsConf.NewMetricsEngine(cfg, legacyBidderList(cfg)),
 	}
 
-	// For bid processing, we need both the hardcoded certificates and the certificates found in container's
-	// local file system
-	certPool := ssl.GetRootCAPool()
-	var readCertErr error
-	certPool, readCertErr = ssl.AppendPEMFileToRootCAPool(certPool, cfg.PemCertsFile)
-	if readCertErr != nil {
-		glog.Infof("Could not read certificates file: %s \n", readCertErr.Error())
-	}
-
-	theClient := &http.Client{
-		Transport: &http.Transport{
-			MaxIdleConns:        cfg.Client.MaxIdleConns,
-			MaxIdleConnsPerHost: cfg.Client.MaxIdleConnsPerHost,
-			IdleConnTimeout:     time.Duration(cfg.Client.IdleConnTimeout) * time.Second,
-			TLSClientConfig:     &tls.Config{RootCAs: certPool},
-		},
+	defaultServer, err = NewPrebidServer(cfg, rateConvertor, WithMetricsEngine(r.MetricsEngine))
+	if err != nil {
+		return nil, err
 	}
-	// Hack because of how legacy handles districtm
-	legacyBidderList := openrtb_ext.BidderList()
-	legacyBidderList = append(legacyBidderList, openrtb_ext.BidderName("districtm"))
-
-	g_cfg = cfg
-	var db *sql.DB
-	// Metrics engine
-	r.MetricsEngine = metricsConf.NewMetricsEngine(cfg, legacyBidderList)
-	db, _, g_storedReqFetcher, _, g_categoriesFetcher, _ = storedRequestsConf.NewStoredRequests(cfg, r.MetricsEngine, theClient, r.Router)
-
-	// todo(zachbadgett): better shutdown
-	//r.Shutdown = shutdown
-	if err := loadDataCache(cfg, db); err != nil {
-		return nil, fmt.Errorf("Prebid Server could not load data cache: %v", err)
+	// The instance's router holds the endpoints which the stored requests config may have added.
+	r.Router = defaultServer.router
+	instance := defaultServer
+	r.Shutdown = func() {
+		if err := instance.Shutdown(context.Background()); err != nil {
+			glog.Errorf("Prebid Server did not shut down cleanly: %v", err)
+		}
 	}
+	return r, nil
+}
 
-	g_analytics = analyticsConf.NewPBSAnalytics(&cfg.Analytics)
-
-	// Metrics engine
-	g_metrics = metricsConf.NewMetricsEngine(cfg, legacyBidderList)
-
-	g_paramsValidator, err = openrtb_ext.NewBidderParamsValidator(schemaDirectory)
-	if err != nil {
-		glog.Fatalf("Failed to create the bidder params validator. %v", err)
+// Shutdown stops the default instance gracefully. See PrebidServer.Shutdown for the details.
+func Shutdown(ctx context.Context) error {
+	if defaultServer == nil {
+		return errNotInitialized
 	}
+	return defaultServer.Shutdown(ctx)
+}
 
-	g_disabledBidders = map[string]string{
-		"indexExchange": "Bidder \"indexExchange\" has been deprecated and is no longer available. Please use bidder \"ix\" and note that the bidder params have changed.",
+// Reload applies a new config to the default instance. See PrebidServer.Reload for the details.
+func Reload(cfg *config.Configuration) error {
+	if defaultServer == nil {
+		return errNotInitialized
 	}
-	//var bidderList []openrtb_ext.BidderName
-
-	p, _ := filepath.Abs(infoDirectory)
-	bidderInfos := adapters.ParseBidderInfos(cfg.Adapters, p, openrtb_ext.BidderList())
-
-	g_bidderMap = exchange.DisableBidders(bidderInfos, g_disabledBidders)
-
-	_, g_defReqJSON = readDefaultRequest(cfg.DefReqConfig)
-
-	g_syncers = usersyncers.NewSyncerMap(cfg)
-	g_gdprPerms = gdpr.NewPermissions(context.Background(), cfg.GDPR, adapters.GDPRAwareSyncerIDs(g_syncers), theClient)
-
-	exchanges = newExchangeMap(cfg)
-
-	g_ex = exchange.NewExchange(theClient, pbc.NewClient(&cfg.CacheURL, &cfg.ExtCacheURL, r.MetricsEngine), cfg, g_metrics, bidderInfos, g_gdprPerms, rateConvertor)
-
-	/*
-			openrtbEndpoint, err := openrtb2.NewEndpoint(theExchange, paramsValidator, fetcher, cfg, r.MetricsEngine, pbsAnalytics, disabledBidders, defReqJSON, bidderMap, categoriesFetcher)
-
-			if err != nil {
-				glog.Fatalf("Failed to create the openrtb endpoint handler. %v", err)
-			}
-
-			ampEndpoint, err := openrtb2.NewAmpEndpoint(theExchange, paramsValidator, ampFetcher, cfg, r.MetricsEngine, pbsAnalytics, disabledBidders, defReqJSON, bidderMap, categoriesFetcher)
+	return defaultServer.Reload(cfg)
+}
 
-			if err != nil {
-				glog.Fatalf("Failed to create the amp endpoint handler. %v", err)
-			}
+// Handler serves all the public endpoints of the default instance.
+// If it's called before New, its handler answers every request with errNotInitialized.
+func Handler() http.Handler {
+	if defaultServer == nil {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeNotInitialized(w)
+		})
+	}
+	return defaultServer.Handler()
+}
 
-			videoEndpoint, err := openrtb2.NewVideoEndpoint(theExchange, paramsValidator, fetcher, cfg, r.MetricsEngine, pbsAnalytics, disabledBidders, defReqJSON, bidderMap, categoriesFetcher)
-			if err != nil {
-				glog.Fatalf("Failed to create the video endpoint handler. %v", err)
-			}
+// errNotInitialized is returned by the wrappers if they're called before New.
+// The ones which can't return an error answer the request with it instead.
+var errNotInitialized = errors.New("Prebid Server has not been initialized. Call New before serving any auction.")
 
-			r.POST("/auction", endpoints.Auction(cfg, syncers, gdprPerms, r.MetricsEngine, dataCache, exchanges))
-			r.POST("/openrtb2/auction", openrtbEndpoint)
-			r.POST("/openrtb2/video", videoEndpoint)
-			r.GET("/openrtb2/amp", ampEndpoint)
-			r.GET("/info/bidders", infoEndpoints.NewBiddersEndpoint(defaultAliases))
-			r.GET("/info/bidders/:bidderName", infoEndpoints.NewBidderDetailsEndpoint(bidderInfos, defaultAliases))
-			r.GET("/bidders/params", NewJsonDirectoryServer(schemaDirectory, paramsValidator, defaultAliases))
-			r.POST("/cookie_sync", endpoints.NewCookieSyncEndpoint(syncers, cfg, gdprPerms, r.MetricsEngine, pbsAnalytics))
-			r.GET("/status", endpoints.NewStatusEndpoint(cfg.StatusResponse))
-			r.GET("/", serveIndex)
-			r.ServeFiles("/static/*filepath", http.Dir("static"))
+func writeNotInitialized(w http.ResponseWriter) {
+	http.Error(w, errNotInitialized.Error(), http.StatusServiceUnavailable)
+}
 
-		userSyncDeps := &pbs.UserSyncDeps{
-			HostCookieConfig: &(cfg.HostCookie),
-			ExternalUrl:      cfg.ExternalURL,
-			RecaptchaSecret:  cfg.RecaptchaSecret,
-			MetricsEngine:    r.MetricsEngine,
-			PBSAnalytics:     pbsAnalytics,
-		}
+func OrtbAuctionEndpointWrapper(w http.ResponseWriter, r *http.Request) error {
+	if defaultServer == nil {
+		return errNotInitialized
+	}
+	defaultServer.Auction(w, r)
+	return nil
+}
 
-			r.GET("/setuid", endpoints.NewSetUIDEndpoint(cfg.HostCookie, syncers, gdprPerms, pbsAnalytics, r.MetricsEngine))
-			r.GET("/getuids", endpoints.NewGetUIDsEndpoint(cfg.HostCookie))
-			r.POST("/optout", userSyncDeps.OptOut)
-			r.GET("/optout", userSyncDeps.OptOut)
-	*/
-	return r, nil
+func AmpAuctionEndpointWrapper(w http.ResponseWriter, r *http.Request) error {
+	if defaultServer == nil {
+		return errNotInitialized
+	}
+	defaultServer.AMP(w, r)
+	return nil
 }
 
-func OrtbAuctionEndpointWrapper(w http.ResponseWriter, r *http.Request) error {
-	ortbAuctionEndpoint, err := openrtb2.NewEndpoint(g_ex, g_paramsValidator, g_storedReqFetcher, g_categoriesFetcher, g_cfg, g_metrics, g_analytics, g_disabledBidders, g_defReqJSON, g_bidderMap)
-	if err != nil {
-		return err
+func VideoAuctionEndpointWrapper(w http.ResponseWriter, r *http.Request) error {
+	if defaultServer == nil {
+		return errNotInitialized
 	}
-	ortbAuctionEndpoint(w, r, nil)
+	defaultServer.Video(w, r)
 	return nil
 }
 
 func AuctionWrapper(w http.ResponseWriter, r *http.Request) {
-	auction := endpoints.Auction(g_cfg, g_syncers, g_gdprPerms, g_metrics, dataCache, exchanges)
-	auction(w, r, nil)
+	if defaultServer == nil {
+		writeNotInitialized(w)
+		return
+	}
+	defaultServer.LegacyAuction(w, r)
 }
 
 func GetUIDSWrapper(w http.ResponseWriter, r *http.Request) {
-	getUID := endpoints.NewGetUIDsEndpoint(g_cfg.HostCookie)
-	getUID(w, r, nil)
+	if defaultServer == nil {
+		writeNotInitialized(w)
+		return
+	}
+	defaultServer.GetUIDs(w, r)
 }
 
 func SetUIDSWrapper(w http.ResponseWriter, r *http.Request) {
-	setUID := endpoints.NewSetUIDEndpoint(g_cfg.HostCookie, g_syncers, g_gdprPerms, g_analytics, g_metrics)
-	setUID(w, r, nil)
+	if defaultServer == nil {
+		writeNotInitialized(w)
+		return
+	}
+	defaultServer.SetUID(w, r)
 }
 
 func CookieSync(w http.ResponseWriter, r *http.Request) {
-	cookiesync := endpoints.NewCookieSyncEndpoint(g_syncers, g_cfg, g_gdprPerms, g_metrics, g_analytics)
-	cookiesync(w, r, nil)
+	if defaultServer == nil {
+		writeNotInitialized(w)
+		return
+	}
+	defaultServer.CookieSync(w, r)
 }
 
+// SyncerMap returns the default instance's syncers, or nil if it's called before New.
 func SyncerMap() map[openrtb_ext.BidderName]usersync.Usersyncer {
-	return g_syncers
+	if defaultServer == nil {
+		return nil
+	}
+	return defaultServer.SyncerMap()
 }
 
 // Fixes #648
@@ -354,7 +299,20 @@ func SupportCORS(handler http.Handler) http.Handler {
 			return true
 		},
 		AllowedHeaders: []string{"Origin", "X-Requested-With", "Content-Type", "Accept"}})
-	return c.Handler(handler)
+	corsHandler := c.Handler(handler)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if corsExemptPaths[r.URL.Path] {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		corsHandler.ServeHTTP(w, r)
+	})
+}
+
+// corsExemptPaths are never readable cross-origin. /optout hands out the token which proves that the user made
+// an opt out request, so other sites mustn't be able to read its responses.
+var corsExemptPaths = map[string]bool{
+	"/optout": true,
 }
 
 type defReq struct {
@@ -367,30 +325,30 @@ type defaultAliases struct {
 	Aliases map[string]string `json:"aliases"`
 }
 
-func readDefaultRequest(defReqConfig config.DefReqConfig) (map[string]string, []byte) {
+// readDefaultRequest reads the default request file, and the aliases in it which the info endpoints show.
+// It returns an error if the file can't be read or parsed, so that a reload can reject it.
+func readDefaultRequest(defReqConfig config.DefReqConfig) (map[string]string, []byte, error) {
 	defReq := &defReq{}
 	aliases := make(map[string]string)
 	if defReqConfig.Type == "file" {
 		if len(defReqConfig.FileSystem.FileName) == 0 {
-			return aliases, []byte{}
+			return aliases, []byte{}, nil
 		}
 		defReqJSON, err := ioutil.ReadFile(defReqConfig.FileSystem.FileName)
 		if err != nil {
-			glog.Fatalf("error reading aliases from file %s: %v", defReqConfig.FileSystem.FileName, err)
-			return aliases, []byte{}
+			return nil, nil, fmt.Errorf("error reading aliases from file %s: %v", defReqConfig.FileSystem.FileName, err)
 		}
 
 		if err := json.Unmarshal(defReqJSON, defReq); err != nil {
 			// we might not have aliases defined, but will atleast show that the JSON file is parsable.
-			glog.Fatalf("error parsing alias json in file %s: %v", defReqConfig.FileSystem.FileName, err)
-			return aliases, []byte{}
+			return nil, nil, fmt.Errorf("error parsing alias json in file %s: %v", defReqConfig.FileSystem.FileName, err)
 		}
 
 		// Read in the alias map if we want to populate the info endpoints with aliases.
 		if defReqConfig.AliasInfo {
 			aliases = defReq.Ext.Prebid.Aliases
 		}
-		return aliases, defReqJSON
+		return aliases, defReqJSON, nil
 	}
-	return aliases, []byte{}
+	return aliases, []byte{}, nil
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/memory_store"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -38,7 +39,10 @@ func ensureHasKey(t *testing.T, data map[string]json.RawMessage, key string) {
 }
 
 func TestNewJsonDirectoryServer(t *testing.T) {
-	handler := NewJsonDirectoryServer("../static/bidder-params", &testValidator{}, nil)
+	handler, err := NewJsonDirectoryServer("../static/bidder-params", &testValidator{}, nil)
+	if err != nil {
+		t.Fatalf("Failed to serve the bidder params: %v", err)
+	}
 	recorder := httptest.NewRecorder()
 	request, _ := http.NewRequest("GET", "/whatever", nil)
 	handler(recorder, request, nil)
@@ -88,6 +92,15 @@ func TestCORSSupport(t *testing.T) {
 	assert.Equal(t, origin, rr.Header().Get("Access-Control-Allow-Origin"))
 }
 
+func TestOptOutIsNotCORSReadable(t *testing.T) {
+	handler := SupportCORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest("GET", "http://some-domain.com/optout?format=json", nil)
+	req.Header.Set("Origin", "https://publisher-domain.com")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Empty(t, rr.Header().Get("Access-Control-Allow-Origin"), "Other sites shouldn't be able to read the opt out token")
+}
+
 func TestNoCache(t *testing.T) {
 	nc := NoCache{
 		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
@@ -116,7 +129,7 @@ func TestNoCache(t *testing.T) {
 
 func TestLoadDataCache(t *testing.T) {
 	// Test dummy
-	if err := loadDataCache(&config.Configuration{
+	if _, err := loadDataCache(&config.Configuration{
 		DataCache: config.DataCache{
 			Type: "dummy",
 		},
@@ -124,19 +137,27 @@ func TestLoadDataCache(t *testing.T) {
 		t.Errorf("data cache: dummy: %s", err)
 	}
 	// Test postgres error
-	if err := loadDataCache(&config.Configuration{
+	if _, err := loadDataCache(&config.Configuration{
 		DataCache: config.DataCache{
 			Type: "postgres",
 		},
 	}, nil); err == nil {
 		t.Errorf("data cache: postgres: db nil should return error")
 	}
+	// Test stored
+	if _, err := loadDataCache(&config.Configuration{
+		DataCache: config.DataCache{
+			Type: "stored",
+		},
+	}, memory_store.NewStore()); err != nil {
+		t.Errorf("data cache: stored: %s", err)
+	}
 	// Test file
 	d, _ := ioutil.TempDir("", "pbs-filecache")
 	defer os.RemoveAll(d)
 	f, _ := ioutil.TempFile(d, "file")
 	defer f.Close()
-	if err := loadDataCache(&config.Configuration{
+	if _, err := loadDataCache(&config.Configuration{
 		DataCache: config.DataCache{
 			Type:     "filecache",
 			Filename: f.Name(),
@@ -155,7 +176,8 @@ var testDefReqConfig = config.DefReqConfig{
 }
 
 func TestLoadDefaultAliases(t *testing.T) {
-	defAliases, aliasJSON := readDefaultRequest(testDefReqConfig)
+	defAliases, aliasJSON, err := readDefaultRequest(testDefReqConfig)
+	assert.NoError(t, err)
 	expectedJSON := []byte(`{"ext":{"prebid":{"aliases": {"test1": "appnexus", "test2": "rubicon", "test3": "openx"}}}}`)
 	expectedAliases := map[string]string{
 		"test1": "appnexus",
@@ -170,7 +192,8 @@ func TestLoadDefaultAliases(t *testing.T) {
 func TestLoadDefaultAliasesNoInfo(t *testing.T) {
 	noInfoConfig := testDefReqConfig
 	noInfoConfig.AliasInfo = false
-	defAliases, aliasJSON := readDefaultRequest(noInfoConfig)
+	defAliases, aliasJSON, err := readDefaultRequest(noInfoConfig)
+	assert.NoError(t, err)
 	expectedJSON := []byte(`{"ext":{"prebid":{"aliases": {"test1": "appnexus", "test2": "rubicon", "test3": "openx"}}}}`)
 	expectedAliases := map[string]string{}
 
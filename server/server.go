@@ -20,8 +20,29 @@ import (
 
 // Listen blocks forever, serving PBS requests on the given port. This will block forever, until the process is shut down.
 func Listen(cfg *config.Configuration, handler http.Handler, adminHandler http.Handler, metrics *metricsconfig.DetailedMetricsEngine) {
-	stopSignals := make(chan os.Signal)
+	ListenUntil(cfg, handler, adminHandler, metrics, nil)
+}
+
+// ListenUntil works like Listen, but also shuts the servers down gracefully once stop gets closed.
+// It returns after every server has stopped.
+func ListenUntil(cfg *config.Configuration, handler http.Handler, adminHandler http.Handler, metrics *metricsconfig.DetailedMetricsEngine, stop <-chan struct{}) {
+	stopSignals := make(chan os.Signal, 1)
 	signal.Notify(stopSignals, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(stopSignals)
+	if stop != nil {
+		finished := make(chan struct{})
+		defer close(finished)
+		go func() {
+			select {
+			case <-stop:
+				select {
+				case stopSignals <- syscall.SIGTERM:
+				default:
+				}
+			case <-finished:
+			}
+		}()
+	}
 
 	// Run the servers. Fan any process-stopper signals out to each server for graceful shutdowns.
 	stopAdmin := make(chan os.Signal)
@@ -4,8 +4,11 @@ import (
 	"net/http"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	metricsconfig "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
 )
 
 func TestNewAdminServer(t *testing.T) {
@@ -81,3 +84,26 @@ func forwardSignal(t *testing.T, outbound chan<- struct{}, inbound <-chan os.Sig
 	}
 	outbound <- s
 }
+
+func TestListenUntil(t *testing.T) {
+	cfg := &config.Configuration{
+		Host:      "127.0.0.1",
+		AdminPort: 0,
+		Port:      0,
+	}
+	metrics := metricsconfig.NewMetricsEngine(cfg, openrtb_ext.BidderList())
+
+	stop := make(chan struct{})
+	returned := make(chan struct{})
+	go func() {
+		ListenUntil(cfg, http.HandlerFunc(handler), http.HandlerFunc(handler), metrics, stop)
+		close(returned)
+	}()
+	close(stop)
+
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Error("ListenUntil should return once the stop channel is closed")
+	}
+}
@@ -0,0 +1,110 @@
+// Code generated by gen_assets.go; DO NOT EDIT.
+
+package static
+
+var assets = map[string]string{
+	"bidder-info/33across.yaml":                 "maintainer:\n  email: \"dev@33across.com\"\ncapabilities:\n  app:\n    mediaTypes:\n    - banner\n  site:\n    mediaTypes:\n    - banner",
+	"bidder-info/adform.yaml":                   "maintainer:\r\n  email: \"scope.sspp@adform.com\"\r\ncapabilities:\r\n  app:\r\n    mediaTypes:\r\n      - banner\r\n  site:\r\n    mediaTypes:\r\n      - banner\r\n",
+	"bidder-info/adkernel.yaml":                 "maintainer:\n  email: \"prebid-dev@adkernel.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner      \n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/adkernelAdn.yaml":              "maintainer:\n  email: \"prebid-dev@adkernel.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/adpone.yaml":                   "maintainer:\n  email: \"tech@adpone.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n  site:\n    mediaTypes:\n      - banner\n",
+	"bidder-info/adtelligent.yaml":              "maintainer:\r\n  email: \"hb@adtelligent.com\"\r\ncapabilities:\r\n  app:\r\n    mediaTypes:\r\n      - banner\r\n  site:\r\n    mediaTypes:\r\n      - banner\r\n      - video\r\n",
+	"bidder-info/advangelists.yaml":             "maintainer:\n  email: \"lokesh@advangelists.com\"\ncapabilities:\n  site:\n    mediaTypes:\n    - banner\n    - video\n    \n  app:\n    mediaTypes:\n    - banner\n    - video\n   \n",
+	"bidder-info/applogy.yaml":                  "maintainer:\n  email: work@applogy.com\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n      - native\n  site:\n    mediaTypes:\n      - banner\n      - video\n      - native\n",
+	"bidder-info/appnexus.yaml":                 "maintainer:\n  email: \"info@prebid.org\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n      - native\n  site:\n    mediaTypes:\n      - banner\n      - video\n      - native\n",
+	"bidder-info/audienceNetwork.yaml":          "maintainer:\n  email: \"info@prebid.org\"\ncapabilities:\n  site:\n    mediaTypes:\n      - banner\n      - video\n      - native\n  app:\n    mediaTypes:\n      - banner\n      - video\n      - native\n",
+	"bidder-info/beachfront.yaml":               "maintainer:\n  email: \"prebid@beachfront.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/brightroll.yaml":               "maintainer:\n  email: \"smithaa@oath.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/consumable.yaml":               "maintainer:\n  email: \"naffis@consumable.com\"\ncapabilities:\n  app:\n    mediaTypes:\n    - banner\n  site:\n    mediaTypes:\n    - banner\n",
+	"bidder-info/conversant.yaml":               "maintainer:\n  email: \"mediapsr@conversantmedia.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/datablocks.yaml":               "maintainer:\n  email: \"henry@datablocks.net\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - native\n      - video\n  site:\n    mediaTypes:\n      - banner\n      - native\n      - video\n",
+	"bidder-info/emx_digital.yaml":              "maintainer:\n  email: \"adops@emxdigital.com\"\ncapabilities:\n  site:\n    mediaTypes:\n      - banner\n",
+	"bidder-info/engagebdr.yaml":                "maintainer:\n  email: \"admin@engagebdr.com\"\ncapabilities:\n  app:\n    mediaTypes:\n    - banner\n    - video\n    - native\n",
+	"bidder-info/eplanning.yaml":                "maintainer:\n  email: \"producto@e-planning.net\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n  site:\n    mediaTypes:\n      - banner\n",
+	"bidder-info/gamma.yaml":                    "maintainer:\n  email: \"support@gammassp.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/gamoshi.yaml":                  "maintainer:\n  email: \"moses@gamoshi.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/grid.yaml":                     "maintainer:\n  email: \"grid-tech@themediagrid.com\"\ncapabilities:\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/gumgum.yaml":                   "maintainer:\n  email: \"pubtech@gumgum.com\"\ncapabilities:\n  site:\n    mediaTypes:\n    - banner\n",
+	"bidder-info/improvedigital.yaml":           "maintainer:\n  email: \"hb@azerion.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/ix.yaml":                       "maintainer:\n  email: \"info@prebid.org\"\ncapabilities:\n  site:\n    mediaTypes:\n      - banner\n",
+	"bidder-info/kubient.yaml":                  "maintainer:\n  email: \"support@kubient.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n  site:\n    mediaTypes:\n      - banner\n      - video\n\n",
+	"bidder-info/lifestreet.yaml":               "maintainer:\n  email: \"mobile.tech@lifestreet.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/lockerdome.yaml":               "maintainer:\n  email: \"bidding@lockerdome.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n  site:\n    mediaTypes:\n      - banner\n",
+	"bidder-info/marsmedia.yaml":                "maintainer:\n  email: \"prebid@mars.media\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/mgid.yaml":                     "maintainer:\n  email: \"prebid@mgid.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - native\n  site:\n    mediaTypes:\n      - banner\n      - native\n",
+	"bidder-info/openx.yaml":                    "maintainer:\n  email: \"team-openx@openx.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/pubmatic.yaml":                 "maintainer:\n  email: \"header-bidding@pubmatic.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/pubnative.yaml":                "maintainer:\n  email: product@pubnative.net\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n      - native\n  site:\n    mediaTypes:\n      - banner\n      - video\n      - native\n",
+	"bidder-info/pulsepoint.yaml":               "maintainer:\n  email: \"info@prebid.org\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n  site:\n    mediaTypes:\n      - banner\n",
+	"bidder-info/rhythmone.yaml":                "maintainer:\n  email: \"support@rhythmone.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/rtbhouse.yaml":                 "maintainer:\n  email: \"inventory.devel@rtbhouse.com\"\ncapabilities:\n  site:\n    mediaTypes:\n      - banner\n",
+	"bidder-info/rubicon.yaml":                  "maintainer:\n  email: \"header-bidding@rubiconproject.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/sharethrough.yaml":             "maintainer:\n  email: \"pubgrowth.engineering@sharethrough.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - native\n      - banner\n  site:\n    mediaTypes:\n      - native\n      - banner\n",
+	"bidder-info/somoaudience.yaml":             "maintainer:\n  email: \"publishers@somoaudience.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - native\n  site:\n    mediaTypes:\n      - banner\n      - native\n      - video\n",
+	"bidder-info/sonobi.yaml":                   "maintainer:\n  email: \"apex@sonobi.com\"\ncapabilities:\n  site:\n    mediaTypes:\n      - banner\n      - video\n  app:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/sovrn.yaml":                    "maintainer:\n  email: \"sovrnoss@sovrn.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n  site:\n    mediaTypes:\n      - banner\n",
+	"bidder-info/spotx.yaml":                    "maintainer:\n  email: \"teameighties@spotx.tv\"\ncapabilities:\n  app:\n    mediaTypes:\n      - video\n  site:\n    mediaTypes:\n      - video\n",
+	"bidder-info/synacormedia.yaml":             "maintainer:\n  email: \"eng-demand@synacor.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/tappx.yaml":                    "maintainer:\n  email: \"tappx@tappx.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/telaria.yaml":                  "maintainer:\n  email: \"github@telaria.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - video\n  site:\n    mediaTypes:\n      - video\n",
+	"bidder-info/triplelift.yaml":               "maintainer:\n  email: \"prebid@triplelift.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n      - video\n  site:\n    mediaTypes:\n      - banner\n      - video\n",
+	"bidder-info/triplelift_native.yaml":        "maintainer:\n  email: \"prebid@triplelift.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - native\n  site:\n    mediaTypes:\n      - native\n",
+	"bidder-info/unruly.yaml":                   "maintainer:\n  email: \"adspaces@unrulygroup.com\"\ncapabilities:\n  site:\n    mediaTypes:\n      - video\n  app:\n    mediaTypes:\n      - video\n",
+	"bidder-info/verizonmedia.yaml":             "maintainer:\n  email: \"hb-fe-tech@verizonmedia.com\"\ncapabilities:\n  site:\n    mediaTypes:\n      - banner",
+	"bidder-info/visx.yaml":                     "maintainer:\n  email: \"service@yoc.com\"\ncapabilities:\n  site:\n    mediaTypes:\n      - banner\n",
+	"bidder-info/vrtcal.yaml":                   "maintainer:\n  email: \"support@vrtcal.com\"\ncapabilities:\n  app:\n    mediaTypes:\n      - banner\n",
+	"bidder-info/yieldmo.yaml":                  "maintainer:\n  email: \"progsupport@yieldmo.com\"\ncapabilities:\n  site:\n    mediaTypes:\n    - banner\n",
+	"bidder-params/33across.json":               "\n{\n    \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n    \"title\": \"33Across Adapter Params\",\n    \"description\": \"A schema which validates params accepted by the 33Across adapter\",\n  \n    \"type\": \"object\",\n    \"properties\": {\n      \"productId\": {\n        \"type\": \"string\",\n        \"description\": \"Product type\"\n      },\n      \"siteId\": {\n        \"type\": \"string\",\n        \"description\": \"Site Id\"\n      },\n      \"zoneId\": {\n        \"type\": \"string\",\n        \"description\": \"Zone Id\"\n      }\n    },\n    \"required\": [\"productId\", \"siteId\"]\n  }",
+	"bidder-params/adform.json":                 "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Adform Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Adform adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"mid\": {\n      \"type\": [\"integer\", \"string\"],\n      \"description\": \"An ID which identifies the placement selling the impression\"\n    },\n    \"priceType\": {\n      \"type\": \"string\",\n      \"enum\": [\"gross\", \"net\"],\n      \"description\": \"An expected price type (net or gross) of bids.\"\n    },\n    \"mkv\": {\n      \"type\": \"string\",\n      \"description\": \"Comma-separated key-value pairs. Forbidden symbols: &. Example: mkv='color:blue,length:350'\",\n      \"pattern\": \"^(\\\\s*|(([^,:&]*[^,:&\\\\s]+[^,:&]*)+:[^,:&]*,)*(([^,:&]*[^,:&\\\\s]+[^,:&]*)+:[^,:&]*,?))$\"\n    },\n    \"mkw\": {\n      \"type\": \"string\",\n      \"description\": \"Comma-separated keywords. Forbidden symbols: &.\",\n      \"pattern\": \"^[^&]*$\"\n    }\n  },\n  \"required\": [\"mid\"]\n}\n",
+	"bidder-params/adkernel.json":               "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Adkernel Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Adkernel adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n      \"zoneId\": {\n        \"type\": \"integer\",\n        \"minimum\": 1,\n        \"description\": \"Publisher Id to use.\"\n      },\n      \"host\": {\n        \"type\": \"string\",\n        \"description\": \"Network host to send request\"\n      }\n  },\n  \"required\": [\"host\", \"zoneId\"]\n}\n",
+	"bidder-params/adkernelAdn.json":            "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"AdkernelAdn Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the AdkernelAdn adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n      \"pubId\": {\n        \"type\": \"integer\",\n        \"minimum\": 1,\n        \"description\": \"Publisher Id to use.\"\n      },\n      \"host\": {\n        \"type\": \"string\",\n        \"description\": \"Network host to send request\"\n      }\n  },\n  \"required\": [\"pubId\"]\n}\n",
+	"bidder-params/adpone.json":                 "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Adpone Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the adpone adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"placementId\": {\n      \"type\": \"string\",\n      \"description\": \"Placement Id\"\n    }\n  },\n  \"required\": []\n}\n",
+	"bidder-params/adtelligent.json":            "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Adtelligent Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Adtelligent adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n    \"placementId\": {\n      \"type\": \"integer\",\n      \"description\": \"An ID which identifies this placement of the impression\"\n    },\n    \"siteId\": {\n      \"type\": \"integer\",\n      \"description\": \"An ID which identifies the site selling the impression\"\n    },\n    \"aid\": {\n      \"type\": \"integer\",\n      \"description\": \"An ID which identifies the channel\"\n    },\n    \"bidFloor\": {\n      \"type\": \"number\",\n      \"description\": \"BidFloor, US Dollars\"\n    }\n  },\n  \"required\": [\"aid\"]\n}\n",
+	"bidder-params/advangelists.json":           "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Advangelists Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Advangelists adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"pubid\": {\n      \"type\": \"string\",\n      \"description\": \"An id used to identify Advangelists publisher.\",\n      \"minLength\": 8\n    }\n  },\n  \"required\": [\"pubid\"]\n}\n",
+	"bidder-params/applogy.json":                "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Applogy Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Applogy adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"token\": {\n      \"type\": \"string\",\n      \"description\": \"Applogy token\"\n    }\n  },\n  \"required\": [\"token\"]\n}\n",
+	"bidder-params/appnexus.json":               "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Appnexus Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the AppNexus adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n    \"placement_id\": {\n      \"type\": \"integer\",\n      \"description\": \"An ID which identifies this placement of the impression\"\n    },\n    \"placementId\": {\n      \"type\": \"integer\",\n      \"description\": \"Deprecated, use placement_id instead.\"\n    },\n    \"inv_code\": {\n      \"type\": \"string\",\n      \"description\": \"A code identifying the inventory of this placement.\"\n    },\n    \"invCode\": {\n      \"type\": \"string\",\n      \"description\": \"Deprecated, use inv_code instead.\"\n    },\n    \"member\": {\n      \"type\": \"string\",\n      \"description\": \"An ID which identifies the member selling the impression.\"\n    },\n    \"keywords\": {\n      \"type\": \"array\",\n      \"minItems\": 1,\n      \"items\": {\n        \"type\": \"object\",\n        \"description\": \"A key with one or more values associated with it. These are used in buy-side segment targeting.\",\n        \"properties\": {\n          \"key\": {\n            \"type\": \"string\"\n          },\n          \"value\": {\n            \"type\": \"array\",\n            \"minItems\": 1,\n            \"items\": {\n              \"type\": \"string\"\n            }\n          }\n        },\n        \"required\": [\"key\"]\n      }\n    },\n    \"traffic_source_code\": {\n      \"type\": \"string\",\n      \"description\": \"Specifies the third-party source of this impression.\"\n    },\n    \"trafficSourceCode\": {\n      \"type\": \"string\",\n      \"description\": \"Deprecated, use traffic_source_code instead.\"\n    },\n    \"reserve\": {\n      \"type\": \"number\",\n      \"description\": \"The minimium acceptable bid, in CPM, using US Dollars\"\n    },\n    \"position\": {\n      \"type\": \"string\",\n      \"enum\": [\"above\", \"below\"],\n      \"description\": \"Specifies the ad unit as above or below the fold\"\n    },\n    \"use_pmt_rule\": {\n      \"type\": \"boolean\",\n      \"description\": \"Boolean to signal AppNexus to apply the relevant payment rule\"\n    },\n    \"private_sizes\" :{\n      \"type\": \"array\",\n      \"items\": {\n        \"type\": \"object\",\n        \"properties\": {\n          \"w\": { \"type\": \"integer\" },\n          \"h\": { \"type\": \"integer\" }\n        },\n        \"required\": [ \"w\", \"h\"]\n      },\n      \"description\": \"Private sizes (ex: [{\\\"w\\\": 300, \\\"h\\\": 250},{...}]), experimental, may not be supported.\"\n    }\n  },\n\n  \"oneOf\": [{\n    \"oneOf\": [{\n      \"required\": [\"placementId\"]\n    }, {\n      \"required\": [\"placement_id\"]\n    }]\n  }, {\n    \"oneOf\": [{\n      \"required\": [\"invCode\", \"member\"]\n    }, {\n      \"required\": [\"inv_code\", \"member\"]\n    }]\n  }],\n\n  \"not\": {\n    \"required\": [\"placementId\", \"invCode\", \"member\"]\n  }\n}\n",
+	"bidder-params/audienceNetwork.json":        "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Facebook Audience Network Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Facebook Audience Network adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"placementId\": {\n      \"type\": \"string\",\n      \"description\": \"An ID which identifies the placement selling the impression\"\n    }\n  },\n  \"required\": [\"placementId\"]\n}\n",
+	"bidder-params/beachfront.json":             "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Beachfront Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Beachfront adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"appId\" : {\n      \"type\": \"string\",\n      \"description\": \"The id of an inventory target. This can only be used in requests that contain one media type. It will be applied to all imps in the request.\"\n    },\n    \"appIds\": {\n      \"type\" : \"object\",\n      \"description\": \"An object that specifies appIds for specific media types. This can be used for either single media type requests or multiple.\",\n      \"properties\": {\n        \"video\" : {\n          \"type\": \"string\",\n          \"description\": \"An appId string that will be applied to video requests in this imp.\"\n        },\n        \"banner\" : {\n          \"type\": \"string\",\n          \"description\": \"An appId string that will be applied to banner requests in this imp.\"\n        }\n      }\n    },\n    \"bidfloor\": {\n      \"type\": \"number\",\n      \"description\": \"The price floor for the bid.\"\n    },\n    \"videoResponseType\": {\n      \"type\": \"string\",\n      \"description\": \"By default the video response will be a nurl URL, but if you want AdM/VAST, set this to 'adm'. If you want both set it to 'both'. Setting it to any other string will have no effect and the default format will be returned.\"\n    }\n  },\n\n  \"required\": [\"bidfloor\"],\n  \"oneOf\": [{\n      \"required\": [\"appId\"] }, {\n      \"required\": [\"appIds\"]\n    }]\n}\n",
+	"bidder-params/brightroll.json":             "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Brightroll Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Brightroll adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n      \"publisher\": {\n        \"type\": \"string\",\n        \"description\": \"Publisher Name to use.\"\n    }\n  },\n  \"required\": [\"publisher\"]\n}\n",
+	"bidder-params/consumable.json":             "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Consumable Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Consumable adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n    \"siteId\": {\n      \"type\": \"number\",\n      \"description\": \"The site ID from Consumable\",\n      \"pattern\": \"^[0-9]+$\"\n    },\n    \"networkId\": {\n      \"type\": \"number\",\n      \"description\": \"The network ID from Consumable\",\n      \"pattern\": \"^[0-9]+$\"\n    },\n    \"unitId\": {\n      \"type\": \"number\",\n      \"description\": \"The unit ID from Consumable\",\n      \"pattern\": \"^[0-9]+$\"\n    },\n    \"unitName\": {\n      \"type\": \"string\",\n      \"description\": \"The unit name from Consumable (expected to be a valid CSS class name)\",\n      \"pattern\": \"^-?[_a-zA-Z]+[_a-zA-Z0-9-]*$\"\n    }\n  },\n  \"required\": [\"siteId\", \"networkId\",\"unitId\"]\n}\n",
+	"bidder-params/conversant.json":             "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Conversant Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Conversant adapter.\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"site_id\": {\n      \"type\": \"string\",\n      \"description\": \"A Conversant specific ID which identifies the site.\"\n    },\n   \t\"secure\": {\n   \t  \"type\": \"integer\",\n   \t  \"description\": \"Override http/https context on ad markup.\"\n   \t},\n    \"bidfloor\" : {\n      \"type\": \"number\",\n      \"description\": \"Minimum bid price that will be considered.\"\n   \t},\n   \t\"tag_id\": {\n      \"type\": \"string\",\n      \"description\": \"Identifies specific ad placement.\"\n    },\n   \t\"position\": {\n      \"type\": \"integer\",\n      \"description\": \"Ad position on screen.\"\n   \t},\n   \t\"mobile\": {\n   \t  \"type\": \"integer\",\n   \t  \"description\": \"Indicate if the site is mobile optimized.\"\n   \t},\n   \t\"mimes\": {\n   \t  \"type\": \"array\",\n   \t  \"description\": \"Array of content MIME types.  For videos only.\",\n   \t  \"items\": {\n   \t     \"type\": \"string\"\n   \t  }\n    },\n    \"maxduration\": {\n      \"type\": \"integer\",\n      \"description\": \"Maximum duration in seconds.  For videos only.\"\n    },\n    \"api\": {\n      \"type\": \"array\",\n      \"description\": \"Array of supported API frameworks.  For videos only.\",\n      \"items\": {\n        \"type\": \"integer\"\n      }\n    },\n    \"protocols\": {\n      \"type\": \"array\",\n      \"description\": \"Array of supported video protocols.  For videos only.\",\n      \"items\": {\n        \"type\": \"integer\"\n      }\n    }\n  },\n  \"required\": [\"site_id\"]\n}",
+	"bidder-params/datablocks.json":             "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Datablocks Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Datablocks adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n      \"sourceId\": {\n        \"type\": \"integer\",\n        \"minimum\": 1,\n        \"description\": \"Website Source Id\"\n      },\n      \"host\": {\n        \"type\": \"string\",\n        \"description\": \"Network Host to request from\"\n      }\n  },\n  \"required\": [\"host\", \"sourceId\"]\n}\n",
+	"bidder-params/emx_digital.json":            "{\n    \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n    \"title\": \"EMX Digital Adapter Params\",\n    \"description\": \"A schema which validates params accepted by the EMX Digital adapter\",\n    \"type\": \"object\",\n    \"properties\": {\n      \"tagid\" : {\n        \"type\": \"string\",\n        \"description\": \"The id of an inventory target\"\n      },\n      \"bidfloor\": {\n        \"type\": \"string\",\n        \"description\": \"The minimum price acceptable for a bid\"\n      }\n    },\n\n    \"required\": [\"tagid\"]\n  }\n",
+	"bidder-params/engagebdr.json":              "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"EngageBDR Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the EngageBDR adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"sspid\": {\n      \"type\": \"string\",\n      \"description\": \"SSPID parameter\",\n      \"pattern\": \"^[0-9]+$\"\n    }\n  },\n  \"required\": [\"sspid\"]\n}\n",
+	"bidder-params/eplanning.json":              "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"EPlanning Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the EPlanning adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"ci\": {\n      \"type\": [\"string\"],\n      \"description\": \"Client ID to use.\"\n    },\n    \"adunit_code\": {\n      \"type\": [\"string\"],\n      \"description\": \"Adunit Code.\"\n    }\n  },\n  \"required\": [\"ci\"]\n}\n",
+	"bidder-params/gamma.json":                  "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Gamma Adapter Params\",\n  \"description\": \"A schema which validates params accepted by Gamma adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n      \"id\": {\n        \"type\": \"string\",\n        \"description\": \"Partner ID\"\n    },\n    \"zid\": {\n      \"type\": \"string\",\n      \"description\": \"Zone ID\"\n    },\n    \"wid\": {\n      \"type\": \"string\",\n      \"description\": \"Web ID\"\n    }\n  },\n  \"required\": [\"id\", \"zid\",\"wid\"]\n}\n",
+	"bidder-params/gamoshi.json":                "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Gamoshi Adapter Params\",\n  \"description\": \"A schema which validates params accepted by Gamoshi adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n      \"supplyPartnerId\": {\n        \"type\": \"string\",\n        \"description\": \"Supply partner id to use.\"\n    },\n    \"favoredMediaType\": {\n      \"type\": \"string\",\n      \"description\": \"favored media type\"\n    }\n  },\n  \"required\": [\"supplyPartnerId\"]\n}\n",
+	"bidder-params/grid.json":                   "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"TheMediaGrid Adapter Params\",\n  \"description\": \"A schema which validates params accepted by TheMediaGrid adapter\",\n  \"type\": \"object\",\n  \"properties\": {},\n  \"required\": []\n}\n",
+	"bidder-params/gumgum.json":                 "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"GumGum Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the GumGum adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"zone\": {\n      \"type\": \"string\",\n      \"description\": \"A tracking id used to identify GumGum zone.\",\n      \"minLength\": 8\n    }\n  },\n  \"required\": [\"zone\"]\n}\n",
+	"bidder-params/improvedigital.json":         "{\n    \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n    \"title\": \"Improve Digital Adapter Params\",\n    \"description\": \"A schema which validates params accepted by Improve Digital adapter\",\n    \"type\": \"object\",\n    \"properties\": {\n        \"placementId\": {\n            \"type\": \"integer\",\n            \"minimum\": 1,\n            \"description\": \"An ID which identifies this placement of the impression\"\n        },\n        \"publisherId\": {\n            \"type\": \"integer\",\n            \"minimum\": 1,\n            \"description\": \"An ID which identifies publisher. Required when using a placementKey\"\n        },\n        \"placementKey\": {\n            \"type\": \"string\",\n            \"description\": \"An uniq name which identifies this placement of the impression. Must be used with publisherId\"\n        },\n        \"keyValues\": {\n            \"type\": \"object\",\n            \"description\": \"Contains one or more key-value pairings for key-value targeting\"\n        },\n        \"size\": {\n            \"type\": \"object\",\n            \"properties\": {\n                \"w\": {\n                    \"type\": \"integer\"\n                },\n                \"h\": {\n                    \"type\": \"integer\"\n                }\n            },\n            \"required\": [\"w\", \"h\"],\n            \"description\": \"Placement size\"\n        }\n    },\n    \"oneOf\": [{\n        \"required\": [\"placementId\"]\n    }, {\n        \"required\": [\"publisherId\", \"placementKey\"]\n    }]\n}\n",
+	"bidder-params/ix.json":                     "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Ix Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Ix adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"siteId\": {\n      \"type\": \"string\",\n      \"minLength\": 1,\n      \"description\": \"An ID which identifies the site selling the impression\"\n    },\n    \"size\": {\n      \"type\": \"array\",\n      \"items\": {\n        \"type\": \"integer\"\n      },\n      \"minItems\": 2,\n      \"maxItems\": 2,\n      \"description\": \"An array of two integer containing the dimension\"\n    }\n  },\n  \"required\": [\"siteId\"]\n}\n",
+	"bidder-params/kubient.json":                "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Kubient Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Kubient adapter\",\n  \"type\": \"object\",\n  \"properties\": {  }\n}\n",
+	"bidder-params/lifestreet.json":             "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Lifestreet Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Lifestreet adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"slot_tag\": {\n      \"type\": \"string\",\n      \"description\": \"A tag which identifies the ad slot\"\n    }\n  },\n  \"required\": [\"slot_tag\"]\n}\n",
+	"bidder-params/lockerdome.json":             "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"LockerDome Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the LockerDome adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"adUnitId\": {\n      \"type\": \"string\",\n      \"description\": \"A tag which identifies the LockerDome ad unit by adUnitId\"\n    }\n  },\n  \"required\": [\"adUnitId\"]\n}\n",
+	"bidder-params/marsmedia.json":              "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Marsmedia Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Marsmedia adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"zone\": {\n      \"type\": \"string\",\n      \"description\": \"Zone ID to use.\"\n    }\n  },\n  \"required\": [\"zone\"]\n}\n",
+	"bidder-params/mgid.json":                   "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Mgid Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Mgid adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n    \"accountId\": {\n      \"type\": \"string\",\n      \"description\": \"Internal Mgid account ID\"\n    },\n    \"placementId\": {\n      \"type\": \"string\",\n      \"description\": \"optional internal Mgid Placement ID\"\n    },\n    \"cur\": {\n      \"type\": \"string\",\n      \"description\": \"optional bidfloor currency\"\n    },\n    \"currency\": {\n      \"type\": \"string\",\n      \"description\": \"optional bidfloor currency\"\n    },\n    \"bidfloor\": {\n      \"type\": \"number\",\n      \"description\": \"optional minimum acceptable bid, in CPM, USD by default\"\n    },\n    \"bidFloor\": {\n      \"type\": \"number\",\n      \"description\": \"optional minimum acceptable bid, in CPM, USD by default\"\n    }\n  },\n  \"required\": [\"accountId\"]\n}\n",
+	"bidder-params/openx.json":                  "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Openx Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Openx adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n    \"unit\": {\n      \"type\": \"string\",\n      \"description\": \"The ad unit id.\",\n      \"pattern\": \"^[0-9]+$\"\n    },\n    \"delDomain\": {\n      \"type\": \"string\",\n      \"description\": \"The delivery domain for the customer.\",\n      \"pattern\": \"\\\\.[a-zA-Z]{2,3}$\",\n      \"format\": \"hostname\"\n    },\n    \"customFloor\": {\n      \"type\": \"number\",\n      \"description\": \"The minimum CPM price in USD.\",\n      \"minimum\": 0\n    },\n    \"customParams\": {\n      \"type\": \"object\",\n      \"description\": \"User-defined targeting key-value pairs.\"\n    }\n  },\n\n  \"required\": [\"unit\", \"delDomain\"]\n}\n",
+	"bidder-params/pubmatic.json":               "{\n\t\"$schema\": \"http://json-schema.org/draft-04/schema#\",\n\t\"title\": \"Pubmatic Adapter Params\",\n\t\"description\": \"A schema which validates params accepted by the Pubmatic adapter\",\n\t\"type\": \"object\",\n\t\"properties\": {\n\t\t\"publisherId\": {\n\t\t\t\"type\": \"string\",\n\t\t\t\"description\": \"An ID which identifies the publisher\"\n\t\t},\n\t\t\"adSlot\": {\n\t\t\t\"type\": \"string\",\n\t\t\t\"description\": \"An ID which identifies the ad slot\"\n\t\t},\n\t\t\"wrapper\": {\n\t\t\t\"type\": \"object\",\n\t\t\t\"description\": \"Specifies pubmatic openwrap configuration for a publisher\",\n\t\t\t\"properties\": {\n\t\t\t\t\"profile\": {\n\t\t\t\t\t\"type\": \"integer\",\n\t\t\t\t\t\"description\": \"An ID which identifies the openwrap profile of publisher\"\n\t\t\t\t},\n\t\t\t\t\"version\": {\n\t\t\t\t\t\"type\": \"integer\",\n\t\t\t\t\t\"description\": \"An ID which identifies version of the openwrap profile\"\n\t\t\t\t}\n\t\t\t},\n\t\t\t\"required\": [\"profile\"]\n\t\t},\n\t\t\"keywords\": {\n\t\t\t\"type\": \"array\",\n\t\t\t\"minItems\": 1,\n\t\t\t\"items\": {\n\t\t\t  \"type\": \"object\",\n\t\t\t  \"description\": \"A key with one or more values associated with it. These are used in buy-side segment targeting.\",\n\t\t\t  \"properties\": {\n\t\t\t\t\"key\": {\n\t\t\t\t  \"type\": \"string\"\n\t\t\t\t},\n\t\t\t\t\"value\": {\n\t\t\t\t  \"type\": \"array\",\n\t\t\t\t  \"minItems\": 1,\n\t\t\t\t  \"items\": {\n\t\t\t\t\t\"type\": \"string\"\n\t\t\t\t  }\n\t\t\t\t}\n\t\t\t  },\n\t\t\t  \"required\": [\"key\", \"value\"]\n\t\t\t}\n\t\t  }\n\t},\n\t\"required\": [\"publisherId\"]\n}\n",
+	"bidder-params/pubnative.json":              "{\n\t\"$schema\": \"http://json-schema.org/draft-04/schema#\",\n\t\"title\": \"Pubnative Adapter Params\",\n\t\"description\": \"A schema which validates params accepted by the Pubnative adapter\",\n\t\"type\": \"object\",\n\t\"properties\": {\n\t\t\"zone_id\": {\n\t\t\t\"type\": \"integer\",\n\t\t\t\"description\": \"The ad zone identifier\"\n\t\t}, \"app_auth_token\": {\n\t\t\t\"type\": \"string\",\n\t\t\t\"description\": \"The app's authentication token\"\n\t\t}\n\t},\n\t\"required\": [\"zone_id\", \"app_auth_token\"]\n}\n",
+	"bidder-params/pulsepoint.json":             "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Pulsepoint Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Pulsepoint adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"cp\": {\n      \"type\": \"integer\",\n      \"description\": \"An ID which identifies the publisher selling the impression\"\n    },\n    \"ct\": {\n      \"type\": \"integer\",\n      \"description\": \"An ID which identifies the ad slot being sold\"\n    },\n    \"cf\": {\n      \"type\": \"string\",\n      \"pattern\": \"^[0-9]+[xX][0-9]+$\",\n      \"description\": \"The size of the ad slot being sold. This should be a string like 300X250\"\n    }\n  },\n  \"required\": [\"cp\", \"ct\", \"cf\"]\n}\n",
+	"bidder-params/rhythmone.json":              "{\r\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\r\n  \"title\": \"Rhythmone Adapter Params\",\r\n  \"description\": \"A schema which validates params accepted by the Rhythmone adapter\",\r\n  \"type\": \"object\",\r\n  \"properties\": {\r\n    \"placementId\": {\r\n      \"type\": \"string\",\r\n      \"description\": \"An ID which is used to frame Rhythmone ad tag\",\r\n      \"minLength\": 1\r\n    },\r\n    \"path\": {\r\n      \"type\": \"string\",\r\n      \"description\": \"An ID which is used to frame Rhythmone ad tag\",\r\n      \"minLength\": 1\r\n    },\r\n    \"zone\": {\r\n      \"type\": \"string\",\r\n      \"description\": \"An ID which is used to frame Rhythmone ad tag\",\r\n      \"minLength\": 1\r\n    }\r\n  },\r\n  \"required\": [\"placementId\", \"path\", \"zone\"]\r\n}\r\n",
+	"bidder-params/rtbhouse.json":               "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"RTB House Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the RTB House adapter\",\n  \"type\": \"object\",\n  \"properties\": {},\n  \"required\": []\n}\n",
+	"bidder-params/rubicon.json":                "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Rubicon Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Rubicon adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"accountId\": {\n      \"type\": \"integer\",\n      \"minimum\": 1,\n      \"description\": \"An ID which identifies the publisher's account\"\n    },\n    \"siteId\": {\n      \"type\": \"integer\",\n      \"minimum\": 1,\n      \"description\": \"An ID which identifies the site selling the impression\"\n    },\n    \"zoneId\": {\n      \"type\": \"integer\",\n      \"minimum\": 1,\n      \"description\": \"An ID which identifies the sub-section of the site where the impression is located\"\n    },\n    \"inventory\": {\n      \"type\": \"object\",\n      \"description\": \"An object defining arbitrary targeting key/value pairs related to the page\",\n      \"additionalProperties\": {\n        \"type\": \"array\"\n      }\n    },\n    \"visitor\": {\n      \"type\": \"object\",\n      \"description\": \"An object defining arbitrary targeting key/value pairs related to the visitor\",\n      \"additionalProperties\": {\n        \"type\": \"array\"\n      }\n    },\n    \"video\": {\n      \"type\": \"object\",\n      \"description\": \"An object defining additional Rubicon video parameters\",\n      \"properties\": {\n        \"language\": {\n          \"type\": \"string\",\n          \"description\": \"Language of the ad - should match content video\"\n        },\n        \"playerHeight\": {\n          \"type\": \"integer\",\n          \"description\": \"Height in pixels of the video player\"\n        },\n        \"playerWidth\": {\n          \"type\": \"integer\",\n          \"description\": \"Width in pixels of the video player\"\n        },\n        \"size_id\": {\n          \"type\": \"integer\",\n          \"description\": \"Rubicon size_id, used to describe type of video ad (preroll, postroll, etc)\"\n        },\n        \"skip\": {\n          \"type\": \"integer\",\n          \"description\": \"Can this ad be skipped ( 0 = no, 1 = yes)\"\n        },\n        \"skipdelay\": {\n          \"type\": \"integer\",\n          \"description\": \"number of seconds until the ad can be skipped\"\n        }\n      }\n    }\n  },\n  \"required\": [\"accountId\", \"siteId\", \"zoneId\"]\n}\n",
+	"bidder-params/sharethrough.json":           "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Sharethrough Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Sharethrough adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"pkey\": {\n      \"type\": \"string\",\n      \"description\": \"placement key to use.\"\n    },\n    \"iframe\": {\n      \"type\": \"boolean\",\n      \"description\": \"whether or not to stay in iframe\",\n      \"default\": false\n    },\n    \"iframeSize\": {\n      \"type\": \"array\",\n      \"minItems\": 2,\n      \"maxItems\": 2,\n      \"items\": {\n        \"type\": \"integer\"\n      },\n      \"description\": \"iframe dimensions\",\n      \"default\": [0, 0]\n    },\n    \"bidfloor\": {\n      \"type\": \"number\",\n      \"description\": \"The floor price, or minimum amount, a publisher will accept for an impression, given in CPM in USD\"\n    }\n  },\n  \"required\": [\"pkey\"]\n}\n",
+	"bidder-params/somoaudience.json":           "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"SomoAudience Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the SomoAudience adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n    \"placement_hash\": {\n      \"type\": \"string\",\n      \"description\": \"A hash defining the placement selling the impression\"\n    },\n    \"bid_floor\": {\n      \"type\": \"number\",\n      \"description\": \"Bid Floor for Impression\",\n      \"minimum\": 0\n    }\n  },\n  \"required\": [\"placement_hash\"]\n}\n",
+	"bidder-params/sonobi.json":                 "{\n    \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n    \"title\": \"Sonobi Adapter Params\",\n    \"description\": \"A schema which validates params accepted by the Sonobi adapter\",\n  \n    \"type\": \"object\",\n    \"properties\": {\n      \"TagID\": {\n        \"type\": \"string\",\n        \"description\": \"An ID which identifies /ad/unit/code or the sonobi placement_id\"\n      }\n    },\n    \"required\": [\"TagID\"]\n  }\n  ",
+	"bidder-params/sovrn.json":                  "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Sovrn Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Sovrn adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n    \"tagid\": {\n      \"type\": \"string\",\n      \"description\": \"An ID which identifies the sovrn ad tag\"\n    },\n    \"tagId\": {\n      \"type\": \"string\",\n      \"description\": \"An ID which identifies the sovrn ad tag (DEPRECATED, use \\\"tagid\\\" instead)\"\n    },\n    \"bidfloor\": {\n      \"type\": \"number\",\n      \"description\": \"The minimium acceptable bid, in CPM, using US Dollars\"\n    }\n  },\n  \"oneOf\": [\n    { \"required\" : [ \"tagid\" ] },\n    { \"required\" : [ \"tagId\" ] }\n  ]\n}\n",
+	"bidder-params/spotx.json":                  "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"OpenX Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the OpenX adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n    \"channel_id\": {\n      \"type\": \"string\",\n      \"minLength\": 5,\n      \"maxLength\": 5,\n      \"description\": \"A unique 5 digit ID that is generated by the SpotX publisher platform when a channel is created\"\n    },\n    \"ad_unit\": {\n      \"type\": \"string\",\n      \"description\": \"Token that describes which ad unit to play: instream or outstream\",\n      \"enum\": [\"instream\", \"outstream\"]\n    },\n    \"secure\": {\n      \"type\": \"boolean\",\n      \"description\": \"Boolean identifying whether the reqeusts should be https or not (used to override the protocol if the page isn’t secure.\"\n    },\n    \"ad_volume\": {\n      \"type\": \"number\",\n      \"minimum\": 0,\n      \"maximum\": 1,\n      \"description\": \"Value between 0 and 1 to denote the volume the ad should start at.\"\n    },\n    \"price_floor\": {\n      \"type\": \"integer\",\n      \"description\": \"Set the current channel price floor in real time.\"\n    },\n    \"hide_skin\": {\n      \"type\": \"boolean\",\n      \"description\": \"Set to true to hide the spotx skin.\"\n    }\n  },\n  \"required\": [\"channel_id\", \"ad_unit\"]\n}\n",
+	"bidder-params/synacormedia.json":           "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Synacormedia Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Synacormedia adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n    \"seatId\": {\n      \"type\": \"string\",\n      \"description\": \"The seat id.\"\n    }\n  },\n\n  \"required\": [\"seatId\"]\n}\n",
+	"bidder-params/tappx.json":                  "{\n\t\"$schema\": \"http://json-schema.org/draft-04/schema#\",\n\t\"title\": \"Tappx Adapter Params\",\n\t\"description\": \"A schema which validates params accepted by the Tappx adapter\",\n\t\"type\": \"object\",\n\t\"properties\": {\n\t\t\"host\": {\n\t\t\t\"type\": \"string\",\n\t\t\t\"description\": \"Tappx host\"\n\t\t},\n\t\t\"tappxkey\": {\n\t\t\t\"type\": \"string\",\n\t\t\t\"description\": \"An ID which identifies the adunit\"\n\t\t},\n\t\t\"endpoint\": {\n\t\t\t\"type\": \"string\",\n\t\t\t\"description\": \"Endpoint provided to publisher\"\n\t\t},\n\t\t\"bidfloor\": {\n\t\t\t\"type\": \"number\",\n\t\t\t\"description\": \"Minimum bid for this impression expressed in CPM (USD)\"\n\t\t}\n\t},\n\t\"required\": [\"host\",\"tappxkey\",\"endpoint\"]\n}\n",
+	"bidder-params/telaria.json":                "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Telaria Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Telaria adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n    \"adCode\": {\n      \"type\": \"string\",\n      \"description\": \"The Ad Unit Code.\"\n    },\n    \"seatCode\": {\n      \"type\": \"string\",\n      \"description\": \"Your Seat Code.\"\n    },\n    \"originalPublisherid\": {\n      \"type\": \"string\",\n      \"description\": \"publisher ID from the original request\"\n    }\n  },\n  \"required\": [\"seatCode\"]\n}\n",
+	"bidder-params/triplelift.json":             "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Triplelift Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Triplelift adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n    \"inventoryCode\": {\n      \"type\": \"string\",\n      \"description\": \"TripleLift inventory code for this ad unit (provided to you by your partner manager)\"\n    },\n    \"floor\" :  {\"description\" : \"the bid floor\", \"type\": \"number\" }\n  },\n  \"required\": [\"inventoryCode\"]\n}\n",
+	"bidder-params/triplelift_native.json":      "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"Triplelift Adapter Params\",\n  \"description\": \"A schema which validates params accepted by the Triplelift adapter\",\n\n  \"type\": \"object\",\n  \"properties\": {\n    \"inventoryCode\": {\n      \"type\": \"string\",\n      \"description\": \"TripleLift inventory code for this ad unit (provided to you by your partner manager)\"\n    },\n    \"floor\" :  {\"description\" : \"the bid floor, in usd\", \"type\": \"number\" }\n  },\n  \"required\": [\"inventoryCode\"]\n}\n",
+	"bidder-params/unruly.json":                 "{\n    \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n    \"title\": \"Unruly Adapter Params\",\n    \"description\": \"A schema which validates params accepted by the Unruly adapter\",\n    \"type\": \"object\",\n    \"properties\": {\n      \"uuid\": {\n        \"type\": \"string\",\n        \"description\": \"uuid\"\n      },\n      \"siteid\": {\n        \"type\": \"string\",\n        \"description\": \"ID for publisher site\"\n      }\n    },\n  \"required\": [\"UUID\", \"SiteId\"]\n}\n",
+	"bidder-params/verizonmedia.json":           "{\n\t\"$schema\": \"http://json-schema.org/draft-04/schema#\",\n\t\"title\": \"VerizonMedia Adapter Params\",\n\t\"description\": \"A schema which validates params accepted by the VerizonMedia adapter\",\n\t\"type\": \"object\",\n\t\"properties\": {\n\t\t\"dcn\": {\n\t\t\t\"type\": \"string\",\n\t\t\t\"description\": \"Site ID provided by One Mobile\"\n\t\t},\n\t\t\"pos\": {\n\t\t\t\"type\": \"string\",\n\t\t\t\"description\": \"Placement ID\"\n\t\t}\n\t},\n\t\"required\": [\"dcn\", \"pos\"]\n}\n",
+	"bidder-params/visx.json":                   "{\n  \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n  \"title\": \"VIS.X Adapter Params\",\n  \"description\": \"A schema which validates params accepted by VIS.X adapter\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"uid\": {\n      \"type\": \"integer\",\n      \"description\": \"An ID which identifies this placement of the impression\"\n    },\n    \"size\": {\n      \"type\": \"array\",\n      \"items\": {\n        \"type\": \"integer\"\n      },\n      \"minItems\": 2,\n      \"maxItems\": 2,\n      \"description\": \"An array of two integer containing the dimension\"\n    }\n  },\n  \"required\": [\"uid\"]\n}\n",
+	"bidder-params/vrtcal.json":                 "\n{\n    \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n    \"title\": \"Vrtcal Adapter Params\",\n    \"description\": \"A schema which validates params accepted by the Vrtcal adapter\",\n  \n    \"type\": \"object\",\n    \"properties\": {\n      \"just_an_unused_vrtcal_param\": {\n        \"type\": \"string\",\n        \"description\": \"We only have this param as the prebid server crashes without at least 1 custom param; Not set to required to achieve parameter-less custom functionality\"\n      }\n    },\n    \"required\": []\n  }\n",
+	"bidder-params/yieldmo.json":                "\n{\n    \"$schema\": \"http://json-schema.org/draft-04/schema#\",\n    \"title\": \"Yieldmo Adapter Params\",\n    \"description\": \"A schema which validates params accepted by the Yieldmo adapter\",\n  \n    \"type\": \"object\",\n    \"properties\": {\n      \"placementId\": {\n        \"type\": \"string\",\n        \"description\": \"Internal Yieldmo Placement ID\"\n      }\n    },\n    \"required\": [\"placementId\"]\n  }\n",
+	"category-mapping/dfp/dfp_dfp.json":         "{\n  \"IAB1-5\": {\n    \"id\": \"movies\",\n    \"name\": \"Filmed Entertainment\"\n  },\n  \"IAB1-6\": {\n    \"id\": \"movies\",\n    \"name\": \"Filmed Entertainment\"\n  },\n  \"IAB1-7\": {\n    \"id\": \"movies\",\n    \"name\": \"Filmed Entertainment\"\n  },\n  \"IAB2-1\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-2\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-3\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-4\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-5\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-6\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-7\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-8\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-9\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-10\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-11\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-12\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-13\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-14\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-15\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-16\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-17\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-18\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-19\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-20\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-21\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-22\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-23\": {\n    \"id\": \"auto\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB3-1\": {\n    \"id\": \"business\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB3-4\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB4-5\": {\n    \"id\": \"business\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB5-1\": {\n    \"id\": \"education\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-10\": {\n    \"id\": \"education\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB7-1\": {\n    \"id\": \"healthcare\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-32\": {\n    \"id\": \"healthcare\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-44\": {\n    \"id\": \"healthcare\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB8-1\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-2\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-3\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-4\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-5\": {\n    \"id\": \"beverages\",\n    \"name\": \"Beer/Wine/Liquor\"\n  },\n  \"IAB8-6\": {\n    \"id\": \"beverages\",\n    \"name\": \"Beverages\"\n  },\n  \"IAB8-7\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-8\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-9\": {\n    \"id\": \"food\",\n    \"name\": \"Restaurant/Fast Food\"\n  },\n  \"IAB8-10\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-11\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-12\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-13\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-14\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-15\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-16\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-17\": {\n    \"id\": \"food\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-18\": {\n    \"id\": \"beverages\",\n    \"name\": \"Beer/Wine/Liquor\"\n  },\n  \"IAB9-5\": {\n    \"id\": \"game\",\n    \"name\": \"Gaming\"\n  },\n  \"IAB9-9\": {\n    \"id\": \"tobacco\",\n    \"name\": \"Tobacco\"\n  },\n  \"IAB9-30\": {\n    \"id\": \"entertainment\",\n    \"name\": \"Entertainment\"\n  },\n  \"IAB10-1\": {\n    \"id\": \"appliances\",\n    \"name\": \"Appliances\"\n  },\n  \"IAB10-7\": {\n    \"id\": \"furnishings\",\n    \"name\": \"Home Furnishings\"\n  },\n  \"IAB11-2\": {\n    \"id\": \"government\",\n    \"name\": \"Government/Municipal\"\n  },\n  \"IAB11-4\": {\n    \"id\": \"government\",\n    \"name\": \"Government/Municipal\"\n  },\n  \"IAB13-2\": {\n    \"id\": \"business\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB13-4\": {\n    \"id\": \"financial\",\n    \"name\": \"Financial Services\"\n  },\n  \"IAB13-6\": {\n    \"id\": \"insurance\",\n    \"name\": \"Insurance\"\n  },\n  \"IAB14-1\": {\n    \"id\": \"dating\",\n    \"name\": \"dating\"\n  },\n  \"IAB16-1\": {\n    \"id\": \"pets\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB16-2\": {\n    \"id\": \"pets\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB16-3\": {\n    \"id\": \"pets\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB16-4\": {\n    \"id\": \"pets\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB16-5\": {\n    \"id\": \"pets\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB16-6\": {\n    \"id\": \"pets\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB16-7\": {\n    \"id\": \"pets\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB17-12\": {\n    \"id\": \"sport\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB18-1\": {\n    \"id\": \"beauty\",\n    \"name\": \"Cosmetics/Toiletries\"\n  },\n  \"IAB18-4\": {\n    \"id\": \"jewelry\",\n    \"name\": \"Jewelry\"\n  },\n  \"IAB18-5\": {\n    \"id\": \"apparel\",\n    \"name\": \"Apparel\"\n  },\n  \"IAB19-2\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-3\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-4\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-6\": {\n    \"id\": \"telecommunications\",\n    \"name\": \"Telecommunications\"\n  },\n  \"IAB19-7\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-8\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-9\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-10\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-11\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-12\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-13\": {\n    \"id\": \"publishing\",\n    \"name\": \"Publishing\"\n  },\n  \"IAB19-14\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-15\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-16\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-17\": {\n    \"id\": \"movies\",\n    \"name\": \"Filmed Entertainment\"\n  },\n  \"IAB19-18\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-19\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-20\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-21\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-22\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-23\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-24\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-25\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-26\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-27\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-28\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-30\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-31\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-32\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-33\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-34\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-35\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-36\": {\n    \"id\": \"electronics\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB20-1\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-2\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-3\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-4\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-5\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-6\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-7\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-8\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-9\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-10\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-11\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-12\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-13\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-14\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-15\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-16\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-17\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-18\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-19\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-20\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-21\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-22\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-23\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-24\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-25\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-26\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-27\": {\n    \"id\": \"travel\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB21-3\": {\n    \"id\": \"realestate\",\n    \"name\": \"Real Estate\"\n  },\n  \"IAB22-2\": {\n    \"id\": \"realestate\",\n    \"name\": \"Real Estate\"\n  }\n}",
+	"category-mapping/freewheel/freewheel.json": "{\n  \"IAB1-1\": {\n    \"id\": \"404\",\n    \"name\": \"Publishing\"\n  },\n  \"IAB1-2\": {\n    \"id\": \"392\",\n    \"name\": \"Entertainment\"\n  },\n  \"IAB1-5\": {\n    \"id\": \"419\",\n    \"name\": \"Filmed Entertainment\"\n  },\n  \"IAB1-6\": {\n    \"id\": \"392\",\n    \"name\": \"Entertainment\"\n  },\n  \"IAB1-7\": {\n    \"id\": \"392\",\n    \"name\": \"Entertainment\"\n  },\n  \"IAB2-1\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-2\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-3\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-4\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-5\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-6\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-7\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-8\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-9\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-10\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-11\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-12\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-13\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-14\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-15\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-16\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-17\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-18\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-19\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-20\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-21\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-22\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB2-23\": {\n    \"id\": \"399\",\n    \"name\": \"Automotive\"\n  },\n  \"IAB3-1\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB3-2\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB3-3\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB3-4\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB3-5\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB3-6\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB3-7\": {\n    \"id\": \"398\",\n    \"name\": \"Government/Municipal\"\n  },\n  \"IAB3-8\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB3-9\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB3-10\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB3-11\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB3-12\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB4-1\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB4-2\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB4-3\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB4-4\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB4-5\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB4-6\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB4-7\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB4-8\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB4-9\": {\n    \"id\": \"417\",\n    \"name\": \"Telecommunications\"\n  },\n  \"IAB4-10\": {\n    \"id\": \"429\",\n    \"name\": \"Military\"\n  },\n  \"IAB4-11\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB5-1\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-2\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-3\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-4\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-5\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-6\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-7\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-8\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-9\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-10\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-11\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-12\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-13\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-14\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB5-15\": {\n    \"id\": \"405\",\n    \"name\": \"Educational Services\"\n  },\n  \"IAB7-1\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-2\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-3\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-4\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-5\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-6\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-7\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-8\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-9\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-10\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-11\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-12\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-13\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-14\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-15\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-16\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-17\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-18\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-19\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-20\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-21\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-22\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-23\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-24\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-25\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-26\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-27\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-28\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-29\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-30\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-31\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-32\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-33\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-34\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-35\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-36\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-37\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-38\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-39\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-40\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-41\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-42\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-43\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-44\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB7-45\": {\n    \"id\": \"406\",\n    \"name\": \"Health Care Services\"\n  },\n  \"IAB8-1\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-2\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-3\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-4\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-5\": {\n    \"id\": \"400\",\n    \"name\": \"Beer/Wine/Liquor\"\n  },\n  \"IAB8-6\": {\n    \"id\": \"401\",\n    \"name\": \"Beverages\"\n  },\n  \"IAB8-7\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-8\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-9\": {\n    \"id\": \"407\",\n    \"name\": \"Restaurant/Fast Food\"\n  },\n  \"IAB8-10\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-11\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-12\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-13\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-14\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-15\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-16\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-17\": {\n    \"id\": \"394\",\n    \"name\": \"Food\"\n  },\n  \"IAB8-18\": {\n    \"id\": \"400\",\n    \"name\": \"Beer/Wine/Liquor\"\n  },\n  \"IAB9-1\": {\n    \"id\": \"392\",\n    \"name\": \"Entertainment\"\n  },\n  \"IAB9-3\": {\n    \"id\": \"418\",\n    \"name\": \"Jewelry\"\n  },\n  \"IAB9-5\": {\n    \"id\": \"413\",\n    \"name\": \"Gaming\"\n  },\n  \"IAB9-6\": {\n    \"id\": \"412\",\n    \"name\": \"Household Products\"\n  },\n  \"IAB9-9\": {\n    \"id\": \"426\",\n    \"name\": \"Tobacco\"\n  },\n  \"IAB9-11\": {\n    \"id\": \"404\",\n    \"name\": \"Publishing\"\n  },\n  \"IAB9-15\": {\n    \"id\": \"404\",\n    \"name\": \"Publishing\"\n  },\n  \"IAB9-16\": {\n    \"id\": \"392\",\n    \"name\": \"Entertainment\"\n  },\n  \"IAB9-18\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB9-19\": {\n    \"id\": \"418\",\n    \"name\": \"Jewelry\"\n  },\n  \"IAB9-23\": {\n    \"id\": \"424\",\n    \"name\": \"Photographic Equipment\"\n  },\n  \"IAB9-24\": {\n    \"id\": \"392\",\n    \"name\": \"Entertainment\"\n  },\n  \"IAB9-25\": {\n    \"id\": \"392\",\n    \"name\": \"Entertainment\"\n  },\n  \"IAB9-30\": {\n    \"id\": \"392\",\n    \"name\": \"Entertainment\"\n  },\n  \"IAB10-1\": {\n    \"id\": \"415\",\n    \"name\": \"Appliances\"\n  },\n  \"IAB10-5\": {\n    \"id\": \"434\",\n    \"name\": \"Home Furnishings\"\n  },\n  \"IAB10-6\": {\n    \"id\": \"434\",\n    \"name\": \"Home Furnishings\"\n  },\n  \"IAB10-7\": {\n    \"id\": \"434\",\n    \"name\": \"Home Furnishings\"\n  },\n  \"IAB10-8\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB10-9\": {\n    \"id\": \"434\",\n    \"name\": \"Home Furnishings\"\n  },\n  \"IAB11-1\": {\n    \"id\": \"398\",\n    \"name\": \"Government/Municipal\"\n  },\n  \"IAB11-2\": {\n    \"id\": \"398\",\n    \"name\": \"Government/Municipal\"\n  },\n  \"IAB11-3\": {\n    \"id\": \"398\",\n    \"name\": \"Government/Municipal\"\n  },\n  \"IAB11-4\": {\n    \"id\": \"398\",\n    \"name\": \"Government/Municipal\"\n  },\n  \"IAB11-5\": {\n    \"id\": \"398\",\n    \"name\": \"Government/Municipal\"\n  },\n  \"IAB12-1\": {\n    \"id\": \"438\",\n    \"name\": \"News\"\n  },\n  \"IAB12-2\": {\n    \"id\": \"438\",\n    \"name\": \"News\"\n  },\n  \"IAB12-3\": {\n    \"id\": \"438\",\n    \"name\": \"News\"\n  },\n  \"IAB13-1\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB13-2\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB13-3\": {\n    \"id\": \"438\",\n    \"name\": \"News\"\n  },\n  \"IAB13-4\": {\n    \"id\": \"391\",\n    \"name\": \"Financial Services\"\n  },\n  \"IAB13-5\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB13-6\": {\n    \"id\": \"436\",\n    \"name\": \"Insurance\"\n  },\n  \"IAB13-7\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB13-8\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB13-9\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB13-10\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB13-11\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB13-12\": {\n    \"id\": \"393\",\n    \"name\": \"Business Services\"\n  },\n  \"IAB16-1\": {\n    \"id\": \"423\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB16-2\": {\n    \"id\": \"423\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB16-3\": {\n    \"id\": \"423\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB16-4\": {\n    \"id\": \"423\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB16-5\": {\n    \"id\": \"423\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB16-6\": {\n    \"id\": \"423\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB16-7\": {\n    \"id\": \"423\",\n    \"name\": \"Pet Food/Supplies\"\n  },\n  \"IAB17-1\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-2\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-3\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-4\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-5\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-6\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-7\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-8\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-9\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-10\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-11\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-12\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-13\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-14\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-15\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-16\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-17\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-18\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-19\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-20\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-21\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-22\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-23\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-24\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-25\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-26\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-27\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-28\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-29\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-30\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-31\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-32\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-33\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-34\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-35\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-36\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-37\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-38\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-39\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-40\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-41\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-42\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-43\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB17-44\": {\n    \"id\": \"425\",\n    \"name\": \"Professional Sports\"\n  },\n  \"IAB18-1\": {\n    \"id\": \"411\",\n    \"name\": \"Cosmetics/Toiletries\"\n  },\n  \"IAB18-2\": {\n    \"id\": \"397\",\n    \"name\": \"Apparel\"\n  },\n  \"IAB18-3\": {\n    \"id\": \"397\",\n    \"name\": \"Apparel\"\n  },\n  \"IAB18-4\": {\n    \"id\": \"418\",\n    \"name\": \"Jewelry\"\n  },\n  \"IAB18-5\": {\n    \"id\": \"397\",\n    \"name\": \"Apparel\"\n  },\n  \"IAB18-6\": {\n    \"id\": \"397\",\n    \"name\": \"Apparel\"\n  },\n  \"IAB19-2\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-3\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-4\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-5\": {\n    \"id\": \"424\",\n    \"name\": \"Photographic Equipment\"\n  },\n  \"IAB19-6\": {\n    \"id\": \"417\",\n    \"name\": \"Telecommunications\"\n  },\n  \"IAB19-7\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-8\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-9\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-10\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-11\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-12\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-13\": {\n    \"id\": \"404\",\n    \"name\": \"Publishing\"\n  },\n  \"IAB19-14\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-15\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-16\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-17\": {\n    \"id\": \"419\",\n    \"name\": \"Filmed Entertainment\"\n  },\n  \"IAB19-18\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-19\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-20\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-21\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-22\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-23\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-24\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-25\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-26\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-27\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-28\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-29\": {\n    \"id\": \"392\",\n    \"name\": \"Entertainment\"\n  },\n  \"IAB19-30\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-31\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-32\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-33\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-34\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-35\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB19-36\": {\n    \"id\": \"409\",\n    \"name\": \"Computing Product\"\n  },\n  \"IAB20-1\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-2\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-3\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-4\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-5\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-6\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-7\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-8\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-9\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-10\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-11\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-12\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-13\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-14\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-15\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-16\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-17\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-18\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-19\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-20\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-21\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-22\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-23\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-24\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-25\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-26\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB20-27\": {\n    \"id\": \"395\",\n    \"name\": \"Travel/Hotels/Airlines\"\n  },\n  \"IAB21-1\": {\n    \"id\": \"416\",\n    \"name\": \"Real Estate\"\n  },\n  \"IAB21-2\": {\n    \"id\": \"416\",\n    \"name\": \"Real Estate\"\n  },\n  \"IAB21-3\": {\n    \"id\": \"416\",\n    \"name\": \"Real Estate\"\n  },\n  \"IAB22-1\": {\n    \"id\": \"416\",\n    \"name\": \"Real Estate\"\n  },\n  \"IAB22-2\": {\n    \"id\": \"416\",\n    \"name\": \"Real Estate\"\n  },\n  \"IAB22-3\": {\n    \"id\": \"416\",\n    \"name\": \"Real Estate\"\n  }\n}",
+}
@@ -0,0 +1,66 @@
+//go:build ignore
+// +build ignore
+
+// This program generates assets.go, which embeds the static files used by Prebid Server into the binary.
+// It is run by `go generate ./static`.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var embeddedDirs = []string{"bidder-info", "bidder-params", "category-mapping"}
+
+func main() {
+	files := make(map[string][]byte)
+	for _, dir := range embeddedDirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+				return nil
+			}
+			contents, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			files[filepath.ToSlash(path)] = contents
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", dir, err)
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_assets.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package static\n\n")
+	buf.WriteString("var assets = map[string]string{\n")
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%q: %q,\n", name, files[name])
+	}
+	buf.WriteString("}\n")
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("Failed to format the generated code: %v", err)
+	}
+	if err := ioutil.WriteFile("assets.go", source, 0644); err != nil {
+		log.Fatalf("Failed to write assets.go: %v", err)
+	}
+}
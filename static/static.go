@@ -0,0 +1,149 @@
+// Package static gives access to the bidder params JSON schemas, bidder info and category mapping files.
+//
+// A copy of these files is compiled into the binary, so Prebid Server can start from any working directory.
+// Hosts which want to use their own files can point the config at a directory instead.
+//
+// After changing any file in static/bidder-params, static/bidder-info or static/category-mapping,
+// run `go generate ./static` to refresh the embedded copy.
+package static
+
+//go:generate go run gen_assets.go
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// These are the directories under static/ which get embedded into the binary.
+const (
+	BidderParamsDir    = "bidder-params"
+	BidderInfoDir      = "bidder-info"
+	CategoryMappingDir = "category-mapping"
+)
+
+// FS is a read-only tree of static files.
+//
+// Names are slash-separated and relative to the root of the tree.
+type FS interface {
+	// ReadDir returns the entries of the named directory, sorted by name.
+	ReadDir(name string) ([]os.FileInfo, error)
+	// ReadFile returns the contents of the named file.
+	ReadFile(name string) ([]byte, error)
+}
+
+// Dir returns an FS which reads the files under root on the local filesystem.
+func Dir(root string) FS {
+	return dirFS(root)
+}
+
+type dirFS string
+
+func (dir dirFS) ReadDir(name string) ([]os.FileInfo, error) {
+	return ioutil.ReadDir(dir.join(name))
+}
+
+func (dir dirFS) ReadFile(name string) ([]byte, error) {
+	return ioutil.ReadFile(dir.join(name))
+}
+
+func (dir dirFS) join(name string) string {
+	return filepath.Join(string(dir), filepath.FromSlash(name))
+}
+
+func (dir dirFS) String() string {
+	return string(dir)
+}
+
+// Embedded returns an FS holding the copy of static/{dir} which was compiled into the binary.
+func Embedded(dir string) FS {
+	return embeddedFS(path.Clean(dir))
+}
+
+type embeddedFS string
+
+func (root embeddedFS) ReadDir(name string) ([]os.FileInfo, error) {
+	dir := root.join(name)
+	prefix := dir + "/"
+	seen := make(map[string]bool)
+	var infos []os.FileInfo
+	for file, contents := range assets {
+		if !strings.HasPrefix(file, prefix) {
+			continue
+		}
+		entry := strings.TrimPrefix(file, prefix)
+		if i := strings.Index(entry, "/"); i >= 0 {
+			if entry = entry[:i]; !seen[entry] {
+				seen[entry] = true
+				infos = append(infos, embeddedFileInfo{name: entry, isDir: true})
+			}
+			continue
+		}
+		infos = append(infos, embeddedFileInfo{name: entry, size: int64(len(contents))})
+	}
+	if len(infos) == 0 {
+		return nil, &os.PathError{Op: "readdir", Path: root.describe(name), Err: os.ErrNotExist}
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name() < infos[j].Name()
+	})
+	return infos, nil
+}
+
+func (root embeddedFS) ReadFile(name string) ([]byte, error) {
+	contents, ok := assets[root.join(name)]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: root.describe(name), Err: os.ErrNotExist}
+	}
+	return []byte(contents), nil
+}
+
+func (root embeddedFS) join(name string) string {
+	return path.Join(string(root), name)
+}
+
+func (root embeddedFS) describe(name string) string {
+	return fmt.Sprintf("embedded:%s", root.join(name))
+}
+
+func (root embeddedFS) String() string {
+	return fmt.Sprintf("embedded:%s", string(root))
+}
+
+type embeddedFileInfo struct {
+	name  string
+	size  int64
+	isDir bool
+}
+
+func (info embeddedFileInfo) Name() string {
+	return info.name
+}
+
+func (info embeddedFileInfo) Size() int64 {
+	return info.size
+}
+
+func (info embeddedFileInfo) Mode() os.FileMode {
+	if info.isDir {
+		return os.ModeDir | 0555
+	}
+	return 0444
+}
+
+func (info embeddedFileInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+func (info embeddedFileInfo) IsDir() bool {
+	return info.isDir
+}
+
+func (info embeddedFileInfo) Sys() interface{} {
+	return nil
+}
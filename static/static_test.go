@@ -0,0 +1,86 @@
+package static
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEmbeddedAssetsUpToDate makes sure that `go generate ./static` was run after the last change to the static files.
+func TestEmbeddedAssetsUpToDate(t *testing.T) {
+	for _, dir := range []string{BidderParamsDir, BidderInfoDir, CategoryMappingDir} {
+		assertSameTree(t, Dir(dir), Embedded(dir), ".")
+	}
+}
+
+func assertSameTree(t *testing.T, expected FS, actual FS, dir string) {
+	t.Helper()
+
+	expectedInfos, err := expected.ReadDir(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	actualInfos, err := actual.ReadDir(dir)
+	if !assert.NoError(t, err, "%v is missing directory %s. Run `go generate ./static`.", actual, dir) {
+		return
+	}
+
+	actualNames := make(map[string]os.FileInfo, len(actualInfos))
+	for _, info := range actualInfos {
+		actualNames[info.Name()] = info
+	}
+	for _, info := range expectedInfos {
+		if info.Name()[0] == '.' {
+			continue
+		}
+		name := dir + "/" + info.Name()
+		if !assert.Contains(t, actualNames, info.Name(), "%v is missing %s. Run `go generate ./static`.", actual, name) {
+			continue
+		}
+		delete(actualNames, info.Name())
+		if info.IsDir() {
+			assertSameTree(t, expected, actual, name)
+			continue
+		}
+		expectedBytes, _ := expected.ReadFile(name)
+		actualBytes, _ := actual.ReadFile(name)
+		assert.Equal(t, string(expectedBytes), string(actualBytes), "%v has a stale copy of %s. Run `go generate ./static`.", actual, name)
+	}
+	for name := range actualNames {
+		t.Errorf("%v has %s/%s, which no longer exists. Run `go generate ./static`.", actual, dir, name)
+	}
+}
+
+func TestEmbeddedReadDir(t *testing.T) {
+	fs := Embedded(CategoryMappingDir)
+
+	infos, err := fs.ReadDir(".")
+	assert.NoError(t, err)
+	if assert.Len(t, infos, 2) {
+		assert.Equal(t, "dfp", infos[0].Name())
+		assert.True(t, infos[0].IsDir())
+		assert.Equal(t, "freewheel", infos[1].Name())
+	}
+
+	infos, err = fs.ReadDir("freewheel")
+	assert.NoError(t, err)
+	if assert.Len(t, infos, 1) {
+		assert.Equal(t, "freewheel.json", infos[0].Name())
+		assert.False(t, infos[0].IsDir())
+	}
+
+	_, err = fs.ReadDir("unknown")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestEmbeddedReadFile(t *testing.T) {
+	fs := Embedded(BidderInfoDir)
+
+	contents, err := fs.ReadFile("appnexus.yaml")
+	assert.NoError(t, err)
+	assert.Contains(t, string(contents), "maintainer:")
+
+	_, err = fs.ReadFile("unknown.yaml")
+	assert.True(t, os.IsNotExist(err))
+}
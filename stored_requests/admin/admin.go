@@ -0,0 +1,304 @@
+// Package admin implements an HTTP API which manages the Stored Requests and Imps kept in a stored_requests.Store.
+//
+// Every change is validated before it gets saved, and is sent to the caches as an events.Save or events.Invalidation.
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/events"
+	"github.com/julienschmidt/httprouter"
+)
+
+// Validator checks the data for a Stored Request or Imp, and returns the reasons why it can't be saved.
+type Validator func(data json.RawMessage) []error
+
+// Endpoint manages the Stored Requests, and optionally the Stored Imps, used by one of the auction endpoints.
+//
+// Register its routes on a router, add its Store to the endpoint's Fetcher and, if the Fetcher is cached,
+// listen to its EventProducer so that the cache sees every change.
+type Endpoint struct {
+	store           stored_requests.Store
+	validateRequest Validator
+	validateImp     Validator
+
+	// writeLock serializes the writes to the store. emitLock is taken before writeLock is released, so that the
+	// caches get the events in the same order as the store without a slow listener holding up the next write.
+	writeLock     sync.Mutex
+	emitLock      sync.Mutex
+	emitEvents    bool
+	saves         chan events.Save
+	invalidations chan events.Invalidation
+}
+
+// eventBufferSize is the number of events which may wait for the listener before a write has to wait too.
+const eventBufferSize = 100
+
+// NewEndpoint builds an Endpoint on top of store. If validateImp is nil, the Endpoint only manages Stored Requests.
+func NewEndpoint(store stored_requests.Store, validateRequest Validator, validateImp Validator) *Endpoint {
+	return &Endpoint{
+		store:           store,
+		validateRequest: validateRequest,
+		validateImp:     validateImp,
+		saves:           make(chan events.Save, eventBufferSize),
+		invalidations:   make(chan events.Invalidation, eventBufferSize),
+	}
+}
+
+// Store returns the Store which holds the data.
+func (e *Endpoint) Store() stored_requests.Store {
+	return e.store
+}
+
+// EventProducer returns the producer of the cache events.
+//
+// The Endpoint only emits events once this has been called, so that it never blocks when there is no cache to update.
+// Someone must keep listening to the producer from then on.
+func (e *Endpoint) EventProducer() events.EventProducer {
+	e.writeLock.Lock()
+	defer e.writeLock.Unlock()
+	e.emitEvents = true
+	return e
+}
+
+func (e *Endpoint) Saves() <-chan events.Save {
+	return e.saves
+}
+
+func (e *Endpoint) Invalidations() <-chan events.Invalidation {
+	return e.invalidations
+}
+
+// Register adds the routes of the Endpoint under prefix. Every request must carry the header "Authorization: Bearer {token}".
+//
+//	GET    {prefix}/requests                           lists the IDs of the Stored Requests
+//	GET    {prefix}/requests/:id                       returns the latest version of the Stored Request
+//	PUT    {prefix}/requests/:id                       validates the body and saves it as a new version
+//	DELETE {prefix}/requests/:id                       deletes the Stored Request
+//	GET    {prefix}/requests/:id/versions              returns every version of the Stored Request, oldest first
+//	POST   {prefix}/requests/:id/rollback/:version     saves the data of an older version as a new version
+//
+// The same routes exist under {prefix}/imps if the Endpoint manages Stored Imps.
+func (e *Endpoint) Register(router *httprouter.Router, prefix string, token string) {
+	e.register(router, prefix+"/requests", token, stored_requests.RequestDataType, e.validateRequest)
+	if e.validateImp != nil {
+		e.register(router, prefix+"/imps", token, stored_requests.ImpDataType, e.validateImp)
+	}
+}
+
+func (e *Endpoint) register(router *httprouter.Router, path string, token string, dataType stored_requests.DataType, validate Validator) {
+	c := &collection{endpoint: e, dataType: dataType, validate: validate}
+	router.GET(path, requireToken(token, c.list))
+	router.GET(path+"/:id", requireToken(token, c.get))
+	router.PUT(path+"/:id", requireToken(token, c.put))
+	router.DELETE(path+"/:id", requireToken(token, c.delete))
+	router.GET(path+"/:id/versions", requireToken(token, c.versions))
+	router.POST(path+"/:id/rollback/:version", requireToken(token, c.rollback))
+}
+
+// requireToken rejects the requests which don't carry the bearer token.
+func requireToken(token string, handle httprouter.Handle) httprouter.Handle {
+	expected := []byte("Bearer " + token)
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("Missing or invalid token.\n"))
+			return
+		}
+		handle(w, r, ps)
+	}
+}
+
+// save validates and stores the data, and then tells the caches about it.
+func (e *Endpoint) save(ctx context.Context, dataType stored_requests.DataType, id string, data json.RawMessage, validate Validator) (stored_requests.Version, []error) {
+	if !json.Valid(data) {
+		return stored_requests.Version{}, []error{fmt.Errorf("Stored %s data is not valid JSON", dataType)}
+	}
+	if validate != nil {
+		if errs := validate(data); len(errs) > 0 {
+			return stored_requests.Version{}, errs
+		}
+	}
+
+	e.writeLock.Lock()
+	version, err := e.store.Save(ctx, dataType, id, data)
+	emit := e.lockEmit(err)
+	e.writeLock.Unlock()
+	if err != nil {
+		return version, []error{err}
+	}
+	if emit {
+		defer e.emitLock.Unlock()
+		save := events.Save{}
+		if dataType == stored_requests.ImpDataType {
+			save.Imps = map[string]json.RawMessage{id: data}
+		} else {
+			save.Requests = map[string]json.RawMessage{id: data}
+		}
+		e.saves <- save
+	}
+	return version, nil
+}
+
+func (e *Endpoint) delete(ctx context.Context, dataType stored_requests.DataType, id string) (stored_requests.Version, error) {
+	e.writeLock.Lock()
+	version, err := e.store.Delete(ctx, dataType, id)
+	emit := e.lockEmit(err)
+	e.writeLock.Unlock()
+	if err != nil {
+		return version, err
+	}
+	if emit {
+		defer e.emitLock.Unlock()
+		invalidation := events.Invalidation{}
+		if dataType == stored_requests.ImpDataType {
+			invalidation.Imps = []string{id}
+		} else {
+			invalidation.Requests = []string{id}
+		}
+		e.invalidations <- invalidation
+	}
+	return version, nil
+}
+
+// lockEmit takes emitLock if the write which just ended with err needs an event. The caller must hold writeLock,
+// and must unlock emitLock once the event is sent.
+func (e *Endpoint) lockEmit(err error) bool {
+	if err != nil || !e.emitEvents {
+		return false
+	}
+	e.emitLock.Lock()
+	return true
+}
+
+// collection serves the routes for the Stored Requests or the Stored Imps of an Endpoint.
+type collection struct {
+	endpoint *Endpoint
+	dataType stored_requests.DataType
+	validate Validator
+}
+
+func (c *collection) list(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ids, err := c.endpoint.store.IDs(r.Context(), c.dataType)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, ids)
+}
+
+func (c *collection) get(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id := ps.ByName("id")
+	versions, err := c.endpoint.store.Versions(r.Context(), c.dataType, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	latest := versions[len(versions)-1]
+	if latest.Deleted {
+		writeError(w, stored_requests.NotFoundError{ID: id, DataType: string(c.dataType)})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprintf(`"%d"`, latest.Version))
+	w.Write(latest.Data)
+}
+
+func (c *collection) put(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil || len(data) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Missing update data.\n"))
+		return
+	}
+	version, errs := c.endpoint.save(r.Context(), c.dataType, ps.ByName("id"), data, c.validate)
+	if len(errs) > 0 {
+		writeInvalid(w, errs)
+		return
+	}
+	writeJSON(w, version)
+}
+
+func (c *collection) delete(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	version, err := c.endpoint.delete(r.Context(), c.dataType, ps.ByName("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, version)
+}
+
+func (c *collection) versions(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	versions, err := c.endpoint.store.Versions(r.Context(), c.dataType, ps.ByName("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, versions)
+}
+
+func (c *collection) rollback(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	id := ps.ByName("id")
+	number, err := strconv.Atoi(ps.ByName("version"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("The version must be a number.\n"))
+		return
+	}
+	versions, err := c.endpoint.store.Versions(r.Context(), c.dataType, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if number < 1 || number > len(versions) || versions[number-1].Deleted {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, "Stored %s with ID=\"%s\" has no version %d to roll back to.\n", c.dataType, id, number)
+		return
+	}
+
+	// The data is validated again, since the bidders or their params may have changed since it was saved.
+	version, errs := c.endpoint.save(r.Context(), c.dataType, id, versions[number-1].Data, c.validate)
+	if len(errs) > 0 {
+		writeInvalid(w, errs)
+		return
+	}
+	writeJSON(w, version)
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	body, err := json.Marshal(value)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	if _, isNotFound := err.(stored_requests.NotFoundError); isNotFound {
+		w.WriteHeader(http.StatusNotFound)
+	} else {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	fmt.Fprintf(w, "%s\n", err.Error())
+}
+
+func writeInvalid(w http.ResponseWriter, errs []error) {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = fmt.Sprintf("Invalid request: %s\n", err.Error())
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write([]byte(strings.Join(messages, "")))
+}
@@ -0,0 +1,208 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/memory_store"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/caches/memory"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/events"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+const testToken = "secret"
+
+// rejectInvalid only accepts objects with "valid": true.
+func rejectInvalid(data json.RawMessage) []error {
+	var parsed struct {
+		Valid bool `json:"valid"`
+	}
+	if json.Unmarshal(data, &parsed) != nil || !parsed.Valid {
+		return []error{errors.New("not valid")}
+	}
+	return nil
+}
+
+func newTestRouter(endpoint *Endpoint) *httprouter.Router {
+	router := httprouter.New()
+	endpoint.Register(router, "/admin/storedrequests/openrtb2", testToken)
+	return router
+}
+
+func doRequest(router http.Handler, method string, path string, body string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestRequiresToken(t *testing.T) {
+	router := newTestRouter(NewEndpoint(memory_store.NewStore(), nil, nil))
+
+	for _, header := range []string{"", "Bearer wrong", testToken} {
+		req := httptest.NewRequest("GET", "/admin/storedrequests/openrtb2/requests", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, "Authorization %q should be rejected", header)
+	}
+
+	unconfigured := httprouter.New()
+	NewEndpoint(memory_store.NewStore(), nil, nil).Register(unconfigured, "/admin", "")
+	req := httptest.NewRequest("GET", "/admin/requests", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	recorder := httptest.NewRecorder()
+	unconfigured.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code, "An empty token should never match")
+}
+
+func TestSaveGetAndDelete(t *testing.T) {
+	router := newTestRouter(NewEndpoint(memory_store.NewStore(), rejectInvalid, rejectInvalid))
+
+	recorder := doRequest(router, "PUT", "/admin/storedrequests/openrtb2/requests/req", `{"valid":false}`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, "Invalid request: not valid\n", recorder.Body.String())
+
+	recorder = doRequest(router, "PUT", "/admin/storedrequests/openrtb2/requests/req", `{"valid":`)
+	assert.Equal(t, http.StatusBadRequest, recorder.Code, "Malformed JSON should be rejected")
+
+	recorder = doRequest(router, "PUT", "/admin/storedrequests/openrtb2/requests/req", `{"valid":true}`)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var version struct {
+		Version int `json:"version"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &version))
+	assert.Equal(t, 1, version.Version)
+
+	recorder = doRequest(router, "GET", "/admin/storedrequests/openrtb2/requests/req", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"valid":true}`, recorder.Body.String())
+	assert.Equal(t, `"1"`, recorder.Header().Get("ETag"))
+
+	recorder = doRequest(router, "GET", "/admin/storedrequests/openrtb2/requests", "")
+	assert.JSONEq(t, `["req"]`, recorder.Body.String())
+	recorder = doRequest(router, "GET", "/admin/storedrequests/openrtb2/imps", "")
+	assert.JSONEq(t, `[]`, recorder.Body.String(), "Requests and imps should be kept apart")
+
+	recorder = doRequest(router, "DELETE", "/admin/storedrequests/openrtb2/requests/req", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder = doRequest(router, "GET", "/admin/storedrequests/openrtb2/requests/req", "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	recorder = doRequest(router, "DELETE", "/admin/storedrequests/openrtb2/requests/req", "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestRequestsOnly(t *testing.T) {
+	router := newTestRouter(NewEndpoint(memory_store.NewStore(), rejectInvalid, nil))
+
+	recorder := doRequest(router, "PUT", "/admin/storedrequests/openrtb2/imps/imp", `{"valid":true}`)
+	assert.Equal(t, http.StatusNotFound, recorder.Code, "Imps shouldn't be served without an imp validator")
+}
+
+func TestVersionsAndRollback(t *testing.T) {
+	router := newTestRouter(NewEndpoint(memory_store.NewStore(), rejectInvalid, rejectInvalid))
+
+	doRequest(router, "PUT", "/admin/storedrequests/openrtb2/imps/imp", `{"valid":true,"v":1}`)
+	doRequest(router, "PUT", "/admin/storedrequests/openrtb2/imps/imp", `{"valid":true,"v":2}`)
+	doRequest(router, "DELETE", "/admin/storedrequests/openrtb2/imps/imp", "")
+
+	recorder := doRequest(router, "GET", "/admin/storedrequests/openrtb2/imps/imp/versions", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var versions []struct {
+		Version int             `json:"version"`
+		Data    json.RawMessage `json:"data"`
+		Deleted bool            `json:"deleted"`
+	}
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &versions))
+	if assert.Len(t, versions, 3) {
+		assert.JSONEq(t, `{"valid":true,"v":1}`, string(versions[0].Data))
+		assert.True(t, versions[2].Deleted)
+	}
+
+	recorder = doRequest(router, "POST", "/admin/storedrequests/openrtb2/imps/imp/rollback/3", "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code, "A deletion can't be rolled back to")
+	recorder = doRequest(router, "POST", "/admin/storedrequests/openrtb2/imps/imp/rollback/9", "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	recorder = doRequest(router, "POST", "/admin/storedrequests/openrtb2/imps/imp/rollback/one", "")
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	recorder = doRequest(router, "POST", "/admin/storedrequests/openrtb2/imps/unknown/rollback/1", "")
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = doRequest(router, "POST", "/admin/storedrequests/openrtb2/imps/imp/rollback/1", "")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	recorder = doRequest(router, "GET", "/admin/storedrequests/openrtb2/imps/imp", "")
+	assert.JSONEq(t, `{"valid":true,"v":1}`, recorder.Body.String())
+	assert.Equal(t, `"4"`, recorder.Header().Get("ETag"), "A rollback should be saved as a new version")
+}
+
+func TestEventsReachTheCache(t *testing.T) {
+	cache := memory.NewCache(&config.InMemoryCache{
+		RequestCacheSize: 256 * 1024,
+		ImpCacheSize:     256 * 1024,
+		TTL:              -1,
+	})
+	endpoint := NewEndpoint(memory_store.NewStore(), rejectInvalid, rejectInvalid)
+	router := newTestRouter(endpoint)
+
+	saved := make(chan struct{})
+	invalidated := make(chan struct{})
+	listener := events.NewEventListener(
+		func() { saved <- struct{}{} },
+		func() { invalidated <- struct{}{} },
+	)
+	go listener.Listen(cache, endpoint.EventProducer())
+	defer listener.Stop()
+
+	go doRequest(router, "PUT", "/admin/storedrequests/openrtb2/imps/imp", `{"valid":true}`)
+	<-saved
+	_, imps := cache.Get(context.Background(), nil, []string{"imp"})
+	assert.JSONEq(t, `{"valid":true}`, string(imps["imp"]))
+
+	go doRequest(router, "DELETE", "/admin/storedrequests/openrtb2/imps/imp", "")
+	<-invalidated
+	_, imps = cache.Get(context.Background(), nil, []string{"imp"})
+	assert.Empty(t, imps)
+}
+
+func TestNoEventsWithoutListener(t *testing.T) {
+	router := newTestRouter(NewEndpoint(memory_store.NewStore(), rejectInvalid, rejectInvalid))
+
+	recorder := doRequest(router, "PUT", "/admin/storedrequests/openrtb2/requests/req", `{"valid":true}`)
+	assert.Equal(t, http.StatusOK, recorder.Code, "Saving shouldn't block when no cache listens to the events")
+}
+
+func TestEventsDontHoldUpWrites(t *testing.T) {
+	endpoint := NewEndpoint(memory_store.NewStore(), rejectInvalid, rejectInvalid)
+	router := newTestRouter(endpoint)
+	producer := endpoint.EventProducer()
+
+	// Nobody reads the events yet, so they wait in the buffer.
+	for _, id := range []string{"a", "b", "c"} {
+		recorder := doRequest(router, "PUT", "/admin/storedrequests/openrtb2/requests/"+id, `{"valid":true}`)
+		assert.Equal(t, http.StatusOK, recorder.Code, "A write shouldn't wait for the listener")
+	}
+	recorder := doRequest(router, "DELETE", "/admin/storedrequests/openrtb2/requests/a", "")
+	assert.Equal(t, http.StatusOK, recorder.Code, "A delete shouldn't wait for the listener")
+
+	for _, id := range []string{"a", "b", "c"} {
+		save := <-producer.Saves()
+		assert.Contains(t, save.Requests, id, "The saves should arrive in the order they were made")
+	}
+	invalidation := <-producer.Invalidations()
+	assert.Equal(t, []string{"a"}, invalidation.Requests)
+}
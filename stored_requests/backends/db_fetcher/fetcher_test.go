@@ -173,6 +173,44 @@ func TestRowErrors(t *testing.T) {
 	assertMapLength(t, 0, data)
 }
 
+func TestResponses(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mockQuery := "SELECT id, data FROM resp_table WHERE id IN ($1, $2)"
+	mock.ExpectQuery(fmt.Sprintf("^%s$", regexp.QuoteMeta(mockQuery))).
+		WithArgs("resp-1", "resp-2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "data"}).AddRow("resp-1", `{"id":"resp-1"}`))
+	fetcher := &dbFetcher{
+		db:                 db,
+		queryMaker:         successfulQueryMaker(""),
+		responseQueryMaker: func(int) string { return mockQuery },
+	}
+
+	data, errs := fetcher.FetchResponses(context.Background(), []string{"resp-1", "resp-2"})
+
+	assertMockExpectations(t, mock)
+	assertErrorCount(t, 1, errs)
+	assertMapLength(t, 1, data)
+	assertHasData(t, data, "resp-1", `{"id":"resp-1"}`)
+}
+
+func TestResponsesUnconfigured(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	fetcher := NewFetcher(db, successfulQueryMaker(""), nil).(*dbFetcher)
+	data, errs := fetcher.FetchResponses(context.Background(), []string{"resp-1"})
+	assertErrorCount(t, 1, errs)
+	assertMapLength(t, 0, data)
+}
+
 func newFetcher(t *testing.T, rows *sqlmock.Rows, query string, args ...driver.Value) (sqlmock.Sqlmock, *dbFetcher) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -27,6 +27,17 @@ func (fetcher EmptyFetcher) FetchRequests(ctx context.Context, requestIDs []stri
 	return
 }
 
+func (fetcher EmptyFetcher) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	errs = make([]error, 0, len(ids))
+	for _, id := range ids {
+		errs = append(errs, stored_requests.NotFoundError{
+			ID:       id,
+			DataType: "Response",
+		})
+	}
+	return
+}
+
 func (fetcher EmptyFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
 	return "", nil
 }
@@ -4,9 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"strings"
 
+	"github.com/PubMatic-OpenWrap/prebid-server/static"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
 )
 
@@ -15,8 +15,15 @@ import (
 //
 // This expects each file in the directory to be named "{config_id}.json".
 // For example, when asked to fetch the request with ID == "23", it will return the data from "directory/23.json".
+// Stored Requests, Imps and Responses live in the "stored_requests", "stored_imps" and "stored_responses" subdirectories.
 func NewFileFetcher(directory string) (stored_requests.AllFetcher, error) {
-	storedData, err := collectStoredData(directory, FileSystem{make(map[string]FileSystem), make(map[string]json.RawMessage)}, nil)
+	return NewFileFetcherFS(static.Dir(directory))
+}
+
+// NewFileFetcherFS works like NewFileFetcher, but loads the files from the root of files.
+// Use static.Embedded(static.CategoryMappingDir) for the category mappings which were compiled into the binary.
+func NewFileFetcherFS(files static.FS) (stored_requests.AllFetcher, error) {
+	storedData, err := collectStoredData(files, ".", FileSystem{make(map[string]FileSystem), make(map[string]json.RawMessage)}, nil)
 	return &eagerFetcher{storedData, nil}, err
 }
 
@@ -33,6 +40,11 @@ func (fetcher *eagerFetcher) FetchRequests(ctx context.Context, requestIDs []str
 	return storedRequests, storedImpressions, errs
 }
 
+func (fetcher *eagerFetcher) FetchResponses(ctx context.Context, ids []string) (map[string]json.RawMessage, []error) {
+	storedResponses := fetcher.FileSystem.Directories["stored_responses"].Files
+	return storedResponses, appendErrors("Response", ids, storedResponses, nil)
+}
+
 func (fetcher *eagerFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
 	fileName := primaryAdServer
 
@@ -81,11 +93,11 @@ type FileSystem struct {
 	Files       map[string]json.RawMessage
 }
 
-func collectStoredData(directory string, fileSystem FileSystem, err error) (FileSystem, error) {
+func collectStoredData(files static.FS, directory string, fileSystem FileSystem, err error) (FileSystem, error) {
 	if err != nil {
 		return FileSystem{nil, nil}, err
 	}
-	fileInfos, err := ioutil.ReadDir(directory)
+	fileInfos, err := files.ReadDir(directory)
 	if err != nil {
 		return FileSystem{nil, nil}, err
 	}
@@ -95,7 +107,7 @@ func collectStoredData(directory string, fileSystem FileSystem, err error) (File
 		if fileInfo.IsDir() {
 
 			fs := FileSystem{make(map[string]FileSystem), make(map[string]json.RawMessage)}
-			fileSys, innerErr := collectStoredData(directory+"/"+fileInfo.Name(), fs, err)
+			fileSys, innerErr := collectStoredData(files, directory+"/"+fileInfo.Name(), fs, err)
 			if innerErr != nil {
 				return FileSystem{nil, nil}, innerErr
 			}
@@ -103,7 +115,7 @@ func collectStoredData(directory string, fileSystem FileSystem, err error) (File
 
 		} else {
 			if strings.HasSuffix(fileInfo.Name(), ".json") { // Skip the .gitignore
-				fileData, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", directory, fileInfo.Name()))
+				fileData, err := files.ReadFile(fmt.Sprintf("%s/%s", directory, fileInfo.Name()))
 				if err != nil {
 					return FileSystem{nil, nil}, err
 				}
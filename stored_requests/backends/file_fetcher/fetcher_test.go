@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/PubMatic-OpenWrap/prebid-server/static"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
 	"github.com/stretchr/testify/assert"
 )
@@ -24,6 +25,23 @@ func TestFileFetcher(t *testing.T) {
 	validateImp(t, storedImps)
 }
 
+func TestFileFetcherResponses(t *testing.T) {
+	fetcher, err := NewFileFetcher("./test")
+	if err != nil {
+		t.Errorf("Failed to create a Fetcher: %v", err)
+	}
+
+	storedResponses, errs := fetcher.(stored_requests.ResponseFetcher).FetchResponses(context.Background(), []string{"some-response", "missing"})
+	assert.Equal(t, []error{stored_requests.NotFoundError{ID: "missing", DataType: "Response"}}, errs)
+	if assert.Contains(t, storedResponses, "some-response") {
+		var seatBids []struct {
+			Seat string `json:"seat"`
+		}
+		assert.NoError(t, json.Unmarshal(storedResponses["some-response"], &seatBids))
+		assert.Equal(t, "appnexus", seatBids[0].Seat)
+	}
+}
+
 func TestInvalidDirectory(t *testing.T) {
 	_, err := NewFileFetcher("./nonexistant-directory")
 	if err == nil {
@@ -158,3 +176,13 @@ func TestCategoriesFetcherNoCategoriesFile(t *testing.T) {
 	assert.Equal(t, fmt.Errorf("Unable to find mapping file for adserver: 'test', publisherId: 'not_exists'"),
 		fetchingErr, "Categories were loaded incorrectly")
 }
+
+func TestEmbeddedCategoriesFetcher(t *testing.T) {
+	fetcher, err := NewFileFetcherFS(static.Embedded(static.CategoryMappingDir))
+	if err != nil {
+		t.Fatalf("Failed to create a category Fetcher from the embedded files: %v", err)
+	}
+	category, err := fetcher.(stored_requests.CategoryFetcher).FetchCategories(nil, "freewheel", "", "IAB1-1")
+	assert.Equal(t, nil, err, "Categories were loaded incorrectly")
+	assert.NotEmpty(t, category, "Categories were loaded incorrectly")
+}
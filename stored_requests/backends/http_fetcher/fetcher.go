@@ -35,6 +35,19 @@ import (
 //   }
 // }
 //
+// Stored Responses are fetched separately, through:
+//
+// GET {endpoint}?response-ids=["resp1","resp2"]
+//
+// This endpoint should return a payload like:
+//
+// {
+//   "responses": {
+//     "resp1": { ... stored data for resp1 ... },
+//     "resp2": null // If resp2 is not found
+//   }
+// }
+//
 //
 func NewFetcher(client *http.Client, endpoint string) *HttpFetcher {
 	// Do some work up-front to figure out if the (configurable) endpoint has a query string or not.
@@ -81,6 +94,47 @@ func (fetcher *HttpFetcher) FetchRequests(ctx context.Context, requestIDs []stri
 	return
 }
 
+func (fetcher *HttpFetcher) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	httpReq, err := http.NewRequest("GET", fetcher.Endpoint+"response-ids=[\""+strings.Join(ids, "\",\"")+"\"]", nil)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	httpResp, err := ctxhttp.Do(ctx, fetcher.client, httpReq)
+	if err != nil {
+		return nil, []error{err}
+	}
+	defer httpResp.Body.Close()
+
+	respBytes, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, []error{err}
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, []error{fmt.Errorf("Error fetching Stored Responses via HTTP. Response code was %d", httpResp.StatusCode)}
+	}
+
+	var responseObj responseContract
+	if err := json.Unmarshal(respBytes, &responseObj); err != nil {
+		return nil, []error{err}
+	}
+	data = responseObj.Responses
+	for _, id := range ids {
+		if value, ok := data[id]; !ok || bytes.Equal(value, []byte("null")) {
+			delete(data, id)
+			errs = append(errs, stored_requests.NotFoundError{
+				ID:       id,
+				DataType: "Response",
+			})
+		}
+	}
+	return
+}
+
 func (fetcher *HttpFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
 	if fetcher.Categories == nil {
 		fetcher.Categories = make(map[string]map[string]stored_requests.Category)
@@ -183,6 +237,7 @@ func convertNullsToErrs(m map[string]json.RawMessage, dataType string, errs []er
 
 // responseContract is used to unmarshal  for the endpoint
 type responseContract struct {
-	Requests map[string]json.RawMessage `json:"requests"`
-	Imps     map[string]json.RawMessage `json:"imps"`
+	Requests  map[string]json.RawMessage `json:"requests"`
+	Imps      map[string]json.RawMessage `json:"imps"`
+	Responses map[string]json.RawMessage `json:"responses"`
 }
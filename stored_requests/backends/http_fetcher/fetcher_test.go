@@ -80,6 +80,29 @@ func TestErrResponse(t *testing.T) {
 	assertErrLength(t, errs, 1)
 }
 
+func TestResponses(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assertMatches(t, r.URL.Query().Get("response-ids"), []string{"resp-1", "resp-2"})
+		w.Write([]byte(`{"responses":{"resp-1":{"id":"resp-1"},"resp-2":null}}`))
+	}
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+	fetcher := NewFetcher(server.Client(), server.URL)
+
+	data, errs := fetcher.FetchResponses(context.Background(), []string{"resp-1", "resp-2"})
+	assertMapKeys(t, data, "resp-1")
+	assertSameErrMsgs(t, []string{`Stored Response with ID="resp-2" not found.`}, errs)
+}
+
+func TestResponsesErrResponse(t *testing.T) {
+	fetcher, close := newFetcherBrokenBackend()
+	defer close()
+
+	data, errs := fetcher.FetchResponses(context.Background(), []string{"resp-1"})
+	assertMapKeys(t, data)
+	assertErrLength(t, errs, 1)
+}
+
 func assertSameContents(t *testing.T, expected map[string]json.RawMessage, actual map[string]json.RawMessage) {
 	if len(expected) != len(actual) {
 		t.Errorf("Wrong counts. Expected %d, actual %d", len(expected), len(actual))
@@ -0,0 +1,123 @@
+package memory_store
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
+)
+
+// NewStore returns a Store which keeps all its data and history in memory.
+//
+// Everything is lost when the process exits, so this is mostly useful for development,
+// or as the reference for Stores backed by a database.
+func NewStore() stored_requests.Store {
+	return &memoryStore{
+		histories: map[stored_requests.DataType]map[string][]stored_requests.Version{
+			stored_requests.RequestDataType: make(map[string][]stored_requests.Version),
+			stored_requests.ImpDataType:     make(map[string][]stored_requests.Version),
+		},
+		now: time.Now,
+	}
+}
+
+type memoryStore struct {
+	mutex     sync.RWMutex
+	histories map[stored_requests.DataType]map[string][]stored_requests.Version
+	now       func() time.Time
+}
+
+func (store *memoryStore) FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (requestData map[string]json.RawMessage, impData map[string]json.RawMessage, errs []error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	requestData, errs = store.fetch(stored_requests.RequestDataType, requestIDs, errs)
+	impData, errs = store.fetch(stored_requests.ImpDataType, impIDs, errs)
+	return
+}
+
+func (store *memoryStore) fetch(dataType stored_requests.DataType, ids []string, errs []error) (map[string]json.RawMessage, []error) {
+	data := make(map[string]json.RawMessage, len(ids))
+	for _, id := range ids {
+		if latest, ok := store.latest(dataType, id); ok {
+			data[id] = latest.Data
+		} else {
+			errs = append(errs, stored_requests.NotFoundError{
+				ID:       id,
+				DataType: string(dataType),
+			})
+		}
+	}
+	return data, errs
+}
+
+func (store *memoryStore) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
+	return "", nil
+}
+
+func (store *memoryStore) Save(ctx context.Context, dataType stored_requests.DataType, id string, data json.RawMessage) (stored_requests.Version, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return store.add(dataType, id, stored_requests.Version{Data: data}), nil
+}
+
+func (store *memoryStore) Delete(ctx context.Context, dataType stored_requests.DataType, id string) (stored_requests.Version, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if _, ok := store.latest(dataType, id); !ok {
+		return stored_requests.Version{}, stored_requests.NotFoundError{ID: id, DataType: string(dataType)}
+	}
+	return store.add(dataType, id, stored_requests.Version{Deleted: true}), nil
+}
+
+func (store *memoryStore) IDs(ctx context.Context, dataType stored_requests.DataType) ([]string, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	ids := make([]string, 0, len(store.histories[dataType]))
+	for id := range store.histories[dataType] {
+		if _, ok := store.latest(dataType, id); ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (store *memoryStore) Versions(ctx context.Context, dataType stored_requests.DataType, id string) ([]stored_requests.Version, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	history := store.histories[dataType][id]
+	if len(history) == 0 {
+		return nil, stored_requests.NotFoundError{ID: id, DataType: string(dataType)}
+	}
+	return append([]stored_requests.Version(nil), history...), nil
+}
+
+// latest returns the newest version of the ID, unless it has been deleted. The caller must hold the lock.
+func (store *memoryStore) latest(dataType stored_requests.DataType, id string) (stored_requests.Version, bool) {
+	history := store.histories[dataType][id]
+	if len(history) == 0 || history[len(history)-1].Deleted {
+		return stored_requests.Version{}, false
+	}
+	return history[len(history)-1], true
+}
+
+// add appends the version to the history of the ID. The caller must hold the write lock.
+func (store *memoryStore) add(dataType stored_requests.DataType, id string, version stored_requests.Version) stored_requests.Version {
+	histories, ok := store.histories[dataType]
+	if !ok {
+		histories = make(map[string][]stored_requests.Version)
+		store.histories[dataType] = histories
+	}
+	version.Version = len(histories[id]) + 1
+	version.Created = store.now()
+	histories[id] = append(histories[id], version)
+	return version
+}
@@ -0,0 +1,80 @@
+package memory_store
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveAndFetch(t *testing.T) {
+	store := NewStore()
+	ctx := context.Background()
+
+	version, err := store.Save(ctx, stored_requests.RequestDataType, "req", json.RawMessage(`{"id":"1"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, version.Version)
+	version, err = store.Save(ctx, stored_requests.RequestDataType, "req", json.RawMessage(`{"id":"2"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version.Version)
+	_, err = store.Save(ctx, stored_requests.ImpDataType, "imp", json.RawMessage(`{"id":"imp"}`))
+	assert.NoError(t, err)
+
+	requests, imps, errs := store.FetchRequests(ctx, []string{"req", "missing"}, []string{"imp"})
+	assert.JSONEq(t, `{"id":"2"}`, string(requests["req"]))
+	assert.JSONEq(t, `{"id":"imp"}`, string(imps["imp"]))
+	assert.Equal(t, []error{stored_requests.NotFoundError{ID: "missing", DataType: "Request"}}, errs)
+}
+
+func TestDelete(t *testing.T) {
+	store := NewStore()
+	ctx := context.Background()
+
+	_, err := store.Delete(ctx, stored_requests.ImpDataType, "imp")
+	assert.Equal(t, stored_requests.NotFoundError{ID: "imp", DataType: "Imp"}, err)
+
+	store.Save(ctx, stored_requests.ImpDataType, "imp", json.RawMessage(`{}`))
+	store.Save(ctx, stored_requests.ImpDataType, "other", json.RawMessage(`{}`))
+	version, err := store.Delete(ctx, stored_requests.ImpDataType, "imp")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version.Version)
+	assert.True(t, version.Deleted)
+
+	_, imps, errs := store.FetchRequests(ctx, nil, []string{"imp"})
+	assert.Empty(t, imps)
+	assert.Len(t, errs, 1)
+
+	ids, err := store.IDs(ctx, stored_requests.ImpDataType)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"other"}, ids)
+
+	_, err = store.Delete(ctx, stored_requests.ImpDataType, "imp")
+	assert.Error(t, err, "A deleted imp can't be deleted again")
+}
+
+func TestVersions(t *testing.T) {
+	store := NewStore()
+	ctx := context.Background()
+
+	_, err := store.Versions(ctx, stored_requests.RequestDataType, "req")
+	assert.Equal(t, stored_requests.NotFoundError{ID: "req", DataType: "Request"}, err)
+
+	store.Save(ctx, stored_requests.RequestDataType, "req", json.RawMessage(`{"id":"1"}`))
+	store.Delete(ctx, stored_requests.RequestDataType, "req")
+	store.Save(ctx, stored_requests.RequestDataType, "req", json.RawMessage(`{"id":"3"}`))
+
+	versions, err := store.Versions(ctx, stored_requests.RequestDataType, "req")
+	assert.NoError(t, err)
+	if assert.Len(t, versions, 3) {
+		assert.Equal(t, 1, versions[0].Version)
+		assert.JSONEq(t, `{"id":"1"}`, string(versions[0].Data))
+		assert.True(t, versions[1].Deleted)
+		assert.Equal(t, 3, versions[2].Version)
+		assert.False(t, versions[2].Created.IsZero())
+	}
+
+	ids, _ := store.IDs(ctx, stored_requests.RequestDataType)
+	assert.Equal(t, []string{"req"}, ids)
+}
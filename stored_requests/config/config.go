@@ -10,8 +10,11 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/julienschmidt/httprouter"
+	"github.com/PubMatic-OpenWrap/prebid-server/cache/datacache"
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/static"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/admin"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/db_fetcher"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/empty_fetcher"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/file_fetcher"
@@ -31,17 +34,28 @@ type dbConnection struct {
 	db   *sql.DB
 }
 
+// AdminEndpoints are the stored_requests/admin Endpoints which manage the data of each auction endpoint.
+// Their Stores get checked before the backends from the config, and their events reach the caches.
+// Nil Endpoints are skipped.
+type AdminEndpoints struct {
+	Auction *admin.Endpoint
+	AMP     *admin.Endpoint
+	Video   *admin.Endpoint
+}
+
 // CreateStoredRequests returns three things:
 //
 // 1. A Fetcher which can be used to get Stored Requests
 // 2. A function which should be called on shutdown for graceful cleanups.
 //
+// If managed isn't nil, its Store is checked before the backends from the config, and its events keep the cache up to date.
+//
 // If any errors occur, the program will exit with an error message.
 // It probably means you have a bad config or networking issue.
 //
 // As a side-effect, it will add some endpoints to the router if the config calls for it.
 // In the future we should look for ways to simplify this so that it's not doing two things.
-func CreateStoredRequests(cfg *config.StoredRequestsSlim, metricsEngine pbsmetrics.MetricsEngine, client *http.Client, router *httprouter.Router, dbc *dbConnection) (fetcher stored_requests.AllFetcher, shutdown func()) {
+func CreateStoredRequests(cfg *config.StoredRequestsSlim, metricsEngine pbsmetrics.MetricsEngine, client *http.Client, router *httprouter.Router, dbc *dbConnection, managed *admin.Endpoint) (fetcher stored_requests.AllFetcher, shutdown func()) {
 	// Create database connection if given options for one
 	if cfg.Postgres.ConnectionInfo.Database != "" {
 		conn := cfg.Postgres.ConnectionInfo.ConnString()
@@ -64,13 +78,20 @@ func CreateStoredRequests(cfg *config.StoredRequestsSlim, metricsEngine pbsmetri
 	}
 
 	eventProducers := newEventProducers(cfg, client, dbc.db, router)
-	fetcher = newFetcher(cfg, client, dbc.db)
+	fetchers := newFetchers(cfg, client, dbc.db)
+	if managed != nil {
+		fetchers = append([]stored_requests.AllFetcher{managed.Store()}, fetchers...)
+	}
+	fetcher = consolidate(fetchers)
 
 	var shutdown1 func()
 
 	if cfg.InMemoryCache.Type != "" {
 		cache := newCache(cfg)
 		fetcher = stored_requests.WithCache(fetcher, cache, metricsEngine)
+		if managed != nil {
+			eventProducers = append(eventProducers, managed.EventProducer())
+		}
 		shutdown1 = addListeners(cache, eventProducers)
 	}
 
@@ -91,7 +112,7 @@ func CreateStoredRequests(cfg *config.StoredRequestsSlim, metricsEngine pbsmetri
 	return
 }
 
-// NewStoredRequests returns five things:
+// NewStoredRequests returns seven things:
 //
 // 1. A DB connection, if one was created. This may be nil.
 // 2. A function which should be called on shutdown for graceful cleanups.
@@ -99,13 +120,15 @@ func CreateStoredRequests(cfg *config.StoredRequestsSlim, metricsEngine pbsmetri
 // 4. A Fetcher which can be used to get Stored Requests for /openrtb2/amp
 // 5. A Fetcher which can be used to get Category Mapping data
 // 6. A Fetcher which can be used to get Stored Requests for /openrtb2/video
+// 7. A Fetcher which can be used to get the accounts and ad unit configs of the legacy /auction endpoint.
+//    This is nil unless the datacache type reads them with the Stored Request backends.
 //
 // If any errors occur, the program will exit with an error message.
 // It probably means you have a bad config or networking issue.
 //
 // As a side-effect, it will add some endpoints to the router if the config calls for it.
 // In the future we should look for ways to simplify this so that it's not doing two things.
-func NewStoredRequests(cfg *config.Configuration, metricsEngine pbsmetrics.MetricsEngine, client *http.Client, router *httprouter.Router) (db *sql.DB, shutdown func(), fetcher stored_requests.Fetcher, ampFetcher stored_requests.Fetcher, categoriesFetcher stored_requests.CategoryFetcher, videoFetcher stored_requests.Fetcher) {
+func NewStoredRequests(cfg *config.Configuration, metricsEngine pbsmetrics.MetricsEngine, client *http.Client, router *httprouter.Router, admins AdminEndpoints) (db *sql.DB, shutdown func(), fetcher stored_requests.Fetcher, ampFetcher stored_requests.Fetcher, categoriesFetcher stored_requests.CategoryFetcher, videoFetcher stored_requests.Fetcher, dataCacheFetcher stored_requests.Fetcher) {
 	// Build individual slim options from combined config struct
 	slimAuction, slimAmp := resolvedStoredRequestsConfig(cfg)
 
@@ -116,10 +139,11 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine pbsmetrics.Metri
 
 	var dbc dbConnection
 
-	fetcher1, shutdown1 := CreateStoredRequests(&slimAuction, metricsEngine, client, router, &dbc)
-	fetcher2, shutdown2 := CreateStoredRequests(&slimAmp, metricsEngine, client, router, &dbc)
-	fetcher3, shutdown3 := CreateStoredRequests(&cfg.CategoryMapping, metricsEngine, client, router, &dbc)
-	fetcher4, shutdown4 := CreateStoredRequests(&cfg.StoredVideo, metricsEngine, client, router, &dbc)
+	fetcher1, shutdown1 := CreateStoredRequests(&slimAuction, metricsEngine, client, router, &dbc, admins.Auction)
+	fetcher2, shutdown2 := CreateStoredRequests(&slimAmp, metricsEngine, client, router, &dbc, admins.AMP)
+	fetcher3, shutdown3 := createCategoryMapping(cfg.CategoryMapping, metricsEngine, client, router, &dbc)
+	fetcher4, shutdown4 := CreateStoredRequests(&cfg.StoredVideo, metricsEngine, client, router, &dbc, admins.Video)
+	fetcher5, shutdown5 := createDataCache(cfg, metricsEngine, client, router, &dbc)
 
 	db = dbc.db
 
@@ -127,12 +151,16 @@ func NewStoredRequests(cfg *config.Configuration, metricsEngine pbsmetrics.Metri
 	ampFetcher = fetcher2.(stored_requests.Fetcher)
 	categoriesFetcher = fetcher3.(stored_requests.CategoryFetcher)
 	videoFetcher = fetcher4.(stored_requests.Fetcher)
+	if fetcher5 != nil {
+		dataCacheFetcher = fetcher5.(stored_requests.Fetcher)
+	}
 
 	shutdown = func() {
 		shutdown1()
 		shutdown2()
 		shutdown3()
 		shutdown4()
+		shutdown5()
 	}
 
 	return
@@ -146,6 +174,7 @@ func resolvedStoredRequestsConfig(cfg *config.Configuration) (auc, amp config.St
 	auc.Files.Path = sr.Path
 	auc.Postgres.ConnectionInfo = sr.Postgres.ConnectionInfo
 	auc.Postgres.FetcherQueries.QueryTemplate = sr.Postgres.FetcherQueries.QueryTemplate
+	auc.Postgres.FetcherQueries.ResponsesQueryTemplate = sr.Postgres.FetcherQueries.ResponsesQueryTemplate
 	auc.Postgres.CacheInitialization.Timeout = sr.Postgres.CacheInitialization.Timeout
 	auc.Postgres.CacheInitialization.Query = sr.Postgres.CacheInitialization.Query
 	auc.Postgres.PollUpdates.RefreshRate = sr.Postgres.PollUpdates.RefreshRate
@@ -164,6 +193,7 @@ func resolvedStoredRequestsConfig(cfg *config.Configuration) (auc, amp config.St
 	amp.Files.Path = sr.Path
 	amp.Postgres.ConnectionInfo = sr.Postgres.ConnectionInfo
 	amp.Postgres.FetcherQueries.QueryTemplate = sr.Postgres.FetcherQueries.AmpQueryTemplate
+	amp.Postgres.FetcherQueries.ResponsesQueryTemplate = sr.Postgres.FetcherQueries.ResponsesQueryTemplate
 	amp.Postgres.CacheInitialization.Timeout = sr.Postgres.CacheInitialization.Timeout
 	amp.Postgres.CacheInitialization.Query = sr.Postgres.CacheInitialization.AmpQuery
 	amp.Postgres.PollUpdates.RefreshRate = sr.Postgres.PollUpdates.RefreshRate
@@ -196,8 +226,65 @@ func addListeners(cache stored_requests.Cache, eventProducers []events.EventProd
 	}
 }
 
+// createDataCache works like CreateStoredRequests for the backends of the legacy /auction endpoint's accounts and
+// ad unit configs. The deprecated "postgres" datacache reads the old tables through the stored_requests.postgres
+// connection. The other types don't need a Fetcher, so they get a nil one.
+func createDataCache(cfg *config.Configuration, metricsEngine pbsmetrics.MetricsEngine, client *http.Client, router *httprouter.Router, dbc *dbConnection) (fetcher stored_requests.AllFetcher, shutdown func()) {
+	switch cfg.DataCache.Type {
+	case "stored":
+		return CreateStoredRequests(&cfg.DataCache.Stored, metricsEngine, client, router, dbc, nil)
+	case "postgres":
+		if cfg.StoredRequests.Postgres.ConnectionInfo.Database == "" {
+			break
+		}
+		glog.Warning("datacache.type=postgres is deprecated. Move the accounts and configs to the backends of datacache.type=stored.")
+		var legacy config.StoredRequestsSlim
+		legacy.Postgres.ConnectionInfo = cfg.StoredRequests.Postgres.ConnectionInfo
+		legacy.Postgres.FetcherQueries.QueryTemplate = datacache.LegacyPostgresQuery
+		// The old cache had at least freecache's minimum size.
+		size := cfg.DataCache.CacheSize
+		if size < 512*1024 {
+			size = 512 * 1024
+		}
+		legacy.InMemoryCache = config.InMemoryCache{
+			Type:             "lru",
+			TTL:              cfg.DataCache.TTLSeconds,
+			RequestCacheSize: size,
+			ImpCacheSize:     size,
+		}
+		return CreateStoredRequests(&legacy, metricsEngine, client, router, dbc, nil)
+	}
+	return nil, func() {}
+}
+
+// createCategoryMapping works like CreateStoredRequests. If the filesystem is enabled without a directory,
+// the category mapping files which were embedded into the binary are used instead.
+func createCategoryMapping(cfg config.StoredRequestsSlim, metricsEngine pbsmetrics.MetricsEngine, client *http.Client, router *httprouter.Router, dbc *dbConnection) (fetcher stored_requests.AllFetcher, shutdown func()) {
+	if !cfg.Files.Enabled || cfg.Files.Path != "" {
+		return CreateStoredRequests(&cfg, metricsEngine, client, router, dbc, nil)
+	}
+
+	cfg.Files.Enabled = false
+	fetcher, shutdown = CreateStoredRequests(&cfg, metricsEngine, client, router, dbc, nil)
+
+	glog.Info("Loading Category Mapping from the files embedded into the binary")
+	embedded, err := file_fetcher.NewFileFetcherFS(static.Embedded(static.CategoryMappingDir))
+	if err != nil {
+		glog.Fatalf("Failed to create a FileFetcher: %v", err)
+	}
+	if _, isEmpty := fetcher.(empty_fetcher.EmptyFetcher); isEmpty {
+		return embedded, shutdown
+	}
+	return stored_requests.MultiFetcher{embedded, fetcher}, shutdown
+}
+
 func newFetcher(cfg *config.StoredRequestsSlim, client *http.Client, db *sql.DB) (fetcher stored_requests.AllFetcher) {
-	idList := make(stored_requests.MultiFetcher, 0, 3)
+	return consolidate(newFetchers(cfg, client, db))
+}
+
+// newFetchers returns the backends which the config asks for, in the order in which they should be checked.
+func newFetchers(cfg *config.StoredRequestsSlim, client *http.Client, db *sql.DB) []stored_requests.AllFetcher {
+	idList := make([]stored_requests.AllFetcher, 0, 3)
 
 	if cfg.Files.Enabled {
 		fFetcher := newFilesystem(cfg.Files.Path)
@@ -205,15 +292,22 @@ func newFetcher(cfg *config.StoredRequestsSlim, client *http.Client, db *sql.DB)
 	}
 	if cfg.Postgres.FetcherQueries.QueryTemplate != "" {
 		glog.Infof("Loading Stored Requests via Postgres.\nQuery: %s", cfg.Postgres.FetcherQueries.QueryTemplate)
-		idList = append(idList, db_fetcher.NewFetcher(db, cfg.Postgres.FetcherQueries.MakeQuery))
+		idList = append(idList, db_fetcher.NewFetcher(db, cfg.Postgres.FetcherQueries.MakeQuery, responsesQueryMaker(cfg.Postgres.FetcherQueries)))
 	}
 	if cfg.HTTP.Endpoint != "" {
 		glog.Infof("Loading Stored Requests via HTTP. endpoint=%s", cfg.HTTP.Endpoint)
 		idList = append(idList, http_fetcher.NewFetcher(client, cfg.HTTP.Endpoint))
 	}
+	return idList
+}
 
-	fetcher = consolidate(idList)
-	return
+// responsesQueryMaker returns nil if Stored Responses shouldn't be loaded from Postgres.
+func responsesQueryMaker(cfg config.PostgresFetcherQueriesSlim) func(int) string {
+	if cfg.ResponsesQueryTemplate == "" {
+		return nil
+	}
+	glog.Infof("Loading Stored Responses via Postgres.\nQuery: %s", cfg.ResponsesQueryTemplate)
+	return cfg.MakeResponsesQuery
 }
 
 func newCache(cfg *config.StoredRequestsSlim) stored_requests.Cache {
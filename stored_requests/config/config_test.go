@@ -7,15 +7,21 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
-	"github.com/julienschmidt/httprouter"
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	metricsConf "github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/admin"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/empty_fetcher"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/http_fetcher"
+	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/backends/memory_store"
 	"github.com/PubMatic-OpenWrap/prebid-server/stored_requests/events"
 	httpEvents "github.com/PubMatic-OpenWrap/prebid-server/stored_requests/events/http"
+	"github.com/julienschmidt/httprouter"
 )
 
 func TestNewEmptyFetcher(t *testing.T) {
@@ -32,6 +38,93 @@ func TestNewEmptyFetcher(t *testing.T) {
 	}
 }
 
+func TestEmbeddedCategoryMapping(t *testing.T) {
+	var dbc dbConnection
+	cfg := config.StoredRequestsSlim{Files: config.FileFetcherConfig{Enabled: true}}
+	fetcher, shutdown := createCategoryMapping(cfg, &metricsConf.DummyMetricsEngine{}, nil, httprouter.New(), &dbc)
+	defer shutdown()
+
+	category, err := fetcher.(stored_requests.CategoryFetcher).FetchCategories(context.Background(), "freewheel", "", "IAB1-1")
+	if err != nil {
+		t.Errorf("The embedded category mapping should be used when no directory is configured. %v", err)
+	}
+	if category == "" {
+		t.Errorf("The embedded category mapping should map IAB1-1 for freewheel")
+	}
+}
+
+func TestDataCacheFetcher(t *testing.T) {
+	var dbc dbConnection
+	cfg := &config.Configuration{DataCache: config.DataCache{
+		Type: "stored",
+		Stored: config.StoredRequestsSlim{
+			Files:         config.FileFetcherConfig{Enabled: true, Path: "../backends/file_fetcher/test"},
+			CacheEvents:   config.CacheEventsConfig{Enabled: true, Endpoint: "/storedrequests/datacache"},
+			InMemoryCache: config.InMemoryCache{Type: "unbounded"},
+		},
+	}}
+	router := httprouter.New()
+	fetcher, shutdown := createDataCache(cfg, &metricsConf.DummyMetricsEngine{}, nil, router, &dbc)
+	defer shutdown()
+
+	accounts, configs, errs := fetcher.FetchRequests(context.Background(), []string{"1"}, []string{"some-imp"})
+	if len(errs) != 0 || accounts["1"] == nil || configs["some-imp"] == nil {
+		t.Errorf("The datacache should read the accounts and configs from the Stored Request backends. Got errors: %v", errs)
+	}
+	if handle, _, _ := router.Lookup("POST", "/storedrequests/datacache"); handle == nil {
+		t.Errorf("The datacache should get its own events API")
+	}
+
+	fetcher, shutdown = createDataCache(&config.Configuration{DataCache: config.DataCache{Type: "postgres"}}, &metricsConf.DummyMetricsEngine{}, nil, router, &dbc)
+	defer shutdown()
+	if fetcher != nil {
+		t.Errorf("The postgres datacache shouldn't have a fetcher without a stored_requests.postgres connection")
+	}
+}
+
+func TestAdminEndpoint(t *testing.T) {
+	var dbc dbConnection
+	cfg := config.StoredRequestsSlim{
+		InMemoryCache: config.InMemoryCache{
+			Type:             "lru",
+			RequestCacheSize: 256 * 1024,
+			ImpCacheSize:     256 * 1024,
+			TTL:              -1,
+		},
+	}
+	managed := admin.NewEndpoint(memory_store.NewStore(), nil, nil)
+	router := httprouter.New()
+	managed.Register(router, "/admin", "secret")
+	fetcher, shutdown := CreateStoredRequests(&cfg, &metricsConf.DummyMetricsEngine{}, nil, router, &dbc, managed)
+	defer shutdown()
+
+	save := func(data string) {
+		req := httptest.NewRequest("PUT", "/admin/requests/1", strings.NewReader(data))
+		req.Header.Set("Authorization", "Bearer secret")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Failed to save the stored request: %s", recorder.Body.String())
+		}
+	}
+
+	save(`{"version":1}`)
+	requests, _, errs := fetcher.FetchRequests(context.Background(), []string{"1"}, nil)
+	if len(errs) > 0 || string(requests["1"]) != `{"version":1}` {
+		t.Fatalf("The fetcher should serve the data from the admin store. Got %s, %v", requests["1"], errs)
+	}
+
+	// The first fetch put the data in the cache, so the new version can only be seen if the event reached it.
+	save(`{"version":2}`)
+	for i := 0; i < 100; i++ {
+		if requests, _, _ = fetcher.FetchRequests(context.Background(), []string{"1"}, nil); string(requests["1"]) == `{"version":2}` {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("The cache should be updated when the admin API saves data. Got %s", requests["1"])
+}
+
 func TestNewHTTPFetcher(t *testing.T) {
 	fetcher := newFetcher(&config.StoredRequestsSlim{
 		HTTP: config.HTTPFetcherConfigSlim{
@@ -30,6 +30,15 @@ type CategoryFetcher interface {
 	FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error)
 }
 
+// ResponseFetcher knows how to fetch Stored Responses by id. These are canned bidder responses which
+// let an auction run without live demand.
+//
+// Fetchers which support Stored Responses implement this alongside Fetcher. Like FetchRequests, the returned
+// map will have a key for every ID unless errors exist, and it may not be written to.
+type ResponseFetcher interface {
+	FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error)
+}
+
 // AllFetcher is an iterface that encapsulates both the original Fetcher and the CategoryFetcher
 type AllFetcher interface {
 	FetchRequests(ctx context.Context, requestIDs []string, impIDs []string) (requestData map[string]json.RawMessage, impData map[string]json.RawMessage, errs []error)
@@ -181,6 +190,14 @@ func (f *fetcherWithCache) FetchRequests(ctx context.Context, requestIDs []strin
 	return
 }
 
+// FetchResponses skips the cache, since Stored Responses are only used for testing.
+func (f *fetcherWithCache) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	if rf, ok := f.fetcher.(ResponseFetcher); ok {
+		return rf.FetchResponses(ctx, ids)
+	}
+	return nil, appendNotFoundErrors("Response", ids, nil, nil)
+}
+
 func (f *fetcherWithCache) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
 	return "", nil
 }
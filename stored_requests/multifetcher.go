@@ -36,6 +36,28 @@ func (mf MultiFetcher) FetchRequests(ctx context.Context, requestIDs []string, i
 	return
 }
 
+// FetchResponses polls the sub-Fetchers which support Stored Responses, in order.
+func (mf MultiFetcher) FetchResponses(ctx context.Context, ids []string) (data map[string]json.RawMessage, errs []error) {
+	data = make(map[string]json.RawMessage, len(ids))
+
+	for _, f := range mf {
+		rf, ok := f.(ResponseFetcher)
+		if !ok {
+			continue
+		}
+		ids = filter(ids, data)
+		theseData, rerrs := rf.FetchResponses(ctx, ids)
+		// Drop NotFound errors, as other fetchers may have them. Also don't want multiple NotFound errors per ID.
+		rerrs = dropMissingIDs(rerrs)
+		if len(rerrs) > 0 {
+			errs = append(errs, rerrs...)
+		}
+		addAll(data, theseData)
+	}
+	errs = appendNotFoundErrors("Response", ids, data, errs)
+	return
+}
+
 func (mf MultiFetcher) FetchCategories(ctx context.Context, primaryAdServer, publisherId, iabCategory string) (string, error) {
 	for _, f := range mf {
 		if cf, ok := f.(CategoryFetcher); ok {
@@ -125,3 +125,36 @@ func TestOtherError(t *testing.T) {
 	assert.JSONEq(t, `{"req_id": "def"}`, string(reqData["def"]), "MultiFetcher should return the right request data")
 	assert.JSONEq(t, `{"imp_id": "imp-1"}`, string(impData["imp-1"]), "MultiFetcher should return the right imp data")
 }
+
+func TestMultiFetcherResponses(t *testing.T) {
+	f1 := &mockFetcher{}
+	f2 := &mockResponseFetcher{data: map[string]json.RawMessage{"resp-1": json.RawMessage(`{"id": "resp-1"}`)}}
+	f3 := &mockResponseFetcher{data: map[string]json.RawMessage{"resp-1": json.RawMessage(`{"id": "shadowed"}`), "resp-2": json.RawMessage(`{"id": "resp-2"}`)}}
+	fetcher := &MultiFetcher{f1, f2, f3}
+
+	data, errs := fetcher.FetchResponses(context.Background(), []string{"resp-1", "resp-2", "resp-3"})
+
+	assert.Len(t, data, 2, "MultiFetcher should return all the stored responses that exist")
+	assert.JSONEq(t, `{"id": "resp-1"}`, string(data["resp-1"]), "The first Fetcher with the response should win")
+	assert.JSONEq(t, `{"id": "resp-2"}`, string(data["resp-2"]), "MultiFetcher should return the right response data")
+	assert.Equal(t, []error{NotFoundError{"resp-3", "Response"}}, errs, "MultiFetcher should return a single NotFoundError per missing ID")
+	assert.Equal(t, []string{"resp-2", "resp-3"}, f3.asked, "Later Fetchers should only be asked for the missing IDs")
+}
+
+type mockResponseFetcher struct {
+	mockFetcher
+	data  map[string]json.RawMessage
+	asked []string
+}
+
+func (f *mockResponseFetcher) FetchResponses(ctx context.Context, ids []string) (map[string]json.RawMessage, []error) {
+	f.asked = ids
+	data := make(map[string]json.RawMessage, len(ids))
+	errs := appendNotFoundErrors("Response", ids, f.data, nil)
+	for _, id := range ids {
+		if value, ok := f.data[id]; ok {
+			data[id] = value
+		}
+	}
+	return data, errs
+}
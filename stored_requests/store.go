@@ -0,0 +1,48 @@
+package stored_requests
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// DataType tells a Store whether an ID refers to a Stored Request or a Stored Imp.
+// The values match the DataType of the NotFoundErrors returned by the Fetchers.
+type DataType string
+
+const (
+	RequestDataType DataType = "Request"
+	ImpDataType     DataType = "Imp"
+)
+
+// Version is one revision of a Stored Request or Imp.
+type Version struct {
+	// Version numbers start at 1 and grow by 1 with every Save or Delete of the ID.
+	Version int             `json:"version"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	// Deleted is true if this revision removed the Stored Request or Imp.
+	Deleted bool      `json:"deleted,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// Store is a Fetcher which can also be written to. It keeps every revision of the data it holds,
+// so that changes can be audited and rolled back.
+//
+// Implementations must be safe for concurrent access by multiple goroutines.
+type Store interface {
+	AllFetcher
+
+	// Save adds data as the newest version of the Stored Request or Imp with the given ID.
+	Save(ctx context.Context, dataType DataType, id string, data json.RawMessage) (Version, error)
+
+	// Delete adds a version which removes the Stored Request or Imp with the given ID.
+	// It returns a NotFoundError if the ID doesn't exist.
+	Delete(ctx context.Context, dataType DataType, id string) (Version, error)
+
+	// IDs returns the sorted IDs of the Stored Requests or Imps which currently exist.
+	IDs(ctx context.Context, dataType DataType) ([]string, error)
+
+	// Versions returns every version of the Stored Request or Imp with the given ID, oldest first.
+	// It returns a NotFoundError if the ID has never been saved.
+	Versions(ctx context.Context, dataType DataType, id string) ([]Version, error)
+}
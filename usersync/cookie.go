@@ -1,7 +1,6 @@
 package usersync
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"math"
@@ -52,18 +51,27 @@ type uidWithExpiry struct {
 
 // ParsePBSCookieFromRequest parses the UserSyncMap from an HTTP Request.
 func ParsePBSCookieFromRequest(r *http.Request, cookie *config.HostCookie) *PBSCookie {
+	parsed, _ := ReadPBSCookieFromRequest(r, cookie)
+	return parsed
+}
+
+// ReadPBSCookieFromRequest parses the UserSyncMap from an HTTP Request, like ParsePBSCookieFromRequest.
+// It also returns ErrInvalidCookie or ErrTamperedCookie if the uids cookie couldn't be used, so that the caller can
+// count them. The returned PBSCookie is usable either way.
+func ReadPBSCookieFromRequest(r *http.Request, cookie *config.HostCookie) (*PBSCookie, error) {
 	if cookie.OptOutCookie.Name != "" {
 		optOutCookie, err1 := r.Cookie(cookie.OptOutCookie.Name)
 		if err1 == nil && optOutCookie.Value == cookie.OptOutCookie.Value {
 			pc := NewPBSCookie()
 			pc.SetPreference(false)
-			return pc
+			return pc, nil
 		}
 	}
 	var parsed *PBSCookie
+	var parseErr error
 	uidCookie, err2 := r.Cookie(UID_COOKIE_NAME)
 	if err2 == nil {
-		parsed = ParsePBSCookie(uidCookie)
+		parsed, parseErr = ParsePBSCookie(uidCookie, &cookie.Security)
 	} else {
 		parsed = NewPBSCookie()
 	}
@@ -73,23 +81,26 @@ func ParsePBSCookieFromRequest(r *http.Request, cookie *config.HostCookie) *PBSC
 			parsed.TrySync(cookie.Family, hostCookie.Value)
 		}
 	}
-	return parsed
+	return parsed, parseErr
 }
 
-// ParsePBSCookie parses the UserSync cookie from a raw HTTP cookie.
-func ParsePBSCookie(uidCookie *http.Cookie) *PBSCookie {
+// ParsePBSCookie parses the UserSync cookie from a raw HTTP cookie. It reads both the signed format, which it checks
+// with the security keys, and the legacy unsigned one.
+//
+// If the cookie can't be used, it returns an empty PBSCookie along with ErrInvalidCookie or ErrTamperedCookie.
+func ParsePBSCookie(uidCookie *http.Cookie, security *config.CookieSecurity) (*PBSCookie, error) {
 	pc := NewPBSCookie()
 
-	j, err := base64.URLEncoding.DecodeString(uidCookie.Value)
+	j, err := decodeCookieValue(uidCookie.Value, security)
 	if err != nil {
-		// corrupted cookie; we should reset
-		return pc
+		// corrupted or forged cookie; we should reset
+		return pc, err
 	}
-	err = json.Unmarshal(j, pc)
-
-	// The error on Unmarshal here isn't terribly important.
-	// If the cookie has been corrupted, we should reset to an empty one anyway.
-	return pc
+	if err := json.Unmarshal(j, pc); err != nil {
+		// If the cookie has been corrupted, we should reset to an empty one anyway.
+		return NewPBSCookie(), ErrInvalidCookie
+	}
+	return pc, nil
 }
 
 // NewPBSCookie returns an empty PBSCookie
@@ -125,13 +136,18 @@ func (cookie *PBSCookie) SetPreference(allow bool) {
 }
 
 // Gets an HTTP cookie containing all the data from this UserSyncMap. This is a snapshot--not a live view.
+// The cookie has the legacy, unsigned format. SetCookieOnResponse signs it with the host's keys.
 func (cookie *PBSCookie) ToHTTPCookie(ttl time.Duration) *http.Cookie {
+	return cookie.toHTTPCookie(ttl, nil)
+}
+
+func (cookie *PBSCookie) toHTTPCookie(ttl time.Duration, security *config.CookieSecurity) *http.Cookie {
 	j, _ := json.Marshal(cookie)
-	b64 := base64.URLEncoding.EncodeToString(j)
+	value, _ := encodeCookieValue(j, security)
 
 	return &http.Cookie{
 		Name:    UID_COOKIE_NAME,
-		Value:   b64,
+		Value:   value,
 		Expires: time.Now().Add(ttl),
 		Path:    "/",
 	}
@@ -152,6 +168,17 @@ func (cookie *PBSCookie) GetUID(familyName string) (string, bool, bool) {
 	return "", false, false
 }
 
+// SyncedAt returns the time at which this user's ID for the given family was set.
+// The second returned value is false if no value was stored.
+func (cookie *PBSCookie) SyncedAt(familyName string) (time.Time, bool) {
+	if cookie != nil {
+		if uid, ok := cookie.uids[familyName]; ok {
+			return uid.Expires.Add(-getTTL(familyName)), true
+		}
+	}
+	return time.Time{}, false
+}
+
 // GetUIDs returns this user's ID for all the bidders
 func (cookie *PBSCookie) GetUIDs() map[string]string {
 	uids := make(map[string]string)
@@ -176,7 +203,7 @@ func (cookie *PBSCookie) GetId(bidderName openrtb_ext.BidderName) (id string, ex
 
 // SetCookieOnResponse is a shortcut for "ToHTTPCookie(); cookie.setDomain(domain); setCookie(w, cookie)"
 func (cookie *PBSCookie) SetCookieOnResponse(w http.ResponseWriter, setSiteCookie bool, secParam string, cfg *config.HostCookie, ttl time.Duration) {
-	httpCookie := cookie.ToHTTPCookie(ttl)
+	httpCookie := cookie.toHTTPCookie(ttl, &cfg.Security)
 	var domain string = cfg.Domain
 
 	if domain != "" {
@@ -195,7 +222,7 @@ func (cookie *PBSCookie) SetCookieOnResponse(w http.ResponseWriter, setSiteCooki
 			}
 		}
 		delete(cookie.uids, oldestElem)
-		httpCookie = cookie.ToHTTPCookie(ttl)
+		httpCookie = cookie.toHTTPCookie(ttl, &cfg.Security)
 		if domain != "" {
 			httpCookie.Domain = domain
 		}
@@ -347,11 +374,15 @@ func (cookie *PBSCookie) UnmarshalJSON(b []byte) error {
 
 // getExpiry gets an expiry date for the cookie, assuming it was generated right now.
 func getExpiry(familyName string) time.Time {
-	ttl := DEFAULT_TTL
+	return time.Now().Add(getTTL(familyName))
+}
+
+// getTTL gets the amount of time which a UID for the family is valid.
+func getTTL(familyName string) time.Duration {
 	if customTTL, ok := customBidderTTLs[familyName]; ok {
-		ttl = customTTL
+		return customTTL
 	}
-	return time.Now().Add(ttl)
+	return DEFAULT_TTL
 }
 
 func timestamp() *time.Time {
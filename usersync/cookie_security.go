@@ -0,0 +1,145 @@
+package usersync
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+)
+
+// The modes of a protected cookie value, which has the format "{mode}.{keyID}.{payload}.{signature}".
+// The legacy, unsigned format is plain base64, which never contains a ".".
+const (
+	cookieModeSigned    = "s"
+	cookieModeEncrypted = "e"
+)
+
+var (
+	// ErrInvalidCookie means the uids cookie couldn't be decoded.
+	ErrInvalidCookie = errors.New("the uids cookie is invalid")
+	// ErrTamperedCookie means the uids cookie's signature didn't match any of the host's keys, or it wasn't signed
+	// although the host requires it.
+	ErrTamperedCookie = errors.New("the uids cookie was not signed by this host")
+)
+
+// CookieErrorReason returns the label which the metrics count an error from ReadPBSCookieFromRequest under.
+func CookieErrorReason(err error) pbsmetrics.CookieError {
+	if err == ErrTamperedCookie {
+		return pbsmetrics.CookieErrorTampered
+	}
+	return pbsmetrics.CookieErrorInvalid
+}
+
+// encodeCookieValue turns the cookie's JSON into the value of the HTTP cookie. It's signed, and maybe encrypted,
+// with the first of the host's keys. If there are none, it uses the legacy format.
+func encodeCookieValue(j []byte, security *config.CookieSecurity) (string, error) {
+	if security == nil || len(security.Keys) == 0 {
+		return base64.URLEncoding.EncodeToString(j), nil
+	}
+
+	key := security.Keys[0]
+	mode := cookieModeSigned
+	payload := j
+	if security.Encrypt {
+		mode = cookieModeEncrypted
+		aead, err := newCookieCipher(key.Secret)
+		if err != nil {
+			return "", err
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+		payload = aead.Seal(nonce, nonce, j, []byte(key.ID))
+	}
+
+	signed := mode + "." + key.ID + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + signCookieValue(key.Secret, signed), nil
+}
+
+// decodeCookieValue checks the value of the HTTP cookie, and returns the cookie's JSON.
+func decodeCookieValue(value string, security *config.CookieSecurity) ([]byte, error) {
+	hasKeys := security != nil && len(security.Keys) > 0
+	if !strings.Contains(value, ".") {
+		if hasKeys && !security.AcceptUnsigned {
+			return nil, ErrTamperedCookie
+		}
+		j, err := base64.URLEncoding.DecodeString(value)
+		if err != nil {
+			return nil, ErrInvalidCookie
+		}
+		return j, nil
+	}
+
+	parts := strings.Split(value, ".")
+	if len(parts) != 4 || (parts[0] != cookieModeSigned && parts[0] != cookieModeEncrypted) {
+		return nil, ErrInvalidCookie
+	}
+	if !hasKeys {
+		return nil, ErrTamperedCookie
+	}
+	key, ok := findCookieKey(security.Keys, parts[1])
+	if !ok {
+		return nil, ErrTamperedCookie
+	}
+	signed := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(signCookieValue(key.Secret, signed)), []byte(parts[3])) {
+		return nil, ErrTamperedCookie
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidCookie
+	}
+	if parts[0] == cookieModeSigned {
+		return payload, nil
+	}
+
+	aead, err := newCookieCipher(key.Secret)
+	if err != nil || len(payload) < aead.NonceSize() {
+		return nil, ErrInvalidCookie
+	}
+	j, err := aead.Open(nil, payload[:aead.NonceSize()], payload[aead.NonceSize():], []byte(key.ID))
+	if err != nil {
+		return nil, ErrTamperedCookie
+	}
+	return j, nil
+}
+
+func findCookieKey(keys []config.CookieKey, id string) (config.CookieKey, bool) {
+	for _, key := range keys {
+		if key.ID == id {
+			return key, true
+		}
+	}
+	return config.CookieKey{}, false
+}
+
+// deriveCookieKey derives a key for one purpose from the configured secret, so that the signing
+// and encryption keys are different.
+func deriveCookieKey(secret string, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+func signCookieValue(secret string, signed string) string {
+	mac := hmac.New(sha256.New, deriveCookieKey(secret, "uids-cookie-signature"))
+	mac.Write([]byte(signed))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newCookieCipher(secret string) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(deriveCookieKey(secret, "uids-cookie-encryption"))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
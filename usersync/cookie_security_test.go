@@ -0,0 +1,107 @@
+package usersync
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PubMatic-OpenWrap/prebid-server/config"
+	"github.com/PubMatic-OpenWrap/prebid-server/pbsmetrics"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCookieKeys = []config.CookieKey{
+	{ID: "new", Secret: "0123456789abcdef-new"},
+	{ID: "old", Secret: "0123456789abcdef-old"},
+}
+
+func TestSignedCookieRoundTrip(t *testing.T) {
+	for _, encrypt := range []bool{false, true} {
+		hostCookie := &config.HostCookie{Security: config.CookieSecurity{Keys: testCookieKeys, Encrypt: encrypt}}
+
+		value := writeCookieValue(newSampleCookie(), hostCookie)
+		parsed, err := ParsePBSCookie(&http.Cookie{Name: UID_COOKIE_NAME, Value: value}, &hostCookie.Security)
+
+		assert.NoError(t, err, "encrypt=%t", encrypt)
+		assert.True(t, strings.HasPrefix(value, map[bool]string{false: "s.new.", true: "e.new."}[encrypt]), "The first key should sign the cookie")
+		assert.Equal(t, newSampleCookie().GetUIDs(), parsed.GetUIDs(), "encrypt=%t", encrypt)
+		payload, _ := base64.RawURLEncoding.DecodeString(strings.Split(value, ".")[2])
+		assert.Equal(t, !encrypt, strings.Contains(string(payload), `"adnxs"`), "Only an encrypted cookie should hide its content")
+	}
+}
+
+func TestSignedCookieKeyRotation(t *testing.T) {
+	oldHost := &config.HostCookie{Security: config.CookieSecurity{Keys: testCookieKeys[1:]}}
+	value := writeCookieValue(newSampleCookie(), oldHost)
+
+	parsed, err := ParsePBSCookie(&http.Cookie{Name: UID_COOKIE_NAME, Value: value}, &config.CookieSecurity{Keys: testCookieKeys})
+	assert.NoError(t, err, "A cookie signed with an older key should still be read")
+	assert.Len(t, parsed.GetUIDs(), 2)
+
+	parsed, err = ParsePBSCookie(&http.Cookie{Name: UID_COOKIE_NAME, Value: value}, &config.CookieSecurity{Keys: testCookieKeys[:1]})
+	assert.Equal(t, ErrTamperedCookie, err, "A cookie signed with a removed key should be rejected")
+	assert.Empty(t, parsed.GetUIDs())
+}
+
+func TestTamperedCookie(t *testing.T) {
+	security := &config.CookieSecurity{Keys: testCookieKeys}
+	value := writeCookieValue(newSampleCookie(), &config.HostCookie{Security: *security})
+	parts := strings.Split(value, ".")
+
+	forged := NewPBSCookie()
+	forged.TrySync("adnxs", "forged")
+	parts[2] = encodedPayload(t, forged)
+
+	parsed, err := ParsePBSCookie(&http.Cookie{Name: UID_COOKIE_NAME, Value: strings.Join(parts, ".")}, security)
+	assert.Equal(t, ErrTamperedCookie, err)
+	assert.Empty(t, parsed.GetUIDs(), "A tampered cookie should be reset")
+	assert.Equal(t, pbsmetrics.CookieErrorTampered, CookieErrorReason(err))
+
+	_, err = ParsePBSCookie(&http.Cookie{Name: UID_COOKIE_NAME, Value: "s.new.notbase64!.sig"}, security)
+	assert.Equal(t, ErrTamperedCookie, err)
+
+	_, err = ParsePBSCookie(&http.Cookie{Name: UID_COOKIE_NAME, Value: "a.b"}, security)
+	assert.Equal(t, ErrInvalidCookie, err)
+	assert.Equal(t, pbsmetrics.CookieErrorInvalid, CookieErrorReason(err))
+}
+
+func TestLegacyCookieWithKeys(t *testing.T) {
+	legacy := newSampleCookie().ToHTTPCookie(time.Hour)
+
+	parsed, err := ParsePBSCookie(legacy, &config.CookieSecurity{Keys: testCookieKeys, AcceptUnsigned: true})
+	assert.NoError(t, err)
+	assert.Len(t, parsed.GetUIDs(), 2, "Unsigned cookies should be read while they're accepted")
+
+	parsed, err = ParsePBSCookie(legacy, &config.CookieSecurity{Keys: testCookieKeys})
+	assert.Equal(t, ErrTamperedCookie, err)
+	assert.Empty(t, parsed.GetUIDs(), "Unsigned cookies should be rejected once they're no longer accepted")
+}
+
+func writeCookieValue(cookie *PBSCookie, hostCookie *config.HostCookie) string {
+	w := httptest.NewRecorder()
+	cookie.SetCookieOnResponse(w, false, "", hostCookie, time.Hour)
+	response := http.Response{Header: w.Header()}
+	for _, c := range response.Cookies() {
+		if c.Name == UID_COOKIE_NAME {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+func encodedPayload(t *testing.T, cookie *PBSCookie) string {
+	t.Helper()
+	value, err := encodeCookieValue(mustMarshal(t, cookie), &config.CookieSecurity{Keys: testCookieKeys})
+	assert.NoError(t, err)
+	return strings.Split(value, ".")[2]
+}
+
+func mustMarshal(t *testing.T, cookie *PBSCookie) []byte {
+	t.Helper()
+	j, err := cookie.MarshalJSON()
+	assert.NoError(t, err)
+	return j
+}
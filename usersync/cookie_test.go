@@ -73,7 +73,7 @@ func TestRejectAudienceNetworkCookie(t *testing.T) {
 		optOut:   false,
 		birthday: timestamp(),
 	}
-	parsed := ParsePBSCookie(raw.ToHTTPCookie(90 * 24 * time.Hour))
+	parsed, _ := ParsePBSCookie(raw.ToHTTPCookie(90*24*time.Hour), nil)
 	if parsed.HasLiveSync("audienceNetwork") {
 		t.Errorf("Cookie serializing and deserializing should delete audienceNetwork values of 0")
 	}
@@ -116,7 +116,7 @@ func TestParseCorruptedCookie(t *testing.T) {
 		Name:  "uids",
 		Value: "bad base64 encoding",
 	}
-	parsed := ParsePBSCookie(&raw)
+	parsed, _ := ParsePBSCookie(&raw, nil)
 	ensureEmptyMap(t, parsed)
 }
 
@@ -126,7 +126,7 @@ func TestParseCorruptedCookieJSON(t *testing.T) {
 		Name:  "uids",
 		Value: cookieData,
 	}
-	parsed := ParsePBSCookie(&raw)
+	parsed, _ := ParsePBSCookie(&raw, nil)
 	ensureEmptyMap(t, parsed)
 }
 
@@ -137,7 +137,7 @@ func TestParseNilSyncMap(t *testing.T) {
 		Name:  UID_COOKIE_NAME,
 		Value: cookieData,
 	}
-	parsed := ParsePBSCookie(&raw)
+	parsed, _ := ParsePBSCookie(&raw, nil)
 	ensureEmptyMap(t, parsed)
 	ensureConsistency(t, parsed)
 }
@@ -263,6 +263,19 @@ func TestGetUIDsWithNilCookie(t *testing.T) {
 	assert.Len(t, uids, 0, "GetUIDs shouldn't return any user syncs for a nil cookie")
 }
 
+func TestSyncedAt(t *testing.T) {
+	cookie := NewPBSCookie()
+	before := time.Now()
+	cookie.TrySync("adnxs", "123")
+
+	syncedAt, ok := cookie.SyncedAt("adnxs")
+	assert.True(t, ok)
+	assert.WithinDuration(t, before, syncedAt, time.Second, "SyncedAt should return the time of the sync")
+
+	_, ok = cookie.SyncedAt("rubicon")
+	assert.False(t, ok, "SyncedAt shouldn't find a family which was never synced")
+}
+
 func TestTrimCookiesClosestExpirationDates(t *testing.T) {
 	cookieToSend, cookieToSendLen := newTestCookie()
 	closestToExpirationDate := "key7"
@@ -340,7 +353,7 @@ func ensureConsistency(t *testing.T, cookie *PBSCookie) {
 		}
 	}
 
-	copiedCookie := ParsePBSCookie(cookie.ToHTTPCookie(90 * 24 * time.Hour))
+	copiedCookie, _ := ParsePBSCookie(cookie.ToHTTPCookie(90*24*time.Hour), nil)
 	if copiedCookie.AllowSyncs() != cookie.AllowSyncs() {
 		t.Error("The PBSCookie interface shouldn't let modifications happen if the user has opted out")
 	}
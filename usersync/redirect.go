@@ -0,0 +1,57 @@
+package usersync
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redirectClockSkew is how far in the future a signature's timestamp may be, for hosts whose clocks differ a little.
+const redirectClockSkew = time.Minute
+
+// SignRedirect returns the signature which /setuid expects in its "sig" query param, alongside a "redirect".
+// The signature stops /setuid from being used as an open redirect: only the host, which knows the secret,
+// can chain a sync onto another URL. It holds the time it was made, so that /setuid can reject old ones
+// which have leaked.
+func SignRedirect(secret string, redirect string, signedAt time.Time) string {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	return timestamp + "." + redirectMAC(secret, timestamp, redirect)
+}
+
+// VerifyRedirect checks that the signature was made by SignRedirect with the same secret and redirect,
+// no more than maxAge ago.
+func VerifyRedirect(secret string, redirect string, signature string, maxAge time.Duration) error {
+	return verifyRedirectAt(secret, redirect, signature, maxAge, time.Now())
+}
+
+func verifyRedirectAt(secret string, redirect string, signature string, maxAge time.Duration, now time.Time) error {
+	if secret == "" {
+		return errors.New("the host doesn't sign redirects")
+	}
+	parts := strings.SplitN(signature, ".", 2)
+	if len(parts) != 2 {
+		return errors.New("the signature must have a timestamp")
+	}
+	if !hmac.Equal([]byte(redirectMAC(secret, parts[0], redirect)), []byte(parts[1])) {
+		return errors.New("the signature doesn't match")
+	}
+	seconds, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return errors.New("the signature must have a timestamp")
+	}
+	signedAt := time.Unix(seconds, 0)
+	if now.Sub(signedAt) > maxAge || signedAt.Sub(now) > redirectClockSkew {
+		return errors.New("the signature has expired")
+	}
+	return nil
+}
+
+func redirectMAC(secret string, timestamp string, redirect string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + redirect))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
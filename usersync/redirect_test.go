@@ -0,0 +1,91 @@
+package usersync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRedirect(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	redirect := "https://sync.example.com/next"
+
+	testCases := []struct {
+		description string
+		secret      string
+		redirect    string
+		signature   string
+		expected    string
+	}{
+		{
+			description: "Valid",
+			secret:      "secret",
+			redirect:    redirect,
+			signature:   SignRedirect("secret", redirect, now.Add(-time.Minute)),
+		},
+		{
+			description: "Small Clock Skew",
+			secret:      "secret",
+			redirect:    redirect,
+			signature:   SignRedirect("secret", redirect, now.Add(30*time.Second)),
+		},
+		{
+			description: "No Secret",
+			redirect:    redirect,
+			signature:   SignRedirect("", redirect, now),
+			expected:    "the host doesn't sign redirects",
+		},
+		{
+			description: "Other Secret",
+			secret:      "secret",
+			redirect:    redirect,
+			signature:   SignRedirect("other", redirect, now),
+			expected:    "the signature doesn't match",
+		},
+		{
+			description: "Other Redirect",
+			secret:      "secret",
+			redirect:    "https://evil.example.com",
+			signature:   SignRedirect("secret", redirect, now),
+			expected:    "the signature doesn't match",
+		},
+		{
+			description: "Changed Timestamp",
+			secret:      "secret",
+			redirect:    redirect,
+			signature:   "1600000000" + SignRedirect("secret", redirect, time.Unix(1500000000, 0))[10:],
+			expected:    "the signature doesn't match",
+		},
+		{
+			description: "No Timestamp",
+			secret:      "secret",
+			redirect:    redirect,
+			signature:   redirectMAC("secret", "", redirect),
+			expected:    "the signature must have a timestamp",
+		},
+		{
+			description: "Expired",
+			secret:      "secret",
+			redirect:    redirect,
+			signature:   SignRedirect("secret", redirect, now.Add(-6*time.Minute)),
+			expected:    "the signature has expired",
+		},
+		{
+			description: "Future",
+			secret:      "secret",
+			redirect:    redirect,
+			signature:   SignRedirect("secret", redirect, now.Add(time.Hour)),
+			expected:    "the signature has expired",
+		},
+	}
+
+	for _, test := range testCases {
+		err := verifyRedirectAt(test.secret, test.redirect, test.signature, 5*time.Minute, now)
+		if test.expected == "" {
+			assert.NoError(t, err, test.description)
+		} else {
+			assert.EqualError(t, err, test.expected, test.description)
+		}
+	}
+}
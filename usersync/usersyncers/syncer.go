@@ -7,7 +7,7 @@ import (
 
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters/adpone"
 
-	"github.com/golang/glog"
+	"github.com/PubMatic-OpenWrap/prebid-server/adapters"
 	ttx "github.com/PubMatic-OpenWrap/prebid-server/adapters/33across"
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters/adform"
 	"github.com/PubMatic-OpenWrap/prebid-server/adapters/adkernel"
@@ -55,6 +55,7 @@ import (
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
 	"github.com/PubMatic-OpenWrap/prebid-server/usersync"
+	"github.com/golang/glog"
 )
 
 // NewSyncerMap returns a map of all the usersyncer objects.
@@ -110,9 +111,31 @@ func NewSyncerMap(cfg *config.Configuration) map[openrtb_ext.BidderName]usersync
 	insertIntoMap(cfg, syncers, openrtb_ext.BidderVrtcal, vrtcal.NewVrtcalSyncer)
 	insertIntoMap(cfg, syncers, openrtb_ext.BidderYieldmo, yieldmo.NewYieldmoSyncer)
 
+	for aliasName, alias := range cfg.BidderAliases {
+		insertAliasIntoMap(syncers, aliasName, alias)
+	}
+
 	return syncers
 }
 
+// insertAliasIntoMap gives a host alias a syncer of its own, if it has a usersync_url. The alias's cookie family is
+// its own name. It has the core bidder's vendor ID unless it defines one.
+func insertAliasIntoMap(syncers map[openrtb_ext.BidderName]usersync.Usersyncer, aliasName string, alias config.BidderAlias) {
+	if alias.UserSyncURL == "" {
+		return
+	}
+	vendorID := alias.GVLVendorID
+	if coreSyncer, ok := syncers[openrtb_ext.BidderName(alias.Bidder)]; ok && vendorID == 0 {
+		vendorID = coreSyncer.GDPRVendorID()
+	}
+	syncType := adapters.SyncTypeRedirect
+	if alias.UserSyncType == string(adapters.SyncTypeIframe) {
+		syncType = adapters.SyncTypeIframe
+	}
+	urlTemplate := template.Must(template.New(aliasName + "_usersync_url").Parse(alias.UserSyncURL))
+	syncers[openrtb_ext.BidderName(aliasName)] = adapters.NewSyncer(aliasName, vendorID, urlTemplate, syncType)
+}
+
 func insertIntoMap(cfg *config.Configuration, syncers map[openrtb_ext.BidderName]usersync.Usersyncer, bidder openrtb_ext.BidderName, syncerFactory func(*template.Template) usersync.Usersyncer) {
 	lowercased := strings.ToLower(string(bidder))
 	urlString := cfg.Adapters[lowercased].UserSyncURL
@@ -6,6 +6,8 @@ import (
 
 	"github.com/PubMatic-OpenWrap/prebid-server/config"
 	"github.com/PubMatic-OpenWrap/prebid-server/openrtb_ext"
+	"github.com/PubMatic-OpenWrap/prebid-server/privacy"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewSyncerMap(t *testing.T) {
@@ -85,6 +87,33 @@ func TestNewSyncerMap(t *testing.T) {
 	}
 }
 
+func TestNewSyncerMapAliases(t *testing.T) {
+	cfg := &config.Configuration{
+		Adapters: map[string]config.Adapter{
+			"appnexus": {UserSyncURL: "https://appnexus.com/sync"},
+		},
+		BidderAliases: map[string]config.BidderAlias{
+			"synced":      {Bidder: "appnexus", UserSyncURL: "https://alias.com/sync", UserSyncType: "iframe"},
+			"ownvendor":   {Bidder: "appnexus", UserSyncURL: "https://alias.com/sync", GVLVendorID: 99},
+			"sharedsyncs": {Bidder: "appnexus"},
+		},
+	}
+
+	syncers := NewSyncerMap(cfg)
+
+	if syncer, ok := syncers["synced"]; assert.True(t, ok) {
+		assert.Equal(t, "synced", syncer.FamilyName(), "An alias with a usersync_url should have its own cookie family")
+		assert.Equal(t, syncers[openrtb_ext.BidderAppnexus].GDPRVendorID(), syncer.GDPRVendorID())
+		info, err := syncer.GetUsersyncInfo(privacy.Policies{})
+		assert.NoError(t, err)
+		assert.Equal(t, "iframe", info.Type)
+	}
+	if syncer, ok := syncers["ownvendor"]; assert.True(t, ok) {
+		assert.Equal(t, uint16(99), syncer.GDPRVendorID())
+	}
+	assert.NotContains(t, syncers, openrtb_ext.BidderName("sharedsyncs"))
+}
+
 // Bidders may have an ID on the IAB-maintained global vendor list.
 // This makes sure that we don't have conflicting IDs among Bidders in our project,
 // since that's almost certainly a bug.